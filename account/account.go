@@ -54,6 +54,13 @@ func GetAccount(ctx context.Context, cfg *config.Configuration, fetcher stored_r
 				Message: fmt.Sprintf("The prebid-server account config DSA for account id \"%s\" is malformed. Please reach out to the prebid server host.", accountID),
 			}}
 		}
+		if account.Privacy.AllowActivities != nil {
+			if activityErrs := account.Privacy.AllowActivities.Validate("privacy.allowactivities", nil); len(activityErrs) > 0 {
+				return nil, []error{&errortypes.MalformedAcct{
+					Message: fmt.Sprintf("The prebid-server account config activities for account id \"%s\" are malformed: %v. Please reach out to the prebid server host.", accountID, activityErrs[0]),
+				}}
+			}
+		}
 
 		// Fill in ID if needed, so it can be left out of account definition
 		if len(account.ID) == 0 {
@@ -32,6 +32,7 @@ type AuctionObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	PrivacyAudit         *openrtb_ext.PrivacyAudit
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
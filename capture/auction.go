@@ -0,0 +1,202 @@
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// Auction is a captured auction: the request, the HTTP calls made to the bidders while it runs and the response.
+// The user and device identifiers, IP addresses and geolocation of the requests are scrubbed, as well as the
+// credentials and cookies of the bidder response headers and the query parameter values of the bidder call URIs.
+type Auction struct {
+	ID          string          `json:"id"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Account     string          `json:"account"`
+	Request     json.RawMessage `json:"request"`
+	BidderCalls []BidderCall    `json:"bidder_calls"`
+	Response    json.RawMessage `json:"response,omitempty"`
+
+	mutex  sync.Mutex
+	ipConf privacy.IPConf
+}
+
+// BidderCall is an HTTP call made to a bidder
+type BidderCall struct {
+	Bidder          string          `json:"bidder"`
+	Method          string          `json:"method"`
+	URI             string          `json:"uri"`
+	RequestBody     json.RawMessage `json:"request_body,omitempty"`
+	Status          int             `json:"status,omitempty"`
+	ResponseHeaders http.Header     `json:"response_headers,omitempty"`
+	ResponseBody    string          `json:"response_body,omitempty"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// NewAuction starts the capture of an auction, the request being scrubbed according to the account IP config.
+// The stored requests are already merged into the request, their ids are removed so the replay does not depend on them.
+func NewAuction(req *openrtb_ext.RequestWrapper, accountID string, ipConf privacy.IPConf) (*Auction, error) {
+	body, err := jsonutil.Marshal(req.BidRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := scrubRequest(body, ipConf, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Auction{
+		ID:          req.ID,
+		Timestamp:   time.Now().UTC(),
+		Account:     accountID,
+		Request:     request,
+		BidderCalls: []BidderCall{},
+		ipConf:      ipConf,
+	}, nil
+}
+
+// AddBidderCall adds an HTTP call made to a bidder. It is safe to call concurrently, the bidders being called in parallel.
+// The request body is kept when it is an OpenRTB request, after being scrubbed as the auction request.
+func (a *Auction) AddBidderCall(bidder string, req *adapters.RequestData, resp *adapters.ResponseData, err error) {
+	if a == nil || req == nil {
+		return
+	}
+
+	call := BidderCall{Bidder: bidder, Method: req.Method, URI: redactURI(req.Uri)}
+	if body, err := scrubRequest(req.Body, a.ipConf, false); err == nil {
+		call.RequestBody = body
+	}
+	if resp != nil {
+		call.Status = resp.StatusCode
+		call.ResponseHeaders = responseHeaders(resp.Headers)
+		call.ResponseBody = string(resp.Body)
+	} else if err != nil {
+		call.Error = err.Error()
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.BidderCalls = append(a.BidderCalls, call)
+}
+
+var errNotOpenRTB = errors.New("body is not an OpenRTB request")
+
+// scrubRequest scrubs a copy of the OpenRTB request, failing when the body is not an OpenRTB request
+func scrubRequest(body []byte, ipConf privacy.IPConf, removeStoredRequests bool) (json.RawMessage, error) {
+	request := &openrtb2.BidRequest{}
+	if err := jsonutil.UnmarshalValid(body, request); err != nil {
+		return nil, err
+	}
+	if len(request.Imp) == 0 {
+		return nil, errNotOpenRTB
+	}
+
+	wrapper := &openrtb_ext.RequestWrapper{BidRequest: request}
+	privacy.ScrubDeviceIDsIPsUserDemoExt(wrapper, ipConf, "eids", true)
+	if wrapper.User != nil {
+		privacy.ScrubUserFPD(wrapper)
+	}
+
+	if removeStoredRequests {
+		if err := removeStoredRequestIDs(wrapper); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := wrapper.RebuildRequest(); err != nil {
+		return nil, err
+	}
+	return jsonutil.Marshal(wrapper.BidRequest)
+}
+
+func removeStoredRequestIDs(wrapper *openrtb_ext.RequestWrapper) error {
+	requestExt, err := wrapper.GetRequestExt()
+	if err != nil {
+		return err
+	}
+	if prebid := requestExt.GetPrebid(); prebid != nil && prebid.StoredRequest != nil {
+		prebid.StoredRequest = nil
+		requestExt.SetPrebid(prebid)
+	}
+
+	imps := wrapper.GetImp()
+	for _, imp := range imps {
+		impExt, err := imp.GetImpExt()
+		if err != nil {
+			return err
+		}
+		if prebid := impExt.GetPrebid(); prebid != nil && prebid.StoredRequest != nil {
+			prebid.StoredRequest = nil
+			impExt.SetPrebid(prebid)
+		}
+	}
+	wrapper.SetImp(imps)
+	return nil
+}
+
+// redactURI replaces the user info and query parameter values of the URI, which may hold credentials or user
+// identifiers. The query parameters are sorted by name. URIs which cannot be parsed are cut before their query.
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		before, _, _ := strings.Cut(uri, "?")
+		return before
+	}
+	if u.User != nil {
+		u.User = url.User(redactedValue)
+	}
+	if len(u.RawQuery) > 0 {
+		query := u.Query()
+		for _, values := range query {
+			for i := range values {
+				values[i] = redactedValue
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	u.Fragment = ""
+	return u.String()
+}
+
+// redactedHeaders are the response headers holding credentials or user identifiers, their values are not captured
+var redactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"WWW-Authenticate",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// redactedValue replaces the values which are not captured
+const redactedValue = "REDACTED"
+
+// responseHeaders copies the headers, without the ones describing the encoding of the body which is captured decoded
+// and with the values of the sensitive ones redacted
+func responseHeaders(headers http.Header) http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	copied := headers.Clone()
+	copied.Del("Content-Encoding")
+	copied.Del("Content-Length")
+	for _, name := range redactedHeaders {
+		if values := copied.Values(name); len(values) > 0 {
+			copied.Set(name, redactedValue)
+		}
+	}
+	return copied
+}
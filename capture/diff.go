@@ -0,0 +1,157 @@
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// DefaultIgnoredPaths are the paths of the response values expected to change from one run of an auction to the other
+var DefaultIgnoredPaths = []string{
+	"ext.responsetimemillis",
+	"ext.tmaxrequest",
+	"ext.prebid.auctiontimestamp",
+	"seatbid.*.bid.*.ext.prebid.bidid",
+	"seatbid.*.bid.*.ext.prebid.cache",
+	"seatbid.*.bid.*.ext.prebid.events",
+	"seatbid.*.bid.*.ext.prebid.targeting.hb_cache_id",
+	"seatbid.*.bid.*.ext.prebid.targeting.hb_uuid",
+}
+
+// DiffResponses compares the bid responses and returns the differences, one per path, the ignored paths excepted.
+// The seat bids are compared by seat and the bids by imp and id, the order the bidders respond in being random.
+// An ignored path may use * to match any array index or object key.
+func DiffResponses(expected, actual json.RawMessage, ignoredPaths []string) ([]string, error) {
+	expectedValue, err := normalizeResponse(expected)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expected response: %v", err)
+	}
+	actualValue, err := normalizeResponse(actual)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actual response: %v", err)
+	}
+
+	ignored := make([][]string, 0, len(ignoredPaths))
+	for _, path := range ignoredPaths {
+		ignored = append(ignored, strings.Split(path, "."))
+	}
+
+	var diffs []string
+	diffValues(nil, expectedValue, actualValue, ignored, &diffs)
+	return diffs, nil
+}
+
+// normalizeResponse sorts the seat bids and bids of the response and decodes it as generic JSON
+func normalizeResponse(data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	response := &openrtb2.BidResponse{}
+	if err := jsonutil.UnmarshalValid(data, response); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(response.SeatBid, func(i, j int) bool {
+		return response.SeatBid[i].Seat < response.SeatBid[j].Seat
+	})
+	for _, seatBid := range response.SeatBid {
+		sort.SliceStable(seatBid.Bid, func(i, j int) bool {
+			if seatBid.Bid[i].ImpID != seatBid.Bid[j].ImpID {
+				return seatBid.Bid[i].ImpID < seatBid.Bid[j].ImpID
+			}
+			return seatBid.Bid[i].ID < seatBid.Bid[j].ID
+		})
+	}
+
+	normalized, err := jsonutil.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = jsonutil.UnmarshalValid(normalized, &value)
+	return value, err
+}
+
+func diffValues(path []string, expected, actual interface{}, ignored [][]string, diffs *[]string) {
+	if isIgnored(path, ignored) {
+		return
+	}
+
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(e)+len(a))
+		for key := range e {
+			keys = append(keys, key)
+		}
+		for key := range a {
+			if _, ok := e[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			diffValues(append(path, key), e[key], a[key], ignored, diffs)
+		}
+		return
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(e) || i < len(a); i++ {
+			var ev, av interface{}
+			if i < len(e) {
+				ev = e[i]
+			}
+			if i < len(a) {
+				av = a[i]
+			}
+			diffValues(append(path, strconv.Itoa(i)), ev, av, ignored, diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: expected %s, got %s", strings.Join(path, "."), formatValue(expected), formatValue(actual)))
+	}
+}
+
+func isIgnored(path []string, ignored [][]string) bool {
+	for _, ignoredPath := range ignored {
+		if len(ignoredPath) != len(path) {
+			continue
+		}
+		matches := true
+		for i, segment := range ignoredPath {
+			if segment != "*" && segment != path[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "nothing"
+	}
+	data, err := jsonutil.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
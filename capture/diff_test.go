@@ -0,0 +1,65 @@
+package capture
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffResponses(t *testing.T) {
+	testCases := []struct {
+		description   string
+		expected      string
+		actual        string
+		ignoredPaths  []string
+		expectedDiffs []string
+		expectedError string
+	}{
+		{
+			description: "same",
+			expected:    `{"id":"1","seatbid":[{"seat":"a","bid":[{"id":"b1","impid":"i1","price":1}]}]}`,
+			actual:      `{"id":"1","seatbid":[{"seat":"a","bid":[{"id":"b1","impid":"i1","price":1}]}]}`,
+		},
+		{
+			description: "different-order",
+			expected:    `{"id":"1","seatbid":[{"seat":"a","bid":[{"id":"b1","impid":"i1","price":1},{"id":"b2","impid":"i1","price":2}]},{"seat":"b","bid":[{"id":"b3","impid":"i2","price":3}]}]}`,
+			actual:      `{"id":"1","seatbid":[{"seat":"b","bid":[{"id":"b3","impid":"i2","price":3}]},{"seat":"a","bid":[{"id":"b2","impid":"i1","price":2},{"id":"b1","impid":"i1","price":1}]}]}`,
+		},
+		{
+			description: "different-values",
+			expected:    `{"id":"1","cur":"USD","seatbid":[{"seat":"a","bid":[{"id":"b1","impid":"i1","price":1}]}]}`,
+			actual:      `{"id":"1","seatbid":[{"seat":"a","bid":[{"id":"b1","impid":"i1","price":1.5}]},{"seat":"b","bid":[{"id":"b2","impid":"i1","price":2}]}]}`,
+			expectedDiffs: []string{
+				`cur: expected "USD", got nothing`,
+				`seatbid.0.bid.0.price: expected 1, got 1.5`,
+				`seatbid.1: expected nothing, got {"bid":[{"id":"b2","impid":"i1","price":2}],"seat":"b"}`,
+			},
+		},
+		{
+			description:  "ignored",
+			expected:     `{"id":"1","seatbid":[{"seat":"a","bid":[{"id":"b1","impid":"i1","price":1,"ext":{"prebid":{"bidid":"x"}}}]}],"ext":{"responsetimemillis":{"a":10}}}`,
+			actual:       `{"id":"1","seatbid":[{"seat":"a","bid":[{"id":"b1","impid":"i1","price":1,"ext":{"prebid":{"bidid":"y"}}}]}],"ext":{"responsetimemillis":{"a":12}}}`,
+			ignoredPaths: DefaultIgnoredPaths,
+		},
+		{
+			description:   "invalid",
+			expected:      `{"id":"1"}`,
+			actual:        `{"id":`,
+			expectedError: "invalid actual response",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			diffs, err := DiffResponses(json.RawMessage(test.expected), json.RawMessage(test.actual), test.ignoredPaths)
+
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedDiffs, diffs)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// ReadAuctions calls fn with each auction of a capture file, stopping at the first error
+func ReadAuctions(r io.Reader, fn func(*Auction) error) error {
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			auction := &Auction{}
+			if err := jsonutil.UnmarshalValid(data, auction); err != nil {
+				return fmt.Errorf("line %d: %v", line, err)
+			}
+			if err := fn(auction); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ReplayTransport serves the HTTP calls to the bidders from the calls captured with an auction, as stored responses would.
+// A call is served by the first captured call not served yet with the same method and URL,
+// or with the same method, host and path when the query string differs, such as for cache busters.
+type ReplayTransport struct {
+	mutex  sync.Mutex
+	calls  []BidderCall
+	served []bool
+}
+
+// Load replaces the captured calls served by the transport with the ones of the auction
+func (t *ReplayTransport) Load(auction *Auction) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.calls = auction.BidderCalls
+	t.served = make([]bool, len(auction.BidderCalls))
+}
+
+// RoundTrip serves the request from the captured calls, failing when no captured call matches the request
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// the request is sent as far as the bidder is concerned
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	call, ok := t.match(req)
+	if !ok {
+		return nil, fmt.Errorf("no captured call for %s %s", req.Method, req.URL)
+	}
+	if call.Error != "" {
+		return nil, errors.New(call.Error)
+	}
+
+	header := call.ResponseHeaders.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", call.Status, http.StatusText(call.Status)),
+		StatusCode:    call.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(call.ResponseBody)),
+		ContentLength: int64(len(call.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+func (t *ReplayTransport) match(req *http.Request) (BidderCall, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// the captured URIs are redacted
+	uri := redactURI(req.URL.String())
+	for i, call := range t.calls {
+		if !t.served[i] && call.Method == req.Method && call.URI == uri {
+			t.served[i] = true
+			return call, true
+		}
+	}
+
+	for i, call := range t.calls {
+		if t.served[i] || call.Method != req.Method {
+			continue
+		}
+		if captured, err := req.URL.Parse(call.URI); err == nil && captured.Host == req.URL.Host && captured.Path == req.URL.Path {
+			t.served[i] = true
+			return call, true
+		}
+	}
+	return BidderCall{}, false
+}
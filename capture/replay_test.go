@@ -0,0 +1,92 @@
+package capture
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAuctions(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		expectedIDs   []string
+		expectedError string
+	}{
+		{
+			description: "auctions",
+			input:       "{\"id\":\"auction-1\"}\n\n{\"id\":\"auction-2\"}\n",
+			expectedIDs: []string{"auction-1", "auction-2"},
+		},
+		{
+			description: "no-trailing-newline",
+			input:       "{\"id\":\"auction-1\"}\n{\"id\":\"auction-2\"}",
+			expectedIDs: []string{"auction-1", "auction-2"},
+		},
+		{
+			description:   "malformed",
+			input:         "{\"id\":\"auction-1\"}\n{\"id\":\n",
+			expectedIDs:   []string{"auction-1"},
+			expectedError: "line 2:",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var ids []string
+			err := ReadAuctions(strings.NewReader(test.input), func(auction *Auction) error {
+				ids = append(ids, auction.ID)
+				return nil
+			})
+
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
+
+func TestReplayTransport(t *testing.T) {
+	transport := &ReplayTransport{}
+	transport.Load(&Auction{BidderCalls: []BidderCall{
+		{Bidder: "a", Method: "POST", URI: "https://a.com/bid?cb=1", Status: http.StatusOK, ResponseHeaders: http.Header{"X-Test": {"1"}}, ResponseBody: `{"id":"a"}`},
+		{Bidder: "b", Method: "POST", URI: "https://b.com/bid", Status: http.StatusNoContent},
+		{Bidder: "b", Method: "POST", URI: "https://b.com/bid", Error: "timeout"},
+		{Bidder: "c", Method: "GET", URI: "https://c.com/bid?key=REDACTED", Status: http.StatusNoContent},
+		{Bidder: "c", Method: "GET", URI: "https://c.com/bid?key=REDACTED&page=REDACTED", Status: http.StatusOK},
+	}})
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post("https://a.com/bid?cb=2", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("X-Test"))
+	assert.Equal(t, `{"id":"a"}`, string(body))
+
+	resp, err = client.Post("https://b.com/bid", "application/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	_, err = client.Post("https://b.com/bid", "application/json", nil)
+	assert.ErrorContains(t, err, "timeout")
+
+	_, err = client.Post("https://a.com/bid?cb=1", "application/json", nil)
+	assert.ErrorContains(t, err, "no captured call for POST https://a.com/bid?cb=1")
+
+	// the URIs are redacted as the captured ones to find the call with the same query parameters
+	resp, err = client.Get("https://c.com/bid?page=2&key=api-key")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = client.Get("https://b.com/bid")
+	assert.ErrorContains(t, err, "no captured call for GET https://b.com/bid")
+}
@@ -0,0 +1,177 @@
+package capture
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	filePrefix     = "capture-"
+	fileExtension  = ".jsonl"
+	fileTimeLayout = "20060102T150405.000000000"
+)
+
+// Writer writes the captured auctions as JSON lines in the background.
+// A new file is started once the current one reaches the maximum size, the oldest files being removed.
+// All the methods of a nil Writer, returned when the capture is disabled, are no-ops.
+type Writer struct {
+	directory   string
+	maxFileSize int64
+	maxFiles    int
+
+	mutex  sync.RWMutex
+	closed bool
+	queue  chan *Auction
+	done   chan struct{}
+
+	file *os.File
+	size int64
+	now  func() time.Time
+}
+
+// NewWriter creates the directory of the capture files and starts writing the auctions, nil when the capture is disabled
+func NewWriter(cfg config.Capture) (*Writer, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
+		return nil, err
+	}
+
+	w := &Writer{
+		directory:   cfg.Directory,
+		maxFileSize: int64(cfg.MaxFileSizeMB) * 1024 * 1024,
+		maxFiles:    cfg.MaxFiles,
+		queue:       make(chan *Auction, cfg.QueueSize),
+		done:        make(chan struct{}),
+		now:         time.Now,
+	}
+	go w.run()
+	return w, nil
+}
+
+// Sample returns whether an auction of an account capturing the given share of its auctions is captured
+func (w *Writer) Sample(rate float64) bool {
+	return w != nil && rate > 0 && rand.Float64() < rate
+}
+
+// Write queues the auction along with its response, the auction being dropped when the queue is full
+func (w *Writer) Write(auction *Auction, response *openrtb2.BidResponse) {
+	if w == nil || auction == nil {
+		return
+	}
+
+	if response != nil {
+		body, err := jsonutil.Marshal(response)
+		if err != nil {
+			glog.Errorf("Failed to capture the response of auction %s: %v", auction.ID, err)
+			return
+		}
+		auction.Response = body
+	}
+
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.queue <- auction:
+	default:
+		glog.Warningf("Capture queue is full, auction %s dropped", auction.ID)
+	}
+}
+
+// Shutdown writes the queued auctions and closes the current file
+func (w *Writer) Shutdown() {
+	if w == nil {
+		return
+	}
+
+	w.mutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mutex.Unlock()
+	<-w.done
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+	for auction := range w.queue {
+		if err := w.write(auction); err != nil {
+			glog.Errorf("Failed to write captured auction %s: %v", auction.ID, err)
+		}
+	}
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			glog.Errorf("Failed to close capture file: %v", err)
+		}
+	}
+}
+
+func (w *Writer) write(auction *Auction) error {
+	auction.mutex.Lock()
+	line, err := jsonutil.Marshal(auction)
+	auction.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if w.file == nil || (w.size > 0 && w.size+int64(len(line)) > w.maxFileSize) {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+// rotate starts a new file and removes the oldest files beyond the maximum number of files
+func (w *Writer) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			glog.Errorf("Failed to close capture file: %v", err)
+		}
+		w.file = nil
+	}
+
+	name := filepath.Join(w.directory, filePrefix+w.now().UTC().Format(fileTimeLayout)+fileExtension)
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.size = 0
+
+	if w.maxFiles == 0 {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(w.directory, filePrefix+"*"+fileExtension))
+	if err != nil {
+		return err
+	}
+	// the file names sort in the order they were created
+	sort.Strings(files)
+	for len(files) > w.maxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			glog.Errorf("Failed to remove capture file %s: %v", files[0], err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWriterDisabled(t *testing.T) {
+	w, err := NewWriter(config.Capture{Enabled: false, Directory: t.TempDir()})
+	require.NoError(t, err)
+	assert.Nil(t, w)
+
+	assert.False(t, w.Sample(1))
+	assert.NotPanics(t, func() {
+		w.Write(&Auction{ID: "auction"}, nil)
+		w.Shutdown()
+	})
+}
+
+func TestWriterSample(t *testing.T) {
+	w := &Writer{}
+	assert.False(t, w.Sample(0))
+	assert.True(t, w.Sample(1))
+}
+
+func TestWriterWrite(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "capture")
+	w, err := NewWriter(config.Capture{Enabled: true, Directory: directory, MaxFileSizeMB: 1, MaxFiles: 2, QueueSize: 10})
+	require.NoError(t, err)
+
+	w.Write(&Auction{ID: "auction-1", Account: "account", Request: []byte(`{"id":"auction-1"}`), BidderCalls: []BidderCall{}}, &openrtb2.BidResponse{ID: "auction-1"})
+	w.Write(&Auction{ID: "auction-2", Account: "account", Request: []byte(`{"id":"auction-2"}`), BidderCalls: []BidderCall{}}, nil)
+	w.Write(nil, nil)
+	w.Shutdown()
+	w.Write(&Auction{ID: "auction-3"}, nil)
+
+	files, err := filepath.Glob(filepath.Join(directory, "capture-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	file, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer file.Close()
+
+	var auctions []*Auction
+	err = ReadAuctions(file, func(auction *Auction) error {
+		auctions = append(auctions, auction)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, auctions, 2)
+	assert.Equal(t, "auction-1", auctions[0].ID)
+	assert.JSONEq(t, `{"id":"auction-1"}`, string(auctions[0].Response))
+	assert.Equal(t, "auction-2", auctions[1].ID)
+	assert.Nil(t, auctions[1].Response)
+}
+
+func TestWriterRotate(t *testing.T) {
+	directory := t.TempDir()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := &Writer{
+		directory:   directory,
+		maxFileSize: 100,
+		maxFiles:    2,
+		now: func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		},
+	}
+
+	for i := 0; i < 4; i++ {
+		auction := &Auction{ID: strings.Repeat("a", 50), BidderCalls: []BidderCall{}}
+		require.NoError(t, w.write(auction))
+	}
+	require.NoError(t, w.file.Close())
+
+	files, err := filepath.Glob(filepath.Join(directory, "*"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(directory, "capture-20240101T000003.000000000.jsonl"),
+		filepath.Join(directory, "capture-20240101T000004.000000000.jsonl"),
+	}, files)
+}
@@ -0,0 +1,144 @@
+// Command replay runs the auctions of a capture file through the exchange again, the calls to the bidders being served
+// from the captured responses, and prints the differences between the new responses and the captured ones.
+//
+// Usage:
+//
+//	replay [-ignore path,...] capture.jsonl
+//
+// It is run from the root of the repository, like the server, using the same configuration. The replayed auctions are
+// neither captured again nor reported to the analytics modules, and their bids are not cached.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prebid/prebid-server/v3/capture"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/router"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+
+	"github.com/golang/glog"
+	"github.com/spf13/viper"
+)
+
+const configFileName = "pbs"
+const infoDirectory = "./static/bidder-info"
+
+func init() {
+	jsoniter.RegisterExtension(&jsonutil.RawMessageExtension{})
+}
+
+func main() {
+	ignore := flag.String("ignore", strings.Join(capture.DefaultIgnoredPaths, ","), "comma separated paths of the response values not compared, * matching any index or key")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: replay [-ignore path,...] capture.jsonl")
+		os.Exit(2)
+	}
+
+	var ignoredPaths []string
+	if *ignore != "" {
+		ignoredPaths = strings.Split(*ignore, ",")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		glog.Exitf("Configuration could not be loaded or did not pass validation: %v", err)
+	}
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		glog.Exitf("Unable to open the capture file: %v", err)
+	}
+	defer file.Close()
+
+	httpTimeout := time.Duration(cfg.CurrencyConverter.FetchTimeoutMilliseconds) * time.Millisecond
+	staleRatesThreshold := time.Duration(cfg.CurrencyConverter.StaleRatesSeconds) * time.Second
+	currencyConverter := currency.NewRateConverter(&http.Client{}, httpTimeout, cfg.CurrencyConverter.FetchURL, staleRatesThreshold)
+	if err := currencyConverter.Run(); err != nil {
+		glog.Warningf("Unable to fetch the currency rates: %v", err)
+	}
+
+	transport := &capture.ReplayTransport{}
+	r, err := router.NewWithBidderTransport(cfg, currencyConverter, transport)
+	if err != nil {
+		glog.Exitf("Unable to create the router: %v", err)
+	}
+	defer r.Shutdown()
+
+	var auctions, differing int
+	err = capture.ReadAuctions(file, func(auction *capture.Auction) error {
+		auctions++
+		diffs, err := replay(r, transport, auction, ignoredPaths)
+		if err != nil {
+			return fmt.Errorf("auction %s: %v", auction.ID, err)
+		}
+		if len(diffs) > 0 {
+			differing++
+			fmt.Printf("auction %s (account %s):\n", auction.ID, auction.Account)
+			for _, diff := range diffs {
+				fmt.Printf("  %s\n", diff)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		glog.Exitf("Replay failed: %v", err)
+	}
+
+	fmt.Printf("%d auctions replayed, %d with differences\n", auctions, differing)
+	if differing > 0 {
+		r.Shutdown()
+		os.Exit(1)
+	}
+}
+
+func loadConfig() (*config.Configuration, error) {
+	bidderInfoPath, err := filepath.Abs(infoDirectory)
+	if err != nil {
+		return nil, err
+	}
+	bidderInfos, err := config.LoadBidderInfoFromDisk(bidderInfoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	config.SetupViper(v, configFileName, bidderInfos)
+	cfg, err := config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	if err != nil {
+		return nil, err
+	}
+
+	// the replayed auctions must not be captured again nor have any effect outside of the replay: the analytics
+	// modules are disabled, and the Prebid Cache requests fail without being sent as the cache host is not set
+	cfg.Capture.Enabled = false
+	cfg.Analytics = config.Analytics{}
+	cfg.CacheURL.Host = ""
+	return cfg, nil
+}
+
+// replay runs the auction through the auction endpoint and compares its response with the captured one
+func replay(handler http.Handler, transport *capture.ReplayTransport, auction *capture.Auction, ignoredPaths []string) ([]string, error) {
+	transport.Load(auction)
+
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewReader(auction.Request))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		return nil, fmt.Errorf("auction endpoint responded with status %d: %s", recorder.Code, strings.TrimSpace(recorder.Body.String()))
+	}
+	return capture.DiffResponses(auction.Response, recorder.Body.Bytes(), ignoredPaths)
+}
@@ -32,6 +32,19 @@ const (
 	RoundingModeUp        BidRoundingMode = "up"
 )
 
+// AuctionMode sets the price the winning bid of an imp clears at
+type AuctionMode string
+
+const (
+	// AuctionModeFirstPrice clears the winning bid at the price bid
+	AuctionModeFirstPrice AuctionMode = "first_price"
+	// AuctionModeSecondPrice clears the winning bid at the highest of the second bid and the floor, plus the increment
+	AuctionModeSecondPrice AuctionMode = "second_price"
+	// AuctionModeSoftFloor clears the winning bids over the soft floor as in the second price mode, the soft floor
+	// being the min price, and the other ones at the price bid
+	AuctionModeSoftFloor AuctionMode = "soft_floor"
+)
+
 // Account represents a publisher account configuration
 type Account struct {
 	ID                      string                                      `mapstructure:"id" json:"id"`
@@ -54,6 +67,9 @@ type Account struct {
 	Privacy                 AccountPrivacy                              `mapstructure:"privacy" json:"privacy"`
 	PreferredMediaType      openrtb_ext.PreferredMediaType              `mapstructure:"preferredmediatype" json:"preferredmediatype"`
 	TargetingPrefix         string                                      `mapstructure:"targeting_prefix" json:"targeting_prefix"`
+	Capture                 AccountCapture                              `mapstructure:"capture" json:"capture"`
+	RateLimit               AccountRateLimit                            `mapstructure:"rate_limit" json:"rate_limit"`
+	Auction                 AccountAuction                              `mapstructure:"auction" json:"auction"`
 }
 
 // CookieSync represents the account-level defaults for the cookie sync endpoint.
@@ -69,6 +85,76 @@ type AccountCCPA struct {
 	ChannelEnabled AccountChannel `mapstructure:"channel_enabled" json:"channel_enabled"`
 }
 
+// AccountCapture represents the account-specific share of the auctions captured, see the host capture config
+type AccountCapture struct {
+	SampleRate float64 `mapstructure:"sample_rate" json:"sample_rate"`
+}
+
+func (c *AccountCapture) validate(errs []error) []error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf(`account_defaults.capture.sample_rate should be between 0 and 1`))
+	}
+	return errs
+}
+
+// AccountRateLimit caps the auctions run by an account, the auctions over the limit being rejected with a 429 status
+type AccountRateLimit struct {
+	// AuctionsPerSecond is the rate the token bucket of the account is refilled at, 0 meaning no limit
+	AuctionsPerSecond float64 `mapstructure:"auctions_per_second" json:"auctions_per_second"`
+	// Burst is the number of auctions which can be run at once, defaulting to the auctions per second
+	Burst int `mapstructure:"burst" json:"burst"`
+	// Bidders overrides the rate limits of the bidder info for the requests of the account, which then has
+	// its own token bucket for the bidder
+	Bidders map[string]AccountBidderRateLimit `mapstructure:"bidders" json:"bidders"`
+}
+
+// AccountBidderRateLimit caps the requests sent to a bidder for an account
+type AccountBidderRateLimit struct {
+	// RequestsPerSecond is the rate the token bucket is refilled at, 0 meaning no limit
+	RequestsPerSecond float64 `mapstructure:"requests_per_second" json:"requests_per_second"`
+	// Burst is the number of requests which can be sent at once, defaulting to the requests per second
+	Burst int `mapstructure:"burst" json:"burst"`
+}
+
+func (rl *AccountRateLimit) validate(errs []error) []error {
+	if rl.AuctionsPerSecond < 0 || rl.Burst < 0 {
+		errs = append(errs, fmt.Errorf(`account_defaults.rate_limit.auctions_per_second and burst should be >= 0`))
+	}
+	for bidder, limit := range rl.Bidders {
+		if limit.RequestsPerSecond < 0 || limit.Burst < 0 {
+			errs = append(errs, fmt.Errorf(`account_defaults.rate_limit.bidders.%s.requests_per_second and burst should be >= 0`, bidder))
+		}
+	}
+	return errs
+}
+
+// AccountAuction sets the price the winning bids of the account clear at, the price bid being kept in bid.ext.origbidcpm
+type AccountAuction struct {
+	Mode AuctionMode `mapstructure:"mode" json:"mode"`
+	// PriceIncrement is added to the second price, in the currency of the response
+	PriceIncrement float64 `mapstructure:"price_increment" json:"price_increment"`
+	// SoftFloor is the price over which the winning bids clear at the second price in the soft floor mode, in the
+	// currency of the response
+	SoftFloor float64 `mapstructure:"soft_floor" json:"soft_floor"`
+}
+
+func (a *AccountAuction) validate(errs []error) []error {
+	switch a.Mode {
+	case "", AuctionModeFirstPrice:
+	case AuctionModeSecondPrice, AuctionModeSoftFloor:
+		// a zero increment would tie the winning bid with the second one
+		if a.PriceIncrement <= 0 {
+			errs = append(errs, fmt.Errorf(`account_defaults.auction.price_increment should be > 0 with the %s mode`, a.Mode))
+		}
+	default:
+		errs = append(errs, fmt.Errorf(`account_defaults.auction.mode must be one of: %s, %s, %s`, AuctionModeFirstPrice, AuctionModeSecondPrice, AuctionModeSoftFloor))
+	}
+	if a.SoftFloor < 0 {
+		errs = append(errs, fmt.Errorf(`account_defaults.auction.soft_floor should be >= 0`))
+	}
+	return errs
+}
+
 type AccountPriceFloors struct {
 	Enabled                bool              `mapstructure:"enabled" json:"enabled"`
 	EnforceFloorsRate      int               `mapstructure:"enforce_floors_rate" json:"enforce_floors_rate"`
@@ -352,6 +438,37 @@ type AccountPrivacy struct {
 	IPv6Config      IPv6             `mapstructure:"ipv6" json:"ipv6"`
 	IPv4Config      IPv4             `mapstructure:"ipv4" json:"ipv4"`
 	PrivacySandbox  PrivacySandbox   `mapstructure:"privacysandbox" json:"privacysandbox"`
+	USNat           AccountUSNat     `mapstructure:"usnat" json:"usnat"`
+}
+
+// USNatNormalization sets how the GPP US state sections are enforced
+type USNatNormalization string
+
+const (
+	// USNatNormalizationNormalize enforces the state sections as the US National section, their fields being mapped
+	// onto the national ones. The state sections not supported yet deny all the activities they apply to.
+	USNatNormalizationNormalize USNatNormalization = "normalize"
+	// USNatNormalizationNationalOnly enforces only the US National section, the state sections being ignored
+	USNatNormalizationNationalOnly USNatNormalization = "national_only"
+)
+
+// AccountUSNat enforces the opt-outs of the GPP US National and state sections applying to a request, i.e. listed in
+// its GPP SIDs, on the syncUser, transmitUfpd and transmitPreciseGeo activities. The sections are enforced before
+// the allow activities rules.
+type AccountUSNat struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// SkipSIDs are the GPP section IDs not enforced
+	SkipSIDs      []int8             `mapstructure:"skip_sids" json:"skip_sids"`
+	Normalization USNatNormalization `mapstructure:"normalization" json:"normalization"`
+}
+
+func (u *AccountUSNat) validate(errs []error) []error {
+	switch u.Normalization {
+	case "", USNatNormalizationNormalize, USNatNormalizationNationalOnly:
+	default:
+		errs = append(errs, fmt.Errorf(`account_defaults.privacy.usnat.normalization must be one of: %s, %s`, USNatNormalizationNormalize, USNatNormalizationNationalOnly))
+	}
+	return errs
 }
 
 type PrivacySandbox struct {
@@ -1,5 +1,14 @@
 package config
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ActivityChannels are the channels an activity condition can match
+var ActivityChannels = []string{"site", "app", "dooh"}
+
 type AllowActivities struct {
 	SyncUser                 Activity `mapstructure:"syncUser" json:"syncUser"`
 	FetchBids                Activity `mapstructure:"fetchBids" json:"fetchBids"`
@@ -11,6 +20,30 @@ type AllowActivities struct {
 	TransmitTids             Activity `mapstructure:"transmitTid" json:"transmitTid"`
 }
 
+// Validate checks the conditions of the rules of the activities, the errors being prefixed with the given path of
+// the activities config
+func (a *AllowActivities) Validate(path string, errs []error) []error {
+	activities := []struct {
+		name     string
+		activity Activity
+	}{
+		{"syncUser", a.SyncUser},
+		{"fetchBids", a.FetchBids},
+		{"enrichUfpd", a.EnrichUserFPD},
+		{"reportAnalytics", a.ReportAnalytics},
+		{"transmitUfpd", a.TransmitUserFPD},
+		{"transmitPreciseGeo", a.TransmitPreciseGeo},
+		{"transmitUniqueRequestIds", a.TransmitUniqueRequestIds},
+		{"transmitTid", a.TransmitTids},
+	}
+	for _, activity := range activities {
+		for i, rule := range activity.activity.Rules {
+			errs = rule.Condition.validate(fmt.Sprintf("%s.%s.rules[%d].condition", path, activity.name, i), errs)
+		}
+	}
+	return errs
+}
+
 type Activity struct {
 	Default *bool          `mapstructure:"default" json:"default"`
 	Rules   []ActivityRule `mapstructure:"rules" json:"rules"`
@@ -21,7 +54,55 @@ type ActivityRule struct {
 	Allow     bool              `mapstructure:"allow" json:"allow"`
 }
 
+// ActivityCondition restricts a rule to the components and the requests matching all of its fields, a field being
+// ignored when not set. The request fields never match the activities having no bid request, such as the user syncs.
 type ActivityCondition struct {
 	ComponentName []string `mapstructure:"componentName" json:"componentName"`
 	ComponentType []string `mapstructure:"componentType" json:"componentType"`
+	// GPPSID matches any of the regs.gpp_sid section IDs
+	GPPSID []int8 `mapstructure:"gppSid" json:"gppSid"`
+	// GeoCountry matches any of the device.geo.country ISO-3166-1-alpha-3 codes, e.g. USA
+	GeoCountry []string `mapstructure:"geoCountry" json:"geoCountry"`
+	// GeoRegion matches any of the device.geo.region ISO-3166-2 subdivision codes, e.g. CA. Only the subdivision
+	// part of the code is accepted, not the CC-SSS form such as US-CA, the country being matched by GeoCountry.
+	GeoRegion []string `mapstructure:"geoRegion" json:"geoRegion"`
+	// COPPA matches whether regs.coppa is set
+	COPPA *bool `mapstructure:"coppa" json:"coppa"`
+	// GDPR matches whether regs.gdpr is 1 or 0, a request without regs.gdpr matching neither. The GDPR applicability
+	// inferred from the device geo or the gdpr.default_value when regs.gdpr is missing is not matched.
+	GDPR *bool `mapstructure:"gdpr" json:"gdpr"`
+	// Channel matches any of the site, app and dooh channels
+	Channel []string `mapstructure:"channel" json:"channel"`
+	// IDPresent matches whether the request holds a user ID, i.e. user.id, user.buyeruid, user.eids or a device ID
+	IDPresent *bool `mapstructure:"idPresent" json:"idPresent"`
+}
+
+func (c *ActivityCondition) validate(path string, errs []error) []error {
+	for _, country := range c.GeoCountry {
+		if len(country) != 3 || !isAlphanumeric(country) {
+			errs = append(errs, fmt.Errorf("%s.geoCountry must hold ISO-3166-1-alpha-3 country codes. Got %q", path, country))
+		}
+	}
+	for _, region := range c.GeoRegion {
+		if _, subdivision, found := strings.Cut(region, "-"); found {
+			errs = append(errs, fmt.Errorf("%s.geoRegion must hold the subdivision part of the ISO-3166-2 codes, the country being set in geoCountry. Got %q, use %q", path, region, subdivision))
+		} else if len(region) == 0 || len(region) > 3 || !isAlphanumeric(region) {
+			errs = append(errs, fmt.Errorf("%s.geoRegion must hold ISO-3166-2 subdivision codes. Got %q", path, region))
+		}
+	}
+	for _, channel := range c.Channel {
+		if !slices.Contains(ActivityChannels, channel) {
+			errs = append(errs, fmt.Errorf("%s.channel must be one of: site, app, dooh. Got %q", path, channel))
+		}
+	}
+	return errs
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
 }
@@ -46,6 +46,12 @@ type BidderInfo struct {
 	AppSecret  string `yaml:"app_secret" mapstructure:"app_secret"`
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string `yaml:"endpointCompression" mapstructure:"endpointCompression"`
+	// CircuitBreaker stops calling the bidder for a while once too many of its calls failed
+	CircuitBreaker *CircuitBreakerInfo `yaml:"circuitBreaker" mapstructure:"circuitBreaker"`
+	// RequestRetry retries or hedges the calls to the bidder, if it declares it can receive the same call more than once
+	RequestRetry *RequestRetryInfo `yaml:"requestRetry" mapstructure:"requestRetry"`
+	// RateLimit caps the requests sent to the bidder by all the accounts, unless overridden in the account config
+	RateLimit *RateLimitInfo `yaml:"rateLimit" mapstructure:"rateLimit"`
 }
 
 type aliasNillableFields struct {
@@ -102,6 +108,44 @@ type OpenRTBInfo struct {
 	MultiformatSupported *bool  `yaml:"multiformat-supported" mapstructure:"multiformat-supported"`
 }
 
+// CircuitBreakerInfo specifies when the calls to a bidder endpoint host are stopped. The breaker of a host opens once
+// the share of calls failing with an error, a timeout or a 5xx status reaches the error rate over the window. No call
+// is made until the open duration elapses, then a number of trial calls are made, the breaker closing if they all
+// succeed.
+type CircuitBreakerInfo struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// WindowSeconds is the duration the error rate is measured over
+	WindowSeconds int `yaml:"windowSeconds" mapstructure:"windowSeconds"`
+	// MinRequests is the number of calls needed over the window before the breaker can open
+	MinRequests int `yaml:"minRequests" mapstructure:"minRequests"`
+	// ErrorRate is the share of failed calls, between 0 and 1, opening the breaker
+	ErrorRate float64 `yaml:"errorRate" mapstructure:"errorRate"`
+	// OpenSeconds is the duration the breaker stays open before the trial calls
+	OpenSeconds int `yaml:"openSeconds" mapstructure:"openSeconds"`
+	// HalfOpenRequests is the number of trial calls needed to close the breaker
+	HalfOpenRequests int `yaml:"halfOpenRequests" mapstructure:"halfOpenRequests"`
+}
+
+// RequestRetryInfo specifies how the calls to a bidder are sent again, within the time left for the bidder to respond
+type RequestRetryInfo struct {
+	// Idempotent declares the bidder handles the same call received more than once as a single call, required to retry or hedge
+	Idempotent bool `yaml:"idempotent" mapstructure:"idempotent"`
+	// RetryOnError sends a call once more when it fails with a connection error or a 5xx status
+	RetryOnError bool `yaml:"retryOnError" mapstructure:"retryOnError"`
+	// HedgePercentile, when set, sends a second call if the first one has not been responded to after this percentile of
+	// the recent response times of the bidder, the first response winning
+	HedgePercentile int `yaml:"hedgePercentile" mapstructure:"hedgePercentile"`
+}
+
+// RateLimitInfo specifies the token bucket limiting the requests sent to a bidder, the requests over the limit
+// being skipped with a seat non bid
+type RateLimitInfo struct {
+	// RequestsPerSecond is the rate the bucket is refilled at, 0 meaning no limit
+	RequestsPerSecond float64 `yaml:"requestsPerSecond" mapstructure:"requestsPerSecond"`
+	// Burst is the number of requests which can be sent at once, defaulting to the requests per second
+	Burst int `yaml:"burst" mapstructure:"burst"`
+}
+
 // Syncer specifies the user sync settings for a bidder. This struct is shared by the account config,
 // so it needs to have both yaml and mapstructure mappings.
 type Syncer struct {
@@ -328,6 +372,15 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if aliasBidderInfo.Capabilities == nil {
 			aliasBidderInfo.Capabilities = parentBidderInfo.Capabilities
 		}
+		if aliasBidderInfo.CircuitBreaker == nil {
+			aliasBidderInfo.CircuitBreaker = parentBidderInfo.CircuitBreaker
+		}
+		if aliasBidderInfo.RequestRetry == nil {
+			aliasBidderInfo.RequestRetry = parentBidderInfo.RequestRetry
+		}
+		if aliasBidderInfo.RateLimit == nil {
+			aliasBidderInfo.RateLimit = parentBidderInfo.RateLimit
+		}
 		if aliasBidderInfo.Debug == nil {
 			aliasBidderInfo.Debug = parentBidderInfo.Debug
 		}
@@ -483,6 +536,51 @@ func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error
 			return err
 		}
 	}
+	if err := validateCircuitBreaker(bidder.CircuitBreaker, bidderName); err != nil {
+		return err
+	}
+	if err := validateRequestRetry(bidder.RequestRetry, bidderName); err != nil {
+		return err
+	}
+	if err := validateRateLimit(bidder.RateLimit, bidderName); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validateCircuitBreaker(info *CircuitBreakerInfo, bidderName string) error {
+	if info == nil || !info.Enabled {
+		return nil
+	}
+	if info.WindowSeconds < 0 || info.MinRequests < 0 || info.OpenSeconds < 0 || info.HalfOpenRequests < 0 {
+		return fmt.Errorf("circuitBreaker windowSeconds, minRequests, openSeconds and halfOpenRequests must be >= 0 for adapter: %s", bidderName)
+	}
+	if info.ErrorRate < 0 || info.ErrorRate > 1 {
+		return fmt.Errorf("circuitBreaker errorRate must be between 0 and 1 for adapter: %s", bidderName)
+	}
+	return nil
+}
+
+func validateRequestRetry(info *RequestRetryInfo, bidderName string) error {
+	if info == nil {
+		return nil
+	}
+	if info.HedgePercentile < 0 || info.HedgePercentile > 99 {
+		return fmt.Errorf("requestRetry hedgePercentile must be between 0 and 99 for adapter: %s", bidderName)
+	}
+	if !info.Idempotent && (info.RetryOnError || info.HedgePercentile > 0) {
+		return fmt.Errorf("requestRetry requires the adapter to be idempotent for adapter: %s", bidderName)
+	}
+	return nil
+}
+
+func validateRateLimit(info *RateLimitInfo, bidderName string) error {
+	if info == nil {
+		return nil
+	}
+	if info.RequestsPerSecond < 0 || info.Burst < 0 {
+		return fmt.Errorf("rateLimit requestsPerSecond and burst must be >= 0 for adapter: %s", bidderName)
+	}
 	return nil
 }
 
@@ -672,6 +770,15 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 		if configBidderInfo.bidderInfo.OpenRTB != nil {
 			mergedBidderInfo.OpenRTB = configBidderInfo.bidderInfo.OpenRTB
 		}
+		if configBidderInfo.bidderInfo.CircuitBreaker != nil {
+			mergedBidderInfo.CircuitBreaker = configBidderInfo.bidderInfo.CircuitBreaker
+		}
+		if configBidderInfo.bidderInfo.RequestRetry != nil {
+			mergedBidderInfo.RequestRetry = configBidderInfo.bidderInfo.RequestRetry
+		}
+		if configBidderInfo.bidderInfo.RateLimit != nil {
+			mergedBidderInfo.RateLimit = configBidderInfo.bidderInfo.RateLimit
+		}
 
 		mergedBidderInfos[string(normalizedBidderName)] = mergedBidderInfo
 	}
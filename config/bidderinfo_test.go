@@ -1393,6 +1393,129 @@ func TestBidderInfoValidationNegative(t *testing.T) {
 				errors.New("syncer could not be created, invalid format override value: x"),
 			},
 		},
+		{
+			"Circuit breaker invalid error rate",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					CircuitBreaker: &CircuitBreakerInfo{
+						Enabled:   true,
+						ErrorRate: 1.5,
+					},
+				},
+			},
+			[]error{
+				errors.New("circuitBreaker errorRate must be between 0 and 1 for adapter: bidderA"),
+			},
+		},
+		{
+			"Circuit breaker negative window",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					CircuitBreaker: &CircuitBreakerInfo{
+						Enabled:       true,
+						WindowSeconds: -1,
+					},
+				},
+			},
+			[]error{
+				errors.New("circuitBreaker windowSeconds, minRequests, openSeconds and halfOpenRequests must be >= 0 for adapter: bidderA"),
+			},
+		},
+		{
+			"Request retry not idempotent",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					RequestRetry: &RequestRetryInfo{
+						RetryOnError: true,
+					},
+				},
+			},
+			[]error{
+				errors.New("requestRetry requires the adapter to be idempotent for adapter: bidderA"),
+			},
+		},
+		{
+			"Request retry invalid hedge percentile",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					RequestRetry: &RequestRetryInfo{
+						Idempotent:      true,
+						HedgePercentile: 100,
+					},
+				},
+			},
+			[]error{
+				errors.New("requestRetry hedgePercentile must be between 0 and 99 for adapter: bidderA"),
+			},
+		},
+		{
+			"Rate limit negative",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					RateLimit: &RateLimitInfo{
+						RequestsPerSecond: -1,
+					},
+				},
+			},
+			[]error{
+				errors.New("rateLimit requestsPerSecond and burst must be >= 0 for adapter: bidderA"),
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -1873,6 +1996,30 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{OpenRTB: &OpenRTBInfo{Version: "2"}, Syncer: &Syncer{Key: "override"}}}},
 			expectedBidderInfos:    BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2"}, Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Don't override CircuitBreaker",
+			givenFsBidderInfos:     BidderInfos{"a": {CircuitBreaker: &CircuitBreakerInfo{Enabled: true}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {CircuitBreaker: &CircuitBreakerInfo{Enabled: true}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override CircuitBreaker",
+			givenFsBidderInfos:     BidderInfos{"a": {CircuitBreaker: &CircuitBreakerInfo{Enabled: true}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{CircuitBreaker: &CircuitBreakerInfo{Enabled: false}, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {CircuitBreaker: &CircuitBreakerInfo{Enabled: false}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override RequestRetry",
+			givenFsBidderInfos:     BidderInfos{"a": {RequestRetry: &RequestRetryInfo{Idempotent: true, RetryOnError: true}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{RequestRetry: &RequestRetryInfo{Idempotent: false}, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {RequestRetry: &RequestRetryInfo{Idempotent: false}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override RateLimit",
+			givenFsBidderInfos:     BidderInfos{"a": {RateLimit: &RateLimitInfo{RequestsPerSecond: 100}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{RateLimit: &RateLimitInfo{RequestsPerSecond: 10, Burst: 20}, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {RateLimit: &RateLimitInfo{RequestsPerSecond: 10, Burst: 20}, Syncer: &Syncer{Key: "override"}}},
+		},
 		{
 			description:            "Don't override AliasOf",
 			givenFsBidderInfos:     BidderInfos{"a": {AliasOf: "Alias1"}},
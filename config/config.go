@@ -106,6 +106,10 @@ type Configuration struct {
 	Hooks       Hooks       `mapstructure:"hooks"`
 	Validations Validations `mapstructure:"validations"`
 	PriceFloors PriceFloors `mapstructure:"price_floors"`
+	// Capture writes the auctions sampled by the accounts to files, see account_defaults.capture.sample_rate
+	Capture Capture `mapstructure:"capture"`
+	// TrafficShaping learns which imps the bidders bid on and stops sending them the others
+	TrafficShaping TrafficShaping `mapstructure:"traffic_shaping"`
 }
 
 type Admin struct {
@@ -184,9 +188,27 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	}
 
 	errs = cfg.Experiment.validate(errs)
+	if cfg.Hooks.Enabled {
+		errs = cfg.Hooks.AsyncExecution.validate(errs)
+		errs = cfg.Hooks.HostExecutionPlan.validate("hooks.host_execution_plan", errs)
+		errs = cfg.Hooks.DefaultAccountExecutionPlan.validate("hooks.default_account_execution_plan", errs)
+	}
 	errs = cfg.BidderInfos.validate(errs)
+	if cfg.Capture.Enabled {
+		errs = cfg.Capture.validate(errs)
+	}
+	errs = cfg.AccountDefaults.Capture.validate(errs)
+	if cfg.TrafficShaping.Enabled {
+		errs = cfg.TrafficShaping.validate(errs)
+	}
+	errs = cfg.AccountDefaults.RateLimit.validate(errs)
+	errs = cfg.AccountDefaults.Auction.validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv6Config.Validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv4Config.Validate(errs)
+	errs = cfg.AccountDefaults.Privacy.USNat.validate(errs)
+	if cfg.AccountDefaults.Privacy.AllowActivities != nil {
+		errs = cfg.AccountDefaults.Privacy.AllowActivities.Validate("account_defaults.privacy.allowactivities", errs)
+	}
 
 	return errs
 }
@@ -280,6 +302,8 @@ type GDPR struct {
 	// to DefaultValue
 	EEACountries    []string `mapstructure:"eea_countries"`
 	EEACountriesMap map[string]struct{}
+	// VendorListCache persists the fetched vendor lists to a local directory, loaded again at startup
+	VendorListCache GDPRVendorListCache `mapstructure:"vendorlist_cache"`
 }
 
 func (cfg *GDPR) validate(v *viper.Viper, errs []error) []error {
@@ -325,6 +349,14 @@ func (cfg *GDPR) validatePurposes(errs []error) []error {
 	return errs
 }
 
+type GDPRVendorListCache struct {
+	// Dir is the directory the vendor lists are loaded from and saved to, no list being persisted when empty
+	Dir string `mapstructure:"dir"`
+	// FallbackToLatest is whether a vendor list version not loaded is replaced with the latest version known for its
+	// spec version, instead of failing the TCF enforcement
+	FallbackToLatest bool `mapstructure:"fallback_to_latest"`
+}
+
 type GDPRTimeouts struct {
 	InitVendorlistFetch   int `mapstructure:"init_vendorlist_fetches"`
 	ActiveVendorlistFetch int `mapstructure:"active_vendorlist_fetch"`
@@ -719,6 +751,77 @@ type TimeoutNotification struct {
 	FailOnly bool `mapstructure:"fail_only"`
 }
 
+// Capture configures the files the sampled auctions are written to, along with the HTTP calls to the bidders
+type Capture struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Directory the capture files are written to
+	Directory string `mapstructure:"directory"`
+	// MaxFileSizeMB is the size from which a new capture file is started
+	MaxFileSizeMB int `mapstructure:"max_file_size_mb"`
+	// MaxFiles is the number of capture files kept, the oldest ones being removed. Use 0 to keep all the files
+	MaxFiles int `mapstructure:"max_files"`
+	// QueueSize is the number of auctions waiting to be written, auctions are dropped once it is reached
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+func (cfg *Capture) validate(errs []error) []error {
+	if cfg.Directory == "" {
+		errs = append(errs, errors.New("capture.directory must be set when capture is enabled"))
+	}
+	if cfg.MaxFileSizeMB <= 0 {
+		errs = append(errs, fmt.Errorf("capture.max_file_size_mb must be > 0. Got %d", cfg.MaxFileSizeMB))
+	}
+	if cfg.MaxFiles < 0 {
+		errs = append(errs, fmt.Errorf("capture.max_files must be >= 0. Got %d", cfg.MaxFiles))
+	}
+	if cfg.QueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("capture.queue_size must be > 0. Got %d", cfg.QueueSize))
+	}
+	return errs
+}
+
+// TrafficShaping configures the learning of the imps each bidder bids on, grouped by floors schema dimensions,
+// the imps of the groups a bidder does not bid on not being sent to it anymore
+type TrafficShaping struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Dimensions are the floors schema fields the imps are grouped by, e.g. size, country or domain
+	Dimensions []string `mapstructure:"dimensions"`
+	// MinRequests is the number of imps of a group sent to a bidder before the group can be pruned for the bidder
+	MinRequests int `mapstructure:"min_requests"`
+	// MinBidRate is the share of the imps of a group a bidder must bid on for the group to be kept, 0 requiring a single bid
+	MinBidRate float64 `mapstructure:"min_bid_rate"`
+	// ExplorationPercent is the percentage of the pruned imps sent anyway, letting the bidders bid on the group again
+	ExplorationPercent int `mapstructure:"exploration_percent"`
+	// MaxGroupsPerBidder caps the number of imp groups learned for a bidder, the imps of the other groups being sent
+	MaxGroupsPerBidder int `mapstructure:"max_groups_per_bidder"`
+	// RefreshPeriodSeconds is how often the groups to prune are computed and the model is saved
+	RefreshPeriodSeconds int `mapstructure:"refresh_period_seconds"`
+	// File the model is saved to and loaded from at startup, so that restarts do not reset it. Not saved when empty
+	File string `mapstructure:"file"`
+}
+
+func (cfg *TrafficShaping) validate(errs []error) []error {
+	if len(cfg.Dimensions) == 0 {
+		errs = append(errs, errors.New("traffic_shaping.dimensions must be set when traffic shaping is enabled"))
+	}
+	if cfg.MinRequests <= 0 {
+		errs = append(errs, fmt.Errorf("traffic_shaping.min_requests must be > 0. Got %d", cfg.MinRequests))
+	}
+	if cfg.MinBidRate < 0 || cfg.MinBidRate > 1 {
+		errs = append(errs, fmt.Errorf("traffic_shaping.min_bid_rate must be between 0 and 1. Got %f", cfg.MinBidRate))
+	}
+	if cfg.ExplorationPercent < 0 || cfg.ExplorationPercent > 100 {
+		errs = append(errs, fmt.Errorf("traffic_shaping.exploration_percent must be between 0 and 100. Got %d", cfg.ExplorationPercent))
+	}
+	if cfg.MaxGroupsPerBidder <= 0 {
+		errs = append(errs, fmt.Errorf("traffic_shaping.max_groups_per_bidder must be > 0. Got %d", cfg.MaxGroupsPerBidder))
+	}
+	if cfg.RefreshPeriodSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("traffic_shaping.refresh_period_seconds must be > 0. Got %d", cfg.RefreshPeriodSeconds))
+	}
+	return errs
+}
+
 type Validations struct {
 	BannerCreativeMaxSize string `mapstructure:"banner_creative_max_size" json:"banner_creative_max_size"`
 	SecureMarkup          string `mapstructure:"secure_markup" json:"secure_markup"`
@@ -1169,6 +1272,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("gdpr.host_vendor_id", 0)
 	v.SetDefault("gdpr.timeouts_ms.init_vendorlist_fetches", 0)
 	v.SetDefault("gdpr.timeouts_ms.active_vendorlist_fetch", 0)
+	v.SetDefault("gdpr.vendorlist_cache.dir", "")
+	v.SetDefault("gdpr.vendorlist_cache.fallback_to_latest", false)
 	v.SetDefault("gdpr.non_standard_publishers", []string{""})
 	v.SetDefault("gdpr.tcf2.enabled", true)
 	v.SetDefault("gdpr.tcf2.purpose1.enforce_vendors", true)
@@ -1233,6 +1338,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.BindEnv("account_defaults.privacy.dsa.gdpr_only")
 	v.SetDefault("account_defaults.privacy.ipv6.anon_keep_bits", 56)
 	v.SetDefault("account_defaults.privacy.ipv4.anon_keep_bits", 24)
+	v.SetDefault("account_defaults.privacy.usnat.enabled", false)
+	v.SetDefault("account_defaults.privacy.usnat.normalization", USNatNormalizationNormalize)
 
 	//Defaults for Price floor fetcher
 	v.SetDefault("price_floors.fetcher.worker", 20)
@@ -1337,7 +1444,29 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("experiment.adscert.remote.url", "")
 	v.SetDefault("experiment.adscert.remote.signing_timeout_ms", 5)
 
+	v.SetDefault("capture.enabled", false)
+	v.SetDefault("capture.directory", "")
+	v.SetDefault("capture.max_file_size_mb", 100)
+	v.SetDefault("capture.max_files", 10)
+	v.SetDefault("capture.queue_size", 1000)
+	v.SetDefault("traffic_shaping.enabled", false)
+	v.SetDefault("traffic_shaping.dimensions", []string{"mediaType", "size", "country", "deviceType", "domain"})
+	v.SetDefault("traffic_shaping.min_requests", 1000)
+	v.SetDefault("traffic_shaping.min_bid_rate", 0)
+	v.SetDefault("traffic_shaping.exploration_percent", 5)
+	v.SetDefault("traffic_shaping.max_groups_per_bidder", 10000)
+	v.SetDefault("traffic_shaping.refresh_period_seconds", 300)
+	v.SetDefault("traffic_shaping.file", "")
+	v.SetDefault("account_defaults.capture.sample_rate", 0)
+	v.SetDefault("account_defaults.rate_limit.auctions_per_second", 0)
+	v.SetDefault("account_defaults.rate_limit.burst", 0)
+	v.SetDefault("account_defaults.auction.mode", AuctionModeFirstPrice)
+	v.SetDefault("account_defaults.auction.price_increment", 0.01)
+	v.SetDefault("account_defaults.auction.soft_floor", 0)
+
 	v.SetDefault("hooks.enabled", false)
+	v.SetDefault("hooks.async_execution.worker", 10)
+	v.SetDefault("hooks.async_execution.capacity", 1000)
 
 	for bidderName := range bidderInfos {
 		setBidderDefaults(v, strings.ToLower(bidderName))
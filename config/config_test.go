@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"net"
 	"os"
@@ -229,6 +230,8 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "account_defaults.events.enabled", false, cfg.AccountDefaults.Events.Enabled)
 
 	cmpBools(t, "hooks.enabled", false, cfg.Hooks.Enabled)
+	cmpInts(t, "hooks.async_execution.worker", 10, cfg.Hooks.AsyncExecution.Worker)
+	cmpInts(t, "hooks.async_execution.capacity", 1000, cfg.Hooks.AsyncExecution.Capacity)
 	cmpStrings(t, "validations.banner_creative_max_size", "skip", cfg.Validations.BannerCreativeMaxSize)
 	cmpStrings(t, "validations.secure_markup", "skip", cfg.Validations.SecureMarkup)
 	cmpInts(t, "validations.max_creative_width", 0, int(cfg.Validations.MaxCreativeWidth))
@@ -236,6 +239,25 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "account_modules_metrics", false, cfg.Metrics.Disabled.AccountModulesMetrics)
 
 	cmpBools(t, "tmax_adjustments.enabled", false, cfg.TmaxAdjustments.Enabled)
+
+	cmpBools(t, "capture.enabled", false, cfg.Capture.Enabled)
+	cmpInts(t, "capture.max_file_size_mb", 100, cfg.Capture.MaxFileSizeMB)
+	cmpInts(t, "capture.max_files", 10, cfg.Capture.MaxFiles)
+	cmpInts(t, "capture.queue_size", 1000, cfg.Capture.QueueSize)
+	assert.Zero(t, cfg.AccountDefaults.Capture.SampleRate, "account_defaults.capture.sample_rate")
+	cmpBools(t, "traffic_shaping.enabled", false, cfg.TrafficShaping.Enabled)
+	assert.Equal(t, []string{"mediaType", "size", "country", "deviceType", "domain"}, cfg.TrafficShaping.Dimensions, "traffic_shaping.dimensions")
+	cmpInts(t, "traffic_shaping.min_requests", 1000, cfg.TrafficShaping.MinRequests)
+	cmpInts(t, "traffic_shaping.exploration_percent", 5, cfg.TrafficShaping.ExplorationPercent)
+	cmpInts(t, "traffic_shaping.max_groups_per_bidder", 10000, cfg.TrafficShaping.MaxGroupsPerBidder)
+	cmpInts(t, "traffic_shaping.refresh_period_seconds", 300, cfg.TrafficShaping.RefreshPeriodSeconds)
+	assert.Zero(t, cfg.AccountDefaults.RateLimit.AuctionsPerSecond, "account_defaults.rate_limit.auctions_per_second")
+	cmpInts(t, "account_defaults.rate_limit.burst", 0, cfg.AccountDefaults.RateLimit.Burst)
+	assert.Equal(t, AuctionModeFirstPrice, cfg.AccountDefaults.Auction.Mode, "account_defaults.auction.mode")
+	assert.Equal(t, 0.01, cfg.AccountDefaults.Auction.PriceIncrement, "account_defaults.auction.price_increment")
+	assert.Zero(t, cfg.AccountDefaults.Auction.SoftFloor, "account_defaults.auction.soft_floor")
+	cmpBools(t, "account_defaults.privacy.usnat.enabled", false, cfg.AccountDefaults.Privacy.USNat.Enabled)
+	assert.Equal(t, USNatNormalizationNormalize, cfg.AccountDefaults.Privacy.USNat.Normalization, "account_defaults.privacy.usnat.normalization")
 	cmpUnsignedInts(t, "tmax_adjustments.bidder_response_duration_min_ms", 0, cfg.TmaxAdjustments.BidderResponseDurationMin)
 	cmpUnsignedInts(t, "tmax_adjustments.bidder_network_latency_buffer_ms", 0, cfg.TmaxAdjustments.BidderNetworkLatencyBuffer)
 	cmpUnsignedInts(t, "tmax_adjustments.pbs_response_preparation_duration_ms", 0, cfg.TmaxAdjustments.PBSResponsePreparationDuration)
@@ -386,6 +408,9 @@ gdpr:
   default_value: "1"
   non_standard_publishers: ["pub1", "pub2"]
   eea_countries: ["eea1", "eea2"]
+  vendorlist_cache:
+    dir: "/var/lib/prebid/gvl"
+    fallback_to_latest: true
   tcf2:
     purpose1:
       enforce_vendors: false
@@ -768,6 +793,9 @@ func TestFullConfig(t *testing.T) {
 	assert.Equal(t, []string{"eea1", "eea2"}, cfg.GDPR.EEACountries, "gdpr.eea_countries")
 	assert.Equal(t, map[string]struct{}{"eea1": {}, "eea2": {}}, cfg.GDPR.EEACountriesMap, "gdpr.eea_countries Hash Map")
 
+	cmpStrings(t, "gdpr.vendorlist_cache.dir", "/var/lib/prebid/gvl", cfg.GDPR.VendorListCache.Dir)
+	cmpBools(t, "gdpr.vendorlist_cache.fallback_to_latest", true, cfg.GDPR.VendorListCache.FallbackToLatest)
+
 	cmpBools(t, "ccpa.enforce", true, cfg.CCPA.Enforce)
 	cmpBools(t, "lmt.enforce", true, cfg.LMT.Enforce)
 
@@ -1436,6 +1464,161 @@ func TestValidateDebug(t *testing.T) {
 	assert.NotNil(t, err, "cfg.debug.timeout_notification.sampling_rate should not be allowed to be greater than 1.0, but it was allowed")
 }
 
+func TestValidateHookAsyncExecution(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Hooks.Enabled = true
+	cfg.Hooks.AsyncExecution.Worker = 0
+	cfg.Hooks.AsyncExecution.Capacity = -1
+
+	errs := cfg.validate(v)
+	assert.ElementsMatch(t, []error{
+		errors.New("hooks.async_execution.worker must be > 0. Got 0"),
+		errors.New("hooks.async_execution.capacity must be >= 0. Got -1"),
+	}, errs)
+}
+
+func TestValidateHookExecutionPlans(t *testing.T) {
+	plan := HookExecutionPlan{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"endpoints": {"/openrtb2/auction": {"stages": {
+		"exitpoint": {"groups": [{"timeout": 5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo", "async": true}, {"module_code": "foobar", "hook_impl_code": "bar"}]}]},
+		"auction_response": {"groups": [{"timeout": 5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "baz", "async": true}]}]}
+	}}}}`), &plan))
+
+	cfg, v := newDefaultConfig(t)
+	cfg.Hooks.Enabled = true
+	cfg.Hooks.HostExecutionPlan = plan
+	cfg.Hooks.DefaultAccountExecutionPlan = plan
+
+	errs := cfg.validate(v)
+	assert.ElementsMatch(t, []error{
+		errors.New("hooks.host_execution_plan: hook foobar foo of the /openrtb2/auction endpoint cannot run async at the exitpoint stage"),
+		errors.New("hooks.default_account_execution_plan: hook foobar foo of the /openrtb2/auction endpoint cannot run async at the exitpoint stage"),
+	}, errs)
+}
+
+func TestValidateCapture(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Capture.Enabled = true
+	cfg.Capture.MaxFileSizeMB = 0
+	cfg.Capture.MaxFiles = -1
+	cfg.Capture.QueueSize = 0
+	cfg.AccountDefaults.Capture.SampleRate = 1.5
+
+	errs := cfg.validate(v)
+	assert.ElementsMatch(t, []error{
+		errors.New("capture.directory must be set when capture is enabled"),
+		errors.New("capture.max_file_size_mb must be > 0. Got 0"),
+		errors.New("capture.max_files must be >= 0. Got -1"),
+		errors.New("capture.queue_size must be > 0. Got 0"),
+		errors.New("account_defaults.capture.sample_rate should be between 0 and 1"),
+	}, errs)
+}
+
+func TestValidateTrafficShaping(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.TrafficShaping.Enabled = true
+	cfg.TrafficShaping.Dimensions = nil
+	cfg.TrafficShaping.MinRequests = 0
+	cfg.TrafficShaping.MinBidRate = 2
+	cfg.TrafficShaping.ExplorationPercent = 101
+	cfg.TrafficShaping.MaxGroupsPerBidder = 0
+	cfg.TrafficShaping.RefreshPeriodSeconds = 0
+
+	errs := cfg.validate(v)
+	assert.ElementsMatch(t, []error{
+		errors.New("traffic_shaping.dimensions must be set when traffic shaping is enabled"),
+		errors.New("traffic_shaping.min_requests must be > 0. Got 0"),
+		errors.New("traffic_shaping.min_bid_rate must be between 0 and 1. Got 2.000000"),
+		errors.New("traffic_shaping.exploration_percent must be between 0 and 100. Got 101"),
+		errors.New("traffic_shaping.max_groups_per_bidder must be > 0. Got 0"),
+		errors.New("traffic_shaping.refresh_period_seconds must be > 0. Got 0"),
+	}, errs)
+}
+
+func TestValidateAccountRateLimit(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AccountDefaults.RateLimit.AuctionsPerSecond = -1
+	cfg.AccountDefaults.RateLimit.Bidders = map[string]AccountBidderRateLimit{
+		"appnexus": {RequestsPerSecond: 10},
+		"rubicon":  {RequestsPerSecond: 10, Burst: -1},
+	}
+
+	errs := cfg.validate(v)
+	assert.ElementsMatch(t, []error{
+		errors.New("account_defaults.rate_limit.auctions_per_second and burst should be >= 0"),
+		errors.New("account_defaults.rate_limit.bidders.rubicon.requests_per_second and burst should be >= 0"),
+	}, errs)
+}
+
+func TestValidateAccountAuction(t *testing.T) {
+	testCases := []struct {
+		description    string
+		auction        AccountAuction
+		expectedErrors []error
+	}{
+		{
+			description: "first-price",
+			auction:     AccountAuction{Mode: AuctionModeFirstPrice},
+		},
+		{
+			description: "second-price",
+			auction:     AccountAuction{Mode: AuctionModeSecondPrice, PriceIncrement: 0.01},
+		},
+		{
+			description:    "second-price-without-increment",
+			auction:        AccountAuction{Mode: AuctionModeSecondPrice},
+			expectedErrors: []error{errors.New("account_defaults.auction.price_increment should be > 0 with the second_price mode")},
+		},
+		{
+			description:    "soft-floor-negative",
+			auction:        AccountAuction{Mode: AuctionModeSoftFloor, PriceIncrement: 0.01, SoftFloor: -1},
+			expectedErrors: []error{errors.New("account_defaults.auction.soft_floor should be >= 0")},
+		},
+		{
+			description:    "unknown-mode",
+			auction:        AccountAuction{Mode: "third_price"},
+			expectedErrors: []error{errors.New("account_defaults.auction.mode must be one of: first_price, second_price, soft_floor")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg, v := newDefaultConfig(t)
+			cfg.AccountDefaults.Auction = test.auction
+
+			errs := cfg.validate(v)
+			assert.ElementsMatch(t, test.expectedErrors, errs)
+		})
+	}
+}
+
+func TestValidateAccountUSNat(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AccountDefaults.Privacy.USNat.Normalization = "states_only"
+
+	errs := cfg.validate(v)
+	assert.ElementsMatch(t, []error{errors.New("account_defaults.privacy.usnat.normalization must be one of: normalize, national_only")}, errs)
+}
+
+func TestValidateAccountActivities(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AccountDefaults.Privacy.AllowActivities = &AllowActivities{
+		TransmitPreciseGeo: Activity{
+			Rules: []ActivityRule{
+				{Condition: ActivityCondition{GeoCountry: []string{"USA"}, GeoRegion: []string{"CA", "VA"}, Channel: []string{"site", "app"}}},
+				{Condition: ActivityCondition{GeoCountry: []string{"US"}, GeoRegion: []string{"US-CA"}, Channel: []string{"tv"}}},
+			},
+		},
+	}
+
+	errs := cfg.validate(v)
+	assert.ElementsMatch(t, []error{
+		errors.New(`account_defaults.privacy.allowactivities.transmitPreciseGeo.rules[1].condition.geoCountry must hold ISO-3166-1-alpha-3 country codes. Got "US"`),
+		errors.New(`account_defaults.privacy.allowactivities.transmitPreciseGeo.rules[1].condition.geoRegion must hold the subdivision part of the ISO-3166-2 codes, the country being set in geoCountry. Got "US-CA", use "CA"`),
+		errors.New(`account_defaults.privacy.allowactivities.transmitPreciseGeo.rules[1].condition.channel must be one of: site, app, dooh. Got "tv"`),
+	}, errs)
+}
+
 func TestValidateAccountsConfigRestrictions(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Accounts.Files.Enabled = true
@@ -1,5 +1,7 @@
 package config
 
+import "fmt"
+
 type Hooks struct {
 	Enabled bool    `mapstructure:"enabled"`
 	Modules Modules `mapstructure:"modules"`
@@ -7,6 +9,43 @@ type Hooks struct {
 	HostExecutionPlan HookExecutionPlan `mapstructure:"host_execution_plan"`
 	// DefaultAccountExecutionPlan can be replaced by the account-specific hook execution plan
 	DefaultAccountExecutionPlan HookExecutionPlan `mapstructure:"default_account_execution_plan"`
+	// AsyncExecution configures the worker pool running the hooks marked async in the execution plans
+	AsyncExecution HookAsyncExecution `mapstructure:"async_execution"`
+}
+
+type HookAsyncExecution struct {
+	// Worker is the number of hooks run concurrently
+	Worker int `mapstructure:"worker"`
+	// Capacity is the number of hooks waiting for a worker, hooks are dropped once it is reached
+	Capacity int `mapstructure:"capacity"`
+}
+
+func (cfg *HookAsyncExecution) validate(errs []error) []error {
+	if cfg.Worker <= 0 {
+		errs = append(errs, fmt.Errorf("hooks.async_execution.worker must be > 0. Got %d", cfg.Worker))
+	}
+	if cfg.Capacity < 0 {
+		errs = append(errs, fmt.Errorf("hooks.async_execution.capacity must be >= 0. Got %d", cfg.Capacity))
+	}
+	return errs
+}
+
+// exitpointStage is the stage hooks cannot run asynchronously at, its payload holds the response writer
+// which is not usable once the response is sent
+const exitpointStage = "exitpoint"
+
+// validate rejects the hooks marked async at a stage they cannot run asynchronously at
+func (plan *HookExecutionPlan) validate(name string, errs []error) []error {
+	for endpoint, endpointPlan := range plan.Endpoints {
+		for _, group := range endpointPlan.Stages[exitpointStage].Groups {
+			for _, hook := range group.HookSequence {
+				if hook.Async {
+					errs = append(errs, fmt.Errorf("%s: hook %s %s of the %s endpoint cannot run async at the %s stage", name, hook.ModuleCode, hook.HookImplCode, endpoint, exitpointStage))
+				}
+			}
+		}
+	}
+	return errs
 }
 
 // Modules mapping provides module specific configuration, format: map[vendor_name]map[module_name]interface{}
@@ -30,5 +69,8 @@ type HookExecutionGroup struct {
 		ModuleCode string `mapstructure:"module_code" json:"module_code"`
 		// HookImplCode is an arbitrary value, used to identify hook when sending metrics, debug information, etc.
 		HookImplCode string `mapstructure:"hook_impl_code" json:"hook_impl_code"`
+		// Async marks an observe-only hook run after the stage proceeds with a read-only copy of the payload.
+		// Its mutations and rejections are ignored, its outcome is only reported through metrics and analytics.
+		Async bool `mapstructure:"async" json:"async"`
 	} `mapstructure:"hook_sequence" json:"hook_sequence"`
 }
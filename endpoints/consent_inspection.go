@@ -0,0 +1,246 @@
+package endpoints
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	accountService "github.com/prebid/prebid-server/v3/account"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacy/usnat"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/stringutil"
+)
+
+// inspectedActivities are the activities the decisions are returned for
+var inspectedActivities = []privacy.Activity{
+	privacy.ActivitySyncUser,
+	privacy.ActivityFetchBids,
+	privacy.ActivityEnrichUserFPD,
+	privacy.ActivityReportAnalytics,
+	privacy.ActivityTransmitUserFPD,
+	privacy.ActivityTransmitPreciseGeo,
+	privacy.ActivityTransmitUniqueRequestIDs,
+	privacy.ActivityTransmitTIDs,
+}
+
+// consentInspection holds the decoded consent strings and the resulting decisions for each bidder
+type consentInspection struct {
+	Account string                             `json:"account"`
+	TCF     *consentInspectionTCF              `json:"tcf,omitempty"`
+	GPP     *consentInspectionGPP              `json:"gpp,omitempty"`
+	Bidders map[string]consentInspectionBidder `json:"bidders"`
+}
+
+type consentInspectionTCF struct {
+	Consent    *gdpr.DecodedConsent    `json:"consent,omitempty"`
+	VendorList *gdpr.ConsentVendorList `json:"vendorList,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+}
+
+type consentInspectionGPP struct {
+	SIDs     []int8                        `json:"sids"`
+	Sections []consentInspectionGPPSection `json:"sections"`
+	Errors   []string                      `json:"errors,omitempty"`
+}
+
+type consentInspectionGPPSection struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+	// USNat holds the opt-outs of a US section, normalized onto the fields of the US National section
+	USNat *usnat.Consent `json:"usnat,omitempty"`
+}
+
+type consentInspectionBidder struct {
+	GVLID      uint16                               `json:"gvlId,omitempty"`
+	GDPR       *consentInspectionGDPR               `json:"gdpr,omitempty"`
+	Activities map[string]consentInspectionActivity `json:"activities"`
+}
+
+// consentInspectionGDPR are the TCF decisions for a bidder
+type consentInspectionGDPR struct {
+	// Sync is whether the user can be synced, purpose 1
+	Sync bool `json:"sync"`
+	// BidRequest is whether the bidder can be called, purpose 2
+	BidRequest bool `json:"bidRequest"`
+	// PassID is whether the user IDs can be sent to the bidder, any of the purposes 2 to 10
+	PassID bool `json:"passId"`
+	// PassGeo is whether the precise geolocation can be sent to the bidder, special feature 1
+	PassGeo bool `json:"passGeo"`
+}
+
+type consentInspectionActivity struct {
+	Allowed bool   `json:"allowed"`
+	Rule    string `json:"rule"`
+}
+
+// NewConsentInspectionEndpoint decodes the TCF v2 and GPP consent strings of the gdpr_consent and gpp query params,
+// and returns the resulting decisions for each bidder with the config of the account param. The bidders param
+// restricts the decisions to a comma separated list of bidders, and the gpp_sid param defaults to the sections of
+// the GPP string.
+func NewConsentInspectionEndpoint(cfg *config.Configuration, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, vendorListFetcher gdpr.VendorListFetcher, accountsFetcher stored_requests.AccountFetcher, activeBidders map[string]openrtb_ext.BidderName, metricsEngine metrics.MetricsEngine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		query := r.URL.Query()
+
+		bidders, err := inspectedBidders(query.Get("bidders"), activeBidders)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		accountID := query.Get("account")
+		if accountID == "" {
+			accountID = metrics.PublisherUnknown
+		}
+		account, errs := accountService.GetAccount(ctx, cfg, accountsFetcher, accountID, metricsEngine)
+		if len(errs) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(errs[0].Error()))
+			return
+		}
+
+		gppSID, err := stringutil.StrToInt8Slice(query.Get("gpp_sid"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid gpp_sid: " + err.Error()))
+			return
+		}
+
+		inspection := consentInspection{
+			Account: accountID,
+			Bidders: make(map[string]consentInspectionBidder, len(bidders)),
+		}
+
+		var permissions gdpr.Permissions
+		if consent := query.Get("gdpr_consent"); consent != "" {
+			inspection.TCF = inspectTCF(ctx, consent, vendorListFetcher, metricsEngine)
+			if inspection.TCF.Error == "" {
+				permissions = gdprPermsBuilder(tcf2CfgBuilder(cfg.GDPR.TCF2, account.GDPR), gdpr.RequestInfo{
+					Consent:     consent,
+					GDPRSignal:  gdpr.SignalYes,
+					PublisherID: accountID,
+				})
+			}
+		}
+
+		gpp := query.Get("gpp")
+		if gpp != "" {
+			inspection.GPP = inspectGPP(gpp, gppSID)
+			gppSID = inspection.GPP.SIDs
+		}
+
+		activityControl := privacy.NewActivityControl(&account.Privacy)
+		activityRequest := privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: gppSID, GPP: gpp})
+		for _, bidder := range bidders {
+			inspection.Bidders[bidder.String()] = inspectBidder(ctx, bidder, cfg.BidderInfos, permissions, activityControl, activityRequest)
+		}
+
+		jsonOutput, err := jsonutil.Marshal(inspection)
+		if err != nil {
+			glog.Errorf("/consent/inspect Critical error when trying to marshal the consent inspection: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
+
+// inspectedBidders returns the bidders listed in the param, or all the active bidders when empty
+func inspectedBidders(param string, activeBidders map[string]openrtb_ext.BidderName) ([]openrtb_ext.BidderName, error) {
+	var bidders []openrtb_ext.BidderName
+	if param == "" {
+		for _, bidder := range activeBidders {
+			bidders = append(bidders, bidder)
+		}
+	} else {
+		for _, name := range strings.Split(param, ",") {
+			bidder, ok := openrtb_ext.NormalizeBidderName(strings.TrimSpace(name))
+			if _, active := activeBidders[bidder.String()]; !ok || !active {
+				return nil, fmt.Errorf("unknown bidder: %s", name)
+			}
+			bidders = append(bidders, bidder)
+		}
+	}
+	sort.Slice(bidders, func(i, j int) bool {
+		return bidders[i] < bidders[j]
+	})
+	return bidders, nil
+}
+
+func inspectTCF(ctx context.Context, consent string, vendorListFetcher gdpr.VendorListFetcher, metricsEngine metrics.MetricsEngine) *consentInspectionTCF {
+	decoded, err := gdpr.DecodeConsent(consent)
+	if err != nil {
+		return &consentInspectionTCF{Error: err.Error()}
+	}
+	vendorList := gdpr.FetchConsentVendorList(ctx, vendorListFetcher, decoded, metricsEngine)
+	return &consentInspectionTCF{Consent: &decoded, VendorList: &vendorList}
+}
+
+func inspectGPP(gpp string, gppSID []int8) *consentInspectionGPP {
+	parsed, errs := gpplib.Parse(gpp)
+	inspection := &consentInspectionGPP{
+		SIDs:     gppSID,
+		Sections: make([]consentInspectionGPPSection, 0, len(parsed.Sections)),
+	}
+	for _, err := range errs {
+		inspection.Errors = append(inspection.Errors, err.Error())
+	}
+
+	for _, section := range parsed.Sections {
+		sid := section.GetID()
+		decoded := consentInspectionGPPSection{
+			ID:    int(sid),
+			Name:  gppConstants.SectionNamesByID[int(sid)],
+			Value: section.GetValue(),
+		}
+		if consent, ok := usnat.Normalize(section); ok {
+			decoded.USNat = &consent
+		}
+		inspection.Sections = append(inspection.Sections, decoded)
+
+		if len(gppSID) == 0 {
+			inspection.SIDs = append(inspection.SIDs, int8(sid))
+		}
+	}
+	return inspection
+}
+
+func inspectBidder(ctx context.Context, bidder openrtb_ext.BidderName, bidderInfos config.BidderInfos, permissions gdpr.Permissions, activityControl privacy.ActivityControl, activityRequest privacy.ActivityRequest) consentInspectionBidder {
+	inspection := consentInspectionBidder{
+		GVLID:      bidderInfos[bidder.String()].GVLVendorID,
+		Activities: make(map[string]consentInspectionActivity, len(inspectedActivities)),
+	}
+
+	if permissions != nil {
+		sync, _ := permissions.BidderSyncAllowed(ctx, bidder)
+		auction := permissions.AuctionActivitiesAllowed(ctx, bidder, bidder)
+		inspection.GDPR = &consentInspectionGDPR{
+			Sync:       sync,
+			BidRequest: auction.AllowBidRequest,
+			PassID:     auction.PassID,
+			PassGeo:    auction.PassGeo,
+		}
+	}
+
+	component := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidder.String()}
+	for _, activity := range inspectedActivities {
+		allowed, rule := activityControl.Evaluate(activity, component, activityRequest)
+		inspection.Activities[activity.String()] = consentInspectionActivity{Allowed: allowed, Rule: rule}
+	}
+	return inspection
+}
@@ -0,0 +1,176 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/go-gdpr/vendorlist"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsentInspectionEndpoint(t *testing.T) {
+	cfg := &config.Configuration{
+		BidderInfos: config.BidderInfos{
+			"appnexus": config.BidderInfo{GVLVendorID: 32},
+			"rubicon":  config.BidderInfo{GVLVendorID: 52},
+		},
+	}
+	activeBidders := map[string]openrtb_ext.BidderName{
+		"appnexus": openrtb_ext.BidderAppnexus,
+		"rubicon":  openrtb_ext.BidderRubicon,
+	}
+	accountsFetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"valid_acct": json.RawMessage(`{"privacy":{"allowactivities":{"syncUser":{"rules":[{"allow":false,"condition":{"componentName":["appnexus"]}}]}}}}`),
+	}}
+	permissionsBuilder := fakePermissionsBuilder{permissions: &fakePermissions{}}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{})}.Builder
+	vendorListFetcher := func(ctx context.Context, specVersion, listVersion uint16, me metrics.MetricsEngine) (vendorlist.VendorList, error) {
+		return nil, errors.New("vendor list not loaded")
+	}
+
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "tcf_gpp_and_account",
+			query:          "?account=valid_acct&bidders=appnexus&gdpr_consent=COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA&gpp=DBABLA~BVVaAAAAAWA.QA",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"account": "valid_acct",
+				"tcf": {
+					"consent": {
+						"version": 2,
+						"tcfPolicyVersion": 2,
+						"vendorListVersion": 34,
+						"specVersion": 2,
+						"cmpId": 431,
+						"cmpVersion": 0,
+						"consentScreen": 0,
+						"consentLanguage": "EN",
+						"created": "2020-05-12T18:47:24.2Z",
+						"lastUpdated": "2020-05-12T18:47:24.2Z",
+						"purposeOneTreatment": false,
+						"purposeConsents": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10],
+						"purposeLegitimateInterests": [2, 3, 4, 5, 6, 7, 8, 9, 10],
+						"specialFeatureOptIns": [1],
+						"vendorConsents": [2, 6, 8],
+						"vendorLegitimateInterests": [2, 6, 8]
+					},
+					"vendorList": {"specVersion": 2, "version": 34, "loaded": false, "error": "vendor list not loaded"}
+				},
+				"gpp": {
+					"sids": [7],
+					"sections": [{
+						"id": 7,
+						"name": "uspnat",
+						"value": "BVVaAAAAAWA.QA",
+						"usnat": {
+							"saleOptOut": true,
+							"sharingOptOut": false,
+							"targetedAdvertisingOptOut": false,
+							"sensitiveDataOptOut": false,
+							"preciseGeolocationOptOut": false,
+							"knownChild": false,
+							"serviceProviderMode": false,
+							"gpc": false
+						}
+					}]
+				},
+				"bidders": {
+					"appnexus": {
+						"gvlId": 32,
+						"gdpr": {"sync": true, "bidRequest": true, "passId": false, "passGeo": false},
+						"activities": {
+							"syncUser": {"allowed": false, "rule": "rules[0]"},
+							"fetchBids": {"allowed": true, "rule": "default"},
+							"enrichUfpd": {"allowed": true, "rule": "default"},
+							"reportAnalytics": {"allowed": true, "rule": "default"},
+							"transmitUfpd": {"allowed": true, "rule": "default"},
+							"transmitPreciseGeo": {"allowed": true, "rule": "default"},
+							"transmitUniqueRequestIds": {"allowed": true, "rule": "default"},
+							"transmitTid": {"allowed": true, "rule": "default"}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name:           "malformed_tcf",
+			query:          "?bidders=rubicon&gdpr_consent=malformed",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"account": "unknown",
+				"tcf": {"error": "malformed consent string malformed: illegal base64 data at input byte 8"},
+				"bidders": {
+					"rubicon": {
+						"gvlId": 52,
+						"activities": {
+							"syncUser": {"allowed": true, "rule": "default"},
+							"fetchBids": {"allowed": true, "rule": "default"},
+							"enrichUfpd": {"allowed": true, "rule": "default"},
+							"reportAnalytics": {"allowed": true, "rule": "default"},
+							"transmitUfpd": {"allowed": true, "rule": "default"},
+							"transmitPreciseGeo": {"allowed": true, "rule": "default"},
+							"transmitUniqueRequestIds": {"allowed": true, "rule": "default"},
+							"transmitTid": {"allowed": true, "rule": "default"}
+						}
+					}
+				}
+			}`,
+		},
+		{
+			name:           "unknown_bidder",
+			query:          "?bidders=appnexus,unknown",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed_gpp_sid",
+			query:          "?gpp_sid=malformed",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			endpoint := NewConsentInspectionEndpoint(cfg, permissionsBuilder, tcf2ConfigBuilder, vendorListFetcher, accountsFetcher, activeBidders, &metricsConf.NilMetricsEngine{})
+			request := httptest.NewRequest("GET", "/consent/inspect"+test.query, nil)
+			response := httptest.NewRecorder()
+			endpoint(response, request)
+
+			assert.Equal(t, test.expectedStatus, response.Code)
+			if len(test.expectedBody) > 0 {
+				assert.JSONEq(t, test.expectedBody, response.Body.String())
+			}
+		})
+	}
+}
+
+func TestInspectedBidders(t *testing.T) {
+	activeBidders := map[string]openrtb_ext.BidderName{
+		"appnexus":        openrtb_ext.BidderAppnexus,
+		"audienceNetwork": openrtb_ext.BidderAudienceNetwork,
+	}
+
+	bidders, err := inspectedBidders("", activeBidders)
+	assert.NoError(t, err)
+	assert.Equal(t, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus, openrtb_ext.BidderAudienceNetwork}, bidders)
+
+	bidders, err = inspectedBidders("AudienceNetwork", activeBidders)
+	assert.NoError(t, err)
+	assert.Equal(t, []openrtb_ext.BidderName{openrtb_ext.BidderAudienceNetwork}, bidders)
+
+	_, err = inspectedBidders("rubicon", activeBidders)
+	assert.EqualError(t, err, "unknown bidder: rubicon")
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	accountService "github.com/prebid/prebid-server/v3/account"
 	"github.com/prebid/prebid-server/v3/amp"
 	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/capture"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/exchange"
@@ -38,6 +40,7 @@ import (
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ratelimit"
 	"github.com/prebid/prebid-server/v3/version"
 )
 
@@ -70,7 +73,10 @@ func NewAmpEndpoint(
 	bidderMap map[string]openrtb_ext.BidderName,
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	hookAsyncPool *hookexecution.AsyncPool,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	captureWriter *capture.Writer,
+	accountRateLimiter *ratelimit.Limiter,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
@@ -103,8 +109,11 @@ func NewAmpEndpoint(
 		ipValidator,
 		storedRespFetcher,
 		hookExecutionPlanBuilder,
+		hookAsyncPool,
 		tmaxAdjustments,
 		openrtb_ext.NormalizeBidderName,
+		captureWriter,
+		accountRateLimiter,
 	}).AmpAuction), nil
 
 }
@@ -118,7 +127,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	// to compute the auction timeout.
 	start := time.Now()
 
-	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAmp, deps.metricsEngine)
+	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAmp, deps.metricsEngine, deps.hookAsyncPool)
 
 	ao := analytics.AmpObject{
 		Status:    http.StatusOK,
@@ -201,6 +210,11 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	labels.PubID = getAccountID(reqWrapper.Site.Publisher)
 	// Look up account now that we have resolved the pubID value
 	account, acctIDErrs := accountService.GetAccount(ctx, deps.cfg, deps.accounts, labels.PubID, deps.metricsEngine)
+	if len(acctIDErrs) == 0 {
+		if err := deps.checkAccountRateLimit(account); err != nil {
+			acctIDErrs = []error{err}
+		}
+	}
 	if len(acctIDErrs) > 0 {
 		// best attempt to rebuild the request for analytics. we're already in an error state, so ignoring a
 		// potential error from this call
@@ -221,6 +235,11 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 				metricsStatus = metrics.RequestStatusAccountConfigErr
 				break
 			}
+			if errCode == errortypes.AccountRateLimitedErrorCode {
+				httpStatus = http.StatusTooManyRequests
+				metricsStatus = metrics.RequestStatusRateLimited
+				break
+			}
 		}
 		w.WriteHeader(httpStatus)
 		labels.RequestStatus = metricsStatus
@@ -258,6 +277,8 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	secGPC := r.Header.Get("Sec-GPC")
 
+	capturedAuction := deps.captureAuction(reqWrapper, account)
+
 	auctionRequest := &exchange.AuctionRequest{
 		BidRequestWrapper:          reqWrapper,
 		Account:                    *account,
@@ -275,6 +296,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		TCF2Config:                 tcf2Config,
 		Activities:                 activityControl,
 		TmaxAdjustments:            deps.tmaxAdjustments,
+		Capture:                    capturedAuction,
 	}
 
 	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
@@ -316,6 +338,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	}
 
 	labels, ao = sendAmpResponse(w, hookExecutor, auctionResponse, reqWrapper, account, labels, ao, errL)
+	deps.captureWriter.Write(capturedAuction, response)
 }
 
 func rejectAmpRequest(
@@ -477,7 +500,7 @@ func getExtBidResponse(
 		}
 
 		stageOutcomes := hookExecutor.GetOutcomes()
-		ao.HookExecutionOutcome = stageOutcomes
+		ao.HookExecutionOutcome = slices.Concat(stageOutcomes, hookExecutor.GetAsyncOutcomes())
 		modules, warns, err := hookexecution.GetModulesJSON(stageOutcomes, reqWrapper.BidRequest, account)
 		if err != nil {
 			err := fmt.Errorf("Failed to get modules outcome: %s", err)
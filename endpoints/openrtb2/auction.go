@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/openrtb/v20/openrtb3"
 	"github.com/prebid/prebid-server/v3/bidadjustment"
+	"github.com/prebid/prebid-server/v3/capture"
 	"github.com/prebid/prebid-server/v3/hooks"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
@@ -51,6 +53,7 @@ import (
 	"github.com/prebid/prebid-server/v3/util/httputil"
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ratelimit"
 	"github.com/prebid/prebid-server/v3/util/uuidutil"
 	"github.com/prebid/prebid-server/v3/version"
 )
@@ -97,7 +100,10 @@ func NewEndpoint(
 	bidderMap map[string]openrtb_ext.BidderName,
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	hookAsyncPool *hookexecution.AsyncPool,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	captureWriter *capture.Writer,
+	accountRateLimiter *ratelimit.Limiter,
 ) (httprouter.Handle, error) {
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
 		return nil, errors.New("NewEndpoint requires non-nil arguments.")
@@ -129,8 +135,11 @@ func NewEndpoint(
 		ipValidator,
 		storedRespFetcher,
 		hookExecutionPlanBuilder,
+		hookAsyncPool,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).Auction), nil
+		openrtb_ext.NormalizeBidderName,
+		captureWriter,
+		accountRateLimiter}).Auction), nil
 }
 
 type endpointDeps struct {
@@ -152,8 +161,11 @@ type endpointDeps struct {
 	privateNetworkIPValidator iputil.IPValidator
 	storedRespFetcher         stored_requests.Fetcher
 	hookExecutionPlanBuilder  hooks.ExecutionPlanBuilder
+	hookAsyncPool             *hookexecution.AsyncPool
 	tmaxAdjustments           *exchange.TmaxAdjustmentsPreprocessed
 	normalizeBidderName       openrtb_ext.BidderNameNormalizer
+	captureWriter             *capture.Writer
+	accountRateLimiter        *ratelimit.Limiter
 }
 
 func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -165,7 +177,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	// to compute the auction timeout.
 	start := time.Now()
 
-	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
+	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine, deps.hookAsyncPool)
 
 	ao := analytics.AuctionObject{
 		Status:    http.StatusOK,
@@ -241,6 +253,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	secGPC := r.Header.Get("Sec-GPC")
 
 	warnings := errortypes.WarningOnly(errL)
+	capturedAuction := deps.captureAuction(req, account)
 
 	auctionRequest := &exchange.AuctionRequest{
 		BidRequestWrapper:          req,
@@ -260,6 +273,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		TCF2Config:                 tcf2Config,
 		Activities:                 activityControl,
 		TmaxAdjustments:            deps.tmaxAdjustments,
+		Capture:                    capturedAuction,
 	}
 	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
 	defer func() {
@@ -275,6 +289,7 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 	ao.Response = response
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
+	ao.PrivacyAudit = auctionResponse.GetPrivacyAudit()
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
 		if errortypes.ReadCode(err) == errortypes.BadInputErrorCode {
@@ -298,6 +313,26 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		glog.Errorf("Error setting seat non-bid: %v", err)
 	}
 	labels, ao = sendAuctionResponse(w, hookExecutor, response, req.BidRequest, account, labels, ao)
+	deps.captureWriter.Write(capturedAuction, response)
+}
+
+// captureAuction starts the capture of the auction when it is sampled by the account, nil otherwise
+func (deps *endpointDeps) captureAuction(req *openrtb_ext.RequestWrapper, account *config.Account) *capture.Auction {
+	if !deps.captureWriter.Sample(account.Capture.SampleRate) {
+		return nil
+	}
+
+	if err := req.RebuildRequest(); err != nil {
+		glog.Errorf("Failed to capture auction: %v", err)
+		return nil
+	}
+	ipConf := privacy.IPConf{IPV6: account.Privacy.IPv6Config, IPV4: account.Privacy.IPv4Config}
+	capturedAuction, err := capture.NewAuction(req, account.ID, ipConf)
+	if err != nil {
+		glog.Errorf("Failed to capture auction: %v", err)
+		return nil
+	}
+	return capturedAuction
 }
 
 // setSeatNonBidRaw is transitional function for setting SeatNonBid inside bidResponse.Ext
@@ -360,7 +395,7 @@ func sendAuctionResponse(
 
 	if response != nil {
 		stageOutcomes := hookExecutor.GetOutcomes()
-		ao.HookExecutionOutcome = stageOutcomes
+		ao.HookExecutionOutcome = slices.Concat(stageOutcomes, hookExecutor.GetAsyncOutcomes())
 
 		ext, warns, err := hookexecution.EnrichExtBidResponse(response.Ext, stageOutcomes, request, account)
 		if err != nil {
@@ -505,6 +540,10 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	if len(errs) > 0 {
 		return
 	}
+	if err := deps.checkAccountRateLimit(account); err != nil {
+		errs = []error{err}
+		return
+	}
 
 	hookExecutor.SetAccount(account)
 	requestJson, rejectErr = hookExecutor.ExecuteRawAuctionStage(requestJson)
@@ -1926,6 +1965,10 @@ func writeError(errs []error, w http.ResponseWriter, labels *metrics.Labels) boo
 				httpStatus = http.StatusInternalServerError
 				metricsStatus = metrics.RequestStatusAccountConfigErr
 				break
+			} else if erVal == errortypes.AccountRateLimitedErrorCode {
+				httpStatus = http.StatusTooManyRequests
+				metricsStatus = metrics.RequestStatusRateLimited
+				break
 			}
 		}
 		w.WriteHeader(httpStatus)
@@ -1938,6 +1981,17 @@ func writeError(errs []error, w http.ResponseWriter, labels *metrics.Labels) boo
 	return rc
 }
 
+// checkAccountRateLimit returns an error when the account runs more auctions than allowed by its rate limit
+func (deps *endpointDeps) checkAccountRateLimit(account *config.Account) error {
+	limit := ratelimit.Limit{PerSecond: account.RateLimit.AuctionsPerSecond, Burst: account.RateLimit.Burst}
+	if deps.accountRateLimiter.Allow(account.ID, limit) {
+		return nil
+	}
+	return &errortypes.AccountRateLimited{
+		Message: fmt.Sprintf("Prebid-server has rate limited Account ID: %s, too many auctions were requested. Please retry later.", account.ID),
+	}
+}
+
 // Returns the account ID for the request
 func getAccountID(pub *openrtb2.Publisher) string {
 	if pub != nil {
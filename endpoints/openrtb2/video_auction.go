@@ -37,6 +37,7 @@ import (
 	"github.com/prebid/prebid-server/v3/util/iputil"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/prebid/prebid-server/v3/util/ratelimit"
 	"github.com/prebid/prebid-server/v3/util/uuidutil"
 	"github.com/prebid/prebid-server/v3/version"
 )
@@ -58,6 +59,7 @@ func NewVideoEndpoint(
 	bidderMap map[string]openrtb_ext.BidderName,
 	cache prebid_cache_client.Client,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	accountRateLimiter *ratelimit.Limiter,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || met == nil {
@@ -92,8 +94,11 @@ func NewVideoEndpoint(
 		ipValidator,
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
+		nil,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).VideoAuctionEndpoint), nil
+		openrtb_ext.NormalizeBidderName,
+		nil,
+		accountRateLimiter}).VideoAuctionEndpoint), nil
 }
 
 /*
@@ -303,6 +308,10 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		handleError(&labels, w, acctIDErrs, &vo, &debugLog)
 		return
 	}
+	if err := deps.checkAccountRateLimit(account); err != nil {
+		handleError(&labels, w, []error{err}, &vo, &debugLog)
+		return
+	}
 
 	// Populate any "missing" OpenRTB fields with info from other sources, (e.g. HTTP request headers).
 	if errs := deps.setFieldsImplicitly(r, bidReqWrapper, account); len(errs) > 0 {
@@ -429,6 +438,10 @@ func handleError(labels *metrics.Labels, w http.ResponseWriter, errL []error, vo
 			status = http.StatusInternalServerError
 			labels.RequestStatus = metrics.RequestStatusAccountConfigErr
 			break
+		} else if erVal == errortypes.AccountRateLimitedErrorCode {
+			status = http.StatusTooManyRequests
+			labels.RequestStatus = metrics.RequestStatusRateLimited
+			break
 		}
 		errors = fmt.Sprintf("%s %s", errors, er.Error())
 	}
@@ -840,6 +840,14 @@ func TestHandleError(t *testing.T) {
 			wantCode:          500,
 			wantMetricsStatus: metrics.RequestStatusAccountConfigErr,
 		},
+		{
+			description: "Rate limited account - return 429 with rate limited metrics status",
+			giveErrors: []error{
+				&errortypes.AccountRateLimited{},
+			},
+			wantCode:          429,
+			wantMetricsStatus: metrics.RequestStatusRateLimited,
+		},
 		{
 			description: "Multiple generic errors - return 500 with generic error metrics status",
 			giveErrors: []error{
@@ -1286,7 +1294,10 @@ func mockDepsWithMetrics(t *testing.T, ex *mockExchangeVideo) (*endpointDeps, *m
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
 	}
 	return deps, metrics, mockModule
 }
@@ -1337,7 +1348,10 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
 	}
 }
 
@@ -1362,7 +1376,10 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
 	}
 
 	return deps
@@ -1389,7 +1406,10 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
 	}
 
 	return edep
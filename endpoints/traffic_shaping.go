@@ -0,0 +1,24 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/trafficshaping"
+)
+
+// NewTrafficShapingEndpoint returns the model learned by the traffic shaper, i.e. the groups of imps sent to each
+// bidder and the ones no longer sent to it.
+func NewTrafficShapingEndpoint(shaper *trafficshaping.Shaper) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		jsonOutput, err := shaper.MarshalModel()
+		if err != nil {
+			glog.Errorf("/traffic_shaping/model Critical error when trying to marshal the traffic shaping model: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
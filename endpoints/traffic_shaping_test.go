@@ -0,0 +1,31 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/trafficshaping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrafficShapingEndpoint(t *testing.T) {
+	shaper, err := trafficshaping.NewShaper(config.TrafficShaping{
+		Enabled:            true,
+		Dimensions:         []string{"mediaType"},
+		MinRequests:        1,
+		MaxGroupsPerBidder: 10,
+	})
+	require.NoError(t, err)
+	shaper.Record("appnexus", "banner", false)
+
+	handler := NewTrafficShapingEndpoint(shaper)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/traffic_shaping/model", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"dimensions":["mediaType"],"updated_at":"0001-01-01T00:00:00Z","bidders":{"appnexus":{"banner":{"requests":1,"bids":0,"pruned":false}}}}`, w.Body.String())
+}
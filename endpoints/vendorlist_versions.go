@@ -0,0 +1,44 @@
+package endpoints
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// vendorListSpecVersions are the versions of the vendor lists loaded for a spec version
+type vendorListSpecVersions struct {
+	SpecVersion uint16   `json:"specVersion"`
+	Latest      uint16   `json:"latest"`
+	Versions    []uint16 `json:"versions"`
+}
+
+// NewVendorListVersionsEndpoint returns the versions of the vendor lists loaded by the TCF enforcement, by spec version.
+func NewVendorListVersionsEndpoint(versions gdpr.VendorListVersions) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		specVersions := make([]vendorListSpecVersions, 0)
+		for specVersion, listVersions := range versions() {
+			specVersions = append(specVersions, vendorListSpecVersions{
+				SpecVersion: specVersion,
+				Latest:      listVersions[len(listVersions)-1],
+				Versions:    listVersions,
+			})
+		}
+		sort.Slice(specVersions, func(i, j int) bool {
+			return specVersions[i].SpecVersion < specVersions[j].SpecVersion
+		})
+
+		jsonOutput, err := jsonutil.Marshal(specVersions)
+		if err != nil {
+			glog.Errorf("/vendorlist/versions Critical error when trying to marshal the vendor list versions: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
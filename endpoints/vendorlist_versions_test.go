@@ -0,0 +1,40 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVendorListVersionsEndpoint(t *testing.T) {
+	testCases := []struct {
+		name         string
+		versions     map[uint16][]uint16
+		expectedBody string
+	}{
+		{
+			name:         "none",
+			versions:     map[uint16][]uint16{},
+			expectedBody: `[]`,
+		},
+		{
+			name:         "several_spec_versions",
+			versions:     map[uint16][]uint16{3: {1, 2, 5}, 2: {2, 3}},
+			expectedBody: `[{"specVersion":2,"latest":3,"versions":[2,3]},{"specVersion":3,"latest":5,"versions":[1,2,5]}]`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			handler := NewVendorListVersionsEndpoint(func() map[uint16][]uint16 { return test.versions })
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest("GET", "/vendorlist/versions", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
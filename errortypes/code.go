@@ -19,6 +19,8 @@ const (
 	FailedToUnmarshalErrorCode
 	InvalidImpFirstPartyDataErrorCode
 	BidderTemporarilyThrottledErrorCode
+	BidderCircuitOpenErrorCode
+	AccountRateLimitedErrorCode
 )
 
 // Defines numeric codes for well-known warnings.
@@ -93,6 +93,25 @@ func (err *AccountDisabled) Severity() Severity {
 	return SeverityFatal
 }
 
+// AccountRateLimited should be used when an account runs more auctions than allowed by the rate limit of its account config.
+//
+// These errors will be written to http.ResponseWriter before canceling execution
+type AccountRateLimited struct {
+	Message string
+}
+
+func (err *AccountRateLimited) Error() string {
+	return err.Message
+}
+
+func (err *AccountRateLimited) Code() int {
+	return AccountRateLimitedErrorCode
+}
+
+func (err *AccountRateLimited) Severity() Severity {
+	return SeverityFatal
+}
+
 // AcctRequired should be used when the environment variable ACCOUNT_REQUIRED has been set to not
 // process requests that don't come with a valid account ID
 //
@@ -197,6 +216,24 @@ func (err *BidderThrottled) Severity() Severity {
 	return SeverityWarning
 }
 
+// BidderCircuitOpen is used when a bidder is not called because the circuit breaker of its endpoint is open,
+// the endpoint having failed too often recently.
+type BidderCircuitOpen struct {
+	Message string
+}
+
+func (err *BidderCircuitOpen) Error() string {
+	return err.Message
+}
+
+func (err *BidderCircuitOpen) Code() int {
+	return BidderCircuitOpenErrorCode
+}
+
+func (err *BidderCircuitOpen) Severity() Severity {
+	return SeverityWarning
+}
+
 // MalformedAcct should be used when the retrieved account config cannot be unmarshaled
 // These errors will be written to http.ResponseWriter before canceling execution
 type MalformedAcct struct {
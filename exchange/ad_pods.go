@@ -0,0 +1,335 @@
+package exchange
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+const (
+	// maxAdPodCandidates caps the number of bids competing to fill an ad pod, the highest priced ones, as the search
+	// of the best combination of bids is exponential in their number
+	maxAdPodCandidates = 20
+	// maxAdPodSearchSteps caps the steps of the searches of the best combinations of bids of all the ad pods of an
+	// auction, shared between the pods. A pod is always given the steps needed to reach its first combination, the
+	// one taking the highest priced bids which fit, so the work of an auction only grows linearly with its pods.
+	maxAdPodSearchSteps = 100_000
+)
+
+// adPod is an OpenRTB 2.6 video ad pod of the request, made of the imps sharing the same imp.video.podid.
+// A dynamic pod imp without pod ID is a pod by itself.
+type adPod struct {
+	slots map[string]*adPodSlot
+}
+
+// adPodSlot is the part of a pod offered by an imp: a single ad for a structured pod, or up to maxseq ads within
+// poddur seconds for a dynamic pod
+type adPodSlot struct {
+	video   *openrtb2.Video
+	dynamic bool
+}
+
+// maxAds returns the number of ads the slot can be filled with, 0 meaning no limit
+func (s *adPodSlot) maxAds() int64 {
+	if !s.dynamic {
+		return 1
+	}
+	return s.video.MaxSeq
+}
+
+// adPodCandidate is a bid competing to fill a slot of a pod
+type adPodCandidate struct {
+	bid      *entities.PbsOrtbBid
+	slot     *adPodSlot
+	duration int64
+	position adcom1.SlotPositionInPod
+}
+
+// buildAdPods returns the ad pods of the request by pod ID
+func buildAdPods(request *openrtb_ext.RequestWrapper) map[string]*adPod {
+	pods := make(map[string]*adPod)
+	for _, imp := range request.GetImp() {
+		video := imp.Video
+		if video == nil {
+			continue
+		}
+
+		dynamic := video.PodDur > 0 || video.MaxSeq > 0
+		podID := video.PodID
+		if podID == "" {
+			if !dynamic {
+				continue
+			}
+			podID = "imp:" + imp.ID
+		}
+
+		pod, ok := pods[podID]
+		if !ok {
+			pod = &adPod{slots: make(map[string]*adPodSlot)}
+			pods[podID] = pod
+		}
+		pod.slots[imp.ID] = &adPodSlot{video: video, dynamic: dynamic}
+	}
+	return pods
+}
+
+// fillAdPods keeps, for each ad pod of the request, the combination of bids with the highest revenue which fits the
+// durations of the pod and its slot positions, and has no two ads of the same advertiser domain or IAB category.
+// The other bids on the imps of the pods are rejected.
+func fillAdPods(request *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, seatNonBidBuilder *SeatNonBidBuilder) {
+	pods := buildAdPods(request)
+	if len(pods) == 0 {
+		return
+	}
+
+	podsByImp := make(map[string]*adPod)
+	for _, pod := range pods {
+		for impID := range pod.slots {
+			podsByImp[impID] = pod
+		}
+	}
+
+	candidates := make(map[*adPod][]*adPodCandidate, len(pods))
+	for _, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			pod, ok := podsByImp[bid.Bid.ImpID]
+			if !ok {
+				continue
+			}
+			if candidate, ok := newAdPodCandidate(bid, pod.slots[bid.Bid.ImpID]); ok {
+				candidates[pod] = append(candidates[pod], candidate)
+			}
+		}
+	}
+
+	steps := max(maxAdPodSearchSteps/len(pods), maxAdPodCandidates+1)
+	selected := make(map[*entities.PbsOrtbBid]struct{})
+	for _, pod := range pods {
+		for _, candidate := range selectAdPodBids(candidates[pod], steps) {
+			selected[candidate.bid] = struct{}{}
+		}
+	}
+
+	for bidderName, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		bids := make([]*entities.PbsOrtbBid, 0, len(seatBid.Bids))
+		for _, bid := range seatBid.Bids {
+			if _, ok := podsByImp[bid.Bid.ImpID]; ok {
+				if _, ok := selected[bid]; !ok {
+					seatNonBidBuilder.rejectBid(bid, int(ResponseRejectedAdPod), bidderName.String())
+					continue
+				}
+			}
+			bids = append(bids, bid)
+		}
+		seatBid.Bids = bids
+	}
+}
+
+// newAdPodCandidate returns the bid as a candidate to fill the slot, unless its duration or position does not fit
+func newAdPodCandidate(bid *entities.PbsOrtbBid, slot *adPodSlot) (*adPodCandidate, bool) {
+	duration := bid.Bid.Dur
+	if duration == 0 && bid.BidVideo != nil {
+		duration = int64(bid.BidVideo.Duration)
+	}
+
+	video := slot.video
+	switch {
+	case len(video.RqdDurs) > 0:
+		if !slices.Contains(video.RqdDurs, duration) {
+			return nil, false
+		}
+	case duration == 0:
+		// the bids without duration are assumed to last as long as allowed to fit a dynamic pod
+		if slot.dynamic && video.PodDur > 0 {
+			if video.MaxDuration == 0 {
+				return nil, false
+			}
+			duration = video.MaxDuration
+		}
+	case video.MinDuration > 0 && duration < video.MinDuration, video.MaxDuration > 0 && duration > video.MaxDuration:
+		return nil, false
+	}
+	if slot.dynamic && video.PodDur > 0 && duration > video.PodDur {
+		return nil, false
+	}
+
+	position, ok := adPodPosition(slot, bid.Bid.SlotInPod)
+	if !ok {
+		return nil, false
+	}
+	return &adPodCandidate{bid: bid, slot: slot, duration: duration, position: position}, true
+}
+
+// adPodPosition returns the position in the pod the bid has to be served at, given the position guaranteed by a
+// structured pod slot and the one the bid is eligible for
+func adPodPosition(slot *adPodSlot, bidPosition adcom1.SlotPositionInPod) (adcom1.SlotPositionInPod, bool) {
+	slotPosition := adcom1.SlotPosAny
+	if !slot.dynamic {
+		slotPosition = slot.video.SlotInPod
+	}
+
+	switch {
+	case slotPosition == adcom1.SlotPosAny || slotPosition == adcom1.SlotPosFirstOrLast && bidPosition != adcom1.SlotPosAny:
+		return bidPosition, true
+	case bidPosition == adcom1.SlotPosAny || bidPosition == adcom1.SlotPosFirstOrLast || bidPosition == slotPosition:
+		return slotPosition, true
+	default:
+		return adcom1.SlotPosAny, false
+	}
+}
+
+// adPodSelection searches the combination of candidates with the highest revenue, exploring the candidates from the
+// highest priced one and pruning the branches which cannot beat the best combination found. The search stops with
+// the best combination found so far once it ran out of steps.
+type adPodSelection struct {
+	candidates []*adPodCandidate
+	// remainingPrices are the sums of the prices of the candidates from each index
+	remainingPrices []float64
+	// steps is the number of search calls left
+	steps int
+
+	selected      []*adPodCandidate
+	best          []*adPodCandidate
+	bestPrice     float64
+	slotAds       map[*adPodSlot]int64
+	slotDurations map[*adPodSlot]int64
+	adDomains     map[string]struct{}
+	categories    map[string]struct{}
+	firstTaken    bool
+	lastTaken     bool
+}
+
+// selectAdPodBids returns the combination of candidates with the highest revenue which fits the pod, or the best one
+// found within the search steps. The first combination found takes the highest priced candidates which fit and is
+// reached within len(candidates)+1 steps.
+func selectAdPodBids(candidates []*adPodCandidate, steps int) []*adPodCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].bid.Bid.Price > candidates[j].bid.Bid.Price
+	})
+	if len(candidates) > maxAdPodCandidates {
+		candidates = candidates[:maxAdPodCandidates]
+	}
+
+	s := &adPodSelection{
+		candidates:      candidates,
+		remainingPrices: make([]float64, len(candidates)+1),
+		steps:           steps,
+		slotAds:         make(map[*adPodSlot]int64),
+		slotDurations:   make(map[*adPodSlot]int64),
+		adDomains:       make(map[string]struct{}),
+		categories:      make(map[string]struct{}),
+	}
+	for i := len(candidates) - 1; i >= 0; i-- {
+		s.remainingPrices[i] = s.remainingPrices[i+1] + candidates[i].bid.Bid.Price
+	}
+	s.search(0, 0)
+	return s.best
+}
+
+func (s *adPodSelection) search(index int, price float64) {
+	if price > s.bestPrice {
+		s.bestPrice = price
+		s.best = slices.Clone(s.selected)
+	}
+	if s.steps <= 0 || index == len(s.candidates) || price+s.remainingPrices[index] <= s.bestPrice {
+		return
+	}
+	s.steps--
+
+	candidate := s.candidates[index]
+	if s.fits(candidate) {
+		for _, position := range s.freePositions(candidate.position) {
+			s.add(candidate, position)
+			s.search(index+1, price+candidate.bid.Bid.Price)
+			s.remove(candidate, position)
+		}
+	}
+	s.search(index+1, price)
+}
+
+// fits returns whether the candidate can be added to the selected ones without exceeding its slot or sharing an
+// advertiser domain or a category with them
+func (s *adPodSelection) fits(candidate *adPodCandidate) bool {
+	slot := candidate.slot
+	if maxAds := slot.maxAds(); maxAds > 0 && s.slotAds[slot] >= maxAds {
+		return false
+	}
+	if slot.dynamic && slot.video.PodDur > 0 && s.slotDurations[slot]+candidate.duration > slot.video.PodDur {
+		return false
+	}
+	for _, adDomain := range candidate.bid.Bid.ADomain {
+		if _, ok := s.adDomains[adDomain]; ok {
+			return false
+		}
+	}
+	for _, category := range candidate.bid.Bid.Cat {
+		if _, ok := s.categories[category]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// freePositions returns the positions of the pod the candidate can still be served at
+func (s *adPodSelection) freePositions(position adcom1.SlotPositionInPod) []adcom1.SlotPositionInPod {
+	var positions []adcom1.SlotPositionInPod
+	if position == adcom1.SlotPosAny {
+		return []adcom1.SlotPositionInPod{adcom1.SlotPosAny}
+	}
+	if (position == adcom1.SlotPosFirst || position == adcom1.SlotPosFirstOrLast) && !s.firstTaken {
+		positions = append(positions, adcom1.SlotPosFirst)
+	}
+	if (position == adcom1.SlotPosLast || position == adcom1.SlotPosFirstOrLast) && !s.lastTaken {
+		positions = append(positions, adcom1.SlotPosLast)
+	}
+	return positions
+}
+
+func (s *adPodSelection) add(candidate *adPodCandidate, position adcom1.SlotPositionInPod) {
+	s.selected = append(s.selected, candidate)
+	s.slotAds[candidate.slot]++
+	s.slotDurations[candidate.slot] += candidate.duration
+	for _, adDomain := range candidate.bid.Bid.ADomain {
+		s.adDomains[adDomain] = struct{}{}
+	}
+	for _, category := range candidate.bid.Bid.Cat {
+		s.categories[category] = struct{}{}
+	}
+	s.setPositionTaken(position, true)
+}
+
+func (s *adPodSelection) remove(candidate *adPodCandidate, position adcom1.SlotPositionInPod) {
+	s.selected = s.selected[:len(s.selected)-1]
+	s.slotAds[candidate.slot]--
+	s.slotDurations[candidate.slot] -= candidate.duration
+	for _, adDomain := range candidate.bid.Bid.ADomain {
+		delete(s.adDomains, adDomain)
+	}
+	for _, category := range candidate.bid.Bid.Cat {
+		delete(s.categories, category)
+	}
+	s.setPositionTaken(position, false)
+}
+
+func (s *adPodSelection) setPositionTaken(position adcom1.SlotPositionInPod, taken bool) {
+	switch position {
+	case adcom1.SlotPosFirst:
+		s.firstTaken = taken
+	case adcom1.SlotPosLast:
+		s.lastTaken = taken
+	}
+}
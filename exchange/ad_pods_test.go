@@ -0,0 +1,293 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildAdPods(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", Video: &openrtb2.Video{PodID: "pod1", SlotInPod: adcom1.SlotPosFirst}},
+			{ID: "imp2", Video: &openrtb2.Video{PodID: "pod1", PodDur: 60, MaxSeq: 3}},
+			{ID: "imp3", Video: &openrtb2.Video{PodDur: 30}},
+			{ID: "imp4", Video: &openrtb2.Video{}},
+			{ID: "imp5", Banner: &openrtb2.Banner{}},
+		},
+	}}
+
+	pods := buildAdPods(request)
+	assert.Len(t, pods, 2)
+	if assert.Contains(t, pods, "pod1") {
+		assert.Len(t, pods["pod1"].slots, 2)
+		assert.False(t, pods["pod1"].slots["imp1"].dynamic)
+		assert.True(t, pods["pod1"].slots["imp2"].dynamic)
+	}
+	if assert.Contains(t, pods, "imp:imp3") {
+		assert.True(t, pods["imp:imp3"].slots["imp3"].dynamic)
+	}
+}
+
+func TestNewAdPodCandidate(t *testing.T) {
+	testCases := []struct {
+		description      string
+		video            *openrtb2.Video
+		bid              *entities.PbsOrtbBid
+		expectedOk       bool
+		expectedDuration int64
+	}{
+		{
+			description:      "required-duration",
+			video:            &openrtb2.Video{RqdDurs: []int64{15, 30}},
+			bid:              &entities.PbsOrtbBid{Bid: &openrtb2.Bid{Dur: 30}},
+			expectedOk:       true,
+			expectedDuration: 30,
+		},
+		{
+			description:      "required-duration-ignoring-min-and-max-duration",
+			video:            &openrtb2.Video{RqdDurs: []int64{15, 60}, MinDuration: 5, MaxDuration: 30},
+			bid:              &entities.PbsOrtbBid{Bid: &openrtb2.Bid{Dur: 60}},
+			expectedOk:       true,
+			expectedDuration: 60,
+		},
+		{
+			description: "not-required-duration",
+			video:       &openrtb2.Video{RqdDurs: []int64{15, 30}},
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{Dur: 20}},
+			expectedOk:  false,
+		},
+		{
+			description:      "duration-from-ext",
+			video:            &openrtb2.Video{MaxDuration: 30},
+			bid:              &entities.PbsOrtbBid{Bid: &openrtb2.Bid{}, BidVideo: &openrtb_ext.ExtBidPrebidVideo{Duration: 15}},
+			expectedOk:       true,
+			expectedDuration: 15,
+		},
+		{
+			description: "over-max-duration",
+			video:       &openrtb2.Video{MaxDuration: 30},
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{Dur: 45}},
+			expectedOk:  false,
+		},
+		{
+			description: "under-min-duration",
+			video:       &openrtb2.Video{MinDuration: 15},
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{Dur: 5}},
+			expectedOk:  false,
+		},
+		{
+			description:      "no-duration-dynamic",
+			video:            &openrtb2.Video{PodDur: 60, MaxDuration: 30},
+			bid:              &entities.PbsOrtbBid{Bid: &openrtb2.Bid{}},
+			expectedOk:       true,
+			expectedDuration: 30,
+		},
+		{
+			description: "no-duration-dynamic-no-max-duration",
+			video:       &openrtb2.Video{PodDur: 60},
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{}},
+			expectedOk:  false,
+		},
+		{
+			description: "over-pod-duration",
+			video:       &openrtb2.Video{PodDur: 60},
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{Dur: 90}},
+			expectedOk:  false,
+		},
+		{
+			description: "conflicting-position",
+			video:       &openrtb2.Video{SlotInPod: adcom1.SlotPosFirst},
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{SlotInPod: adcom1.SlotPosLast}},
+			expectedOk:  false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			slot := &adPodSlot{video: test.video, dynamic: test.video.PodDur > 0}
+			candidate, ok := newAdPodCandidate(test.bid, slot)
+			assert.Equal(t, test.expectedOk, ok)
+			if ok {
+				assert.Equal(t, test.expectedDuration, candidate.duration)
+			}
+		})
+	}
+}
+
+func TestAdPodPosition(t *testing.T) {
+	testCases := []struct {
+		description      string
+		slotPosition     adcom1.SlotPositionInPod
+		dynamic          bool
+		bidPosition      adcom1.SlotPositionInPod
+		expectedPosition adcom1.SlotPositionInPod
+		expectedOk       bool
+	}{
+		{"any-slot", adcom1.SlotPosAny, false, adcom1.SlotPosLast, adcom1.SlotPosLast, true},
+		{"any-bid", adcom1.SlotPosFirst, false, adcom1.SlotPosAny, adcom1.SlotPosFirst, true},
+		{"same", adcom1.SlotPosLast, false, adcom1.SlotPosLast, adcom1.SlotPosLast, true},
+		{"first-or-last-slot", adcom1.SlotPosFirstOrLast, false, adcom1.SlotPosFirst, adcom1.SlotPosFirst, true},
+		{"first-or-last-bid", adcom1.SlotPosLast, false, adcom1.SlotPosFirstOrLast, adcom1.SlotPosLast, true},
+		{"conflict", adcom1.SlotPosFirst, false, adcom1.SlotPosLast, adcom1.SlotPosAny, false},
+		{"dynamic-slot", adcom1.SlotPosFirst, true, adcom1.SlotPosLast, adcom1.SlotPosLast, true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			slot := &adPodSlot{video: &openrtb2.Video{SlotInPod: test.slotPosition}, dynamic: test.dynamic}
+			position, ok := adPodPosition(slot, test.bidPosition)
+			assert.Equal(t, test.expectedPosition, position)
+			assert.Equal(t, test.expectedOk, ok)
+		})
+	}
+}
+
+func TestSelectAdPodBids(t *testing.T) {
+	dynamicSlot := &adPodSlot{video: &openrtb2.Video{PodDur: 60, MaxSeq: 3}, dynamic: true}
+	firstSlot := &adPodSlot{video: &openrtb2.Video{SlotInPod: adcom1.SlotPosFirst}}
+	secondSlot := &adPodSlot{video: &openrtb2.Video{}}
+
+	newCandidate := func(id string, price float64, slot *adPodSlot, duration int64, position adcom1.SlotPositionInPod, adDomains, categories []string) *adPodCandidate {
+		return &adPodCandidate{
+			bid:      &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: id, Price: price, ADomain: adDomains, Cat: categories}},
+			slot:     slot,
+			duration: duration,
+			position: position,
+		}
+	}
+
+	testCases := []struct {
+		description string
+		candidates  []*adPodCandidate
+		steps       int
+		expectedIDs []string
+	}{
+		{
+			description: "empty",
+			candidates:  nil,
+			expectedIDs: nil,
+		},
+		{
+			description: "duration-knapsack",
+			candidates: []*adPodCandidate{
+				newCandidate("long", 5, dynamicSlot, 45, adcom1.SlotPosAny, nil, nil),
+				newCandidate("short1", 3, dynamicSlot, 30, adcom1.SlotPosAny, nil, nil),
+				newCandidate("short2", 3, dynamicSlot, 30, adcom1.SlotPosAny, nil, nil),
+			},
+			expectedIDs: []string{"short1", "short2"},
+		},
+		{
+			description: "out-of-steps",
+			candidates: []*adPodCandidate{
+				newCandidate("long", 5, dynamicSlot, 45, adcom1.SlotPosAny, nil, nil),
+				newCandidate("short1", 3, dynamicSlot, 30, adcom1.SlotPosAny, nil, nil),
+				newCandidate("short2", 3, dynamicSlot, 30, adcom1.SlotPosAny, nil, nil),
+			},
+			steps:       4,
+			expectedIDs: []string{"long"},
+		},
+		{
+			description: "max-seq",
+			candidates: []*adPodCandidate{
+				newCandidate("bid1", 4, dynamicSlot, 15, adcom1.SlotPosAny, nil, nil),
+				newCandidate("bid2", 3, dynamicSlot, 15, adcom1.SlotPosAny, nil, nil),
+				newCandidate("bid3", 2, dynamicSlot, 15, adcom1.SlotPosAny, nil, nil),
+				newCandidate("bid4", 1, dynamicSlot, 15, adcom1.SlotPosAny, nil, nil),
+			},
+			expectedIDs: []string{"bid1", "bid2", "bid3"},
+		},
+		{
+			description: "competitive-separation",
+			candidates: []*adPodCandidate{
+				newCandidate("brand1", 5, dynamicSlot, 15, adcom1.SlotPosAny, []string{"brand.com"}, nil),
+				newCandidate("brand2", 4, dynamicSlot, 15, adcom1.SlotPosAny, []string{"brand.com"}, nil),
+				newCandidate("car1", 3, dynamicSlot, 15, adcom1.SlotPosAny, []string{"car1.com"}, []string{"IAB2"}),
+				newCandidate("car2", 2, dynamicSlot, 15, adcom1.SlotPosAny, []string{"car2.com"}, []string{"IAB2"}),
+			},
+			expectedIDs: []string{"brand1", "car1"},
+		},
+		{
+			description: "positions",
+			candidates: []*adPodCandidate{
+				newCandidate("first1", 5, dynamicSlot, 15, adcom1.SlotPosFirst, nil, nil),
+				newCandidate("first2", 4, dynamicSlot, 15, adcom1.SlotPosFirst, nil, nil),
+				newCandidate("firstOrLast", 3, dynamicSlot, 15, adcom1.SlotPosFirstOrLast, nil, nil),
+				newCandidate("last", 1, dynamicSlot, 15, adcom1.SlotPosLast, nil, nil),
+			},
+			expectedIDs: []string{"first1", "firstOrLast"},
+		},
+		{
+			description: "structured",
+			candidates: []*adPodCandidate{
+				newCandidate("first1", 5, firstSlot, 30, adcom1.SlotPosFirst, []string{"brand.com"}, nil),
+				newCandidate("first2", 4, firstSlot, 30, adcom1.SlotPosFirst, nil, nil),
+				newCandidate("second1", 3, secondSlot, 30, adcom1.SlotPosAny, []string{"brand.com"}, nil),
+				newCandidate("second2", 1, secondSlot, 30, adcom1.SlotPosAny, nil, nil),
+			},
+			expectedIDs: []string{"first2", "second1"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			steps := test.steps
+			if steps == 0 {
+				steps = maxAdPodSearchSteps
+			}
+			var ids []string
+			for _, candidate := range selectAdPodBids(test.candidates, steps) {
+				ids = append(ids, candidate.bid.Bid.ID)
+			}
+			assert.ElementsMatch(t, test.expectedIDs, ids)
+		})
+	}
+}
+
+func TestFillAdPods(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", Video: &openrtb2.Video{PodID: "pod1", PodDur: 60, MaxSeq: 2}},
+			{ID: "imp2", Banner: &openrtb2.Banner{}},
+		},
+	}}
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {
+			Seat: "appnexus",
+			Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 5, Dur: 30, ADomain: []string{"brand.com"}}},
+				{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2", Price: 1}},
+			},
+		},
+		"rubicon": {
+			Seat: "rubicon",
+			Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", Price: 4, Dur: 30, ADomain: []string{"brand.com"}}},
+				{Bid: &openrtb2.Bid{ID: "bid4", ImpID: "imp1", Price: 2, Dur: 30}},
+				{Bid: &openrtb2.Bid{ID: "bid5", ImpID: "imp1", Price: 3, Dur: 45}},
+			},
+		},
+	}
+	seatNonBidBuilder := SeatNonBidBuilder{}
+
+	fillAdPods(request, seatBids, &seatNonBidBuilder)
+
+	bidIDs := make(map[openrtb_ext.BidderName][]string)
+	for bidderName, seatBid := range seatBids {
+		for _, bid := range seatBid.Bids {
+			bidIDs[bidderName] = append(bidIDs[bidderName], bid.Bid.ID)
+		}
+	}
+	assert.Equal(t, map[openrtb_ext.BidderName][]string{"appnexus": {"bid1", "bid2"}, "rubicon": {"bid4"}}, bidIDs)
+
+	rejectedPrices := make(map[float64]int)
+	for _, nonBid := range seatNonBidBuilder["rubicon"] {
+		rejectedPrices[nonBid.Ext.Prebid.Bid.Price] = nonBid.StatusCode
+	}
+	assert.Equal(t, map[float64]int{4: int(ResponseRejectedAdPod), 3: int(ResponseRejectedAdPod)}, rejectedPrices)
+	assert.NotContains(t, seatNonBidBuilder, "appnexus")
+}
@@ -0,0 +1,127 @@
+package exchange
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+const (
+	// clearingPricePrecision is the number of decimals the clearing prices are rounded to
+	clearingPricePrecision = 4
+	// auctionPriceMacro is replaced by the clearing price in the win and billing notice URLs of the cleared bids
+	auctionPriceMacro = "${AUCTION_PRICE}"
+)
+
+// applyAuctionMode clears the winning bid of each imp at the price of the account auction mode. It runs once the event
+// URLs are made and the all processed bid responses hooks are done, and before the category mapping and the targeting
+// keys, whose price buckets are built from the clearing price. Of the notice URLs, only the ${AUCTION_PRICE} macros of
+// the bid nurl and burl carry the clearing price. The price bid is still in bid.ext.origbidcpm, in the currency of the
+// bidder.
+func applyAuctionMode(request *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, auction config.AccountAuction, preferDeals bool, conversions currency.Conversions) []error {
+	if auction.Mode != config.AuctionModeSecondPrice && auction.Mode != config.AuctionModeSoftFloor {
+		return nil
+	}
+
+	winningBids := make(map[string]*entities.PbsOrtbBid)
+	winningSeatBids := make(map[string]*entities.PbsOrtbSeatBid)
+	for _, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if wbid, ok := winningBids[bid.Bid.ImpID]; !ok || isNewWinningBid(bid.Bid, wbid.Bid, preferDeals) {
+				winningBids[bid.Bid.ImpID] = bid
+				winningSeatBids[bid.Bid.ImpID] = seatBid
+			}
+		}
+	}
+
+	secondPrices := make(map[string]float64, len(winningBids))
+	for _, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if bid != winningBids[bid.Bid.ImpID] && bid.Bid.Price > secondPrices[bid.Bid.ImpID] {
+				secondPrices[bid.Bid.ImpID] = bid.Bid.Price
+			}
+		}
+	}
+
+	var errs []error
+	for _, imp := range request.GetImp() {
+		bid, ok := winningBids[imp.ID]
+		if !ok {
+			continue
+		}
+
+		floor, err := convertFloor(imp, winningSeatBids[imp.ID].Currency, conversions)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("bid %s of imp %s cleared without the floor: %v", bid.Bid.ID, imp.ID, err))
+		}
+
+		if price, ok := clearingPrice(auction, bid.Bid.Price, secondPrices[imp.ID], floor); ok {
+			clearBid(bid, price)
+		}
+	}
+	return errs
+}
+
+// convertFloor returns the floor of the imp in the currency of the bids
+func convertFloor(imp *openrtb_ext.ImpWrapper, bidCurrency string, conversions currency.Conversions) (float64, error) {
+	if imp.BidFloor <= 0 {
+		return 0, nil
+	}
+
+	floorCurrency := imp.BidFloorCur
+	if floorCurrency == "" {
+		floorCurrency = "USD"
+	}
+	if bidCurrency == "" {
+		bidCurrency = "USD"
+	}
+	if floorCurrency == bidCurrency {
+		return imp.BidFloor, nil
+	}
+
+	rate, err := conversions.GetRate(floorCurrency, bidCurrency)
+	if err != nil {
+		return 0, err
+	}
+	return imp.BidFloor * rate, nil
+}
+
+// clearingPrice returns the price the winning bid clears at given the second price and the floor, if lower than
+// the price bid
+func clearingPrice(auction config.AccountAuction, bidPrice, secondPrice, floor float64) (float64, bool) {
+	minPrice := math.Max(secondPrice, floor)
+	if auction.Mode == config.AuctionModeSoftFloor {
+		if bidPrice < auction.SoftFloor {
+			return bidPrice, false
+		}
+		minPrice = math.Max(minPrice, auction.SoftFloor)
+	}
+
+	scale := math.Pow(10, clearingPricePrecision)
+	price := math.Round((minPrice+auction.PriceIncrement)*scale) / scale
+	if price >= bidPrice {
+		return bidPrice, false
+	}
+	return price, true
+}
+
+// clearBid sets the bid price to the clearing price, also replacing the price macro in its notice URLs
+func clearBid(bid *entities.PbsOrtbBid, price float64) {
+	bid.Bid.Price = price
+
+	formattedPrice := strconv.FormatFloat(price, 'f', -1, 64)
+	bid.Bid.NURL = strings.ReplaceAll(bid.Bid.NURL, auctionPriceMacro, formattedPrice)
+	bid.Bid.BURL = strings.ReplaceAll(bid.Bid.BURL, auctionPriceMacro, formattedPrice)
+}
@@ -0,0 +1,298 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/experiment/adscert"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClearingPrice(t *testing.T) {
+	testCases := []struct {
+		description   string
+		auction       config.AccountAuction
+		bidPrice      float64
+		secondPrice   float64
+		floor         float64
+		expectedPrice float64
+		expectedClear bool
+	}{
+		{
+			description:   "second-price",
+			auction:       config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.01},
+			bidPrice:      2,
+			secondPrice:   1,
+			floor:         0.5,
+			expectedPrice: 1.01,
+			expectedClear: true,
+		},
+		{
+			description:   "second-price-floor",
+			auction:       config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.01},
+			bidPrice:      2,
+			secondPrice:   1,
+			floor:         1.5,
+			expectedPrice: 1.51,
+			expectedClear: true,
+		},
+		{
+			description:   "second-price-single-bid",
+			auction:       config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.01},
+			bidPrice:      2,
+			expectedPrice: 0.01,
+			expectedClear: true,
+		},
+		{
+			description:   "second-price-over-bid",
+			auction:       config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.01},
+			bidPrice:      2,
+			secondPrice:   1.995,
+			expectedPrice: 2,
+			expectedClear: false,
+		},
+		{
+			description:   "soft-floor-over",
+			auction:       config.AccountAuction{Mode: config.AuctionModeSoftFloor, PriceIncrement: 0.01, SoftFloor: 1.5},
+			bidPrice:      2,
+			secondPrice:   1,
+			floor:         0.5,
+			expectedPrice: 1.51,
+			expectedClear: true,
+		},
+		{
+			description:   "soft-floor-over-second-price",
+			auction:       config.AccountAuction{Mode: config.AuctionModeSoftFloor, PriceIncrement: 0.01, SoftFloor: 1.5},
+			bidPrice:      2,
+			secondPrice:   1.8,
+			expectedPrice: 1.81,
+			expectedClear: true,
+		},
+		{
+			description:   "soft-floor-under",
+			auction:       config.AccountAuction{Mode: config.AuctionModeSoftFloor, PriceIncrement: 0.01, SoftFloor: 1.5},
+			bidPrice:      1.2,
+			secondPrice:   1,
+			expectedPrice: 1.2,
+			expectedClear: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			price, clear := clearingPrice(test.auction, test.bidPrice, test.secondPrice, test.floor)
+			assert.Equal(t, test.expectedPrice, price)
+			assert.Equal(t, test.expectedClear, clear)
+		})
+	}
+}
+
+func TestApplyAuctionMode(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", BidFloor: 1, BidFloorCur: "EUR"},
+			{ID: "imp2"},
+		},
+	}}
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 1.2}})
+
+	newSeatBids := func() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+		return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"appnexus": {
+				Currency: "USD",
+				Bids: []*entities.PbsOrtbBid{
+					{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 3, NURL: "http://win.com?price=${AUCTION_PRICE}", BURL: "http://bill.com?price=${AUCTION_PRICE}"}, OriginalBidCPM: 3, OriginalBidCur: "USD"},
+					{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2", Price: 1}},
+				},
+			},
+			"rubicon": {
+				Currency: "USD",
+				Bids: []*entities.PbsOrtbBid{
+					{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", Price: 1}},
+					{Bid: &openrtb2.Bid{ID: "bid4", ImpID: "imp2", Price: 0.5, DealID: "deal"}},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		description    string
+		auction        config.AccountAuction
+		preferDeals    bool
+		expectedPrices map[string]float64
+	}{
+		{
+			description:    "first-price",
+			auction:        config.AccountAuction{Mode: config.AuctionModeFirstPrice, PriceIncrement: 0.01},
+			expectedPrices: map[string]float64{"bid1": 3, "bid2": 1, "bid3": 1, "bid4": 0.5},
+		},
+		{
+			description:    "default",
+			expectedPrices: map[string]float64{"bid1": 3, "bid2": 1, "bid3": 1, "bid4": 0.5},
+		},
+		{
+			description:    "second-price",
+			auction:        config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.01},
+			expectedPrices: map[string]float64{"bid1": 1.21, "bid2": 0.51, "bid3": 1, "bid4": 0.5},
+		},
+		{
+			description:    "second-price-prefer-deals",
+			auction:        config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.01},
+			preferDeals:    true,
+			expectedPrices: map[string]float64{"bid1": 1.21, "bid2": 1, "bid3": 1, "bid4": 0.5},
+		},
+		{
+			description:    "soft-floor",
+			auction:        config.AccountAuction{Mode: config.AuctionModeSoftFloor, PriceIncrement: 0.01, SoftFloor: 2.5},
+			expectedPrices: map[string]float64{"bid1": 2.51, "bid2": 1, "bid3": 1, "bid4": 0.5},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			seatBids := newSeatBids()
+
+			errs := applyAuctionMode(request, seatBids, test.auction, test.preferDeals, conversions)
+			assert.Empty(t, errs)
+
+			prices := make(map[string]float64)
+			for _, seatBid := range seatBids {
+				for _, bid := range seatBid.Bids {
+					prices[bid.Bid.ID] = bid.Bid.Price
+				}
+			}
+			assert.Equal(t, test.expectedPrices, prices)
+		})
+	}
+}
+
+func TestApplyAuctionModeClearedBid(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}}
+	bid := &entities.PbsOrtbBid{
+		Bid:            &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 3, NURL: "http://win.com?price=${AUCTION_PRICE}", BURL: "http://bill.com?price=${AUCTION_PRICE}"},
+		OriginalBidCPM: 2.5,
+		OriginalBidCur: "EUR",
+	}
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Currency: "USD", Bids: []*entities.PbsOrtbBid{bid}},
+		"rubicon":  {Currency: "USD", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 1.5}}}},
+	}
+
+	errs := applyAuctionMode(request, seatBids, config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.05}, false, currency.NewConstantRates())
+	assert.Empty(t, errs)
+	assert.Equal(t, 1.55, bid.Bid.Price)
+	assert.Equal(t, "http://win.com?price=1.55", bid.Bid.NURL)
+	assert.Equal(t, "http://bill.com?price=1.55", bid.Bid.BURL)
+	assert.Equal(t, 2.5, bid.OriginalBidCPM, "the price bid is kept for bid.ext.origbidcpm")
+	assert.Equal(t, "EUR", bid.OriginalBidCur)
+}
+
+func TestApplyAuctionModeFloorConversionError(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1", BidFloor: 1, BidFloorCur: "JPY"}}}}
+	bid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 3}}
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Currency: "USD", Bids: []*entities.PbsOrtbBid{bid}},
+	}
+
+	errs := applyAuctionMode(request, seatBids, config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.01}, false, currency.NewRates(nil))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, 0.01, bid.Bid.Price)
+}
+
+type fixedBidsBidder struct {
+	bids []*entities.PbsOrtbBid
+}
+
+func (b *fixedBidsBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, executor hookexecution.StageExecutor, ruleToAdjustments openrtb_ext.AdjustmentsByDealID) ([]*entities.PbsOrtbSeatBid, extraBidderRespInfo, []error) {
+	return []*entities.PbsOrtbSeatBid{{Bids: b.bids, Currency: "USD", Seat: string(bidderRequest.BidderName)}}, extraBidderRespInfo{}, nil
+}
+
+func (b *fixedBidsBidder) logHealthCheck(success bool) {}
+
+func (b *fixedBidsBidder) shouldRequest() bool {
+	return true
+}
+
+func TestHoldAuctionSecondPriceTargeting(t *testing.T) {
+	e := &exchange{
+		adapterMap: map[openrtb_ext.BidderName]AdaptedBidder{
+			openrtb_ext.BidderAppnexus: &fixedBidsBidder{bids: []*entities.PbsOrtbBid{{
+				Bid:      &openrtb2.Bid{ID: "winning-bid", ImpID: "some-imp-id", Price: 5, CrID: "1"},
+				BidType:  openrtb_ext.BidTypeVideo,
+				BidVideo: &openrtb_ext.ExtBidPrebidVideo{Duration: 30},
+			}}},
+			openrtb_ext.BidderRubicon: &fixedBidsBidder{bids: []*entities.PbsOrtbBid{{
+				Bid:      &openrtb2.Bid{ID: "losing-bid", ImpID: "some-imp-id", Price: 1.5, CrID: "2"},
+				BidType:  openrtb_ext.BidTypeVideo,
+				BidVideo: &openrtb_ext.ExtBidPrebidVideo{Duration: 15},
+			}}},
+		},
+		me:                &metricsConfig.NilMetricsEngine{},
+		cache:             &wellBehavedCache{},
+		gdprPermsBuilder:  fakePermissionsBuilder{permissions: &permissionsMock{allowAllBidders: true}}.Builder,
+		currencyConverter: currency.NewRateConverter(&http.Client{}, time.Duration(1), "", time.Duration(0)),
+		gdprDefaultValue:  gdpr.SignalYes,
+		bidIDGenerator:    &fakeBidIDGenerator{GenerateBidID: false, ReturnError: false},
+	}
+	e.requestSplitter = requestSplitter{
+		me:               e.me,
+		gdprPermsBuilder: e.gdprPermsBuilder,
+	}
+
+	auctionRequest := &AuctionRequest{
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			ID:   "some-request-id",
+			Site: &openrtb2.Site{},
+			Imp: []openrtb2.Imp{{
+				ID:    "some-imp-id",
+				Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}},
+				Ext:   json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1},"rubicon":{}}}}`),
+			}},
+			Ext: json.RawMessage(`{"prebid":{"targeting":{"pricegranularity":{"precision":2,"ranges":[{"min":0,"max":20,"increment":0.1}]},"includewinners":true,"includebidderkeys":false,"includebrandcategory":{"withcategory":false},"durationrangesec":[15,30]}}}`),
+		}},
+		Account: config.Account{
+			Auction: config.AccountAuction{Mode: config.AuctionModeSecondPrice, PriceIncrement: 0.01},
+		},
+		UserSyncs:    &emptyUsersync{},
+		StartTime:    time.Now(),
+		HookExecutor: &hookexecution.EmptyHookExecutor{},
+		TCF2Config:   gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}
+
+	bidResp, err := e.HoldAuction(context.Background(), auctionRequest, &DebugLog{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var winningBid *openrtb2.Bid
+	for _, seatBid := range bidResp.SeatBid {
+		for i := range seatBid.Bid {
+			if seatBid.Bid[i].ID == "winning-bid" {
+				winningBid = &seatBid.Bid[i]
+			}
+		}
+	}
+	if !assert.NotNil(t, winningBid, "the winning bid is missing from the response") {
+		return
+	}
+
+	var bidExt openrtb_ext.ExtBid
+	if !assert.NoError(t, json.Unmarshal(winningBid.Ext, &bidExt)) {
+		return
+	}
+	assert.Equal(t, 1.51, winningBid.Price)
+	assert.Equal(t, "1.50", bidExt.Prebid.Targeting["hb_pb"])
+	assert.Equal(t, "1.50_30s", bidExt.Prebid.Targeting["hb_pb_cat_dur"], "the category duration key is built from the clearing price")
+}
@@ -9,6 +9,8 @@ import (
 type AuctionResponse struct {
 	*openrtb2.BidResponse
 	ExtBidResponse *openrtb_ext.ExtBidResponse
+	// PrivacyAudit records the privacy signals of the auction and the privacy decisions taken for each bidder
+	PrivacyAudit *openrtb_ext.PrivacyAudit
 }
 
 // GetSeatNonBid returns array of seat non-bid if present. nil otherwise
@@ -18,3 +20,11 @@ func (ar *AuctionResponse) GetSeatNonBid() []openrtb_ext.SeatNonBid {
 	}
 	return nil
 }
+
+// GetPrivacyAudit returns the privacy audit of the auction if present. nil otherwise
+func (ar *AuctionResponse) GetPrivacyAudit() *openrtb_ext.PrivacyAudit {
+	if ar != nil {
+		return ar.PrivacyAudit
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v3/bidadjustment"
+	"github.com/prebid/prebid-server/v3/capture"
 	"github.com/prebid/prebid-server/v3/config/util"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
@@ -79,6 +80,7 @@ type bidRequestOptions struct {
 	tmaxAdjustments        *TmaxAdjustmentsPreprocessed
 	bidderRequestStartTime time.Time
 	responseDebugAllowed   bool
+	capture                *capture.Auction
 }
 
 type extraBidderRespInfo struct {
@@ -105,11 +107,13 @@ const (
 // The name refers to the "Adapter" architecture pattern, and should not be confused with a Prebid "Adapter"
 // (which is being phased out and replaced by Bidder for OpenRTB auctions)
 func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, debugInfo *config.DebugInfo, endpointCompression string) AdaptedBidder {
+	info := cfg.BidderInfos[string(name)]
 	ba := &BidderAdapter{
-		Bidder:     bidder,
-		BidderName: name,
-		Client:     client,
-		me:         me,
+		Bidder:          bidder,
+		BidderName:      name,
+		Client:          client,
+		me:              me,
+		circuitBreakers: newCircuitBreakers(info.CircuitBreaker, name, me),
 		config: bidderAdapterConfig{
 			Debug:                  cfg.Debug,
 			DisableConnMetrics:     cfg.Metrics.Disabled.AdapterConnectionMetrics,
@@ -123,6 +127,7 @@ func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Config
 				shortQueueWaitThreshold: time.Duration(cfg.Client.Throttle.ShortQueueWaitThresholdMS) * time.Millisecond,
 				throttleWindow:          cfg.Client.Throttle.ThrottleWindow,
 			},
+			RetryConfig: newBidderAdapterRetryConfig(info.RequestRetry),
 		},
 	}
 	if ba.config.ThrottleConfig.throttleWindow <= 0 {
@@ -150,6 +155,8 @@ type BidderAdapter struct {
 	config     bidderAdapterConfig
 	healthBits atomic.Uint64 // use atomic on this
 
+	circuitBreakers *circuitBreakers
+	responseTimes   responseTimes
 }
 
 type bidderAdapterConfig struct {
@@ -159,6 +166,7 @@ type bidderAdapterConfig struct {
 	DebugInfo              config.DebugInfo
 	EndpointCompression    string
 	ThrottleConfig         bidderAdapterThrottleConfig
+	RetryConfig            bidderAdapterRetryConfig
 }
 
 type bidderAdapterThrottleConfig struct {
@@ -280,6 +288,10 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 	// even if the timeout occurs sometime halfway through.
 	for i := 0; i < dataLen; i++ {
 		httpInfo := <-responseChannel
+		// the stored responses are not HTTP calls, they are not captured
+		if httpInfo.request != nil && httpInfo.request.Uri != "" {
+			bidRequestOptions.capture.AddBidderCall(string(bidderRequest.BidderName), httpInfo.request, httpInfo.response, httpInfo.err)
+		}
 		// If this is a test bid, capture debugging info from the requests.
 		// Write debug data to ext in case if:
 		// - headerDebugAllowed (debug override header specified correct) - it overrides all other debug restrictions
@@ -307,7 +319,7 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 			errs = append(errs, moreErrs...)
 
 			if bidResponse != nil {
-				reject := hookExecutor.ExecuteRawBidderResponseStage(bidResponse, string(bidder.BidderName))
+				rejectedBids, reject := hookExecutor.ExecuteRawBidderResponseStage(bidResponse, string(bidder.BidderName))
 				if reject != nil {
 					errs = append(errs, reject)
 					continue
@@ -316,6 +328,7 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 				if bidResponse.Currency == "" {
 					bidResponse.Currency = defaultCurrency
 				}
+				seatNonBidBuilder.rejectHookBids(rejectedBids, bidResponse.Currency, string(bidder.BidderName))
 				if len(bidderRequest.BidRequest.Cur) == 0 {
 					bidderRequest.BidRequest.Cur = []string{defaultCurrency}
 				}
@@ -569,29 +582,66 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
 func (bidder *BidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
-	if bidder.shouldRequest() {
-		return bidder.doRequestImpl(ctx, req, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
+	if !bidder.shouldRequest() {
+		return &httpCallInfo{
+			request: req,
+			err:     &errortypes.BidderThrottled{Message: fmt.Sprintf("Bidder %s is temporarily throttled", bidder.BidderName)},
+		}
 	}
-	return &httpCallInfo{
-		request: req,
-		err:     &errortypes.BidderThrottled{Message: fmt.Sprintf("Bidder %s is temporarily throttled", bidder.BidderName)},
+
+	host := circuitBreakerHost(req.Uri)
+	if !bidder.circuitBreakers.allow(host) {
+		return &httpCallInfo{
+			request: req,
+			err:     &errortypes.BidderCircuitOpen{Message: fmt.Sprintf("Bidder %s is temporarily not called, too many calls to %s failed", bidder.BidderName, host)},
+		}
 	}
+	httpInfo := bidder.doRequestAttempts(ctx, req, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
+	bidder.circuitBreakers.record(host, circuitBreakerResultOf(httpInfo))
+	return httpInfo
 }
 
 func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
+	httpInfo, times := bidder.doRequestCall(ctx, req, logger, bidderRequestStartTime, tmaxAdjustments)
+	bidder.recordRequestCallTimes(times)
+	return httpInfo
+}
+
+// requestCallTimes are the durations of a call to a bidder reported through metrics. They are recorded apart from
+// the call so the attempts of a retried or hedged call only record the ones of the attempt whose result is used.
+type requestCallTimes struct {
+	sent               bool
+	overheadTime       time.Duration
+	responded          bool
+	serverResponseTime time.Duration
+}
+
+func (bidder *BidderAdapter) recordRequestCallTimes(times requestCallTimes) {
+	if times.sent {
+		bidder.me.RecordOverheadTime(metrics.PreBidder, times.overheadTime)
+	}
+	if times.responded {
+		bidder.me.RecordBidderServerResponseTime(times.serverResponseTime)
+	}
+}
+
+// doRequestCall makes the call to the bidder and logs the health of the bidder, returning the durations to record.
+// A call canceled because another attempt of it won says nothing about the health of the bidder.
+func (bidder *BidderAdapter) doRequestCall(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) (*httpCallInfo, requestCallTimes) {
+	var times requestCallTimes
 	requestBody, err := getRequestBody(req, bidder.config.EndpointCompression)
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
 			err:     err,
-		}
+		}, times
 	}
 	httpReq, err := http.NewRequest(req.Method, req.Uri, requestBody)
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
 			err:     err,
-		}
+		}, times
 	}
 	httpReq.Header = req.Headers
 
@@ -600,7 +650,8 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	if !bidder.config.DisableConnMetrics {
 		ctx = bidder.addClientTrace(ctx, bidder.config.DisableConnDialMetrics)
 	}
-	bidder.me.RecordOverheadTime(metrics.PreBidder, time.Since(bidderRequestStartTime))
+	times.sent = true
+	times.overheadTime = time.Since(bidderRequestStartTime)
 
 	if tmaxAdjustments != nil && tmaxAdjustments.IsEnforced {
 		if hasShorterDurationThanTmax(&bidderTmaxCtx{ctx}, *tmaxAdjustments) {
@@ -608,13 +659,20 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 			return &httpCallInfo{
 				request: req,
 				err:     &errortypes.TmaxTimeout{Message: "exceeded tmax duration"},
-			}
+			}, times
 		}
 	}
 
 	httpCallStart := time.Now()
 	httpResp, err := ctxhttp.Do(ctx, bidder.Client, httpReq)
 	if err != nil {
+		if context.Cause(ctx) == errRequestAttemptLost {
+			// the call was canceled by the exchange once another attempt of it won
+			return &httpCallInfo{
+				request: req,
+				err:     err,
+			}, times
+		}
 		bidder.logHealthCheck(false)
 		if err == context.DeadlineExceeded {
 			err = &errortypes.Timeout{Message: err.Error()}
@@ -636,7 +694,7 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		return &httpCallInfo{
 			request: req,
 			err:     err,
-		}
+		}, times
 	}
 	defer httpResp.Body.Close()
 
@@ -645,7 +703,7 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		return &httpCallInfo{
 			request: req,
 			err:     err,
-		}
+		}, times
 	}
 
 	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 400 {
@@ -658,7 +716,8 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 
 	bidder.logHealthCheck(true)
-	bidder.me.RecordBidderServerResponseTime(time.Since(httpCallStart))
+	times.responded = true
+	times.serverResponseTime = time.Since(httpCallStart)
 	return &httpCallInfo{
 		request: req,
 		response: &adapters.ResponseData{
@@ -667,7 +726,7 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 			Headers:    httpResp.Header,
 		},
 		err: err,
-	}
+	}, times
 }
 
 func (bidder *BidderAdapter) doTimeoutNotification(timeoutBidder adapters.TimeoutBidder, req *adapters.RequestData, logger util.LogMsg) {
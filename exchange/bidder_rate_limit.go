@@ -0,0 +1,58 @@
+package exchange
+
+import (
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ratelimit"
+)
+
+// applyBidderRateLimits removes the requests to the bidders over their rate limit, rejecting their imps with a seat non bid.
+// The rate limit of a bidder is set in its bidder info and can be overridden in the account config, the account then
+// having its own token bucket for the bidder.
+func (e *exchange) applyBidderRateLimits(bidderRequests []BidderRequest, account *config.Account) ([]BidderRequest, SeatNonBidBuilder) {
+	seatNonBidBuilder := SeatNonBidBuilder{}
+	if e.bidderRateLimiter == nil {
+		return bidderRequests, seatNonBidBuilder
+	}
+
+	allowed := make([]BidderRequest, 0, len(bidderRequests))
+	for _, bidderRequest := range bidderRequests {
+		key, limit := e.bidderRateLimit(bidderRequest.BidderName, bidderRequest.BidderCoreName, account)
+		if e.bidderRateLimiter.Allow(key, limit) {
+			allowed = append(allowed, bidderRequest)
+			continue
+		}
+
+		e.me.RecordAdapterRateLimited(bidderRequest.BidderCoreName)
+		impIDs := make([]string, 0, len(bidderRequest.BidRequest.Imp))
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			impIDs = append(impIDs, imp.ID)
+		}
+		seatNonBidBuilder.rejectImps(impIDs, RequestBlockedRateLimited, string(bidderRequest.BidderName))
+	}
+	return allowed, seatNonBidBuilder
+}
+
+// bidderRateLimit returns the token bucket key and the rate limit of a bidder for an account. The rate limit of an
+// alias is looked up by its name first, then by the name of its core bidder, in the account config then in the bidder
+// infos. The alias and its core bidder share a token bucket when the rate limit of the core bidder applies.
+func (e *exchange) bidderRateLimit(bidder, coreBidder openrtb_ext.BidderName, account *config.Account) (string, ratelimit.Limit) {
+	names := []string{string(bidder)}
+	if coreBidder != "" && coreBidder != bidder {
+		names = append(names, string(coreBidder))
+	}
+
+	for _, name := range names {
+		if accountLimit, ok := account.RateLimit.Bidders[name]; ok {
+			key := "account:" + account.ID + ":bidder:" + name
+			return key, ratelimit.Limit{PerSecond: accountLimit.RequestsPerSecond, Burst: accountLimit.Burst}
+		}
+	}
+
+	for _, name := range names {
+		if info, ok := e.bidderInfo[name]; ok && info.RateLimit != nil {
+			return "bidder:" + name, ratelimit.Limit{PerSecond: info.RateLimit.RequestsPerSecond, Burst: info.RateLimit.Burst}
+		}
+	}
+	return "bidder:" + string(bidder), ratelimit.Limit{}
+}
@@ -0,0 +1,156 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestApplyBidderRateLimits(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterRateLimited", mock.Anything).Return()
+
+	e := &exchange{
+		me: me,
+		bidderInfo: config.BidderInfos{
+			"appnexus": {RateLimit: &config.RateLimitInfo{RequestsPerSecond: 1}},
+			"rubicon":  {},
+		},
+		bidderRateLimiter: ratelimit.NewLimiter(),
+	}
+	bidderRequests := []BidderRequest{
+		{
+			BidderName:     "appnexus",
+			BidderCoreName: openrtb_ext.BidderAppnexus,
+			BidRequest:     &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}}},
+		},
+		{
+			BidderName:     "rubicon",
+			BidderCoreName: openrtb_ext.BidderRubicon,
+			BidRequest:     &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}},
+		},
+	}
+	account := &config.Account{ID: "account1"}
+
+	allowed, seatNonBids := e.applyBidderRateLimits(bidderRequests, account)
+	assert.Len(t, allowed, 2)
+	assert.Empty(t, seatNonBids)
+
+	allowed, seatNonBids = e.applyBidderRateLimits(bidderRequests, account)
+	assert.Equal(t, []BidderRequest{bidderRequests[1]}, allowed)
+	assert.Equal(t, SeatNonBidBuilder{
+		"appnexus": {
+			{ImpId: "imp1", StatusCode: int(RequestBlockedRateLimited)},
+			{ImpId: "imp2", StatusCode: int(RequestBlockedRateLimited)},
+		},
+	}, seatNonBids)
+	me.AssertCalled(t, "RecordAdapterRateLimited", openrtb_ext.BidderAppnexus)
+	me.AssertNumberOfCalls(t, "RecordAdapterRateLimited", 1)
+
+	// an account overriding the rate limit of the bidder has its own bucket
+	overridingAccount := &config.Account{
+		ID: "account2",
+		RateLimit: config.AccountRateLimit{
+			Bidders: map[string]config.AccountBidderRateLimit{
+				"appnexus": {RequestsPerSecond: 1, Burst: 2},
+				"rubicon":  {RequestsPerSecond: 1},
+			},
+		},
+	}
+	allowed, _ = e.applyBidderRateLimits(bidderRequests, overridingAccount)
+	assert.Len(t, allowed, 2)
+	allowed, _ = e.applyBidderRateLimits(bidderRequests, overridingAccount)
+	assert.Equal(t, []BidderRequest{bidderRequests[0]}, allowed)
+	me.AssertCalled(t, "RecordAdapterRateLimited", openrtb_ext.BidderRubicon)
+}
+
+func TestBidderRateLimit(t *testing.T) {
+	e := &exchange{
+		bidderInfo: config.BidderInfos{
+			"appnexus":      {RateLimit: &config.RateLimitInfo{RequestsPerSecond: 100, Burst: 200}},
+			"appnexusAlias": {RateLimit: &config.RateLimitInfo{RequestsPerSecond: 50}},
+			"rubicon":       {},
+		},
+	}
+	account := &config.Account{
+		ID: "account1",
+		RateLimit: config.AccountRateLimit{
+			Bidders: map[string]config.AccountBidderRateLimit{
+				"openx":      {RequestsPerSecond: 10},
+				"openxAlias": {RequestsPerSecond: 5},
+			},
+		},
+	}
+
+	testCases := []struct {
+		description   string
+		bidder        openrtb_ext.BidderName
+		coreBidder    openrtb_ext.BidderName
+		expectedKey   string
+		expectedLimit ratelimit.Limit
+	}{
+		{
+			description:   "bidder-info",
+			bidder:        openrtb_ext.BidderAppnexus,
+			coreBidder:    openrtb_ext.BidderAppnexus,
+			expectedKey:   "bidder:appnexus",
+			expectedLimit: ratelimit.Limit{PerSecond: 100, Burst: 200},
+		},
+		{
+			description:   "alias-bidder-info",
+			bidder:        "appnexusAlias",
+			coreBidder:    openrtb_ext.BidderAppnexus,
+			expectedKey:   "bidder:appnexusAlias",
+			expectedLimit: ratelimit.Limit{PerSecond: 50},
+		},
+		{
+			description:   "alias-falling-back-to-core-bidder-info",
+			bidder:        "appnexusRequestAlias",
+			coreBidder:    openrtb_ext.BidderAppnexus,
+			expectedKey:   "bidder:appnexus",
+			expectedLimit: ratelimit.Limit{PerSecond: 100, Burst: 200},
+		},
+		{
+			description:   "account",
+			bidder:        openrtb_ext.BidderOpenx,
+			coreBidder:    openrtb_ext.BidderOpenx,
+			expectedKey:   "account:account1:bidder:openx",
+			expectedLimit: ratelimit.Limit{PerSecond: 10},
+		},
+		{
+			description:   "alias-account",
+			bidder:        "openxAlias",
+			coreBidder:    openrtb_ext.BidderOpenx,
+			expectedKey:   "account:account1:bidder:openxAlias",
+			expectedLimit: ratelimit.Limit{PerSecond: 5},
+		},
+		{
+			description:   "alias-falling-back-to-core-bidder-account",
+			bidder:        "openxRequestAlias",
+			coreBidder:    openrtb_ext.BidderOpenx,
+			expectedKey:   "account:account1:bidder:openx",
+			expectedLimit: ratelimit.Limit{PerSecond: 10},
+		},
+		{
+			description:   "no-limit",
+			bidder:        "rubiconAlias",
+			coreBidder:    openrtb_ext.BidderRubicon,
+			expectedKey:   "bidder:rubiconAlias",
+			expectedLimit: ratelimit.Limit{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			key, limit := e.bidderRateLimit(test.bidder, test.coreBidder, account)
+			assert.Equal(t, test.expectedKey, key)
+			assert.Equal(t, test.expectedLimit, limit)
+		})
+	}
+}
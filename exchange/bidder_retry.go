@@ -0,0 +1,173 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/config/util"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/metrics"
+)
+
+const (
+	// responseTimeSamples is the number of recent response times of a bidder the hedging delay is computed from
+	responseTimeSamples = 200
+	// minResponseTimeSamples is the number of response times needed before hedging a call
+	minResponseTimeSamples = 20
+)
+
+type bidderAdapterRetryConfig struct {
+	// retryOnError sends a call once more when it fails with a connection error or a 5xx status
+	retryOnError bool
+	// hedgePercentile sends a second call when the first has not been responded to after this percentile of the response times
+	hedgePercentile int
+}
+
+func newBidderAdapterRetryConfig(info *config.RequestRetryInfo) bidderAdapterRetryConfig {
+	if info == nil || !info.Idempotent {
+		return bidderAdapterRetryConfig{}
+	}
+	return bidderAdapterRetryConfig{
+		retryOnError:    info.RetryOnError,
+		hedgePercentile: info.HedgePercentile,
+	}
+}
+
+func (c bidderAdapterRetryConfig) enabled() bool {
+	return c.retryOnError || c.hedgePercentile > 0
+}
+
+// responseTimes keeps the recent response times of a bidder
+type responseTimes struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func (r *responseTimes) add(duration time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.samples) < responseTimeSamples {
+		r.samples = append(r.samples, duration)
+		return
+	}
+	r.samples[r.next] = duration
+	r.next = (r.next + 1) % responseTimeSamples
+}
+
+// percentile returns the percentile of the recent response times, false while there are not enough of them
+func (r *responseTimes) percentile(percentile int) (time.Duration, bool) {
+	r.mutex.Lock()
+	if len(r.samples) < minResponseTimeSamples {
+		r.mutex.Unlock()
+		return 0, false
+	}
+	sorted := make([]time.Duration, len(r.samples))
+	copy(sorted, r.samples)
+	r.mutex.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)-1)*percentile/100], true
+}
+
+// errRequestAttemptLost is the cause the attempts of a call still running once another attempt won are canceled with
+var errRequestAttemptLost = errors.New("another attempt of the call won")
+
+type requestAttemptResult struct {
+	attempt  metrics.RequestAttempt
+	httpInfo *httpCallInfo
+	times    requestCallTimes
+}
+
+// doRequestAttempts makes the call to the bidder, retried once when it fails with a connection error or a 5xx status
+// and hedged with a second call when the response takes longer than usual, as configured for the bidder.
+// The calls are only sent again while the bidder has time left to respond, the first successful response winning.
+// Only the metrics of the attempt whose result is returned are recorded.
+func (bidder *BidderAdapter) doRequestAttempts(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
+	retryConfig := bidder.config.RetryConfig
+	if !retryConfig.enabled() {
+		return bidder.doRequestImpl(ctx, req, logger, bidderRequestStartTime, tmaxAdjustments)
+	}
+
+	// the calls still running once a response won are canceled
+	attemptCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(errRequestAttemptLost)
+
+	// up to three attempts: the first call, its hedge and a retry
+	results := make(chan requestAttemptResult, 3)
+	send := func(attempt metrics.RequestAttempt) {
+		go func() {
+			start := time.Now()
+			httpInfo, times := bidder.doRequestCall(attemptCtx, req, logger, bidderRequestStartTime, tmaxAdjustments)
+			if httpInfo.response != nil {
+				bidder.responseTimes.add(time.Since(start))
+			}
+			results <- requestAttemptResult{attempt: attempt, httpInfo: httpInfo, times: times}
+		}()
+	}
+
+	send(metrics.RequestAttemptFirst)
+	pending := 1
+
+	var hedge <-chan time.Time
+	if retryConfig.hedgePercentile > 0 {
+		if delay, ok := bidder.responseTimes.percentile(retryConfig.hedgePercentile); ok {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			hedge = timer.C
+		}
+	}
+
+	retried := false
+	var last requestAttemptResult
+	for pending > 0 {
+		select {
+		case <-hedge:
+			hedge = nil
+			if hasTimeForAnotherAttempt(&bidderTmaxCtx{ctx}, tmaxAdjustments) {
+				send(metrics.RequestAttemptHedge)
+				pending++
+			}
+		case result := <-results:
+			pending--
+			if !shouldRetryRequest(result.httpInfo) {
+				bidder.recordRequestCallTimes(result.times)
+				if result.httpInfo.response != nil {
+					bidder.me.RecordAdapterRequestWin(bidder.BidderName, result.attempt)
+				}
+				return result.httpInfo
+			}
+			last = result
+			if pending == 0 && retryConfig.retryOnError && !retried && hasTimeForAnotherAttempt(&bidderTmaxCtx{ctx}, tmaxAdjustments) {
+				retried = true
+				hedge = nil
+				send(metrics.RequestAttemptRetry)
+				pending++
+			}
+		}
+	}
+	bidder.recordRequestCallTimes(last.times)
+	return last.httpInfo
+}
+
+// shouldRetryRequest returns whether a call failed with a connection error or a 5xx status.
+// A call timing out is not retried, there is no time left for another one.
+func shouldRetryRequest(httpInfo *httpCallInfo) bool {
+	if httpInfo.response != nil {
+		return httpInfo.response.StatusCode >= 500
+	}
+	if httpInfo.err == nil {
+		return false
+	}
+	switch errortypes.ReadCode(httpInfo.err) {
+	case errortypes.TimeoutErrorCode, errortypes.TmaxTimeoutErrorCode:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,319 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newRequestRetryConfig returns the host config setting the request retries of appnexus
+func newRequestRetryConfig(requestRetry *config.RequestRetryInfo) *config.Configuration {
+	return &config.Configuration{BidderInfos: config.BidderInfos{"appnexus": {RequestRetry: requestRetry}}}
+}
+
+func TestNewBidderAdapterRetryConfig(t *testing.T) {
+	assert.Equal(t, bidderAdapterRetryConfig{}, newBidderAdapterRetryConfig(nil))
+	assert.Equal(t, bidderAdapterRetryConfig{}, newBidderAdapterRetryConfig(&config.RequestRetryInfo{Idempotent: false, RetryOnError: true, HedgePercentile: 95}))
+	assert.Equal(t, bidderAdapterRetryConfig{retryOnError: true, hedgePercentile: 95}, newBidderAdapterRetryConfig(&config.RequestRetryInfo{Idempotent: true, RetryOnError: true, HedgePercentile: 95}))
+}
+
+func TestDoRequestAttemptsRetry(t *testing.T) {
+	testCases := []struct {
+		description       string
+		requestRetry      *config.RequestRetryInfo
+		statusCodes       []int
+		expectedCalls     int32
+		expectedStatus    int
+		expectedWinMetric metrics.RequestAttempt
+	}{
+		{
+			description:       "retried-server-error",
+			requestRetry:      &config.RequestRetryInfo{Idempotent: true, RetryOnError: true},
+			statusCodes:       []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedCalls:     2,
+			expectedStatus:    http.StatusOK,
+			expectedWinMetric: metrics.RequestAttemptRetry,
+		},
+		{
+			description:    "retried-once",
+			requestRetry:   &config.RequestRetryInfo{Idempotent: true, RetryOnError: true},
+			statusCodes:    []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectedCalls:  2,
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			description:       "success-not-retried",
+			requestRetry:      &config.RequestRetryInfo{Idempotent: true, RetryOnError: true},
+			statusCodes:       []int{http.StatusOK},
+			expectedCalls:     1,
+			expectedStatus:    http.StatusOK,
+			expectedWinMetric: metrics.RequestAttemptFirst,
+		},
+		{
+			description:       "bad-request-not-retried",
+			requestRetry:      &config.RequestRetryInfo{Idempotent: true, RetryOnError: true},
+			statusCodes:       []int{http.StatusBadRequest, http.StatusOK},
+			expectedCalls:     1,
+			expectedStatus:    http.StatusBadRequest,
+			expectedWinMetric: metrics.RequestAttemptFirst,
+		},
+		{
+			description:    "not-idempotent",
+			requestRetry:   &config.RequestRetryInfo{Idempotent: false, RetryOnError: true},
+			statusCodes:    []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedCalls:  1,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			description:    "no-retry",
+			statusCodes:    []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedCalls:  1,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := calls.Add(1)
+				w.WriteHeader(test.statusCodes[call-1])
+			}))
+			defer server.Close()
+
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordOverheadTime", mock.Anything, mock.Anything).Return()
+			me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+			me.On("RecordAdapterRequestWin", mock.Anything, mock.Anything).Return()
+
+			bidder := AdaptBidder(nil, server.Client(), newRequestRetryConfig(test.requestRetry), me, openrtb_ext.BidderAppnexus, nil, "").(*BidderAdapter)
+			bidder.config.DisableConnMetrics = true
+
+			httpInfo := bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}")}, time.Now(), nil)
+
+			require.NotNil(t, httpInfo.response)
+			assert.Equal(t, test.expectedStatus, httpInfo.response.StatusCode)
+			assert.Equal(t, test.expectedCalls, calls.Load())
+			if test.expectedWinMetric != "" {
+				me.AssertCalled(t, "RecordAdapterRequestWin", openrtb_ext.BidderAppnexus, test.expectedWinMetric)
+				me.AssertNumberOfCalls(t, "RecordAdapterRequestWin", 1)
+			} else {
+				me.AssertNotCalled(t, "RecordAdapterRequestWin", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDoRequestAttemptsRetryConnectionError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// drop the connection without responding
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordOverheadTime", mock.Anything, mock.Anything).Return()
+	me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+	me.On("RecordAdapterRequestWin", mock.Anything, mock.Anything).Return()
+
+	bidder := AdaptBidder(nil, server.Client(), newRequestRetryConfig(&config.RequestRetryInfo{Idempotent: true, RetryOnError: true}), me, openrtb_ext.BidderAppnexus, nil, "").(*BidderAdapter)
+	bidder.config.DisableConnMetrics = true
+
+	httpInfo := bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}")}, time.Now(), nil)
+
+	assert.NoError(t, httpInfo.err)
+	require.NotNil(t, httpInfo.response)
+	assert.Equal(t, http.StatusNoContent, httpInfo.response.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+	me.AssertCalled(t, "RecordAdapterRequestWin", openrtb_ext.BidderAppnexus, metrics.RequestAttemptRetry)
+}
+
+func TestDoRequestAttemptsHedge(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordOverheadTime", mock.Anything, mock.Anything).Return()
+	me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+	me.On("RecordAdapterRequestWin", mock.Anything, mock.Anything).Return()
+
+	bidder := AdaptBidder(nil, server.Client(), newRequestRetryConfig(&config.RequestRetryInfo{Idempotent: true, HedgePercentile: 90}), me, openrtb_ext.BidderAppnexus, nil, "").(*BidderAdapter)
+	bidder.config.DisableConnMetrics = true
+	for i := 0; i < minResponseTimeSamples; i++ {
+		bidder.responseTimes.add(10 * time.Millisecond)
+	}
+
+	start := time.Now()
+	httpInfo := bidder.doRequestAttempts(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}")}, glog.Warningf, time.Now(), nil)
+
+	require.NotNil(t, httpInfo.response)
+	assert.Equal(t, http.StatusNoContent, httpInfo.response.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(2), calls.Load())
+	me.AssertCalled(t, "RecordAdapterRequestWin", openrtb_ext.BidderAppnexus, metrics.RequestAttemptHedge)
+}
+
+func TestDoRequestAttemptsHedgeLoserCanceled(t *testing.T) {
+	var calls atomic.Int32
+	loserCanceled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// the server only notices the call is canceled once it read its body
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+			close(loserCanceled)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordOverheadTime", mock.Anything, mock.Anything).Return()
+	me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+	me.On("RecordAdapterRequestWin", mock.Anything, mock.Anything).Return()
+
+	bidder := AdaptBidder(nil, server.Client(), newRequestRetryConfig(&config.RequestRetryInfo{Idempotent: true, HedgePercentile: 90}), me, openrtb_ext.BidderAppnexus, nil, "").(*BidderAdapter)
+	bidder.config.DisableConnMetrics = true
+	bidder.config.ThrottleConfig.enabled = true
+	for i := 0; i < minResponseTimeSamples; i++ {
+		bidder.responseTimes.add(10 * time.Millisecond)
+	}
+
+	httpInfo := bidder.doRequestAttempts(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}")}, glog.Warningf, time.Now(), nil)
+
+	require.NotNil(t, httpInfo.response)
+	assert.Equal(t, http.StatusNoContent, httpInfo.response.StatusCode)
+
+	select {
+	case <-loserCanceled:
+	case <-time.After(time.Second):
+		require.Fail(t, "the first call was not canceled once the hedge won")
+	}
+	assert.Never(t, func() bool { return bidder.getHealth() != 0 }, 100*time.Millisecond, 5*time.Millisecond, "the canceled call must not affect the health of the bidder")
+	me.AssertNumberOfCalls(t, "RecordOverheadTime", 1)
+	me.AssertNumberOfCalls(t, "RecordBidderServerResponseTime", 1)
+	me.AssertCalled(t, "RecordAdapterRequestWin", openrtb_ext.BidderAppnexus, metrics.RequestAttemptHedge)
+}
+
+func TestDoRequestAttemptsNoHedgeWithoutResponseTimes(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordOverheadTime", mock.Anything, mock.Anything).Return()
+	me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+	me.On("RecordAdapterRequestWin", mock.Anything, mock.Anything).Return()
+
+	bidder := AdaptBidder(nil, server.Client(), newRequestRetryConfig(&config.RequestRetryInfo{Idempotent: true, HedgePercentile: 50}), me, openrtb_ext.BidderAppnexus, nil, "").(*BidderAdapter)
+	bidder.config.DisableConnMetrics = true
+
+	httpInfo := bidder.doRequestAttempts(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}")}, glog.Warningf, time.Now(), nil)
+
+	require.NotNil(t, httpInfo.response)
+	assert.Equal(t, int32(1), calls.Load())
+	me.AssertCalled(t, "RecordAdapterRequestWin", openrtb_ext.BidderAppnexus, metrics.RequestAttemptFirst)
+	assert.Len(t, bidder.responseTimes.samples, 1)
+}
+
+func TestResponseTimesPercentile(t *testing.T) {
+	r := &responseTimes{}
+	for i := 1; i < minResponseTimeSamples; i++ {
+		r.add(time.Duration(i) * time.Millisecond)
+	}
+	_, ok := r.percentile(50)
+	assert.False(t, ok, "not enough samples")
+
+	r.add(20 * time.Millisecond)
+	percentile, ok := r.percentile(50)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, percentile)
+	percentile, _ = r.percentile(99)
+	assert.Equal(t, 19*time.Millisecond, percentile)
+
+	// the oldest samples are replaced
+	for i := 0; i < responseTimeSamples; i++ {
+		r.add(100 * time.Millisecond)
+	}
+	assert.Len(t, r.samples, responseTimeSamples)
+	percentile, _ = r.percentile(1)
+	assert.Equal(t, 100*time.Millisecond, percentile)
+}
+
+func TestShouldRetryRequest(t *testing.T) {
+	testCases := []struct {
+		description string
+		httpInfo    *httpCallInfo
+		expected    bool
+	}{
+		{
+			description: "success",
+			httpInfo:    &httpCallInfo{response: &adapters.ResponseData{StatusCode: http.StatusOK}},
+			expected:    false,
+		},
+		{
+			description: "server-error",
+			httpInfo:    &httpCallInfo{response: &adapters.ResponseData{StatusCode: http.StatusInternalServerError}},
+			expected:    true,
+		},
+		{
+			description: "connection-error",
+			httpInfo:    &httpCallInfo{err: &http.ProtocolError{ErrorString: "connection reset"}},
+			expected:    true,
+		},
+		{
+			description: "timeout",
+			httpInfo:    &httpCallInfo{err: &errortypes.Timeout{}},
+			expected:    false,
+		},
+		{
+			description: "tmax-timeout",
+			httpInfo:    &httpCallInfo{err: &errortypes.TmaxTimeout{}},
+			expected:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, shouldRetryRequest(test.httpInfo))
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/capture"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/errortypes"
@@ -36,11 +37,13 @@ import (
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/prebid/prebid-server/v3/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // TestSingleBidder makes sure that the following things work if the Bidder needs only one request.
@@ -172,6 +175,82 @@ func TestSingleBidder(t *testing.T) {
 	}
 }
 
+func TestRequestBidCapture(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", `{"seatbid":[]}`))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method:  "POST",
+			Uri:     server.URL,
+			Body:    []byte(`{"imp":[{"id":"impId"}],"user":{"buyeruid":"buyer-id"}}`),
+			Headers: http.Header{},
+		},
+		bidResponse: &adapters.BidderResponse{},
+	}
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	currencyConverter := currency.NewRateConverter(&http.Client{}, time.Duration(1), "", time.Duration(0))
+
+	request := &openrtb2.BidRequest{ID: "request-id", Imp: []openrtb2.Imp{{ID: "impId"}}}
+	capturedAuction, err := capture.NewAuction(&openrtb_ext.RequestWrapper{BidRequest: request}, "account-id", privacy.IPConf{})
+	require.NoError(t, err)
+
+	bidderReq := BidderRequest{
+		BidRequest: request,
+		BidderName: "test",
+	}
+	bidReqOptions := bidRequestOptions{capture: capturedAuction}
+	_, _, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+	assert.Empty(t, errs)
+
+	require.Len(t, capturedAuction.BidderCalls, 1)
+	call := capturedAuction.BidderCalls[0]
+	assert.Equal(t, "test", call.Bidder)
+	assert.Equal(t, "POST", call.Method)
+	assert.Equal(t, server.URL, call.URI)
+	assert.JSONEq(t, `{"id":"","imp":[{"id":"impId"}],"user":{}}`, string(call.RequestBody))
+	assert.Equal(t, 200, call.Status)
+	assert.Equal(t, `{"seatbid":[]}`, call.ResponseBody)
+}
+
+func TestRequestBidCircuitBreaker(t *testing.T) {
+	server := httptest.NewServer(mockHandler(http.StatusServiceUnavailable, "getBody", ""))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method:  "POST",
+			Uri:     server.URL,
+			Body:    []byte(`{"imp":[{"id":"impId"}]}`),
+			Headers: http.Header{},
+			ImpIDs:  []string{"impId"},
+		},
+		bidResponse: &adapters.BidderResponse{},
+	}
+	cfg := &config.Configuration{BidderInfos: config.BidderInfos{
+		"appnexus": {CircuitBreaker: &config.CircuitBreakerInfo{Enabled: true, MinRequests: 1, ErrorRate: 1}},
+	}}
+	bidder := AdaptBidder(bidderImpl, server.Client(), cfg, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	currencyConverter := currency.NewRateConverter(&http.Client{}, time.Duration(1), "", time.Duration(0))
+
+	bidderReq := BidderRequest{
+		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+		BidderName: "test",
+	}
+
+	// the server error opens the breaker
+	_, extraInfo, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+	require.Len(t, errs, 1)
+	assert.IsType(t, &errortypes.BadServerResponse{}, errs[0])
+	assert.Equal(t, SeatNonBidBuilder{"test": {{ImpId: "impId", StatusCode: int(ErrorGeneral)}}}, extraInfo.seatNonBidBuilder)
+
+	// the bidder is no longer called
+	_, extraInfo, errs = bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+	require.Len(t, errs, 1)
+	assert.IsType(t, &errortypes.BidderCircuitOpen{}, errs[0])
+	assert.Equal(t, SeatNonBidBuilder{"test": {{ImpId: "impId", StatusCode: int(RequestBlockedGeneral)}}}, extraInfo.seatNonBidBuilder)
+}
+
 func TestSingleBidderGzip(t *testing.T) {
 	type aTest struct {
 		debugInfo    *config.DebugInfo
@@ -0,0 +1,303 @@
+package exchange
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// Default values of the circuit breaker config when not set in the bidder info
+const (
+	defaultCircuitBreakerWindow           = 10 * time.Second
+	defaultCircuitBreakerMinRequests      = 20
+	defaultCircuitBreakerErrorRate        = 0.5
+	defaultCircuitBreakerOpenDuration     = 30 * time.Second
+	defaultCircuitBreakerHalfOpenRequests = 5
+)
+
+// maxCircuitBreakerHosts bounds the number of endpoint hosts a bidder keeps a circuit breaker for, the hosts of its
+// calls being expanded from the endpoint template and possibly from the request
+const maxCircuitBreakerHosts = 100
+
+// circuitBreakerResult is the outcome of a call to a bidder endpoint as far as its circuit breaker is concerned
+type circuitBreakerResult int
+
+const (
+	// circuitBreakerSuccess is a call the endpoint responded to without a 5xx status
+	circuitBreakerSuccess circuitBreakerResult = iota
+	// circuitBreakerFailure is a call failing with an error, a timeout or a 5xx status
+	circuitBreakerFailure
+	// circuitBreakerIgnored is a call not made for reasons unrelated to the health of the endpoint
+	circuitBreakerIgnored
+)
+
+type circuitBreakerConfig struct {
+	window           time.Duration
+	minRequests      int
+	errorRate        float64
+	openDuration     time.Duration
+	halfOpenRequests int
+}
+
+// circuitBreakers holds a circuit breaker for each endpoint host of a bidder, stopping the calls to a host once too
+// many of them failed. The least recently used breaker, preferably a closed one, is dropped to make room for a new host
+// once there are maxCircuitBreakerHosts of them. The state recorded in the metrics is the most severe one of the hosts.
+// All the methods of a nil circuitBreakers, used when the breaker is disabled, allow every call.
+type circuitBreakers struct {
+	bidder openrtb_ext.BidderName
+	config circuitBreakerConfig
+	me     metrics.MetricsEngine
+	now    func() time.Time
+
+	mutex    sync.Mutex
+	breakers map[string]*circuitBreaker
+	// state is the most severe state of the breakers, as last recorded in the metrics
+	state metrics.CircuitBreakerState
+}
+
+type circuitBreaker struct {
+	state    metrics.CircuitBreakerState
+	openedAt time.Time
+	lastUsed time.Time
+	// buckets count the calls of each second of the window, indexed by the second modulo the number of buckets
+	buckets []circuitBreakerBucket
+	// trials and trialSuccesses count the calls allowed and succeeded while half open
+	trials         int
+	trialSuccesses int
+}
+
+type circuitBreakerBucket struct {
+	second   int64
+	requests int
+	failures int
+}
+
+func newCircuitBreakers(info *config.CircuitBreakerInfo, bidder openrtb_ext.BidderName, me metrics.MetricsEngine) *circuitBreakers {
+	if info == nil || !info.Enabled {
+		return nil
+	}
+
+	cfg := circuitBreakerConfig{
+		window:           time.Duration(info.WindowSeconds) * time.Second,
+		minRequests:      info.MinRequests,
+		errorRate:        info.ErrorRate,
+		openDuration:     time.Duration(info.OpenSeconds) * time.Second,
+		halfOpenRequests: info.HalfOpenRequests,
+	}
+	if cfg.window <= 0 {
+		cfg.window = defaultCircuitBreakerWindow
+	}
+	if cfg.minRequests <= 0 {
+		cfg.minRequests = defaultCircuitBreakerMinRequests
+	}
+	if cfg.errorRate <= 0 {
+		cfg.errorRate = defaultCircuitBreakerErrorRate
+	}
+	if cfg.openDuration <= 0 {
+		cfg.openDuration = defaultCircuitBreakerOpenDuration
+	}
+	if cfg.halfOpenRequests <= 0 {
+		cfg.halfOpenRequests = defaultCircuitBreakerHalfOpenRequests
+	}
+
+	me.RecordAdapterCircuitBreakerState(bidder, metrics.CircuitBreakerClosed)
+
+	return &circuitBreakers{
+		bidder:   bidder,
+		config:   cfg,
+		me:       me,
+		now:      time.Now,
+		breakers: make(map[string]*circuitBreaker),
+		state:    metrics.CircuitBreakerClosed,
+	}
+}
+
+// allow returns whether a call to the host can be made, counting it as a trial call when the breaker is half open.
+// Every allowed call must be followed by a call to record with its result.
+func (cb *circuitBreakers) allow(host string) bool {
+	if cb == nil {
+		return true
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.now()
+	breaker := cb.breaker(host, now)
+	switch breaker.state {
+	case metrics.CircuitBreakerOpen:
+		if now.Sub(breaker.openedAt) < cb.config.openDuration {
+			return false
+		}
+		breaker.trials = 0
+		breaker.trialSuccesses = 0
+		cb.setState(breaker, metrics.CircuitBreakerHalfOpen)
+		fallthrough
+	case metrics.CircuitBreakerHalfOpen:
+		if breaker.trials >= cb.config.halfOpenRequests {
+			return false
+		}
+		breaker.trials++
+	}
+	return true
+}
+
+// record registers the result of a call to the host, opening or closing the breaker accordingly
+func (cb *circuitBreakers) record(host string, result circuitBreakerResult) {
+	if cb == nil {
+		return
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.now()
+	breaker := cb.breaker(host, now)
+	switch breaker.state {
+	case metrics.CircuitBreakerClosed:
+		if result == circuitBreakerIgnored {
+			return
+		}
+		requests, failures := breaker.count(now, result == circuitBreakerFailure)
+		if requests >= cb.config.minRequests && float64(failures) >= cb.config.errorRate*float64(requests) {
+			breaker.openedAt = now
+			cb.setState(breaker, metrics.CircuitBreakerOpen)
+		}
+	case metrics.CircuitBreakerHalfOpen:
+		switch result {
+		case circuitBreakerFailure:
+			breaker.openedAt = now
+			cb.setState(breaker, metrics.CircuitBreakerOpen)
+		case circuitBreakerSuccess:
+			breaker.trialSuccesses++
+			if breaker.trialSuccesses >= cb.config.halfOpenRequests {
+				breaker.buckets = make([]circuitBreakerBucket, len(breaker.buckets))
+				cb.setState(breaker, metrics.CircuitBreakerClosed)
+			}
+		case circuitBreakerIgnored:
+			// the trial call was not made, another one is allowed in its place
+			breaker.trials--
+		}
+	}
+}
+
+// breaker returns the breaker of the host, making room for it when there are already maxCircuitBreakerHosts of them
+func (cb *circuitBreakers) breaker(host string, now time.Time) *circuitBreaker {
+	breaker, ok := cb.breakers[host]
+	if !ok {
+		if len(cb.breakers) >= maxCircuitBreakerHosts {
+			cb.evict()
+		}
+		buckets := int(cb.config.window / time.Second)
+		if buckets < 1 {
+			buckets = 1
+		}
+		breaker = &circuitBreaker{
+			state:   metrics.CircuitBreakerClosed,
+			buckets: make([]circuitBreakerBucket, buckets),
+		}
+		cb.breakers[host] = breaker
+	}
+	breaker.lastUsed = now
+	return breaker
+}
+
+// evict drops the least recently used breaker, a closed one if any
+func (cb *circuitBreakers) evict() {
+	var evicted string
+	var evictedBreaker *circuitBreaker
+	for host, breaker := range cb.breakers {
+		if evictedBreaker == nil || breaker.evictedBefore(evictedBreaker) {
+			evicted, evictedBreaker = host, breaker
+		}
+	}
+	delete(cb.breakers, evicted)
+	cb.recordState()
+}
+
+func (b *circuitBreaker) evictedBefore(other *circuitBreaker) bool {
+	closed, otherClosed := b.state == metrics.CircuitBreakerClosed, other.state == metrics.CircuitBreakerClosed
+	if closed != otherClosed {
+		return closed
+	}
+	return b.lastUsed.Before(other.lastUsed)
+}
+
+func (cb *circuitBreakers) setState(breaker *circuitBreaker, state metrics.CircuitBreakerState) {
+	if breaker.state == state {
+		return
+	}
+	breaker.state = state
+	cb.recordState()
+}
+
+// recordState records the most severe state of the breakers in the metrics when it changed
+func (cb *circuitBreakers) recordState() {
+	state := metrics.CircuitBreakerClosed
+	for _, breaker := range cb.breakers {
+		switch breaker.state {
+		case metrics.CircuitBreakerOpen:
+			state = metrics.CircuitBreakerOpen
+		case metrics.CircuitBreakerHalfOpen:
+			if state == metrics.CircuitBreakerClosed {
+				state = metrics.CircuitBreakerHalfOpen
+			}
+		}
+	}
+	if cb.state == state {
+		return
+	}
+	cb.state = state
+	cb.me.RecordAdapterCircuitBreakerState(cb.bidder, state)
+}
+
+// count adds a call to the bucket of the current second and returns the calls and failures over the window
+func (b *circuitBreaker) count(now time.Time, failure bool) (requests, failures int) {
+	second := now.Unix()
+	bucket := &b.buckets[int(second%int64(len(b.buckets)))]
+	if bucket.second != second {
+		*bucket = circuitBreakerBucket{second: second}
+	}
+	bucket.requests++
+	if failure {
+		bucket.failures++
+	}
+
+	oldest := second - int64(len(b.buckets))
+	for _, bucket := range b.buckets {
+		if bucket.second > oldest {
+			requests += bucket.requests
+			failures += bucket.failures
+		}
+	}
+	return requests, failures
+}
+
+// circuitBreakerHost returns the host of the endpoint a call is made to, each host having its own breaker
+func circuitBreakerHost(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Host == "" {
+		return uri
+	}
+	return parsed.Host
+}
+
+// circuitBreakerResultOf classifies the result of a call to a bidder endpoint
+func circuitBreakerResultOf(httpInfo *httpCallInfo) circuitBreakerResult {
+	if httpInfo.response != nil {
+		if httpInfo.response.StatusCode >= 500 {
+			return circuitBreakerFailure
+		}
+		return circuitBreakerSuccess
+	}
+	if _, ok := httpInfo.err.(*errortypes.TmaxTimeout); ok || httpInfo.err == nil {
+		// the call was not made, there was not enough time left for the bidder to respond
+		return circuitBreakerIgnored
+	}
+	return circuitBreakerFailure
+}
@@ -0,0 +1,259 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewCircuitBreaker(t *testing.T) {
+	testCases := []struct {
+		description    string
+		info           *config.CircuitBreakerInfo
+		expectedConfig *circuitBreakerConfig
+	}{
+		{
+			description: "nil",
+		},
+		{
+			description: "disabled",
+			info:        &config.CircuitBreakerInfo{Enabled: false, MinRequests: 10},
+		},
+		{
+			description: "defaults",
+			info:        &config.CircuitBreakerInfo{Enabled: true},
+			expectedConfig: &circuitBreakerConfig{
+				window:           10 * time.Second,
+				minRequests:      20,
+				errorRate:        0.5,
+				openDuration:     30 * time.Second,
+				halfOpenRequests: 5,
+			},
+		},
+		{
+			description: "configured",
+			info:        &config.CircuitBreakerInfo{Enabled: true, WindowSeconds: 5, MinRequests: 10, ErrorRate: 0.8, OpenSeconds: 60, HalfOpenRequests: 2},
+			expectedConfig: &circuitBreakerConfig{
+				window:           5 * time.Second,
+				minRequests:      10,
+				errorRate:        0.8,
+				openDuration:     60 * time.Second,
+				halfOpenRequests: 2,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cb := newCircuitBreakers(test.info, openrtb_ext.BidderAppnexus, &metricsConfig.NilMetricsEngine{})
+
+			if test.expectedConfig == nil {
+				assert.Nil(t, cb)
+				assert.True(t, cb.allow("bidder.com"))
+				assert.NotPanics(t, func() { cb.record("bidder.com", circuitBreakerFailure) })
+				return
+			}
+			assert.Equal(t, *test.expectedConfig, cb.config)
+			assert.Equal(t, metrics.CircuitBreakerClosed, cb.state)
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterCircuitBreakerState", mock.Anything, mock.Anything).Return()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreakers(&config.CircuitBreakerInfo{Enabled: true, WindowSeconds: 10, MinRequests: 4, ErrorRate: 0.5, OpenSeconds: 30, HalfOpenRequests: 2}, openrtb_ext.BidderAppnexus, me)
+	cb.now = func() time.Time { return now }
+
+	call := func(result circuitBreakerResult) bool {
+		if !cb.allow("bidder.com") {
+			return false
+		}
+		cb.record("bidder.com", result)
+		return true
+	}
+
+	// not enough calls to open
+	assert.True(t, call(circuitBreakerFailure))
+	assert.True(t, call(circuitBreakerFailure))
+	assert.True(t, call(circuitBreakerFailure))
+	assert.Equal(t, metrics.CircuitBreakerClosed, cb.state)
+
+	// the failures older than the window are forgotten
+	now = now.Add(11 * time.Second)
+	assert.True(t, call(circuitBreakerSuccess))
+	assert.True(t, call(circuitBreakerFailure))
+	assert.True(t, call(circuitBreakerSuccess))
+	assert.True(t, call(circuitBreakerIgnored))
+	assert.Equal(t, metrics.CircuitBreakerClosed, cb.state)
+
+	// the error rate is reached
+	assert.True(t, call(circuitBreakerFailure))
+	assert.Equal(t, metrics.CircuitBreakerOpen, cb.state)
+	assert.False(t, call(circuitBreakerSuccess))
+
+	// half open once the open duration elapsed, a failed trial opening it again
+	now = now.Add(30 * time.Second)
+	assert.True(t, call(circuitBreakerFailure))
+	assert.Equal(t, metrics.CircuitBreakerOpen, cb.state)
+	assert.False(t, call(circuitBreakerSuccess))
+
+	// the trials are limited while half open, ignored trials being replaced
+	now = now.Add(30 * time.Second)
+	assert.True(t, cb.allow("bidder.com"))
+	assert.True(t, cb.allow("bidder.com"))
+	assert.False(t, cb.allow("bidder.com"))
+	cb.record("bidder.com", circuitBreakerIgnored)
+	assert.True(t, cb.allow("bidder.com"))
+	cb.record("bidder.com", circuitBreakerSuccess)
+	assert.Equal(t, metrics.CircuitBreakerHalfOpen, cb.state)
+	cb.record("bidder.com", circuitBreakerSuccess)
+	assert.Equal(t, metrics.CircuitBreakerClosed, cb.state)
+
+	// the calls before the breaker opened are forgotten once closed
+	assert.True(t, call(circuitBreakerFailure))
+	assert.Equal(t, metrics.CircuitBreakerClosed, cb.state)
+
+	me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerClosed)
+	me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+	me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerHalfOpen)
+	me.AssertNumberOfCalls(t, "RecordAdapterCircuitBreakerState", 6)
+}
+
+func TestCircuitBreakerHosts(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterCircuitBreakerState", mock.Anything, mock.Anything).Return()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreakers(&config.CircuitBreakerInfo{Enabled: true, MinRequests: 1, ErrorRate: 1}, openrtb_ext.BidderAppnexus, me)
+	cb.now = func() time.Time { return now }
+
+	// a failing host does not stop the calls to the other hosts
+	assert.True(t, cb.allow("down.bidder.com"))
+	cb.record("down.bidder.com", circuitBreakerFailure)
+	assert.False(t, cb.allow("down.bidder.com"))
+	assert.True(t, cb.allow("up.bidder.com"))
+	cb.record("up.bidder.com", circuitBreakerSuccess)
+	assert.Equal(t, metrics.CircuitBreakerOpen, cb.state)
+
+	// the least recently used closed breaker makes room for a new host once the hosts are bounded
+	for i := 0; i < maxCircuitBreakerHosts; i++ {
+		now = now.Add(time.Millisecond)
+		assert.True(t, cb.allow(fmt.Sprintf("%d.bidder.com", i)))
+		cb.record(fmt.Sprintf("%d.bidder.com", i), circuitBreakerSuccess)
+	}
+	assert.Len(t, cb.breakers, maxCircuitBreakerHosts)
+	assert.Contains(t, cb.breakers, "down.bidder.com")
+	assert.NotContains(t, cb.breakers, "up.bidder.com")
+	assert.NotContains(t, cb.breakers, "0.bidder.com")
+	assert.False(t, cb.allow("down.bidder.com"))
+
+	// the least recently used open breaker is dropped once all the breakers are open
+	now = now.Add(time.Millisecond)
+	for host, breaker := range cb.breakers {
+		if host != "down.bidder.com" {
+			breaker.state = metrics.CircuitBreakerOpen
+			breaker.openedAt = now
+			breaker.lastUsed = now
+		}
+	}
+	assert.True(t, cb.allow("new.bidder.com"))
+	assert.NotContains(t, cb.breakers, "down.bidder.com")
+
+	me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerClosed)
+	me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+	me.AssertNumberOfCalls(t, "RecordAdapterCircuitBreakerState", 2)
+}
+
+func TestCircuitBreakerHost(t *testing.T) {
+	testCases := []struct {
+		description  string
+		uri          string
+		expectedHost string
+	}{
+		{
+			description:  "host",
+			uri:          "https://bidder.com/bid?key=value",
+			expectedHost: "bidder.com",
+		},
+		{
+			description:  "host-and-port",
+			uri:          "http://10.0.0.1:8080/bid",
+			expectedHost: "10.0.0.1:8080",
+		},
+		{
+			description:  "no-host",
+			uri:          "/bid",
+			expectedHost: "/bid",
+		},
+		{
+			description:  "invalid",
+			uri:          "https://bidder.com:port/bid",
+			expectedHost: "https://bidder.com:port/bid",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedHost, circuitBreakerHost(test.uri))
+		})
+	}
+}
+
+func TestCircuitBreakerResultOf(t *testing.T) {
+	testCases := []struct {
+		description    string
+		httpInfo       *httpCallInfo
+		expectedResult circuitBreakerResult
+	}{
+		{
+			description:    "success",
+			httpInfo:       &httpCallInfo{response: &adapters.ResponseData{StatusCode: http.StatusOK}},
+			expectedResult: circuitBreakerSuccess,
+		},
+		{
+			description:    "bad-request",
+			httpInfo:       &httpCallInfo{response: &adapters.ResponseData{StatusCode: http.StatusBadRequest}, err: &errortypes.BadServerResponse{}},
+			expectedResult: circuitBreakerSuccess,
+		},
+		{
+			description:    "server-error",
+			httpInfo:       &httpCallInfo{response: &adapters.ResponseData{StatusCode: http.StatusServiceUnavailable}, err: &errortypes.BadServerResponse{}},
+			expectedResult: circuitBreakerFailure,
+		},
+		{
+			description:    "timeout",
+			httpInfo:       &httpCallInfo{err: &errortypes.Timeout{}},
+			expectedResult: circuitBreakerFailure,
+		},
+		{
+			description:    "connection-error",
+			httpInfo:       &httpCallInfo{err: errors.New("connection refused")},
+			expectedResult: circuitBreakerFailure,
+		},
+		{
+			description:    "tmax-timeout",
+			httpInfo:       &httpCallInfo{err: &errortypes.TmaxTimeout{}},
+			expectedResult: circuitBreakerIgnored,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedResult, circuitBreakerResultOf(test.httpInfo))
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/prebid/prebid-server/v3/adapters"
 	"github.com/prebid/prebid-server/v3/adservertargeting"
 	"github.com/prebid/prebid-server/v3/bidadjustment"
+	"github.com/prebid/prebid-server/v3/capture"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/dsa"
@@ -35,9 +36,11 @@ import (
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/trafficshaping"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/maputil"
+	"github.com/prebid/prebid-server/v3/util/ratelimit"
 
 	"github.com/buger/jsonparser"
 	"github.com/gofrs/uuid"
@@ -85,6 +88,8 @@ type exchange struct {
 	priceFloorEnabled        bool
 	priceFloorFetcher        floors.FloorFetcher
 	singleFormatBidders      map[openrtb_ext.BidderName]struct{}
+	bidderRateLimiter        *ratelimit.Limiter
+	trafficShaper            *trafficshaping.Shaper
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -136,7 +141,7 @@ func (randomDeduplicateBidBooleanGenerator) Generate() bool {
 	return rand.Intn(100) < 50
 }
 
-func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, singleFormatBidders map[openrtb_ext.BidderName]struct{}) Exchange {
+func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, singleFormatBidders map[openrtb_ext.BidderName]struct{}, trafficShaper *trafficshaping.Shaper) Exchange {
 	bidderToSyncerKey := map[string]string{}
 	for bidder, syncer := range syncersByBidder {
 		bidderToSyncerKey[bidder] = syncer.Key()
@@ -185,6 +190,8 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		priceFloorEnabled:        cfg.PriceFloors.Enabled,
 		priceFloorFetcher:        priceFloorFetcher,
 		singleFormatBidders:      singleFormatBidders,
+		bidderRateLimiter:        ratelimit.NewLimiter(),
+		trafficShaper:            trafficShaper,
 	}
 }
 
@@ -223,6 +230,8 @@ type AuctionRequest struct {
 	QueryParams             url.Values
 	BidderResponseStartTime time.Time
 	TmaxAdjustments         *TmaxAdjustmentsPreprocessed
+	// Capture records the HTTP calls to the bidders when the auction is captured, nil otherwise
+	Capture *capture.Auction
 }
 
 // BidderRequest holds the bidder specific request and all other
@@ -242,11 +251,19 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		return nil, nil
 	}
 
+	tmaxBeforeHooks := r.BidRequestWrapper.TMax
 	err := r.HookExecutor.ExecuteProcessedAuctionStage(r.BidRequestWrapper)
 	if err != nil {
 		return nil, err
 	}
 
+	// the auction deadline was derived from the incoming tmax, so it is tightened when the hooks lower it
+	if tmax := r.BidRequestWrapper.TMax; tmax > 0 && (tmaxBeforeHooks <= 0 || tmax < tmaxBeforeHooks) {
+		var cancel context.CancelFunc
+		ctx, cancel = tightenAuctionDeadline(ctx, r.StartTime, tmax)
+		defer cancel()
+	}
+
 	requestExt, err := r.BidRequestWrapper.GetRequestExt()
 	if err != nil {
 		return nil, err
@@ -350,7 +367,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		Prebid: *requestExtPrebid,
 		SChain: requestExt.GetSChain(),
 	}
-	bidderRequests, privacyLabels, errs := e.requestSplitter.cleanOpenRTBRequests(ctx, *r, requestExtLegacy, gdprSignal, gdprEnforced, bidAdjustmentFactors)
+	bidderRequests, privacyLabels, privacyAudit, errs := e.requestSplitter.cleanOpenRTBRequests(ctx, *r, requestExtLegacy, gdprSignal, gdprEnforced, bidAdjustmentFactors)
 	for _, err := range errs {
 		if errortypes.ReadCode(err) == errortypes.InvalidImpFirstPartyDataErrorCode {
 			return nil, err
@@ -396,6 +413,15 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		anyBidsReturned = true
 
 	} else {
+		// The bidders over their rate limit are not called
+		var rateLimitedSeatNonBids SeatNonBidBuilder
+		bidderRequests, rateLimitedSeatNonBids = e.applyBidderRateLimits(bidderRequests, &r.Account)
+
+		// The imps the bidders are not expected to bid on are not sent to them
+		var impGroups map[string]string
+		var shapedSeatNonBids SeatNonBidBuilder
+		bidderRequests, impGroups, shapedSeatNonBids = e.shapeTraffic(r.BidRequestWrapper, bidderRequests)
+
 		// List of bidders we have requests for.
 		liveAdapters = listBiddersWithRequests(bidderRequests)
 
@@ -410,13 +436,15 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		liveAdaptersPreferredMediaType := getBidderPreferredMediaTypeMap(requestExtPrebid, &r.Account, liveAdapters, e.singleFormatBidders)
 
 		var extraRespInfo extraAuctionResponseInfo
-		adapterBids, adapterExtra, extraRespInfo = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExtLegacy.Prebid.Experiment, r.HookExecutor, r.StartTime, bidAdjustmentRules, r.TmaxAdjustments, responseDebugAllow, liveAdaptersPreferredMediaType)
+		adapterBids, adapterExtra, extraRespInfo = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExtLegacy.Prebid.Experiment, r.HookExecutor, r.StartTime, bidAdjustmentRules, r.TmaxAdjustments, responseDebugAllow, liveAdaptersPreferredMediaType, r.Capture)
 		fledge = extraRespInfo.fledge
 		anyBidsReturned = extraRespInfo.bidsFound
 		r.BidderResponseStartTime = extraRespInfo.bidderResponseStartTime
 		if extraRespInfo.seatNonBidBuilder != nil {
 			seatNonBidBuilder = extraRespInfo.seatNonBidBuilder
 		}
+		seatNonBidBuilder.append(rateLimitedSeatNonBids, shapedSeatNonBids)
+		e.recordTrafficShapingOutcomes(bidderRequests, impGroups, adapterBids, adapterExtra)
 	}
 
 	var (
@@ -444,19 +472,6 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			}
 		}
 
-		var bidCategory map[string]string
-		//If includebrandcategory is present in ext then CE feature is on.
-		if requestExtPrebid.Targeting != nil && requestExtPrebid.Targeting.IncludeBrandCategory != nil {
-			var rejections []string
-			bidCategory, adapterBids, rejections, err = applyCategoryMapping(ctx, *requestExtPrebid.Targeting, adapterBids, e.categoriesFetcher, targData, &randomDeduplicateBidBooleanGenerator{}, &seatNonBidBuilder, r.Account)
-			if err != nil {
-				return nil, fmt.Errorf("Error in category mapping : %s", err.Error())
-			}
-			for _, message := range rejections {
-				errs = append(errs, errors.New(message))
-			}
-		}
-
 		if e.bidIDGenerator.Enabled() {
 			for bidder, seatBid := range adapterBids {
 				for i := range seatBid.Bids {
@@ -472,7 +487,30 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		evTracking := getEventTracking(requestExtPrebid, r.StartTime, &r.Account, e.bidderInfo, e.externalURL)
 		adapterBids = evTracking.modifyBidsForEvents(adapterBids)
 
-		r.HookExecutor.ExecuteAllProcessedBidResponsesStage(adapterBids)
+		rejectedHookBids := r.HookExecutor.ExecuteAllProcessedBidResponsesStage(adapterBids)
+		seatNonBidBuilder.rejectProcessedHookBids(rejectedHookBids, adapterBids)
+
+		// The clearing prices are set once the hooks are done with the bids, so the price buckets of the
+		// category mapping and of the targeting keys are built from them
+		preferDeals := targData != nil && targData.preferDeals
+		auctionModeErrs := applyAuctionMode(r.BidRequestWrapper, adapterBids, r.Account.Auction, preferDeals, conversions)
+		errs = append(errs, auctionModeErrs...)
+
+		var bidCategory map[string]string
+		//If includebrandcategory is present in ext then CE feature is on.
+		if requestExtPrebid.Targeting != nil && requestExtPrebid.Targeting.IncludeBrandCategory != nil {
+			var rejections []string
+			bidCategory, adapterBids, rejections, err = applyCategoryMapping(ctx, *requestExtPrebid.Targeting, adapterBids, e.categoriesFetcher, targData, &randomDeduplicateBidBooleanGenerator{}, &seatNonBidBuilder, r.Account)
+			if err != nil {
+				return nil, fmt.Errorf("Error in category mapping : %s", err.Error())
+			}
+			for _, message := range rejections {
+				errs = append(errs, errors.New(message))
+			}
+		}
+
+		// The bids on the imps of the ad pods are narrowed down to the ones filling the pods
+		fillAdPods(r.BidRequestWrapper, adapterBids, &seatNonBidBuilder)
 
 		if targData != nil {
 			multiBidMap := buildMultiBidMap(requestExtPrebid)
@@ -548,6 +586,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral] = append(bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral], generalWarning)
 	}
 
+	if responseDebugAllow && bidResponseExt.Debug != nil {
+		bidResponseExt.Debug.Privacy = privacyAudit
+	}
+
 	e.bidValidationEnforcement.SetBannerCreativeMaxSize(r.Account.Validations)
 
 	// Build the response
@@ -563,6 +605,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	return &AuctionResponse{
 		BidResponse:    bidResponse,
 		ExtBidResponse: bidResponseExt,
+		PrivacyAudit:   privacyAudit,
 	}, nil
 }
 
@@ -738,6 +781,15 @@ func updateHbPbCatDur(bid *entities.PbsOrtbBid, dealTier openrtb_ext.DealTier, b
 	}
 }
 
+// tightenAuctionDeadline returns a context whose deadline is no later than tmax milliseconds after the
+// auction start. The parent deadline is kept when it is earlier.
+func tightenAuctionDeadline(ctx context.Context, start time.Time, tmax int64) (context.Context, context.CancelFunc) {
+	if start.IsZero() {
+		start = time.Now()
+	}
+	return context.WithDeadline(ctx, start.Add(time.Duration(tmax)*time.Millisecond))
+}
+
 func (e *exchange) makeAuctionContext(ctx context.Context, needsCache bool) (auctionCtx context.Context, cancel context.CancelFunc) {
 	auctionCtx = ctx
 	cancel = func() {}
@@ -765,7 +817,8 @@ func (e *exchange) getAllBids(
 	bidAdjustmentRules map[string][]openrtb_ext.Adjustment,
 	tmaxAdjustments *TmaxAdjustmentsPreprocessed,
 	responseDebugAllowed bool,
-	liveAdaptersPreferredMediaType openrtb_ext.PreferredMediaType) (
+	liveAdaptersPreferredMediaType openrtb_ext.PreferredMediaType,
+	capturedAuction *capture.Auction) (
 	map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid,
 	map[openrtb_ext.BidderName]*seatResponseExtra,
 	extraAuctionResponseInfo) {
@@ -812,6 +865,7 @@ func (e *exchange) getAllBids(
 				tmaxAdjustments:        tmaxAdjustments,
 				bidderRequestStartTime: start,
 				responseDebugAllowed:   responseDebugAllowed,
+				capture:                capturedAuction,
 			}
 			seatBids, extraBidderRespInfo, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(ctx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes, hookExecutor, bidAdjustmentRules)
 			brw.bidderResponseStartTime = extraBidderRespInfo.respProcessingStartTime
@@ -17,18 +17,25 @@ const (
 	ErrorGeneral                           NonBidReason = 100 // Error - General
 	ErrorTimeout                           NonBidReason = 101 // Error - Timeout
 	ErrorBidderUnreachable                 NonBidReason = 103 // Error - Bidder Unreachable
+	RequestBlockedGeneral                  NonBidReason = 200 // Request Blocked - General
+	RequestBlockedOptimized                NonBidReason = 203 // Request Blocked - Optimized
 	ResponseRejectedGeneral                NonBidReason = 300
 	ResponseRejectedBelowFloor             NonBidReason = 301 // Response Rejected - Below Floor
 	ResponseRejectedCategoryMappingInvalid NonBidReason = 303 // Response Rejected - Category Mapping Invalid
 	ResponseRejectedBelowDealFloor         NonBidReason = 304 // Response Rejected - Bid was Below Deal Floor
+	ResponseRejectedInvalidCreative        NonBidReason = 350 // Response Rejected - Invalid Creative
 	ResponseRejectedCreativeSizeNotAllowed NonBidReason = 351 // Response Rejected - Invalid Creative (Size Not Allowed)
 	ResponseRejectedCreativeNotSecure      NonBidReason = 352 // Response Rejected - Invalid Creative (Not Secure)
+	RequestBlockedRateLimited              NonBidReason = 500 // Request Blocked - Rate Limited, exchange specific
+	ResponseRejectedAdPod                  NonBidReason = 501 // Response Rejected - Not Selected to Fill the Ad Pod, exchange specific
 )
 
 func errorToNonBidReason(err error) NonBidReason {
 	switch errortypes.ReadCode(err) {
 	case errortypes.TimeoutErrorCode:
 		return ErrorTimeout
+	case errortypes.BidderCircuitOpenErrorCode:
+		return RequestBlockedGeneral
 	default:
 		return ErrorGeneral
 	}
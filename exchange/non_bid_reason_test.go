@@ -28,6 +28,15 @@ func Test_httpInfoToNonBidReason(t *testing.T) {
 			},
 			want: ErrorTimeout,
 		},
+		{
+			name: "error-circuitOpen",
+			args: args{
+				httpInfo: &httpCallInfo{
+					err: &errortypes.BidderCircuitOpen{},
+				},
+			},
+			want: RequestBlockedGeneral,
+		},
 		{
 			name: "error-general",
 			args: args{
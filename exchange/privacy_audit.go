@@ -0,0 +1,61 @@
+package exchange
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+)
+
+// newPrivacyAudit starts the privacy audit of an auction with the privacy signals parsed from the request
+func newPrivacyAudit(req *openrtb_ext.RequestWrapper, gpp gpplib.GppContainer, gdprSignal gdpr.Signal, tcfVersion int, privacyLabels metrics.PrivacyLabels, gpcHeader string) *openrtb_ext.PrivacyAudit {
+	signals := openrtb_ext.PrivacyAuditSignals{
+		GDPR:         int(gdprSignal),
+		GDPREnforced: privacyLabels.GDPREnforced,
+		TCFVersion:   tcfVersion,
+		CCPAProvided: privacyLabels.CCPAProvided,
+		CCPAEnforced: privacyLabels.CCPAEnforced,
+		COPPA:        privacyLabels.COPPAEnforced,
+		LMT:          privacyLabels.LMTEnforced,
+		GPC:          gpcHeader == "1",
+	}
+
+	for _, sid := range gpp.SectionTypes {
+		signals.GPPSections = append(signals.GPPSections, int(sid))
+	}
+	if req.Regs != nil {
+		signals.GPPSID = req.Regs.GPPSID
+	}
+	if regExt, err := req.GetRegExt(); err == nil {
+		if gpc := regExt.GetGPC(); gpc != nil && *gpc == "1" {
+			signals.GPC = true
+		}
+	}
+
+	return &openrtb_ext.PrivacyAudit{
+		Signals: signals,
+		Bidders: make(map[openrtb_ext.BidderName]*openrtb_ext.PrivacyAuditBidder),
+	}
+}
+
+// allowActivity returns whether the activity is allowed for the component, recording the check and the rule which
+// decided it in the audit of the bidder
+func allowActivity(audit *openrtb_ext.PrivacyAuditBidder, activities privacy.ActivityControl, activity privacy.Activity, scope privacy.Component, request privacy.ActivityRequest) bool {
+	allowed, rule := activities.Evaluate(activity, scope, request)
+	if audit != nil {
+		audit.Activities = append(audit.Activities, openrtb_ext.PrivacyAuditActivity{
+			Activity: activity.String(),
+			Allowed:  allowed,
+			Rule:     rule,
+		})
+	}
+	return allowed
+}
+
+// auditScrubber records a scrubber applied to the request of the bidder
+func auditScrubber(audit *openrtb_ext.PrivacyAuditBidder, scrubber string) {
+	if audit != nil {
+		audit.Scrubbers = append(audit.Scrubbers, scrubber)
+	}
+}
@@ -0,0 +1,154 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewPrivacyAudit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		request   *openrtb2.BidRequest
+		gpp       gpplib.GppContainer
+		labels    metrics.PrivacyLabels
+		gpcHeader string
+		expected  openrtb_ext.PrivacyAuditSignals
+	}{
+		{
+			name:     "no_signals",
+			request:  &openrtb2.BidRequest{},
+			expected: openrtb_ext.PrivacyAuditSignals{GDPR: 1, TCFVersion: 2},
+		},
+		{
+			name: "all_signals",
+			request: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPPSID: []int8{2, 7}, Ext: json.RawMessage(`{"gpc":"1"}`)},
+			},
+			gpp: gpplib.GppContainer{SectionTypes: []gppConstants.SectionID{gppConstants.SectionTCFEU2, gppConstants.SectionUSPNAT}},
+			labels: metrics.PrivacyLabels{
+				GDPREnforced:  true,
+				CCPAProvided:  true,
+				CCPAEnforced:  true,
+				COPPAEnforced: true,
+				LMTEnforced:   true,
+			},
+			expected: openrtb_ext.PrivacyAuditSignals{
+				GDPR:         1,
+				GDPREnforced: true,
+				TCFVersion:   2,
+				GPPSections:  []int{2, 7},
+				GPPSID:       []int8{2, 7},
+				CCPAProvided: true,
+				CCPAEnforced: true,
+				COPPA:        true,
+				LMT:          true,
+				GPC:          true,
+			},
+		},
+		{
+			name:      "gpc_header",
+			request:   &openrtb2.BidRequest{},
+			gpcHeader: "1",
+			expected:  openrtb_ext.PrivacyAuditSignals{GDPR: 1, TCFVersion: 2, GPC: true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: test.request}
+			audit := newPrivacyAudit(req, test.gpp, gdpr.SignalYes, 2, test.labels, test.gpcHeader)
+			assert.Equal(t, test.expected, audit.Signals)
+			assert.Empty(t, audit.Bidders)
+		})
+	}
+}
+
+func TestAllowActivity(t *testing.T) {
+	activities := privacy.NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitPreciseGeo: buildDefaultActivityConfig("appnexus", false),
+		},
+	})
+	request := privacy.NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}})
+
+	audit := &openrtb_ext.PrivacyAuditBidder{}
+	assert.False(t, allowActivity(audit, activities, privacy.ActivityTransmitPreciseGeo, privacy.Component{Type: privacy.ComponentTypeBidder, Name: "appnexus"}, request))
+	assert.True(t, allowActivity(audit, activities, privacy.ActivityTransmitPreciseGeo, privacy.Component{Type: privacy.ComponentTypeBidder, Name: "rubicon"}, request))
+	assert.True(t, allowActivity(nil, activities, privacy.ActivityTransmitPreciseGeo, privacy.Component{Type: privacy.ComponentTypeBidder, Name: "rubicon"}, request))
+
+	expected := []openrtb_ext.PrivacyAuditActivity{
+		{Activity: "transmitPreciseGeo", Allowed: false, Rule: "rules[0]"},
+		{Activity: "transmitPreciseGeo", Allowed: true, Rule: privacy.RuleNameDefault},
+	}
+	assert.Equal(t, expected, audit.Activities)
+}
+
+func TestCleanOpenRTBRequestsPrivacyAudit(t *testing.T) {
+	testCases := []struct {
+		name          string
+		privacyConfig config.AccountPrivacy
+		expected      *openrtb_ext.PrivacyAuditBidder
+	}{
+		{
+			name:          "fetch_bids_denied",
+			privacyConfig: getFetchBidsActivityConfig("appnexus", false),
+			expected: &openrtb_ext.PrivacyAuditBidder{
+				Blocked: "fetchBids",
+				Activities: []openrtb_ext.PrivacyAuditActivity{
+					{Activity: "fetchBids", Allowed: false, Rule: "rules[0]"},
+				},
+			},
+		},
+		{
+			name:          "transmit_precise_geo_denied",
+			privacyConfig: getTransmitPreciseGeoActivityConfig("appnexus", false),
+			expected: &openrtb_ext.PrivacyAuditBidder{
+				Activities: []openrtb_ext.PrivacyAuditActivity{
+					{Activity: "fetchBids", Allowed: true, Rule: privacy.RuleNameDefault},
+					{Activity: "transmitUfpd", Allowed: true, Rule: privacy.RuleNameDefault},
+					{Activity: "transmitPreciseGeo", Allowed: false, Rule: "rules[0]"},
+					{Activity: "transmitTid", Allowed: true, Rule: privacy.RuleNameDefault},
+				},
+				Scrubbers: []string{privacy.ScrubberGeoAndDeviceIP},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			auctionReq := AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: newBidRequest()},
+				UserSyncs:         &emptyUsersync{},
+				Activities:        privacy.NewActivityControl(&test.privacyConfig),
+				TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+			}
+
+			metricsMock := metrics.MetricsEngineMock{}
+			metricsMock.Mock.On("RecordAdapterBuyerUIDScrubbed", mock.Anything).Return()
+			reqSplitter := &requestSplitter{
+				bidderToSyncerKey: map[string]string{},
+				me:                &metricsMock,
+				bidderInfo:        config.BidderInfos{"appnexus": config.BidderInfo{OpenRTB: &config.OpenRTBInfo{Version: "2.6"}}},
+			}
+
+			_, _, privacyAudit, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, false, map[string]float64{})
+			assert.Empty(t, errs)
+			if assert.NotNil(t, privacyAudit) {
+				assert.Equal(t, 0, privacyAudit.Signals.GDPR)
+				assert.Equal(t, test.expected, privacyAudit.Bidders[openrtb_ext.BidderAppnexus])
+			}
+		})
+	}
+}
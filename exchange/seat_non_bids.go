@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
 
@@ -35,6 +36,35 @@ func (b SeatNonBidBuilder) rejectBid(bid *entities.PbsOrtbBid, nonBidReason int,
 	b[seat] = append(b[seat], nonBid)
 }
 
+// rejectHookBids appends a non bid object to the builder for every bid rejected by the raw bidder response hooks,
+// the bids not setting a seat are reported under the seat of the bidder
+func (b SeatNonBidBuilder) rejectHookBids(rejectedBids []hookstage.RejectedBid, currency, bidder string) {
+	for _, rejected := range rejectedBids {
+		if rejected.Bid == nil || rejected.Bid.Bid == nil {
+			continue
+		}
+
+		seat := bidder
+		if rejected.Bid.Seat != "" {
+			seat = rejected.Bid.Seat.String()
+		}
+		bid := &entities.PbsOrtbBid{Bid: rejected.Bid.Bid, OriginalBidCPM: rejected.Bid.Bid.Price, OriginalBidCur: currency}
+		b.rejectBid(bid, rejected.NonBidReason, seat)
+	}
+}
+
+// rejectProcessedHookBids appends a non bid object to the builder for every bid rejected by the all processed bid responses
+// hooks, the bids are reported under the seat of the seat bid they were removed from
+func (b SeatNonBidBuilder) rejectProcessedHookBids(rejectedBids []hookstage.RejectedProcessedBid, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+	for _, rejected := range rejectedBids {
+		seat := rejected.Bidder.String()
+		if seatBid := seatBids[rejected.Bidder]; seatBid != nil && seatBid.Seat != "" {
+			seat = seatBid.Seat
+		}
+		b.rejectBid(rejected.Bid, rejected.NonBidReason, seat)
+	}
+}
+
 // rejectImps appends a non bid object to the builder for every specified imp
 func (b SeatNonBidBuilder) rejectImps(impIds []string, nonBidReason NonBidReason, seat string) {
 	nonBids := []openrtb_ext.NonBid{}
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
 	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/stretchr/testify/assert"
 )
@@ -254,6 +256,104 @@ func TestAppend(t *testing.T) {
 	}
 }
 
+func TestRejectHookBids(t *testing.T) {
+	tests := []struct {
+		name         string
+		rejectedBids []hookstage.RejectedBid
+		want         SeatNonBidBuilder
+	}{
+		{
+			name:         "no_bids",
+			rejectedBids: nil,
+			want:         SeatNonBidBuilder{},
+		},
+		{
+			name:         "nil_bid",
+			rejectedBids: []hookstage.RejectedBid{{Bid: &adapters.TypedBid{}, NonBidReason: 350}},
+			want:         SeatNonBidBuilder{},
+		},
+		{
+			name: "bids_of_bidder_and_seat",
+			rejectedBids: []hookstage.RejectedBid{
+				{Bid: &adapters.TypedBid{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 2}}, NonBidReason: 350},
+				{Bid: &adapters.TypedBid{Bid: &openrtb2.Bid{ImpID: "imp2", Price: 3}, Seat: "seat2"}, NonBidReason: 352},
+			},
+			want: SeatNonBidBuilder{
+				"bidder1": []openrtb_ext.NonBid{
+					{
+						ImpId:      "imp1",
+						StatusCode: 350,
+						Ext: &openrtb_ext.NonBidExt{
+							Prebid: openrtb_ext.ExtResponseNonBidPrebid{
+								Bid: openrtb_ext.NonBidObject{Price: 2, OriginalBidCPM: 2, OriginalBidCur: "EUR"},
+							},
+						},
+					},
+				},
+				"seat2": []openrtb_ext.NonBid{
+					{
+						ImpId:      "imp2",
+						StatusCode: 352,
+						Ext: &openrtb_ext.NonBidExt{
+							Prebid: openrtb_ext.ExtResponseNonBidPrebid{
+								Bid: openrtb_ext.NonBidObject{Price: 3, OriginalBidCPM: 3, OriginalBidCur: "EUR"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := SeatNonBidBuilder{}
+			builder.rejectHookBids(tt.rejectedBids, "EUR", "bidder1")
+			assert.Equal(t, tt.want, builder)
+		})
+	}
+}
+
+func TestRejectProcessedHookBids(t *testing.T) {
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"bidder1": {},
+		"bidder2": {Seat: "seat2"},
+	}
+	rejectedBids := []hookstage.RejectedProcessedBid{
+		{Bidder: "bidder1", Bid: &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 2}, OriginalBidCPM: 2, OriginalBidCur: "EUR"}, NonBidReason: 300},
+		{Bidder: "bidder2", Bid: &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp2", Price: 3}, OriginalBidCPM: 3, OriginalBidCur: "EUR"}, NonBidReason: 301},
+		{Bidder: "bidder1", Bid: &entities.PbsOrtbBid{}, NonBidReason: 300},
+	}
+
+	builder := SeatNonBidBuilder{}
+	builder.rejectProcessedHookBids(rejectedBids, seatBids)
+
+	want := SeatNonBidBuilder{
+		"bidder1": []openrtb_ext.NonBid{
+			{
+				ImpId:      "imp1",
+				StatusCode: 300,
+				Ext: &openrtb_ext.NonBidExt{
+					Prebid: openrtb_ext.ExtResponseNonBidPrebid{
+						Bid: openrtb_ext.NonBidObject{Price: 2, OriginalBidCPM: 2, OriginalBidCur: "EUR"},
+					},
+				},
+			},
+		},
+		"seat2": []openrtb_ext.NonBid{
+			{
+				ImpId:      "imp2",
+				StatusCode: 301,
+				Ext: &openrtb_ext.NonBidExt{
+					Prebid: openrtb_ext.ExtResponseNonBidPrebid{
+						Bid: openrtb_ext.NonBidObject{Price: 3, OriginalBidCPM: 3, OriginalBidCur: "EUR"},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, builder)
+}
+
 func TestRejectImps(t *testing.T) {
 	tests := []struct {
 		name    string
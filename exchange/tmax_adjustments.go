@@ -61,3 +61,16 @@ func getBidderTmax(ctx bidderTmaxContext, requestTmaxMS int64, tmaxAdjustments T
 	}
 	return requestTmaxMS
 }
+
+// hasTimeForAnotherAttempt returns whether a bidder call can be sent again, the bidder having at least the minimum response
+// duration of the tmax adjustments left to respond, or any time left when the adjustments are not enforced
+func hasTimeForAnotherAttempt(ctx bidderTmaxContext, tmaxAdjustments *TmaxAdjustmentsPreprocessed) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	if tmaxAdjustments != nil && tmaxAdjustments.IsEnforced {
+		return !hasShorterDurationThanTmax(ctx, *tmaxAdjustments)
+	}
+	return ctx.Until(deadline) > 0
+}
@@ -106,3 +106,47 @@ func TestProcessTMaxAdjustments(t *testing.T) {
 		})
 	}
 }
+
+func TestHasTimeForAnotherAttempt(t *testing.T) {
+	now := time.Date(2023, 5, 30, 1, 0, 0, 0, time.UTC)
+	enforced := &TmaxAdjustmentsPreprocessed{IsEnforced: true, BidderNetworkLatencyBuffer: 50, PBSResponsePreparationDuration: 50, BidderResponseDurationMin: 100}
+	tests := []struct {
+		description     string
+		ctx             bidderTmaxContext
+		tmaxAdjustments *TmaxAdjustmentsPreprocessed
+		expected        bool
+	}{
+		{
+			description: "no-deadline",
+			ctx:         &mockBidderTmaxCtx{now: now, ok: false},
+			expected:    true,
+		},
+		{
+			description: "time-left",
+			ctx:         &mockBidderTmaxCtx{now: now, deadline: now.Add(10 * time.Millisecond), ok: true},
+			expected:    true,
+		},
+		{
+			description: "deadline-passed",
+			ctx:         &mockBidderTmaxCtx{now: now, deadline: now.Add(-10 * time.Millisecond), ok: true},
+			expected:    false,
+		},
+		{
+			description:     "enforced-time-left",
+			ctx:             &mockBidderTmaxCtx{now: now, deadline: now.Add(200 * time.Millisecond), ok: true},
+			tmaxAdjustments: enforced,
+			expected:        true,
+		},
+		{
+			description:     "enforced-not-enough-time-left",
+			ctx:             &mockBidderTmaxCtx{now: now, deadline: now.Add(150 * time.Millisecond), ok: true},
+			tmaxAdjustments: enforced,
+			expected:        false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, hasTimeForAnotherAttempt(test.ctx, test.tmaxAdjustments))
+		})
+	}
+}
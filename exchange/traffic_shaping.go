@@ -0,0 +1,82 @@
+package exchange
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// shapeTraffic removes from the bidder requests the imps the bidders are not expected to bid on according to the
+// traffic shaping model, rejecting them with a seat non bid. The bidders left without imps are not called.
+// It returns the traffic shaping group of each imp, by imp ID, to record the outcome of the auction with.
+func (e *exchange) shapeTraffic(request *openrtb_ext.RequestWrapper, bidderRequests []BidderRequest) ([]BidderRequest, map[string]string, SeatNonBidBuilder) {
+	seatNonBidBuilder := SeatNonBidBuilder{}
+	if e.trafficShaper == nil {
+		return bidderRequests, nil, seatNonBidBuilder
+	}
+
+	impGroups := make(map[string]string, request.LenImp())
+	for _, imp := range request.GetImp() {
+		impGroups[imp.ID] = e.trafficShaper.Group(request, imp)
+	}
+
+	shaped := make([]BidderRequest, 0, len(bidderRequests))
+	for _, bidderRequest := range bidderRequests {
+		// the imps with stored bid responses are not sent to the bidder anyway
+		if len(bidderRequest.BidderStoredResponses) > 0 {
+			shaped = append(shaped, bidderRequest)
+			continue
+		}
+
+		imps := make([]openrtb2.Imp, 0, len(bidderRequest.BidRequest.Imp))
+		var prunedImpIDs []string
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			if group, ok := impGroups[imp.ID]; ok && !e.trafficShaper.Allow(string(bidderRequest.BidderName), group) {
+				prunedImpIDs = append(prunedImpIDs, imp.ID)
+				continue
+			}
+			imps = append(imps, imp)
+		}
+		seatNonBidBuilder.rejectImps(prunedImpIDs, RequestBlockedOptimized, string(bidderRequest.BidderName))
+
+		if len(imps) == 0 {
+			continue
+		}
+		if len(prunedImpIDs) > 0 {
+			bidderRequest.BidRequest.Imp = imps
+		}
+		shaped = append(shaped, bidderRequest)
+	}
+	return shaped, impGroups, seatNonBidBuilder
+}
+
+// recordTrafficShapingOutcomes records which imps the bidders bid on, for the bidders which responded without error
+func (e *exchange) recordTrafficShapingOutcomes(bidderRequests []BidderRequest, impGroups map[string]string, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra) {
+	if e.trafficShaper == nil {
+		return
+	}
+
+	for _, bidderRequest := range bidderRequests {
+		if len(bidderRequest.BidderStoredResponses) > 0 {
+			continue
+		}
+		if extra, ok := adapterExtra[bidderRequest.BidderName]; ok && len(extra.Errors) > 0 {
+			continue
+		}
+
+		bidImpIDs := make(map[string]struct{})
+		if seatBid, ok := adapterBids[bidderRequest.BidderName]; ok {
+			for _, bid := range seatBid.Bids {
+				if bid != nil && bid.Bid != nil {
+					bidImpIDs[bid.Bid.ImpID] = struct{}{}
+				}
+			}
+		}
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			if group, ok := impGroups[imp.ID]; ok {
+				_, bid := bidImpIDs[imp.ID]
+				e.trafficShaper.Record(string(bidderRequest.BidderName), group, bid)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/trafficshaping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTrafficShaper(t *testing.T) *trafficshaping.Shaper {
+	shaper, err := trafficshaping.NewShaper(config.TrafficShaping{
+		Enabled:            true,
+		Dimensions:         []string{"mediaType"},
+		MinRequests:        2,
+		MaxGroupsPerBidder: 10,
+	})
+	require.NoError(t, err)
+	return shaper
+}
+
+func TestShapeTraffic(t *testing.T) {
+	shaper := newTestTrafficShaper(t)
+	e := &exchange{trafficShaper: shaper}
+
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{{ID: "imp1", Banner: &openrtb2.Banner{}}, {ID: "imp2", Video: &openrtb2.Video{}}},
+	}}
+	newBidderRequests := func() []BidderRequest {
+		return []BidderRequest{
+			{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}}}},
+			{BidderName: "rubicon", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}},
+			{BidderName: "pubmatic", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}, BidderStoredResponses: map[string]json.RawMessage{"imp1": nil}},
+		}
+	}
+
+	// learn that appnexus and rubicon do not bid on banner imps
+	for i := 0; i < 2; i++ {
+		bidderRequests, impGroups, seatNonBids := e.shapeTraffic(request, newBidderRequests())
+		assert.Len(t, bidderRequests, 3)
+		assert.Equal(t, map[string]string{"imp1": "banner", "imp2": "video-outstream"}, impGroups)
+		assert.Empty(t, seatNonBids)
+
+		adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ImpID: "imp2"}}}},
+		}
+		e.recordTrafficShapingOutcomes(bidderRequests, impGroups, adapterBids, map[openrtb_ext.BidderName]*seatResponseExtra{})
+	}
+	require.NoError(t, shaper.Run())
+
+	bidderRequests, _, seatNonBids := e.shapeTraffic(request, newBidderRequests())
+	expectedBidderRequests := newBidderRequests()
+	expectedBidderRequests[0].BidRequest.Imp = []openrtb2.Imp{{ID: "imp2"}}
+	assert.Equal(t, []BidderRequest{expectedBidderRequests[0], expectedBidderRequests[2]}, bidderRequests)
+	assert.Equal(t, SeatNonBidBuilder{
+		"appnexus": {{ImpId: "imp1", StatusCode: int(RequestBlockedOptimized)}},
+		"rubicon":  {{ImpId: "imp1", StatusCode: int(RequestBlockedOptimized)}},
+	}, seatNonBids)
+}
+
+func TestShapeTrafficDisabled(t *testing.T) {
+	e := &exchange{}
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}}
+	bidderRequests := []BidderRequest{{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}}}
+
+	shaped, impGroups, seatNonBids := e.shapeTraffic(request, bidderRequests)
+	assert.Equal(t, bidderRequests, shaped)
+	assert.Nil(t, impGroups)
+	assert.Empty(t, seatNonBids)
+	e.recordTrafficShapingOutcomes(bidderRequests, impGroups, nil, nil)
+}
+
+func TestRecordTrafficShapingOutcomesSkipsErrors(t *testing.T) {
+	shaper := newTestTrafficShaper(t)
+	e := &exchange{trafficShaper: shaper}
+	bidderRequests := []BidderRequest{{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}}}
+	adapterExtra := map[openrtb_ext.BidderName]*seatResponseExtra{
+		"appnexus": {Errors: []openrtb_ext.ExtBidderMessage{{Code: errortypes.TimeoutErrorCode}}},
+	}
+
+	for i := 0; i < 2; i++ {
+		e.recordTrafficShapingOutcomes(bidderRequests, map[string]string{"imp1": "banner"}, nil, adapterExtra)
+	}
+	require.NoError(t, shaper.Run())
+	assert.True(t, shaper.Allow("appnexus", "banner"), "the timed out requests are not counted as no bids")
+}
@@ -62,7 +62,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	gdprSignal gdpr.Signal,
 	gdprEnforced bool,
 	bidAdjustmentFactors map[string]float64,
-) (bidderRequests []BidderRequest, privacyLabels metrics.PrivacyLabels, errs []error) {
+) (bidderRequests []BidderRequest, privacyLabels metrics.PrivacyLabels, privacyAudit *openrtb_ext.PrivacyAudit, errs []error) {
 	req := auctionReq.BidRequestWrapper
 	if err := PreloadExts(req); err != nil {
 		return
@@ -107,8 +107,8 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	}
 
 	var gpp gpplib.GppContainer
+	var gppErrs []error
 	if req.BidRequest.Regs != nil && len(req.BidRequest.Regs.GPP) > 0 {
-		var gppErrs []error
 		gpp, gppErrs = gpplib.Parse(req.BidRequest.Regs.GPP)
 		if len(gppErrs) > 0 {
 			errs = append(errs, gppErrs[0])
@@ -137,13 +137,14 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	privacyLabels.LMTEnforced = lmt
 
 	var gdprPerms gdpr.Permissions = &gdpr.AlwaysAllow{}
+	var tcfVersion int
 
 	if gdprEnforced {
 		privacyLabels.GDPREnforced = true
 		parsedConsent, err := vendorconsent.ParseString(consent)
 		if err == nil {
-			version := int(parsedConsent.Version())
-			privacyLabels.GDPRTCFVersion = metrics.TCFVersionToValue(version)
+			tcfVersion = int(parsedConsent.Version())
+			privacyLabels.GDPRTCFVersion = metrics.TCFVersionToValue(tcfVersion)
 		}
 
 		gdprRequestInfo := gdpr.RequestInfo{
@@ -155,6 +156,12 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		gdprPerms = rs.gdprPermsBuilder(auctionReq.TCF2Config, gdprRequestInfo)
 	}
 
+	privacyAudit = newPrivacyAudit(req, gpp, gdprSignal, tcfVersion, privacyLabels, auctionReq.GlobalPrivacyControlHeader)
+
+	// the activities are all evaluated on the request as received, so the scrubbing done for one activity does not
+	// change the outcome of the conditions of another
+	activityRequest := privacy.NewRequestFromBidRequest(*req).WithGPP(gpp, gppErrs)
+
 	bidderRequests = make([]BidderRequest, 0, len(impsByBidder))
 
 	for bidder, imps := range impsByBidder {
@@ -193,8 +200,18 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 
 		auctionPermissions := gdprPerms.AuctionActivitiesAllowed(ctx, coreBidder, openrtb_ext.BidderName(bidder))
 
+		bidderAudit := &openrtb_ext.PrivacyAuditBidder{CCPAEnforced: ccpaEnforcer.ShouldEnforce(bidder)}
+		if gdprEnforced {
+			bidderAudit.GDPR = &openrtb_ext.PrivacyAuditGDPR{
+				AllowBidRequest: auctionPermissions.AllowBidRequest,
+				PassGeo:         auctionPermissions.PassGeo,
+				PassID:          auctionPermissions.PassID,
+			}
+		}
+		privacyAudit.Bidders[openrtb_ext.BidderName(bidder)] = bidderAudit
+
 		// privacy blocking
-		if rs.isBidderBlockedByPrivacy(reqWrapperCopy, auctionReq.Activities, auctionPermissions, coreBidder, openrtb_ext.BidderName(bidder)) {
+		if rs.isBidderBlockedByPrivacy(activityRequest, auctionReq.Activities, auctionPermissions, coreBidder, openrtb_ext.BidderName(bidder), bidderAudit) {
 			continue
 		}
 
@@ -202,7 +219,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		applyFPD(auctionReq.FirstPartyData, coreBidder, openrtb_ext.BidderName(bidder), isRequestAlias, reqWrapperCopy, fpdUserEIDsPresent)
 
 		// privacy scrubbing
-		if err := rs.applyPrivacy(reqWrapperCopy, coreBidder, bidder, auctionReq, auctionPermissions, ccpaEnforcer, lmt, coppa); err != nil {
+		if err := rs.applyPrivacy(reqWrapperCopy, activityRequest, coreBidder, bidder, auctionReq, auctionPermissions, ccpaEnforcer, lmt, coppa, bidderAudit); err != nil {
 			errs = append(errs, err)
 			continue
 		}
@@ -347,44 +364,53 @@ func PreloadExts(req *openrtb_ext.RequestWrapper) error {
 	return nil
 }
 
-func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrapper, activities privacy.ActivityControl, auctionPermissions gdpr.AuctionPermissions, coreBidder, bidderName openrtb_ext.BidderName) bool {
+func (rs *requestSplitter) isBidderBlockedByPrivacy(activityRequest privacy.ActivityRequest, activities privacy.ActivityControl, auctionPermissions gdpr.AuctionPermissions, coreBidder, bidderName openrtb_ext.BidderName, audit *openrtb_ext.PrivacyAuditBidder) bool {
 	// activities control
 	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName.String()}
-	fetchBidsActivityAllowed := activities.Allow(privacy.ActivityFetchBids, scope, privacy.NewRequestFromBidRequest(*r))
+	fetchBidsActivityAllowed := allowActivity(audit, activities, privacy.ActivityFetchBids, scope, activityRequest)
 	if !fetchBidsActivityAllowed {
+		if audit != nil {
+			audit.Blocked = privacy.ActivityFetchBids.String()
+		}
 		return true
 	}
 
 	// gdpr
 	if !auctionPermissions.AllowBidRequest {
 		rs.me.RecordAdapterGDPRRequestBlocked(coreBidder)
+		if audit != nil {
+			audit.Blocked = "gdpr"
+		}
 		return true
 	}
 
 	return false
 }
 
-func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper, coreBidderName openrtb_ext.BidderName, bidderName string, auctionReq AuctionRequest, auctionPermissions gdpr.AuctionPermissions, ccpaEnforcer privacy.PolicyEnforcer, lmt bool, coppa bool) error {
+func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper, activityRequest privacy.ActivityRequest, coreBidderName openrtb_ext.BidderName, bidderName string, auctionReq AuctionRequest, auctionPermissions gdpr.AuctionPermissions, ccpaEnforcer privacy.PolicyEnforcer, lmt bool, coppa bool, audit *openrtb_ext.PrivacyAuditBidder) error {
 	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName}
 	ipConf := privacy.IPConf{IPV6: auctionReq.Account.Privacy.IPv6Config, IPV4: auctionReq.Account.Privacy.IPv4Config}
 
 	bidRequest := ortb.CloneBidRequestPartial(reqWrapper.BidRequest)
 	reqWrapper.BidRequest = bidRequest
 
-	passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passIDActivityAllowed := allowActivity(audit, auctionReq.Activities, privacy.ActivityTransmitUserFPD, scope, activityRequest)
 	buyerUIDSet := reqWrapper.User != nil && reqWrapper.User.BuyerUID != ""
 	buyerUIDRemoved := false
 	if !passIDActivityAllowed {
 		privacy.ScrubUserFPD(reqWrapper)
+		auditScrubber(audit, privacy.ScrubberUserFPD)
 		buyerUIDRemoved = true
 	} else {
 		if !auctionPermissions.PassID {
 			privacy.ScrubGdprID(reqWrapper)
+			auditScrubber(audit, privacy.ScrubberGdprID)
 			buyerUIDRemoved = true
 		}
 
 		if ccpaEnforcer.ShouldEnforce(bidderName) {
 			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false)
+			auditScrubber(audit, privacy.ScrubberDeviceIDsIPsUserDemoExt)
 			buyerUIDRemoved = true
 		}
 	}
@@ -392,25 +418,34 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 		rs.me.RecordAdapterBuyerUIDScrubbed(coreBidderName)
 	}
 
-	passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passGeoActivityAllowed := allowActivity(audit, auctionReq.Activities, privacy.ActivityTransmitPreciseGeo, scope, activityRequest)
 	if !passGeoActivityAllowed {
 		privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
+		auditScrubber(audit, privacy.ScrubberGeoAndDeviceIP)
 	} else {
 		if !auctionPermissions.PassGeo {
 			privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
+			auditScrubber(audit, privacy.ScrubberGeoAndDeviceIP)
 		}
 		if ccpaEnforcer.ShouldEnforce(bidderName) {
 			privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", false)
+			auditScrubber(audit, privacy.ScrubberDeviceIDsIPsUserDemoExt)
 		}
 	}
 
 	if lmt || coppa {
 		privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", coppa)
+		if coppa {
+			auditScrubber(audit, privacy.ScrubberDeviceIDsIPsUserDemoExtGeo)
+		} else {
+			auditScrubber(audit, privacy.ScrubberDeviceIDsIPsUserDemoExt)
+		}
 	}
 
-	passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passTIDAllowed := allowActivity(audit, auctionReq.Activities, privacy.ActivityTransmitTIDs, scope, activityRequest)
 	if !passTIDAllowed {
 		privacy.ScrubTID(reqWrapper)
+		auditScrubber(audit, privacy.ScrubberTID)
 	}
 
 	if err := reqWrapper.RebuildRequest(); err != nil {
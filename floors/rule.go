@@ -226,20 +226,29 @@ func createRuleKey(floorSchema openrtb_ext.PriceFloorSchema, request *openrtb_ex
 	return ruleKeys
 }
 
+// RuleKey returns the values of the schema fields for an imp, for the features grouping the imps the same way as the floors
+func RuleKey(fields []string, request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper) []string {
+	return createRuleKey(openrtb_ext.PriceFloorSchema{Fields: fields}, request, imp)
+}
+
 // getDeviceType returns device type provided into request
 func getDeviceType(request *openrtb_ext.RequestWrapper) string {
 	value := catchAll
 	if request.Device == nil || len(request.Device.UA) == 0 {
 		return value
 	}
-	if isMobileDevice(request.Device.UA) {
-		value = Phone
-	} else if isTabletDevice(request.Device.UA) {
-		value = Tablet
-	} else {
-		value = Desktop
+	return DeviceTypeFromUserAgent(request.Device.UA)
+}
+
+// DeviceTypeFromUserAgent classifies the device of a non empty user agent as a phone, tablet or desktop
+func DeviceTypeFromUserAgent(userAgent string) string {
+	if isMobileDevice(userAgent) {
+		return Phone
 	}
-	return value
+	if isTabletDevice(userAgent) {
+		return Tablet
+	}
+	return Desktop
 }
 
 // getDeviceCountry returns device country provided into request
@@ -429,22 +438,19 @@ func getAdUnitCode(imp *openrtb_ext.ImpWrapper) string {
 	return adUnitCode
 }
 
+var (
+	mobileUserAgent = regexp.MustCompile("(?i)Phone|iPhone|Android.*Mobile|Mobile.*Android")
+	tabletUserAgent = regexp.MustCompile("(?i)tablet|iPad|touch.*Windows NT|Windows NT.*touch|Android")
+)
+
 // isMobileDevice returns true if device is mobile
 func isMobileDevice(userAgent string) bool {
-	isMobile, err := regexp.MatchString("(?i)Phone|iPhone|Android.*Mobile|Mobile.*Android", userAgent)
-	if err != nil {
-		return false
-	}
-	return isMobile
+	return mobileUserAgent.MatchString(userAgent)
 }
 
 // isTabletDevice returns true if device is tablet
 func isTabletDevice(userAgent string) bool {
-	isTablet, err := regexp.MatchString("(?i)tablet|iPad|touch.*Windows NT|Windows NT.*touch|Android", userAgent)
-	if err != nil {
-		return false
-	}
-	return isTablet
+	return tabletUserAgent.MatchString(userAgent)
 }
 
 // prepareRuleCombinations prepares rule combinations based on schema dimensions and request fields
@@ -22,8 +22,9 @@ var validSchemaDimensions = map[string]struct{}{
 	DeviceType: {},
 }
 
-// validateSchemaDimensions validates schema dimesions given in floors JSON
-func validateSchemaDimensions(fields []string) error {
+// ValidateSchemaDimensions validates schema dimesions given in floors JSON or in the config of the features grouping
+// the imps the same way as the floors
+func ValidateSchemaDimensions(fields []string) error {
 	for i := range fields {
 		if _, isPresent := validSchemaDimensions[fields[i]]; !isPresent {
 			return fmt.Errorf("Invalid schema dimension provided = '%s' in Schema Fields = '%v'", fields[i], fields)
@@ -83,7 +84,7 @@ func selectValidFloorModelGroups(modelGroups []openrtb_ext.PriceFloorModelGroup,
 	}
 
 	for _, modelGroup := range modelGroups {
-		if err := validateSchemaDimensions(modelGroup.Schema.Fields); err != nil {
+		if err := ValidateSchemaDimensions(modelGroup.Schema.Fields); err != nil {
 			errs = append(errs, err)
 			continue
 		}
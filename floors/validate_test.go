@@ -423,7 +423,7 @@ func TestValidateSchemaDimensions(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateSchemaDimensions(tt.fields)
+			err := ValidateSchemaDimensions(tt.fields)
 			assert.Equal(t, tt.err, err)
 		})
 	}
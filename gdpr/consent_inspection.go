@@ -0,0 +1,112 @@
+package gdpr
+
+import (
+	"context"
+	"time"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/prebid-server/v3/metrics"
+)
+
+const (
+	// purposeCount is the number of TCF v2 purposes
+	purposeCount = 10
+	// specialFeatureCount is the number of TCF v2 special features
+	specialFeatureCount = 2
+)
+
+// DecodedConsent is the content of a TCF v2 consent string
+type DecodedConsent struct {
+	Version                   uint8     `json:"version"`
+	TCFPolicyVersion          uint8     `json:"tcfPolicyVersion"`
+	VendorListVersion         uint16    `json:"vendorListVersion"`
+	SpecVersion               uint16    `json:"specVersion"`
+	CMPID                     uint16    `json:"cmpId"`
+	CMPVersion                uint16    `json:"cmpVersion"`
+	ConsentScreen             uint8     `json:"consentScreen"`
+	ConsentLanguage           string    `json:"consentLanguage"`
+	Created                   time.Time `json:"created"`
+	LastUpdated               time.Time `json:"lastUpdated"`
+	PurposeOneTreatment       bool      `json:"purposeOneTreatment"`
+	PurposeConsents           []int     `json:"purposeConsents"`
+	PurposeLITransparency     []int     `json:"purposeLegitimateInterests"`
+	SpecialFeatureOptIns      []uint16  `json:"specialFeatureOptIns"`
+	VendorConsents            []uint16  `json:"vendorConsents"`
+	VendorLegitimateInterests []uint16  `json:"vendorLegitimateInterests"`
+}
+
+// DecodeConsent decodes a TCF v2 consent string, listing the purposes, special features and vendors the user
+// consented to or did not object to the legitimate interest of
+func DecodeConsent(consent string) (DecodedConsent, error) {
+	pc, err := parseConsent(consent)
+	if err != nil {
+		return DecodedConsent{}, err
+	}
+
+	cm := pc.consentMeta
+	decoded := DecodedConsent{
+		Version:                   pc.encodingVersion,
+		TCFPolicyVersion:          cm.TCFPolicyVersion(),
+		VendorListVersion:         pc.listVersion,
+		SpecVersion:               pc.specVersion,
+		CMPID:                     cm.CmpID(),
+		CMPVersion:                cm.CmpVersion(),
+		ConsentScreen:             cm.ConsentScreen(),
+		ConsentLanguage:           cm.ConsentLanguage(),
+		Created:                   cm.Created(),
+		LastUpdated:               cm.LastUpdated(),
+		PurposeOneTreatment:       cm.PurposeOneTreatment(),
+		PurposeConsents:           []int{},
+		PurposeLITransparency:     []int{},
+		SpecialFeatureOptIns:      []uint16{},
+		VendorConsents:            []uint16{},
+		VendorLegitimateInterests: []uint16{},
+	}
+	for purpose := 1; purpose <= purposeCount; purpose++ {
+		if cm.PurposeAllowed(consentconstants.Purpose(purpose)) {
+			decoded.PurposeConsents = append(decoded.PurposeConsents, purpose)
+		}
+		if cm.PurposeLITransparency(consentconstants.Purpose(purpose)) {
+			decoded.PurposeLITransparency = append(decoded.PurposeLITransparency, purpose)
+		}
+	}
+	for feature := uint16(1); feature <= specialFeatureCount; feature++ {
+		if cm.SpecialFeatureOptIn(feature) {
+			decoded.SpecialFeatureOptIns = append(decoded.SpecialFeatureOptIns, feature)
+		}
+	}
+	for vendor := uint16(1); vendor <= cm.MaxVendorID() && vendor != 0; vendor++ {
+		if cm.VendorConsent(vendor) {
+			decoded.VendorConsents = append(decoded.VendorConsents, vendor)
+		}
+	}
+	for vendor := uint16(1); vendor <= cm.VendorLegitInterestMaxID() && vendor != 0; vendor++ {
+		if cm.VendorLegitInterest(vendor) {
+			decoded.VendorLegitimateInterests = append(decoded.VendorLegitimateInterests, vendor)
+		}
+	}
+	return decoded, nil
+}
+
+// ConsentVendorList describes the vendor list the permissions of a consent string are computed with
+type ConsentVendorList struct {
+	SpecVersion uint16 `json:"specVersion"`
+	Version     uint16 `json:"version"`
+	Loaded      bool   `json:"loaded"`
+	Error       string `json:"error,omitempty"`
+}
+
+// FetchConsentVendorList returns the vendor list used for the decoded consent string, as loaded in the vendor
+// list cache of the fetcher
+func FetchConsentVendorList(ctx context.Context, fetcher VendorListFetcher, decoded DecodedConsent, me metrics.MetricsEngine) ConsentVendorList {
+	vendorList := ConsentVendorList{SpecVersion: decoded.SpecVersion, Version: decoded.VendorListVersion}
+	list, err := fetcher(ctx, decoded.SpecVersion, decoded.VendorListVersion, me)
+	if err != nil {
+		vendorList.Error = err.Error()
+		return vendorList
+	}
+	vendorList.SpecVersion = list.SpecVersion()
+	vendorList.Version = list.Version()
+	vendorList.Loaded = true
+	return vendorList
+}
@@ -0,0 +1,93 @@
+package gdpr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/go-gdpr/vendorlist"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeConsent(t *testing.T) {
+	testCases := []struct {
+		name          string
+		consent       string
+		expected      DecodedConsent
+		expectedError bool
+	}{
+		{
+			name:    "valid",
+			consent: "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA",
+			expected: DecodedConsent{
+				Version:                   2,
+				TCFPolicyVersion:          2,
+				VendorListVersion:         34,
+				SpecVersion:               2,
+				CMPID:                     431,
+				ConsentLanguage:           "EN",
+				Created:                   time.Date(2020, 5, 12, 18, 47, 24, 200000000, time.UTC),
+				LastUpdated:               time.Date(2020, 5, 12, 18, 47, 24, 200000000, time.UTC),
+				PurposeConsents:           []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+				PurposeLITransparency:     []int{2, 3, 4, 5, 6, 7, 8, 9, 10},
+				SpecialFeatureOptIns:      []uint16{1},
+				VendorConsents:            []uint16{2, 6, 8},
+				VendorLegitimateInterests: []uint16{2, 6, 8},
+			},
+		},
+		{
+			name:          "malformed",
+			consent:       "malformed",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			decoded, err := DecodeConsent(test.consent)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected.Created, decoded.Created.UTC())
+			assert.Equal(t, test.expected.LastUpdated, decoded.LastUpdated.UTC())
+			decoded.Created = test.expected.Created
+			decoded.LastUpdated = test.expected.LastUpdated
+			assert.Equal(t, test.expected, decoded)
+		})
+	}
+}
+
+func TestFetchConsentVendorList(t *testing.T) {
+	fetcher := listFetcher(map[uint16]map[uint16]vendorlist.VendorList{
+		2: {
+			34: parseVendorListDataV2(t, MarshalVendorList(vendorList{GVLSpecificationVersion: 2, VendorListVersion: 34, Vendors: map[string]*vendor{}})),
+		},
+	})
+
+	testCases := []struct {
+		name     string
+		decoded  DecodedConsent
+		expected ConsentVendorList
+	}{
+		{
+			name:     "loaded",
+			decoded:  DecodedConsent{SpecVersion: 2, VendorListVersion: 34},
+			expected: ConsentVendorList{SpecVersion: 2, Version: 34, Loaded: true},
+		},
+		{
+			name:     "not_loaded",
+			decoded:  DecodedConsent{SpecVersion: 2, VendorListVersion: 35},
+			expected: ConsentVendorList{SpecVersion: 2, Version: 35, Error: "spec version 2 vendor list 35 not found"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			vendorList := FetchConsentVendorList(context.Background(), fetcher, test.decoded, &metrics.MetricsEngineMock{})
+			assert.Equal(t, test.expected, vendorList)
+		})
+	}
+}
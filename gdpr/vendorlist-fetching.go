@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -20,49 +23,66 @@ import (
 )
 
 type saveVendors func(uint16, uint16, api.VendorList)
+type loadVendors func(uint16, uint16) api.VendorList
 type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error)
 
+// VendorListVersions returns the versions of the vendor lists loaded, sorted, by spec version
+type VendorListVersions func() map[uint16][]uint16
+
 // This file provides the vendorlist-fetching function for Prebid Server.
 //
 // For more info, see https://github.com/prebid/prebid-server/issues/504
 //
 // Nothing in this file is exported. Public APIs can be found in gdpr.go
 
-func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http.Client, metricsEngine metrics.MetricsEngine, urlMaker func(uint16, uint16) string) VendorListFetcher {
-	cacheSave, cacheLoad := newVendorListCache()
+// NewVendorListFetcher returns the fetcher of the vendor lists, preloaded from the directory of the vendor list cache
+// if any and over HTTP, along with the versions of the lists it holds.
+func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http.Client, metricsEngine metrics.MetricsEngine, urlMaker func(uint16, uint16) string) (VendorListFetcher, VendorListVersions) {
+	cache := newVendorListCache()
+	dir := vendorListDir(cfg.VendorListCache.Dir)
+	dir.load(cache.save)
 
 	preloadContext, cancel := context.WithTimeout(initCtx, cfg.Timeouts.InitTimeout())
 	defer cancel()
-	preloadCache(preloadContext, client, urlMaker, cacheSave, metricsEngine)
+	preloadCache(preloadContext, client, urlMaker, cache.save, cache.load, dir, metricsEngine)
 
 	saveOneRateLimited := newOccasionalSaver(cfg.Timeouts.ActiveTimeout())
-	return func(ctx context.Context, specVersion, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error) {
+	fetcher := func(ctx context.Context, specVersion, listVersion uint16, metricsEngine metrics.MetricsEngine) (vendorlist.VendorList, error) {
 		// Attempt To Load From Cache
-		if list := cacheLoad(specVersion, listVersion); list != nil {
+		if list := cache.load(specVersion, listVersion); list != nil {
 			return list, nil
 		}
 
 		// Attempt To Download
 		// - May not add to cache immediately.
-		saveOneRateLimited(ctx, client, urlMaker(specVersion, listVersion), cacheSave, metricsEngine)
+		saveOneRateLimited(ctx, client, urlMaker(specVersion, listVersion), cache.save, dir, metricsEngine)
 
 		// Attempt To Load From Cache Again
 		// - May have been added by the call to saveOneRateLimited.
-		if list := cacheLoad(specVersion, listVersion); list != nil {
+		if list := cache.load(specVersion, listVersion); list != nil {
 			return list, nil
 		}
 
+		// Attempt To Fall Back To The Latest Known Version
+		if cfg.VendorListCache.FallbackToLatest {
+			if list := cache.latest(specVersion); list != nil {
+				metricsEngine.RecordGvlListFallback()
+				return list, nil
+			}
+		}
+
 		// Give Up
 		return nil, makeVendorListNotFoundError(specVersion, listVersion)
 	}
+	return fetcher, cache.versions
 }
 
 func makeVendorListNotFoundError(specVersion, listVersion uint16) error {
 	return fmt.Errorf("gdpr vendor list spec version %d list version %d does not exist, or has not been loaded yet. Try again in a few minutes", specVersion, listVersion)
 }
 
-// preloadCache saves all the known versions of the vendor list for future use.
-func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, metricsEngine metrics.MetricsEngine) {
+// preloadCache saves all the known versions of the vendor list for future use, but the ones already loaded.
+func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, loader loadVendors, dir vendorListDir, metricsEngine metrics.MetricsEngine) {
 	versions := [2]struct {
 		specVersion      uint16
 		firstListVersion uint16
@@ -77,10 +97,12 @@ func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16
 		},
 	}
 	for _, v := range versions {
-		latestVersion := saveOne(ctx, client, urlMaker(v.specVersion, 0), saver, metricsEngine)
+		latestVersion := saveOne(ctx, client, urlMaker(v.specVersion, 0), saver, dir, metricsEngine)
 
 		for i := v.firstListVersion; i < latestVersion; i++ {
-			saveOne(ctx, client, urlMaker(v.specVersion, i), saver, metricsEngine)
+			if loader(v.specVersion, i) == nil {
+				saveOne(ctx, client, urlMaker(v.specVersion, i), saver, dir, metricsEngine)
+			}
 		}
 	}
 }
@@ -99,24 +121,24 @@ func VendorListURLMaker(specVersion, listVersion uint16) string {
 // The goal here is to update quickly when new versions of the VendorList are released, but not wreck
 // server performance if a bad CMP starts sending us malformed consent strings that advertize a version
 // that doesn't exist yet.
-func newOccasionalSaver(timeout time.Duration) func(ctx context.Context, client *http.Client, url string, saver saveVendors, metricsEngine metrics.MetricsEngine) {
+func newOccasionalSaver(timeout time.Duration) func(ctx context.Context, client *http.Client, url string, saver saveVendors, dir vendorListDir, metricsEngine metrics.MetricsEngine) {
 	lastSaved := &atomic.Value{}
 	lastSaved.Store(time.Time{})
 
-	return func(ctx context.Context, client *http.Client, url string, saver saveVendors, metricsEngine metrics.MetricsEngine) {
+	return func(ctx context.Context, client *http.Client, url string, saver saveVendors, dir vendorListDir, metricsEngine metrics.MetricsEngine) {
 		now := time.Now()
 		timeSinceLastSave := now.Sub(lastSaved.Load().(time.Time))
 
 		if timeSinceLastSave.Minutes() > 10 {
 			withTimeout, cancel := context.WithTimeout(ctx, timeout)
 			defer cancel()
-			saveOne(withTimeout, client, url, saver, metricsEngine)
+			saveOne(withTimeout, client, url, saver, dir, metricsEngine)
 			lastSaved.Store(now)
 		}
 	}
 }
 
-func saveOne(ctx context.Context, client *http.Client, url string, saver saveVendors, me metrics.MetricsEngine) uint16 {
+func saveOne(ctx context.Context, client *http.Client, url string, saver saveVendors, dir vendorListDir, me metrics.MetricsEngine) uint16 {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		glog.Errorf("Failed to build GET %s request. Cookie syncs may be affected: %v", url, err)
@@ -150,24 +172,121 @@ func saveOne(ctx context.Context, client *http.Client, url string, saver saveVen
 	}
 
 	saver(newList.SpecVersion(), newList.Version(), newList)
+	dir.save(newList.SpecVersion(), newList.Version(), respBody)
 	return newList.Version()
 }
 
-func newVendorListCache() (save func(specVersion, listVersion uint16, list api.VendorList), load func(specVersion, listVersion uint16) api.VendorList) {
-	cache := &sync.Map{}
+// vendorListCache holds the vendor lists loaded by spec version and list version
+type vendorListCache struct {
+	mutex sync.RWMutex
+	lists map[uint16]map[uint16]api.VendorList
+}
+
+func newVendorListCache() *vendorListCache {
+	return &vendorListCache{lists: make(map[uint16]map[uint16]api.VendorList)}
+}
+
+func (c *vendorListCache) save(specVersion, listVersion uint16, list api.VendorList) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	lists, ok := c.lists[specVersion]
+	if !ok {
+		lists = make(map[uint16]api.VendorList)
+		c.lists[specVersion] = lists
+	}
+	lists[listVersion] = list
+}
+
+func (c *vendorListCache) load(specVersion, listVersion uint16) api.VendorList {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.lists[specVersion][listVersion]
+}
+
+// latest returns the vendor list with the highest version loaded for the spec version, nil if none
+func (c *vendorListCache) latest(specVersion uint16) api.VendorList {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var latest api.VendorList
+	for listVersion, list := range c.lists[specVersion] {
+		if latest == nil || listVersion > latest.Version() {
+			latest = list
+		}
+	}
+	return latest
+}
+
+func (c *vendorListCache) versions() map[uint16][]uint16 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 
-	save = func(specVersion uint16, listVersion uint16, list api.VendorList) {
-		key := fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
-		cache.Store(key, list)
+	versions := make(map[uint16][]uint16, len(c.lists))
+	for specVersion, lists := range c.lists {
+		listVersions := make([]uint16, 0, len(lists))
+		for listVersion := range lists {
+			listVersions = append(listVersions, listVersion)
+		}
+		slices.Sort(listVersions)
+		versions[specVersion] = listVersions
 	}
+	return versions
+}
 
-	load = func(specVersion, listVersion uint16) api.VendorList {
-		key := fmt.Sprint(specVersion) + "-" + fmt.Sprint(listVersion)
-		list, ok := cache.Load(key)
-		if ok {
-			return list.(vendorlist.VendorList)
+// vendorListDir is the directory the vendor lists are persisted to as returned over HTTP, disabled when empty
+type vendorListDir string
+
+// load saves the vendor lists of the directory, creating it if missing
+func (d vendorListDir) load(saver saveVendors) {
+	if d == "" {
+		return
+	}
+	if err := os.MkdirAll(string(d), 0755); err != nil {
+		glog.Errorf("Failed to create the vendor list directory %s. Vendor lists will not be persisted: %v", d, err)
+		return
+	}
+
+	files, err := filepath.Glob(filepath.Join(string(d), "*.json"))
+	if err != nil {
+		glog.Errorf("Failed to list the vendor lists of %s: %v", d, err)
+		return
+	}
+	loaded := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			glog.Errorf("Failed to read the vendor list %s: %v", file, err)
+			continue
 		}
-		return nil
+		list, err := vendorlist2.ParseEagerly(data)
+		if err != nil {
+			glog.Errorf("Vendor list %s is malformed and was skipped: %v", file, err)
+			continue
+		}
+		saver(list.SpecVersion(), list.Version(), list)
+		loaded++
+	}
+	glog.Infof("Loaded %d vendor lists from %s", loaded, d)
+}
+
+// save writes a vendor list to the directory, unless already there. The file is replaced at once, a list partially
+// written not being loaded at the next start.
+func (d vendorListDir) save(specVersion, listVersion uint16, data []byte) {
+	if d == "" {
+		return
+	}
+
+	file := filepath.Join(string(d), "v"+strconv.Itoa(int(specVersion))+"-vendor-list-v"+strconv.Itoa(int(listVersion))+".json")
+	if _, err := os.Stat(file); err == nil {
+		return
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		glog.Errorf("Failed to save the vendor list %s: %v", file, err)
+		return
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		glog.Errorf("Failed to save the vendor list %s: %v", file, err)
 	}
-	return
 }
@@ -4,7 +4,10 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -98,7 +101,7 @@ func TestFetcherThrottling(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
-	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
+	fetcher, _ := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 
 	// Dynamically Load List 2 Successfully
 	_, errList1 := fetcher(context.Background(), 3, 2, m)
@@ -110,6 +113,129 @@ func TestFetcherThrottling(t *testing.T) {
 	assert.EqualError(t, errList2, "gdpr vendor list spec version 3 list version 3 does not exist, or has not been loaded yet. Try again in a few minutes")
 }
 
+func TestFetcherFallbackToLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 1,
+		vendorLists: map[int]map[int]string{
+			3: {
+				1: vendorList1,
+			},
+		},
+	})))
+	defer server.Close()
+
+	testCases := []struct {
+		name             string
+		fallbackToLatest bool
+		specVersion      uint16
+		expectedVersion  uint16
+		expectedError    string
+		expectedFallback int
+	}{
+		{
+			name:             "fallback",
+			fallbackToLatest: true,
+			specVersion:      3,
+			expectedVersion:  1,
+			expectedFallback: 1,
+		},
+		{
+			name:             "fallback_disabled",
+			fallbackToLatest: false,
+			specVersion:      3,
+			expectedError:    "gdpr vendor list spec version 3 list version 2 does not exist, or has not been loaded yet. Try again in a few minutes",
+		},
+		{
+			name:             "no_list_of_spec_version",
+			fallbackToLatest: true,
+			specVersion:      2,
+			expectedError:    "gdpr vendor list spec version 2 list version 2 does not exist, or has not been loaded yet. Try again in a few minutes",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.VendorListCache.FallbackToLatest = test.fallbackToLatest
+			m := &metrics.MetricsEngineMock{}
+			m.On("RecordGvlListRequest")
+			m.On("RecordGvlListFallback")
+
+			fetcher, _ := NewVendorListFetcher(context.Background(), cfg, server.Client(), m, testURLMaker(server))
+			vendorList, err := fetcher(context.Background(), test.specVersion, 2, m)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedVersion, vendorList.Version())
+			}
+			m.AssertNumberOfCalls(t, "RecordGvlListFallback", test.expectedFallback)
+		})
+	}
+}
+
+func TestFetcherVendorListDir(t *testing.T) {
+	var requests atomic.Int32
+	handler := mockServer(serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists: map[int]map[int]string{
+			3: {
+				1: vendorList1,
+				2: vendorList2,
+			},
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		handler(w, req)
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.VendorListCache.Dir = t.TempDir()
+	m := &metrics.MetricsEngineMock{}
+	m.On("RecordGvlListRequest")
+
+	// Fetch Over HTTP And Persist To The Directory
+	_, versions := NewVendorListFetcher(context.Background(), cfg, server.Client(), m, testURLMaker(server))
+	assert.Equal(t, map[uint16][]uint16{3: {1, 2}}, versions())
+	assert.Equal(t, int32(3), requests.Load(), "latest lists of spec versions 2 and 3, list 1 of spec version 3")
+
+	files, err := filepath.Glob(filepath.Join(cfg.VendorListCache.Dir, "*.json"))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(cfg.VendorListCache.Dir, "v3-vendor-list-v1.json"),
+		filepath.Join(cfg.VendorListCache.Dir, "v3-vendor-list-v2.json"),
+	}, files)
+
+	// Load From The Directory, Only The Latest Lists Being Fetched
+	requests.Store(0)
+	_, versions = NewVendorListFetcher(context.Background(), cfg, server.Client(), m, testURLMaker(server))
+	assert.Equal(t, map[uint16][]uint16{3: {1, 2}}, versions())
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Load From The Directory Without Network
+	server.Close()
+	fetcher, versions := NewVendorListFetcher(context.Background(), cfg, server.Client(), m, testURLMaker(server))
+	assert.Equal(t, map[uint16][]uint16{3: {1, 2}}, versions())
+
+	vendorList, err := fetcher(context.Background(), 3, 2, m)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(2), vendorList.Version())
+}
+
+func TestVendorListDirMalformedFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "v3-vendor-list-v1.json"), []byte(vendorList1), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "v3-vendor-list-v2.json"), []byte("malformed"), 0644))
+
+	cache := newVendorListCache()
+	vendorListDir(dir).load(cache.save)
+
+	assert.Equal(t, map[uint16][]uint16{3: {1}}, cache.versions())
+}
+
 func TestMalformedVendorlist(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
 		vendorListLatestVersion: 1,
@@ -123,7 +249,7 @@ func TestMalformedVendorlist(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
-	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
+	fetcher, _ := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 	_, err := fetcher(context.Background(), 3, 1, m)
 
 	// Fetching should fail since vendor list could not be unmarshalled.
@@ -138,7 +264,7 @@ func TestServerUrlInvalid(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(2)
-	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, invalidURLGenerator)
+	fetcher, _ := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, invalidURLGenerator)
 	_, err := fetcher(context.Background(), 3, 1, m)
 
 	assert.EqualError(t, err, "gdpr vendor list spec version 3 list version 1 does not exist, or has not been loaded yet. Try again in a few minutes")
@@ -150,7 +276,7 @@ func TestServerUnavailable(t *testing.T) {
 
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(2)
-	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
+	fetcher, _ := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), m, testURLMaker(server))
 	_, err := fetcher(context.Background(), 3, 1, m)
 
 	assert.EqualError(t, err, "gdpr vendor list spec version 3 list version 1 does not exist, or has not been loaded yet. Try again in a few minutes")
@@ -209,6 +335,10 @@ func (s *saver) saveVendorLists(specVersion uint16, listVersion uint16, gvl api.
 	*s = append(*s, vi)
 }
 
+func noVendorLists(uint16, uint16) api.VendorList {
+	return nil
+}
+
 func TestPreloadCache(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
 		vendorListLatestVersion: 3,
@@ -264,7 +394,7 @@ func TestPreloadCache(t *testing.T) {
 	s := make(saver, 0, 5)
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(5)
-	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, m)
+	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, noVendorLists, "", m)
 
 	expectedLoadedVersions := []versionInfo{
 		{specVersion: 2, listVersion: 2},
@@ -393,7 +523,7 @@ func runTest(t *testing.T, test test, server *httptest.Server) {
 	config := testConfig()
 	m := &metrics.MetricsEngineMock{}
 	m.On("RecordGvlListRequest").Times(3)
-	fetcher := NewVendorListFetcher(context.Background(), config, server.Client(), m, testURLMaker(server))
+	fetcher, _ := NewVendorListFetcher(context.Background(), config, server.Client(), m, testURLMaker(server))
 	vendorList, err := fetcher(context.Background(), test.setup.specVersion, test.setup.listVersion, m)
 
 	if test.expected.errorMessage != "" {
@@ -0,0 +1,416 @@
+package hookexecution
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+)
+
+// AsyncPool runs the hooks marked async in the execution plans on a bounded number of workers
+// started on the first submitted hook. A single pool is shared by the hook executors of all the requests.
+type AsyncPool struct {
+	once   sync.Once
+	worker int
+	tasks  chan func()
+}
+
+// NewAsyncPool builds the worker pool running async hooks according to the host configuration.
+func NewAsyncPool(cfg config.HookAsyncExecution) *AsyncPool {
+	return newAsyncPool(cfg.Worker, cfg.Capacity)
+}
+
+func newAsyncPool(worker, capacity int) *AsyncPool {
+	return &AsyncPool{
+		worker: worker,
+		tasks:  make(chan func(), capacity),
+	}
+}
+
+// submit queues the task for a worker, the task is dropped and false returned when the queue is full.
+// Executors built without a pool drop all their async hooks.
+func (p *AsyncPool) submit(task func()) bool {
+	if p == nil {
+		return false
+	}
+	p.once.Do(p.start)
+
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *AsyncPool) start() {
+	for i := 0; i < p.worker; i++ {
+		go func() {
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+}
+
+// asyncOutcomes holds the outcomes of the async hooks submitted so far, in the order they were submitted.
+// Each outcome holds a single group with the result of a single hook.
+type asyncOutcomes struct {
+	sync.Mutex
+	hooks []*submittedAsyncHook
+}
+
+// submittedAsyncHook is an async hook handed to the worker pool, its outcome is set once it completes.
+type submittedAsyncHook struct {
+	hookID    HookID
+	entity    entity
+	stage     string
+	submitted time.Time
+	timeout   time.Duration
+	outcome   *StageOutcome
+}
+
+// add registers the hook before it is submitted and returns the function setting its outcome.
+func (ao *asyncOutcomes) add(executionCtx executionContext, hookID HookID, timeout time.Duration) func(StageOutcome) {
+	h := &submittedAsyncHook{
+		hookID:    hookID,
+		entity:    executionCtx.entity,
+		stage:     executionCtx.stage,
+		submitted: time.Now(),
+		timeout:   timeout,
+	}
+
+	ao.Lock()
+	defer ao.Unlock()
+	ao.hooks = append(ao.hooks, h)
+
+	return func(outcome StageOutcome) {
+		ao.Lock()
+		defer ao.Unlock()
+		h.outcome = &outcome
+	}
+}
+
+// get returns the outcomes of the submitted hooks. Hooks still queued or running are reported
+// as pending until their timeout elapses, and as timed out afterwards.
+func (ao *asyncOutcomes) get() []StageOutcome {
+	ao.Lock()
+	defer ao.Unlock()
+
+	outcomes := make([]StageOutcome, 0, len(ao.hooks))
+	for _, h := range ao.hooks {
+		if h.outcome != nil {
+			outcomes = append(outcomes, *h.outcome)
+			continue
+		}
+
+		elapsed := time.Since(h.submitted)
+		hookOutcome := HookOutcome{
+			Status:        StatusPending,
+			Action:        ActionNone,
+			HookID:        h.hookID,
+			ExecutionTime: ExecutionTime{ExecutionTimeMillis: elapsed},
+		}
+		if elapsed >= h.timeout {
+			hookOutcome.Status = StatusTimeout
+			hookOutcome.Errors = []string{fmt.Sprintf(
+				"Module (name: %s, hook code: %s) async hook did not complete in %s",
+				h.hookID.ModuleCode,
+				h.hookID.HookImplCode,
+				h.timeout,
+			)}
+		}
+		outcomes = append(outcomes, newAsyncStageOutcome(h.entity, h.stage, hookOutcome))
+	}
+	return outcomes
+}
+
+func newAsyncStageOutcome(entity entity, stage string, hookOutcome HookOutcome) StageOutcome {
+	return StageOutcome{
+		ExecutionTime: hookOutcome.ExecutionTime,
+		Entity:        entity,
+		Stage:         stage,
+		Groups: []GroupOutcome{{
+			ExecutionTime:     hookOutcome.ExecutionTime,
+			InvocationResults: []HookOutcome{hookOutcome},
+		}},
+	}
+}
+
+// payloadCloner copies the payload passed to an async hook, so the hook does not observe the changes the
+// request proceeding meanwhile makes to it. Copies are shallow to keep them cheap: the objects the auction
+// changes in place after the stage are copied while the nested data it only ever replaces is shared, so
+// async hooks must not change anything but the fields of the copied objects.
+type payloadCloner[P any] func(P) (P, error)
+
+// asyncHook is an async hook along with the copy of the payload it is invoked with.
+type asyncHook[H any, P any] struct {
+	hw        hooks.HookWrapper[H]
+	moduleCtx hookstage.ModuleInvocationContext
+	payload   P
+	timeout   time.Duration
+	err       error
+}
+
+// splitAsyncHooks separates the async hooks of the group from the hooks run by the stage.
+// The async hooks are given a copy of the payload as it is before the group runs.
+func splitAsyncHooks[H any, P any](
+	executionCtx executionContext,
+	group hooks.Group[H],
+	payload P,
+	clonePayload payloadCloner[P],
+) (hooks.Group[H], []asyncHook[H, P]) {
+	var asyncHooks []asyncHook[H, P]
+	syncGroup := hooks.Group[H]{Timeout: group.Timeout, Hooks: make([]hooks.HookWrapper[H], 0, len(group.Hooks))}
+
+	for _, hook := range group.Hooks {
+		// stages that can not copy their payload run all their hooks, the plan builder keeps async hooks away from them
+		if !hook.Async || clonePayload == nil {
+			syncGroup.Hooks = append(syncGroup.Hooks, hook)
+			continue
+		}
+
+		mCtx := executionCtx.getModuleContext(hook.Module)
+		mCtx.HookImplCode = hook.Code
+		// the module context is updated by the later stages while the hook runs
+		mCtx.ModuleContext = maps.Clone(mCtx.ModuleContext)
+
+		h := asyncHook[H, P]{hw: hook, moduleCtx: mCtx, timeout: group.Timeout}
+		h.payload, h.err = clonePayload(payload)
+		if h.err == nil {
+			h.payload = handleModuleActivities(hook.Code, executionCtx.activityControl, h.payload, executionCtx.account)
+		} else {
+			h.err = fmt.Errorf("failed to copy payload for async hook: %s", h.err)
+		}
+		asyncHooks = append(asyncHooks, h)
+	}
+
+	return syncGroup, asyncHooks
+}
+
+// executeAsyncHooks submits the async hooks to the worker pool.
+// Their outcomes are only reported through metrics and the outcomes passed to analytics.
+func executeAsyncHooks[H any, P any](
+	executionCtx executionContext,
+	asyncHooks []asyncHook[H, P],
+	hookHandler hookHandler[H, P],
+	metricEngine metrics.MetricsEngine,
+) {
+	for _, h := range asyncHooks {
+		h := h
+		hookID := HookID{ModuleCode: h.hw.Module, HookImplCode: h.hw.Code}
+		setOutcome := executionCtx.asyncOutcomes.add(executionCtx, hookID, h.timeout)
+		submitted := executionCtx.asyncPool.submit(func() {
+			setOutcome(executeAsyncHook(executionCtx, h, hookHandler, metricEngine))
+		})
+		if !submitted {
+			metricEngine.RecordModuleAsyncDropped(metrics.ModuleLabels{
+				Module:    moduleReplacer.Replace(h.hw.Module),
+				Stage:     executionCtx.stage,
+				AccountID: executionCtx.accountID,
+			})
+			setOutcome(newAsyncStageOutcome(executionCtx.entity, executionCtx.stage, HookOutcome{
+				Status: StatusExecutionFailure,
+				Action: ActionNone,
+				HookID: hookID,
+				Errors: []string{fmt.Sprintf(
+					"Module (name: %s, hook code: %s) async hook dropped, the async execution queue is full",
+					hookID.ModuleCode,
+					hookID.HookImplCode,
+				)},
+			}))
+		}
+	}
+}
+
+func executeAsyncHook[H any, P any](
+	executionCtx executionContext,
+	h asyncHook[H, P],
+	hookHandler hookHandler[H, P],
+	metricEngine metrics.MetricsEngine,
+) StageOutcome {
+	hr := hookResponse[P]{Err: h.err, HookID: HookID{ModuleCode: h.hw.Module, HookImplCode: h.hw.Code}}
+	if h.err == nil {
+		resp := make(chan hookResponse[P], 1)
+		// async hooks are never interrupted by a rejection, hence the nil channel
+		executeHook(h.moduleCtx, h.hw, h.payload, hookHandler, h.timeout, resp, nil)
+		hr = <-resp
+	}
+
+	hookOutcome := handleAsyncHookResponse(executionCtx, hr, metricEngine)
+	return newAsyncStageOutcome(executionCtx.entity, executionCtx.stage, hookOutcome)
+}
+
+// handleAsyncHookResponse builds the outcome of an async hook.
+// Async hooks are observe-only, their mutations and rejections are reported but never applied.
+func handleAsyncHookResponse[P any](
+	ctx executionContext,
+	hr hookResponse[P],
+	metricEngine metrics.MetricsEngine,
+) HookOutcome {
+	labels := metrics.ModuleLabels{Module: moduleReplacer.Replace(hr.HookID.ModuleCode), Stage: ctx.stage, AccountID: ctx.accountID}
+	metricEngine.RecordModuleCalled(labels, hr.ExecutionTime)
+
+	hookOutcome := HookOutcome{
+		Status:        StatusSuccess,
+		Action:        ActionNone,
+		HookID:        hr.HookID,
+		Message:       hr.Result.Message,
+		Errors:        hr.Result.Errors,
+		Warnings:      hr.Result.Warnings,
+		DebugMessages: hr.Result.DebugMessages,
+		AnalyticsTags: hr.Result.AnalyticsTags,
+		ExecutionTime: ExecutionTime{ExecutionTimeMillis: hr.ExecutionTime},
+	}
+
+	if hr.Err != nil {
+		handleHookError(hr, &hookOutcome, metricEngine, labels)
+		return hookOutcome
+	}
+
+	if hr.Result.Reject {
+		metricEngine.RecordModuleExecutionError(labels)
+		hookOutcome.Status = StatusExecutionFailure
+		hookOutcome.Errors = append(
+			hookOutcome.Errors,
+			fmt.Sprintf(
+				"Module (name: %s, hook code: %s) tried to reject request from an async hook that does not support rejection",
+				hr.HookID.ModuleCode,
+				hr.HookID.HookImplCode,
+			),
+		)
+		return hookOutcome
+	}
+
+	if len(hr.Result.ChangeSet.Mutations()) > 0 {
+		hookOutcome.Warnings = append(
+			hookOutcome.Warnings,
+			fmt.Sprintf(
+				"Module (name: %s, hook code: %s) returned mutations from an async hook that does not support mutations, mutations are ignored",
+				hr.HookID.ModuleCode,
+				hr.HookID.HookImplCode,
+			),
+		)
+	}
+	metricEngine.RecordModuleSuccessNooped(labels)
+
+	return hookOutcome
+}
+
+func cloneEntrypointPayload(payload hookstage.EntrypointPayload) (hookstage.EntrypointPayload, error) {
+	clone := hookstage.EntrypointPayload{Body: bytes.Clone(payload.Body)}
+	if payload.Request != nil {
+		// the hook outlives the request, the body is only available through the payload
+		clone.Request = payload.Request.Clone(context.Background())
+		clone.Request.Body = http.NoBody
+	}
+	return clone, nil
+}
+
+func cloneRawAuctionRequestPayload(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
+	return bytes.Clone(payload), nil
+}
+
+func cloneProcessedAuctionRequestPayload(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+	request, err := cloneRequestWrapper(payload.Request)
+	return hookstage.ProcessedAuctionRequestPayload{Request: request}, err
+}
+
+func cloneBidderRequestPayload(payload hookstage.BidderRequestPayload) (hookstage.BidderRequestPayload, error) {
+	request, err := cloneRequestWrapper(payload.Request)
+	return hookstage.BidderRequestPayload{Request: request, Bidder: payload.Bidder}, err
+}
+
+func cloneRawBidderResponsePayload(payload hookstage.RawBidderResponsePayload) (hookstage.RawBidderResponsePayload, error) {
+	clone := hookstage.RawBidderResponsePayload{Bidder: payload.Bidder}
+	if payload.BidderResponse != nil {
+		response := *payload.BidderResponse
+		response.Bids = make([]*adapters.TypedBid, len(payload.BidderResponse.Bids))
+		for i, typedBid := range payload.BidderResponse.Bids {
+			response.Bids[i] = cloneTypedBid(typedBid)
+		}
+		clone.BidderResponse = &response
+	}
+	return clone, nil
+}
+
+func cloneAllProcessedBidResponsesPayload(payload hookstage.AllProcessedBidResponsesPayload) (hookstage.AllProcessedBidResponsesPayload, error) {
+	clone := hookstage.AllProcessedBidResponsesPayload{Responses: make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, len(payload.Responses))}
+	for bidder, seatBid := range payload.Responses {
+		if seatBid == nil {
+			clone.Responses[bidder] = nil
+			continue
+		}
+		seatBidClone := *seatBid
+		seatBidClone.Bids = make([]*entities.PbsOrtbBid, len(seatBid.Bids))
+		for i, pbsBid := range seatBid.Bids {
+			seatBidClone.Bids[i] = clonePbsOrtbBid(pbsBid)
+		}
+		clone.Responses[bidder] = &seatBidClone
+	}
+	return clone, nil
+}
+
+func cloneAuctionResponsePayload(payload hookstage.AuctionResponsePayload) (hookstage.AuctionResponsePayload, error) {
+	if payload.BidResponse == nil {
+		return payload, nil
+	}
+	response := *payload.BidResponse
+	response.SeatBid = slices.Clone(payload.BidResponse.SeatBid)
+	for i := range response.SeatBid {
+		response.SeatBid[i].Bid = slices.Clone(response.SeatBid[i].Bid)
+	}
+	return hookstage.AuctionResponsePayload{BidResponse: &response}, nil
+}
+
+// cloneRequestWrapper copies the bid request along with the changes made through the wrapper. The wrapper
+// rebuilds the imps and the objects holding an ext by setting their fields in place, so they are copied.
+func cloneRequestWrapper(request *openrtb_ext.RequestWrapper) (*openrtb_ext.RequestWrapper, error) {
+	if request == nil {
+		return nil, nil
+	}
+	if err := request.RebuildRequest(); err != nil {
+		return nil, err
+	}
+
+	bidRequest := ortb.CloneBidRequestPartial(request.BidRequest)
+	bidRequest.Imp = slices.Clone(bidRequest.Imp)
+	bidRequest.Site = ptrutil.Clone(bidRequest.Site)
+	bidRequest.App = ptrutil.Clone(bidRequest.App)
+	bidRequest.DOOH = ptrutil.Clone(bidRequest.DOOH)
+	bidRequest.Regs = ortb.CloneRegs(bidRequest.Regs)
+	return &openrtb_ext.RequestWrapper{BidRequest: bidRequest}, nil
+}
+
+// cloneTypedBid copies the bid the bidder adapter returned, whose price is adjusted in place by the auction.
+func cloneTypedBid(typedBid *adapters.TypedBid) *adapters.TypedBid {
+	if typedBid == nil {
+		return nil
+	}
+	clone := *typedBid
+	clone.Bid = ptrutil.Clone(typedBid.Bid)
+	return &clone
+}
+
+func clonePbsOrtbBid(pbsBid *entities.PbsOrtbBid) *entities.PbsOrtbBid {
+	if pbsBid == nil {
+		return nil
+	}
+	clone := *pbsBid
+	clone.Bid = ptrutil.Clone(pbsBid.Bid)
+	return &clone
+}
@@ -0,0 +1,285 @@
+package hookexecution
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockChangePayloadHook changes the payload it is given in place instead of returning mutations
+type mockChangePayloadHook struct{}
+
+func (e mockChangePayloadHook) HandleProcessedAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.ProcessedAuctionRequestPayload) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	payload.Request.User.Yob = 1990
+	return hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{Message: "observed"}, nil
+}
+
+func (e mockChangePayloadHook) HandleRawBidderResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.RawBidderResponsePayload) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	payload.BidderResponse.Bids[0].Bid.Price = 0
+	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{}, nil
+}
+
+type TestAsyncPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestAsyncPlanBuilder) PlanForProcessedAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.ProcessedAuctionRequest] {
+	return hooks.Plan[hookstage.ProcessedAuctionRequest]{
+		hooks.Group[hookstage.ProcessedAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.ProcessedAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockUpdateBidRequestHook{}, Async: true},
+				{Module: "foobar", Code: "bar", Hook: mockChangePayloadHook{}, Async: true},
+			},
+		},
+		hooks.Group[hookstage.ProcessedAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.ProcessedAuctionRequest]{
+				{Module: "foobar", Code: "baz", Hook: mockRejectHook{}, Async: true},
+			},
+		},
+	}
+}
+
+func (e TestAsyncPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
+	return hooks.Plan[hookstage.RawBidderResponse]{
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "foo", Hook: mockChangePayloadHook{}, Async: true},
+				{Module: "foobar", Code: "bar", Hook: mockUpdateBidderResponseHook{}},
+			},
+		},
+	}
+}
+
+// asyncHooksCompleted returns true once all the async hooks submitted by the executor have their outcome
+func asyncHooksCompleted(exec *hookExecutor) bool {
+	exec.asyncOutcomes.Lock()
+	defer exec.asyncOutcomes.Unlock()
+	for _, h := range exec.asyncOutcomes.hooks {
+		if h.outcome == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExecuteProcessedAuctionStageWithAsyncHooks(t *testing.T) {
+	exec := NewHookExecutor(TestAsyncPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{}, newAsyncPool(1, 10))
+
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "some-id", User: &openrtb2.User{ID: "user-id", Yob: 2010}}}
+	err := exec.ExecuteProcessedAuctionStage(request)
+
+	assert.NoError(t, err, "Async hooks can not reject the stage.")
+	assert.Equal(t, &openrtb2.BidRequest{ID: "some-id", User: &openrtb2.User{ID: "user-id", Yob: 2010}}, request.BidRequest, "Async hooks can not change the request.")
+	assert.Equal(t, []StageOutcome{{Entity: entityAuctionRequest, Stage: hooks.StageProcessedAuctionRequest.String(), Groups: []GroupOutcome{}}}, exec.GetOutcomes(), "Async hooks are not part of the stage outcome.")
+
+	require.Eventually(t, func() bool { return asyncHooksCompleted(exec) }, time.Second, time.Millisecond)
+	require.Len(t, exec.GetAsyncOutcomes(), 3)
+
+	outcomes := make(map[string]HookOutcome)
+	for _, outcome := range exec.GetAsyncOutcomes() {
+		assert.Equal(t, entityAuctionRequest, outcome.Entity)
+		assert.Equal(t, hooks.StageProcessedAuctionRequest.String(), outcome.Stage)
+		require.Len(t, outcome.Groups, 1)
+		require.Len(t, outcome.Groups[0].InvocationResults, 1)
+		hookOutcome := outcome.Groups[0].InvocationResults[0]
+		hookOutcome.ExecutionTime = ExecutionTime{}
+		outcomes[hookOutcome.HookID.HookImplCode] = hookOutcome
+	}
+
+	assert.Equal(t, map[string]HookOutcome{
+		"foo": {
+			HookID:   HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+			Status:   StatusSuccess,
+			Action:   ActionNone,
+			Warnings: []string{"Module (name: foobar, hook code: foo) returned mutations from an async hook that does not support mutations, mutations are ignored"},
+		},
+		"bar": {
+			HookID:  HookID{ModuleCode: "foobar", HookImplCode: "bar"},
+			Status:  StatusSuccess,
+			Action:  ActionNone,
+			Message: "observed",
+		},
+		"baz": {
+			HookID: HookID{ModuleCode: "foobar", HookImplCode: "baz"},
+			Status: StatusExecutionFailure,
+			Action: ActionNone,
+			Errors: []string{"Module (name: foobar, hook code: baz) tried to reject request from an async hook that does not support rejection"},
+		},
+	}, outcomes)
+}
+
+func TestExecuteRawBidderResponseStageWithAsyncHooks(t *testing.T) {
+	exec := NewHookExecutor(TestAsyncPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{}, newAsyncPool(1, 10))
+
+	response := &adapters.BidderResponse{Bids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid-id", Price: 1}}}}
+	_, reject := exec.ExecuteRawBidderResponseStage(response, "some-bidder")
+	require.Nil(t, reject)
+
+	require.Eventually(t, func() bool { return asyncHooksCompleted(exec) }, time.Second, time.Millisecond)
+	require.Len(t, exec.GetAsyncOutcomes(), 1)
+
+	assert.Equal(t, 1.0, response.Bids[0].Bid.Price, "Async hooks are given a copy of the payload.")
+	assert.Equal(t, entity("some-bidder"), exec.GetAsyncOutcomes()[0].Entity)
+	assert.Len(t, exec.GetOutcomes()[0].Groups[0].InvocationResults, 1, "Only sync hooks are part of the stage outcome.")
+}
+
+func TestExecuteAsyncHooksDroppedWhenPoolIsFull(t *testing.T) {
+	metricEngine := &metrics.MetricsEngineMock{}
+	metricEngine.On("RecordModuleAsyncDropped", metrics.ModuleLabels{Module: "foobar", Stage: hooks.StageProcessedAuctionRequest.String()}).Times(3)
+
+	exec := NewHookExecutor(TestAsyncPlanBuilder{}, EndpointAuction, metricEngine, newAsyncPool(0, 0))
+
+	err := exec.ExecuteProcessedAuctionStage(&openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{User: &openrtb2.User{}}})
+
+	assert.NoError(t, err)
+	metricEngine.AssertExpectations(t)
+	metricEngine.AssertNotCalled(t, "RecordModuleCalled", mock.Anything, mock.Anything)
+
+	outcomes := exec.GetAsyncOutcomes()
+	require.Len(t, outcomes, 3)
+	assert.Equal(t, StageOutcome{
+		Entity: entityAuctionRequest,
+		Stage:  hooks.StageProcessedAuctionRequest.String(),
+		Groups: []GroupOutcome{{
+			InvocationResults: []HookOutcome{{
+				HookID: HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+				Status: StatusExecutionFailure,
+				Action: ActionNone,
+				Errors: []string{"Module (name: foobar, hook code: foo) async hook dropped, the async execution queue is full"},
+			}},
+		}},
+	}, outcomes[0])
+}
+
+func TestGetAsyncOutcomesOfUnfinishedHooks(t *testing.T) {
+	testCases := []struct {
+		description    string
+		timeout        time.Duration
+		expectedStatus Status
+		expectedErrors []string
+	}{
+		{
+			description:    "hook-within-timeout-is-pending",
+			timeout:        time.Minute,
+			expectedStatus: StatusPending,
+		},
+		{
+			description:    "hook-past-timeout-timed-out",
+			timeout:        0,
+			expectedStatus: StatusTimeout,
+			expectedErrors: []string{"Module (name: foobar, hook code: foo) async hook did not complete in 0s"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			ao := &asyncOutcomes{}
+			executionCtx := executionContext{entity: entityAuctionRequest, stage: hooks.StageProcessedAuctionRequest.String()}
+			ao.add(executionCtx, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, test.timeout)
+
+			outcomes := ao.get()
+			require.Len(t, outcomes, 1)
+			assert.Equal(t, entityAuctionRequest, outcomes[0].Entity)
+			assert.Equal(t, hooks.StageProcessedAuctionRequest.String(), outcomes[0].Stage)
+			require.Len(t, outcomes[0].Groups, 1)
+			require.Len(t, outcomes[0].Groups[0].InvocationResults, 1)
+			hookOutcome := outcomes[0].Groups[0].InvocationResults[0]
+			assert.Equal(t, test.expectedStatus, hookOutcome.Status)
+			assert.Equal(t, ActionNone, hookOutcome.Action)
+			assert.Equal(t, test.expectedErrors, hookOutcome.Errors)
+		})
+	}
+}
+
+func TestGetAsyncOutcomesOfFinishedHook(t *testing.T) {
+	ao := &asyncOutcomes{}
+	setOutcome := ao.add(executionContext{}, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, 0)
+	outcome := StageOutcome{Entity: entityAuctionRequest, Groups: []GroupOutcome{{InvocationResults: []HookOutcome{{Status: StatusSuccess}}}}}
+	setOutcome(outcome)
+
+	assert.Equal(t, []StageOutcome{outcome}, ao.get())
+}
+
+func TestCloneProcessedAuctionRequestPayload(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Imp:  []openrtb2.Imp{{ID: "imp-id"}},
+		Site: &openrtb2.Site{ID: "site-id"},
+		Regs: &openrtb2.Regs{COPPA: 1},
+	}}
+
+	clone, err := cloneProcessedAuctionRequestPayload(hookstage.ProcessedAuctionRequestPayload{Request: request})
+	require.NoError(t, err)
+
+	impWrapper := request.GetImp()[0]
+	impWrapper.BidFloor = 1
+	request.Site.Ext = []byte(`{"foo":"bar"}`)
+	request.Regs.Ext = []byte(`{"foo":"bar"}`)
+	require.NoError(t, request.RebuildRequest())
+
+	assert.Equal(t, &openrtb2.BidRequest{
+		Imp:  []openrtb2.Imp{{ID: "imp-id"}},
+		Site: &openrtb2.Site{ID: "site-id"},
+		Regs: &openrtb2.Regs{COPPA: 1},
+	}, clone.Request.BidRequest)
+}
+
+func TestCloneAllProcessedBidResponsesPayload(t *testing.T) {
+	seatBid := &entities.PbsOrtbSeatBid{Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid-id", Price: 1}}}}
+	payload := hookstage.AllProcessedBidResponsesPayload{Responses: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{"some-bidder": seatBid}}
+
+	clone, err := cloneAllProcessedBidResponsesPayload(payload)
+	require.NoError(t, err)
+
+	seatBid.Bids[0].Bid.Price = 2
+	seatBid.Bids[0].BidTargets = map[string]string{"hb_pb": "2.00"}
+	seatBid.Bids = append(seatBid.Bids, &entities.PbsOrtbBid{})
+
+	assert.Equal(t, map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"some-bidder": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid-id", Price: 1}}}},
+	}, clone.Responses)
+}
+
+func TestCloneAuctionResponsePayload(t *testing.T) {
+	response := &openrtb2.BidResponse{ID: "some-id", SeatBid: []openrtb2.SeatBid{{Seat: "some-bidder", Bid: []openrtb2.Bid{{ID: "bid-id"}}}}}
+
+	clone, err := cloneAuctionResponsePayload(hookstage.AuctionResponsePayload{BidResponse: response})
+	require.NoError(t, err)
+
+	response.Ext = []byte(`{"foo":"bar"}`)
+	response.SeatBid[0].Bid[0].AdM = "some-adm"
+
+	assert.Equal(t, &openrtb2.BidResponse{ID: "some-id", SeatBid: []openrtb2.SeatBid{{Seat: "some-bidder", Bid: []openrtb2.Bid{{ID: "bid-id"}}}}}, clone.BidResponse)
+}
+
+func TestCloneEntrypointPayload(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", nil)
+	require.NoError(t, err)
+	request.Header.Set("foo", "bar")
+	payload := hookstage.EntrypointPayload{Request: request, Body: []byte(`{"id":"some-id"}`)}
+
+	clone, err := cloneEntrypointPayload(payload)
+	require.NoError(t, err)
+	clone.Request.Header.Set("foo", "baz")
+	clone.Body[2] = 'x'
+
+	assert.Equal(t, "bar", request.Header.Get("foo"))
+	assert.Equal(t, `{"id":"some-id"}`, string(payload.Body))
+	assert.Equal(t, http.NoBody, clone.Request.Body)
+}
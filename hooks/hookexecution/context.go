@@ -13,14 +13,17 @@ import (
 type executionContext struct {
 	endpoint        string
 	stage           string
+	entity          entity
 	accountID       string
 	account         *config.Account
 	moduleContexts  *moduleContexts
 	activityControl privacy.ActivityControl
+	asyncPool       *AsyncPool
+	asyncOutcomes   *asyncOutcomes
 }
 
 func (ctx executionContext) getModuleContext(moduleName string) hookstage.ModuleInvocationContext {
-	moduleInvocationCtx := hookstage.ModuleInvocationContext{Endpoint: ctx.endpoint}
+	moduleInvocationCtx := hookstage.ModuleInvocationContext{Endpoint: ctx.endpoint, ActivityControl: ctx.activityControl}
 	if ctx.moduleContexts != nil {
 		if mc, ok := ctx.moduleContexts.get(moduleName); ok {
 			moduleInvocationCtx.ModuleContext = mc
@@ -38,6 +38,7 @@ func executeStage[H any, P any](
 	plan hooks.Plan[H],
 	payload P,
 	hookHandler hookHandler[H, P],
+	clonePayload payloadCloner[P],
 	metricEngine metrics.MetricsEngine,
 ) (StageOutcome, P, stageModuleContext, *RejectError) {
 	stageOutcome := StageOutcome{}
@@ -45,8 +46,20 @@ func executeStage[H any, P any](
 	stageModuleCtx := stageModuleContext{}
 	stageModuleCtx.groupCtx = make([]groupModuleContext, 0, len(plan))
 
+	// async hooks of the groups reached are run once the stage proceeds, whether it is rejected or not
+	var asyncHooks []asyncHook[H, P]
+	defer func() {
+		executeAsyncHooks(executionCtx, asyncHooks, hookHandler, metricEngine)
+	}()
+
 	for _, group := range plan {
-		groupOutcome, newPayload, moduleContexts, rejectErr := executeGroup(executionCtx, group, payload, hookHandler, metricEngine)
+		syncGroup, groupAsyncHooks := splitAsyncHooks(executionCtx, group, payload, clonePayload)
+		asyncHooks = append(asyncHooks, groupAsyncHooks...)
+		if len(syncGroup.Hooks) == 0 {
+			continue
+		}
+
+		groupOutcome, newPayload, moduleContexts, rejectErr := executeGroup(executionCtx, syncGroup, payload, hookHandler, metricEngine)
 		stageOutcome.ExecutionTimeMillis += groupOutcome.ExecutionTimeMillis
 		stageOutcome.Groups = append(stageOutcome.Groups, groupOutcome)
 		stageModuleCtx.groupCtx = append(stageModuleCtx.groupCtx, moduleContexts)
@@ -233,3 +233,13 @@ func TestHandleModuleActivitiesNoBidderRequestPayload(t *testing.T) {
 		})
 	}
 }
+
+func TestGetModuleContextActivityControl(t *testing.T) {
+	activityControl := privacy.NewActivityControl(getTransmitPreciseGeoActivityConfig("foo", false))
+	executionCtx := executionContext{endpoint: "/openrtb2/auction", activityControl: activityControl}
+
+	miCtx := executionCtx.getModuleContext("foo")
+
+	assert.Equal(t, "/openrtb2/auction", miCtx.Endpoint)
+	assert.False(t, miCtx.ActivityControl.Allow(privacy.ActivityTransmitPreciseGeo, privacy.Component{Type: privacy.ComponentTypeGeneral, Name: "foo"}, privacy.ActivityRequest{}))
+}
@@ -37,8 +37,8 @@ type StageExecutor interface {
 	ExecuteRawAuctionStage(body []byte) ([]byte, *RejectError)
 	ExecuteProcessedAuctionStage(req *openrtb_ext.RequestWrapper) error
 	ExecuteBidderRequestStage(req *openrtb_ext.RequestWrapper, bidder string) *RejectError
-	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError
-	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
+	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) ([]hookstage.RejectedBid, *RejectError)
+	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []hookstage.RejectedProcessedBid
 	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
 	ExecuteExitpointStage(response any, w http.ResponseWriter) any
 }
@@ -48,6 +48,9 @@ type HookStageExecutor interface {
 	SetAccount(account *config.Account)
 	SetActivityControl(activityControl privacy.ActivityControl)
 	GetOutcomes() []StageOutcome
+	// GetAsyncOutcomes returns the outcomes of the async hooks completed so far,
+	// they are meant for analytics only and are not part of the response.
+	GetAsyncOutcomes() []StageOutcome
 }
 
 type hookExecutor struct {
@@ -59,17 +62,21 @@ type hookExecutor struct {
 	moduleContexts  *moduleContexts
 	metricEngine    metrics.MetricsEngine
 	activityControl privacy.ActivityControl
+	asyncPool       *AsyncPool
+	asyncOutcomes   *asyncOutcomes
 	// Mutex needed for BidderRequest and RawBidderResponse Stages as they are run in several goroutines
 	sync.Mutex
 }
 
-func NewHookExecutor(builder hooks.ExecutionPlanBuilder, endpoint string, me metrics.MetricsEngine) *hookExecutor {
+func NewHookExecutor(builder hooks.ExecutionPlanBuilder, endpoint string, me metrics.MetricsEngine, asyncPool *AsyncPool) *hookExecutor {
 	return &hookExecutor{
 		endpoint:       endpoint,
 		planBuilder:    builder,
 		stageOutcomes:  []StageOutcome{},
 		moduleContexts: &moduleContexts{ctxs: make(map[string]hookstage.ModuleContext)},
 		metricEngine:   me,
+		asyncPool:      asyncPool,
+		asyncOutcomes:  &asyncOutcomes{},
 	}
 }
 
@@ -90,6 +97,10 @@ func (e *hookExecutor) GetOutcomes() []StageOutcome {
 	return e.stageOutcomes
 }
 
+func (e *hookExecutor) GetAsyncOutcomes() []StageOutcome {
+	return e.asyncOutcomes.get()
+}
+
 func (e *hookExecutor) ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *RejectError) {
 	plan := e.planBuilder.PlanForEntrypointStage(e.endpoint)
 	if len(plan) == 0 {
@@ -106,11 +117,11 @@ func (e *hookExecutor) ExecuteEntrypointStage(req *http.Request, body []byte) ([
 	}
 
 	stageName := hooks.StageEntrypoint.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityHttpRequest)
 	payload := hookstage.EntrypointPayload{Request: req, Body: body}
 
-	outcome, payload, contexts, rejectErr := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
-	outcome.Entity = entityHttpRequest
+	outcome, payload, contexts, rejectErr := executeStage(executionCtx, plan, payload, handler, cloneEntrypointPayload, e.metricEngine)
+	outcome.Entity = executionCtx.entity
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
@@ -135,11 +146,11 @@ func (e *hookExecutor) ExecuteRawAuctionStage(requestBody []byte) ([]byte, *Reje
 	}
 
 	stageName := hooks.StageRawAuctionRequest.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityAuctionRequest)
 	payload := hookstage.RawAuctionRequestPayload(requestBody)
 
-	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
-	outcome.Entity = entityAuctionRequest
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, cloneRawAuctionRequestPayload, e.metricEngine)
+	outcome.Entity = executionCtx.entity
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
@@ -168,11 +179,11 @@ func (e *hookExecutor) ExecuteProcessedAuctionStage(request *openrtb_ext.Request
 	}
 
 	stageName := hooks.StageProcessedAuctionRequest.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityAuctionRequest)
 	payload := hookstage.ProcessedAuctionRequestPayload{Request: request}
 
-	outcome, _, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
-	outcome.Entity = entityAuctionRequest
+	outcome, _, contexts, reject := executeStage(executionCtx, plan, payload, handler, cloneProcessedAuctionRequestPayload, e.metricEngine)
+	outcome.Entity = executionCtx.entity
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
@@ -202,10 +213,10 @@ func (e *hookExecutor) ExecuteBidderRequestStage(req *openrtb_ext.RequestWrapper
 	}
 
 	stageName := hooks.StageBidderRequest.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entity(bidder))
 	payload := hookstage.BidderRequestPayload{Request: req, Bidder: bidder}
-	outcome, _, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
-	outcome.Entity = entity(bidder)
+	outcome, _, contexts, reject := executeStage(executionCtx, plan, payload, handler, cloneBidderRequestPayload, e.metricEngine)
+	outcome.Entity = executionCtx.entity
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
@@ -214,10 +225,10 @@ func (e *hookExecutor) ExecuteBidderRequestStage(req *openrtb_ext.RequestWrapper
 	return reject
 }
 
-func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError {
+func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) ([]hookstage.RejectedBid, *RejectError) {
 	plan := e.planBuilder.PlanForRawBidderResponseStage(e.endpoint, e.account)
 	if len(plan) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	handler := func(
@@ -230,24 +241,24 @@ func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderRe
 	}
 
 	stageName := hooks.StageRawBidderResponse.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entity(bidder))
 	payload := hookstage.RawBidderResponsePayload{BidderResponse: response, Bidder: bidder}
 
-	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, cloneRawBidderResponsePayload, e.metricEngine)
 	response = payload.BidderResponse
-	outcome.Entity = entity(bidder)
+	outcome.Entity = executionCtx.entity
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
 	e.pushStageOutcome(outcome)
 
-	return reject
+	return payload.RejectedBids, reject
 }
 
-func (e *hookExecutor) ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+func (e *hookExecutor) ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []hookstage.RejectedProcessedBid {
 	plan := e.planBuilder.PlanForAllProcessedBidResponsesStage(e.endpoint, e.account)
 	if len(plan) == 0 {
-		return
+		return nil
 	}
 
 	handler := func(
@@ -260,14 +271,16 @@ func (e *hookExecutor) ExecuteAllProcessedBidResponsesStage(adapterBids map[open
 	}
 
 	stageName := hooks.StageAllProcessedBidResponses.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityAllProcessedBidResponses)
 	payload := hookstage.AllProcessedBidResponsesPayload{Responses: adapterBids}
-	outcome, _, contexts, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
-	outcome.Entity = entityAllProcessedBidResponses
+	outcome, payload, contexts, _ := executeStage(executionCtx, plan, payload, handler, cloneAllProcessedBidResponsesPayload, e.metricEngine)
+	outcome.Entity = executionCtx.entity
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
 	e.pushStageOutcome(outcome)
+
+	return payload.RejectedBids
 }
 
 func (e *hookExecutor) ExecuteAuctionResponseStage(response *openrtb2.BidResponse) {
@@ -286,11 +299,11 @@ func (e *hookExecutor) ExecuteAuctionResponseStage(response *openrtb2.BidRespons
 	}
 
 	stageName := hooks.StageAuctionResponse.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityAuctionResponse)
 	payload := hookstage.AuctionResponsePayload{BidResponse: response}
 
-	outcome, _, contexts, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
-	outcome.Entity = entityAuctionResponse
+	outcome, _, contexts, _ := executeStage(executionCtx, plan, payload, handler, cloneAuctionResponsePayload, e.metricEngine)
+	outcome.Entity = executionCtx.entity
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
@@ -313,11 +326,11 @@ func (e *hookExecutor) ExecuteExitpointStage(response any, w http.ResponseWriter
 	}
 
 	stageName := hooks.StageExitpoint.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityExitpoint)
 	payload := hookstage.ExitpointPayload{W: w, Response: response}
 
-	outcome, payload, context, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
-	outcome.Entity = entityExitpoint
+	outcome, payload, context, _ := executeStage(executionCtx, plan, payload, handler, nil, e.metricEngine)
+	outcome.Entity = executionCtx.entity
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(context)
@@ -326,14 +339,17 @@ func (e *hookExecutor) ExecuteExitpointStage(response any, w http.ResponseWriter
 	return payload.Response
 }
 
-func (e *hookExecutor) newContext(stage string) executionContext {
+func (e *hookExecutor) newContext(stage string, entity entity) executionContext {
 	return executionContext{
 		account:         e.account,
 		accountID:       e.accountID,
 		endpoint:        e.endpoint,
 		moduleContexts:  e.moduleContexts,
 		stage:           stage,
+		entity:          entity,
 		activityControl: e.activityControl,
+		asyncPool:       e.asyncPool,
+		asyncOutcomes:   e.asyncOutcomes,
 	}
 }
 
@@ -361,6 +377,10 @@ func (executor EmptyHookExecutor) GetOutcomes() []StageOutcome {
 	return []StageOutcome{}
 }
 
+func (executor EmptyHookExecutor) GetAsyncOutcomes() []StageOutcome {
+	return []StageOutcome{}
+}
+
 func (executor EmptyHookExecutor) ExecuteEntrypointStage(_ *http.Request, body []byte) ([]byte, *RejectError) {
 	return body, nil
 }
@@ -377,11 +397,12 @@ func (executor EmptyHookExecutor) ExecuteBidderRequestStage(_ *openrtb_ext.Reque
 	return nil
 }
 
-func (executor EmptyHookExecutor) ExecuteRawBidderResponseStage(_ *adapters.BidderResponse, _ string) *RejectError {
-	return nil
+func (executor EmptyHookExecutor) ExecuteRawBidderResponseStage(_ *adapters.BidderResponse, _ string) ([]hookstage.RejectedBid, *RejectError) {
+	return nil, nil
 }
 
-func (executor EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+func (executor EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []hookstage.RejectedProcessedBid {
+	return nil
 }
 
 func (executor EmptyHookExecutor) ExecuteAuctionResponseStage(_ *openrtb2.BidResponse) {
@@ -359,7 +359,7 @@ func TestExecuteEntrypointStage(t *testing.T) {
 			req, err := http.NewRequest(http.MethodPost, test.givenUrl, reader)
 			assert.NoError(t, err)
 
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
 			newBody, reject := exec.ExecuteEntrypointStage(req, body)
 
 			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
@@ -385,7 +385,7 @@ func TestMetricsAreGatheredDuringHookExecution(t *testing.T) {
 
 	metricEngine := &metrics.MetricsEngineMock{}
 	builder := TestAllHookResultsBuilder{}
-	exec := NewHookExecutor(TestAllHookResultsBuilder{}, "/openrtb2/auction", metricEngine)
+	exec := NewHookExecutor(TestAllHookResultsBuilder{}, "/openrtb2/auction", metricEngine, nil)
 	moduleName := "module.x-1"
 	moduleLabels := metrics.ModuleLabels{
 		Module: moduleReplacer.Replace(moduleName),
@@ -668,7 +668,7 @@ func TestExecuteRawAuctionStage(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.description, func(t *testing.T) {
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
 
 			privacyConfig := getModuleActivities("foo", false, false)
 			ac := privacy.NewActivityControl(privacyConfig)
@@ -886,7 +886,7 @@ func TestExecuteProcessedAuctionStage(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.description, func(ti *testing.T) {
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
 
 			privacyConfig := getModuleActivities("foo", false, false)
 			ac := privacy.NewActivityControl(privacyConfig)
@@ -1156,7 +1156,7 @@ func TestExecuteBidderRequestStage(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.description, func(t *testing.T) {
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
 			privacyConfig := getModuleActivities("foo", false, false)
 			ac := privacy.NewActivityControl(privacyConfig)
 			exec.SetActivityControl(ac)
@@ -1412,13 +1412,13 @@ func TestExecuteRawBidderResponseStage(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.description, func(ti *testing.T) {
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
 
 			privacyConfig := getModuleActivities("foo", false, false)
 			ac := privacy.NewActivityControl(privacyConfig)
 			exec.SetActivityControl(ac)
 
-			reject := exec.ExecuteRawBidderResponseStage(&test.givenBidderResponse, "the-bidder")
+			_, reject := exec.ExecuteRawBidderResponseStage(&test.givenBidderResponse, "the-bidder")
 
 			assert.Equal(ti, test.expectedReject, reject, "Unexpected stage reject.")
 			assert.Equal(ti, test.expectedBidderResponse, test.givenBidderResponse, "Incorrect response update.")
@@ -1434,6 +1434,33 @@ func TestExecuteRawBidderResponseStage(t *testing.T) {
 	}
 }
 
+func TestExecuteRawBidderResponseStageRejectedBids(t *testing.T) {
+	rejectedBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid-1"}}
+	keptBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid-2"}}
+	response := &adapters.BidderResponse{Bids: []*adapters.TypedBid{rejectedBid, keptBid}}
+
+	exec := NewHookExecutor(TestRejectBidsPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
+	rejectedBids, reject := exec.ExecuteRawBidderResponseStage(response, "the-bidder")
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.Equal(t, []*adapters.TypedBid{keptBid}, response.Bids, "Rejected bid not removed from the response.")
+	assert.Equal(t, []hookstage.RejectedBid{{Bid: rejectedBid, NonBidReason: 350}}, rejectedBids, "Incorrect rejected bids.")
+}
+
+func TestExecuteAllProcessedBidResponsesStageRejectedBids(t *testing.T) {
+	rejectedBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-1"}}
+	keptBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-2"}}
+	responses := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"the-bidder": {Bids: []*entities.PbsOrtbBid{rejectedBid, keptBid}},
+	}
+
+	exec := NewHookExecutor(TestRejectBidsPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
+	rejectedBids := exec.ExecuteAllProcessedBidResponsesStage(responses)
+
+	assert.Equal(t, []*entities.PbsOrtbBid{keptBid}, responses["the-bidder"].Bids, "Rejected bid not removed from the seat bid.")
+	assert.Equal(t, []hookstage.RejectedProcessedBid{{Bidder: "the-bidder", Bid: rejectedBid, NonBidReason: 300}}, rejectedBids, "Incorrect rejected bids.")
+}
+
 func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
 	foobarModuleCtx := &moduleContexts{ctxs: map[string]hookstage.ModuleContext{"foobar": nil}}
 
@@ -1687,7 +1714,7 @@ func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.description, func(t *testing.T) {
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
 
 			privacyConfig := getModuleActivities("foo", false, false)
 			ac := privacy.NewActivityControl(privacyConfig)
@@ -1932,7 +1959,7 @@ func TestExecuteAuctionResponseStage(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.description, func(t *testing.T) {
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
 
 			privacyConfig := getModuleActivities("foo", false, false)
 			ac := privacy.NewActivityControl(privacyConfig)
@@ -2315,7 +2342,7 @@ func TestExecuteExitpointStage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			e := NewHookExecutor(tt.fields.planBuilder, tt.fields.endpoint, tt.fields.metricEngine)
+			e := NewHookExecutor(tt.fields.planBuilder, tt.fields.endpoint, tt.fields.metricEngine, nil)
 			e.SetAccount(tt.fields.account)
 			e.SetActivityControl(tt.fields.activityControl)
 			newResponse := e.ExecuteExitpointStage(tt.args.response, tt.args.w)
@@ -2336,7 +2363,7 @@ func TestExecuteExitpointStage(t *testing.T) {
 func TestInterStageContextCommunication(t *testing.T) {
 	body := []byte(`{"foo": "bar"}`)
 	reader := bytes.NewReader(body)
-	exec := NewHookExecutor(TestWithModuleContextsPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	exec := NewHookExecutor(TestWithModuleContextsPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{}, nil)
 	req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", reader)
 	assert.NoError(t, err)
 
@@ -2392,7 +2419,7 @@ func TestInterStageContextCommunication(t *testing.T) {
 	}}, exec.moduleContexts, "Wrong module contexts after executing processed-auction hook.")
 
 	// test that context added at the raw bidder response stage merged with existing module contexts
-	reject = exec.ExecuteRawBidderResponseStage(&adapters.BidderResponse{}, "some-bidder")
+	_, reject = exec.ExecuteRawBidderResponseStage(&adapters.BidderResponse{}, "some-bidder")
 	assert.Nil(t, reject, "Unexpected reject from raw-bidder-response stage.")
 	assert.Equal(t, &moduleContexts{ctxs: map[string]hookstage.ModuleContext{
 		"module-1": {
@@ -2439,6 +2466,32 @@ func TestInterStageContextCommunication(t *testing.T) {
 	}}, exec.moduleContexts, "Wrong module contexts after executing auction-response hook.")
 }
 
+type TestRejectBidsPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestRejectBidsPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
+	return hooks.Plan[hookstage.RawBidderResponse]{
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "foo", Hook: mockRejectBidsHook{}},
+			},
+		},
+	}
+}
+
+func (e TestRejectBidsPlanBuilder) PlanForAllProcessedBidResponsesStage(_ string, _ *config.Account) hooks.Plan[hookstage.AllProcessedBidResponses] {
+	return hooks.Plan[hookstage.AllProcessedBidResponses]{
+		hooks.Group[hookstage.AllProcessedBidResponses]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.AllProcessedBidResponses]{
+				{Module: "foobar", Code: "foo", Hook: mockRejectProcessedBidsHook{}},
+			},
+		},
+	}
+}
+
 type TestApplyHookMutationsBuilder struct {
 	hooks.EmptyPlanBuilder
 }
@@ -384,6 +384,24 @@ func (e mockUpdateBidderResponseHook) HandleRawBidderResponseHook(_ context.Cont
 	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{ChangeSet: c}, nil
 }
 
+type mockRejectBidsHook struct{}
+
+func (e mockRejectBidsHook) HandleRawBidderResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.RawBidderResponsePayload) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	c := hookstage.ChangeSet[hookstage.RawBidderResponsePayload]{}
+	c.RawBidderResponse().Bids().RejectBids([]hookstage.RejectedBid{{Bid: payload.BidderResponse.Bids[0], NonBidReason: 350}})
+
+	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{ChangeSet: c}, nil
+}
+
+type mockRejectProcessedBidsHook struct{}
+
+func (e mockRejectProcessedBidsHook) HandleAllProcessedBidResponsesHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.AllProcessedBidResponsesPayload) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
+	c := hookstage.ChangeSet[hookstage.AllProcessedBidResponsesPayload]{}
+	c.AllProcessedBidResponses().Bids().RejectBids([]hookstage.RejectedProcessedBid{{Bidder: "the-bidder", Bid: payload.Responses["the-bidder"].Bids[0], NonBidReason: 300}})
+
+	return hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]{ChangeSet: c}, nil
+}
+
 type mockUpdateBiddersResponsesHook struct{}
 
 func (e mockUpdateBiddersResponsesHook) HandleAllProcessedBidResponsesHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.AllProcessedBidResponsesPayload) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
@@ -14,6 +14,7 @@ const (
 	StatusTimeout          Status = "timeout"           // hook was not completed in the allotted time
 	StatusFailure          Status = "failure"           // expected module-side failure occurred during hook execution
 	StatusExecutionFailure Status = "execution_failure" // unexpected failure occurred during hook execution
+	StatusPending          Status = "pending"           // async hook was not completed yet
 )
 
 // Action indicates the type of taken behaviour after the successful hook execution.
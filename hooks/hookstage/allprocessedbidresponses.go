@@ -15,6 +15,8 @@ import (
 // the account-level module config is passed to hooks.
 //
 // Rejection has no effect and is completely ignored at this stage.
+// Single bids can be rejected instead using the ChangeSetProcessedBids.RejectBids mutation,
+// they are reported as seat non bids with the given reason.
 type AllProcessedBidResponses interface {
 	HandleAllProcessedBidResponsesHook(
 		context.Context,
@@ -27,5 +29,14 @@ type AllProcessedBidResponses interface {
 // processed responses received from bidders.
 // Hooks are allowed to modify payload object and discard bids using mutations.
 type AllProcessedBidResponsesPayload struct {
-	Responses map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+	Responses    map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+	RejectedBids []RejectedProcessedBid
+}
+
+// RejectedProcessedBid is a processed bid removed from the seat bid of a bidder by a hook,
+// along with the seat non bid reason it is reported with.
+type RejectedProcessedBid struct {
+	Bidder       openrtb_ext.BidderName
+	Bid          *entities.PbsOrtbBid
+	NonBidReason int
 }
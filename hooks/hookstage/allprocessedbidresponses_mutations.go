@@ -0,0 +1,67 @@
+package hookstage
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+func (c *ChangeSet[T]) AllProcessedBidResponses() ChangeSetAllProcessedBidResponses[T] {
+	return ChangeSetAllProcessedBidResponses[T]{changeSet: c}
+}
+
+type ChangeSetAllProcessedBidResponses[T any] struct {
+	changeSet *ChangeSet[T]
+}
+
+func (c ChangeSetAllProcessedBidResponses[T]) Bids() ChangeSetProcessedBids[T] {
+	return ChangeSetProcessedBids[T]{changeSetAllProcessedBidResponses: c}
+}
+
+func (c ChangeSetAllProcessedBidResponses[T]) castPayload(p T) (AllProcessedBidResponsesPayload, error) {
+	if payload, ok := any(p).(AllProcessedBidResponsesPayload); ok {
+		return payload, nil
+	}
+	return AllProcessedBidResponsesPayload{}, errors.New("failed to cast AllProcessedBidResponsesPayload")
+}
+
+type ChangeSetProcessedBids[T any] struct {
+	changeSetAllProcessedBidResponses ChangeSetAllProcessedBidResponses[T]
+}
+
+// UpdateBids replaces the list of bids of the given bidder's seat bid using mutations.
+func (c ChangeSetProcessedBids[T]) UpdateBids(bidder openrtb_ext.BidderName, bids []*entities.PbsOrtbBid) {
+	c.changeSetAllProcessedBidResponses.changeSet.AddMutation(func(p T) (T, error) {
+		responsesPayload, err := c.changeSetAllProcessedBidResponses.castPayload(p)
+		if err != nil {
+			return p, err
+		}
+		if seatBid, ok := responsesPayload.Responses[bidder]; ok && seatBid != nil {
+			seatBid.Bids = bids
+		}
+		return p, nil
+	}, MutationUpdate, "processedbidresponses", string(bidder), "bids")
+}
+
+// RejectBids removes the bids from the seat bids of their bidders using mutations,
+// the bids are reported as seat non bids with their reason.
+func (c ChangeSetProcessedBids[T]) RejectBids(rejected []RejectedProcessedBid) {
+	c.changeSetAllProcessedBidResponses.changeSet.AddMutation(func(p T) (T, error) {
+		responsesPayload, err := c.changeSetAllProcessedBidResponses.castPayload(p)
+		if err != nil {
+			return p, err
+		}
+		for _, r := range rejected {
+			if seatBid, ok := responsesPayload.Responses[r.Bidder]; ok && seatBid != nil {
+				seatBid.Bids = slices.DeleteFunc(seatBid.Bids, func(bid *entities.PbsOrtbBid) bool { return bid == r.Bid })
+			}
+		}
+		responsesPayload.RejectedBids = append(responsesPayload.RejectedBids, rejected...)
+		if payload, ok := any(responsesPayload).(T); ok {
+			return payload, nil
+		}
+		return p, errors.New("failed to cast AllProcessedBidResponsesPayload")
+	}, MutationDelete, "processedbidresponses", "bids")
+}
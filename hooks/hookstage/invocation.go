@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/privacy"
 )
 
 // HookResult represents the result of execution the concrete hook instance.
@@ -31,6 +32,11 @@ type ModuleInvocationContext struct {
 	ModuleContext ModuleContext
 	// HookImplCode is the hook_impl_code for a module instance to differentiate between multiple hooks
 	HookImplCode string
+	// ActivityControl holds the activity rules of the request. The executor scrubs the payload a hook reads according
+	// to the activities, it does not restrict what a hook adds to the request, so the modules enriching the request,
+	// e.g. with the geo of the device, check the activities through it. It is only set once the account is resolved,
+	// from the processed auction request stage on.
+	ActivityControl privacy.ActivityControl
 }
 
 // ModuleContext holds arbitrary data passed between module hooks at different stages.
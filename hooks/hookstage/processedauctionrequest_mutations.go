@@ -91,3 +91,59 @@ func (c ChangeBidders[T]) Delete(biddersToDelete map[string]struct{}) {
 		return p, err
 	}, MutationDelete, "bidrequest", "imp", "ext", "prebid", "bidders")
 }
+
+// AddForImp keeps only the bidders found in finalBidders in the ext.prebid.bidder object of the imp with the given ID.
+func (c ChangeBidders[T]) AddForImp(impID string, finalBidders map[string]struct{}) {
+	c.changeSetProcessedAuctionRequest.changeSet.AddMutation(func(p T) (T, error) {
+		bidRequest, err := c.changeSetProcessedAuctionRequest.castPayload(p)
+		if err != nil {
+			return p, err
+		}
+		return p, filterImpBidders(bidRequest, impID, func(bidder string) bool {
+			_, exists := finalBidders[bidder]
+			return exists
+		})
+	}, MutationAdd, "bidrequest", "imp", impID, "ext", "prebid", "bidders")
+}
+
+// DeleteForImp removes the bidders found in biddersToDelete from the ext.prebid.bidder object of the imp with the given ID.
+func (c ChangeBidders[T]) DeleteForImp(impID string, biddersToDelete map[string]struct{}) {
+	c.changeSetProcessedAuctionRequest.changeSet.AddMutation(func(p T) (T, error) {
+		bidRequest, err := c.changeSetProcessedAuctionRequest.castPayload(p)
+		if err != nil {
+			return p, err
+		}
+		return p, filterImpBidders(bidRequest, impID, func(bidder string) bool {
+			_, exists := biddersToDelete[bidder]
+			return !exists
+		})
+	}, MutationDelete, "bidrequest", "imp", impID, "ext", "prebid", "bidders")
+}
+
+// filterImpBidders retains the ext.prebid.bidder entries of the imp with the given ID for which keep returns true
+func filterImpBidders(bidRequest *openrtb_ext.RequestWrapper, impID string, keep func(string) bool) error {
+	for _, impWrapper := range bidRequest.GetImp() {
+		if impWrapper.ID != impID {
+			continue
+		}
+
+		impExt, err := impWrapper.GetImpExt()
+		if err != nil {
+			return err
+		}
+		impPrebid := impExt.GetPrebid()
+		if impPrebid == nil {
+			return nil
+		}
+
+		newImpBidders := make(map[string]json.RawMessage)
+		for bidderName, bidderData := range impPrebid.Bidder {
+			if keep(bidderName) {
+				newImpBidders[bidderName] = bidderData
+			}
+		}
+		impPrebid.Bidder = newImpBidders
+		impExt.SetPrebid(impPrebid)
+	}
+	return nil
+}
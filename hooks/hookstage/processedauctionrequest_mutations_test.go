@@ -246,3 +246,70 @@ func TestDeletePrebidBidders(t *testing.T) {
 	}
 
 }
+
+func TestPrebidBiddersForImp(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(ChangeBidders[ProcessedAuctionRequestPayload])
+		expectImpA map[string]json.RawMessage
+		expectImpB map[string]json.RawMessage
+	}{
+		{
+			name: "add-for-imp-keeps-only-allowed-bidders-of-that-imp",
+			mutate: func(cb ChangeBidders[ProcessedAuctionRequestPayload]) {
+				cb.AddForImp("ImpA", map[string]struct{}{"bidderA": {}})
+			},
+			expectImpA: map[string]json.RawMessage{"bidderA": json.RawMessage(`{}`)},
+			expectImpB: map[string]json.RawMessage{"bidderA": json.RawMessage(`{}`), "bidderB": json.RawMessage(`{}`)},
+		},
+		{
+			name: "delete-for-imp-removes-bidders-of-that-imp",
+			mutate: func(cb ChangeBidders[ProcessedAuctionRequestPayload]) {
+				cb.DeleteForImp("ImpB", map[string]struct{}{"bidderA": {}})
+			},
+			expectImpA: map[string]json.RawMessage{"bidderA": json.RawMessage(`{}`), "bidderB": json.RawMessage(`{}`)},
+			expectImpB: map[string]json.RawMessage{"bidderB": json.RawMessage(`{}`)},
+		},
+		{
+			name: "unknown-imp-id-is-a-noop",
+			mutate: func(cb ChangeBidders[ProcessedAuctionRequestPayload]) {
+				cb.DeleteForImp("ImpC", map[string]struct{}{"bidderA": {}})
+			},
+			expectImpA: map[string]json.RawMessage{"bidderA": json.RawMessage(`{}`), "bidderB": json.RawMessage(`{}`)},
+			expectImpB: map[string]json.RawMessage{"bidderA": json.RawMessage(`{}`), "bidderB": json.RawMessage(`{}`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brw := openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "ImpA"}, {ID: "ImpB"}}}}
+			for _, imp := range brw.GetImp() {
+				impExt, err := imp.GetImpExt()
+				assert.NoError(t, err)
+				impExt.SetPrebid(&openrtb_ext.ExtImpPrebid{Bidder: map[string]json.RawMessage{
+					"bidderA": json.RawMessage(`{}`),
+					"bidderB": json.RawMessage(`{}`),
+				}})
+			}
+			payload := ProcessedAuctionRequestPayload{Request: &brw}
+
+			cpar := ChangeSetProcessedAuctionRequest[ProcessedAuctionRequestPayload]{
+				changeSet: &ChangeSet[ProcessedAuctionRequestPayload]{},
+			}
+			tt.mutate(cpar.Bidders())
+
+			for _, mut := range cpar.changeSet.Mutations() {
+				_, err := mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+
+			impExtA, err := payload.Request.GetImp()[0].GetImpExt()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectImpA, impExtA.GetPrebid().Bidder)
+
+			impExtB, err := payload.Request.GetImp()[1].GetImpExt()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectImpB, impExtB.GetPrebid().Bidder)
+		})
+	}
+}
@@ -13,6 +13,8 @@ import (
 // the account-level module config is passed to hooks.
 //
 // Rejection results in ignoring the bidder's response.
+// Single bids can be rejected instead using the ChangeSetBids.RejectBids mutation,
+// they are reported as seat non bids with the given reason.
 type RawBidderResponse interface {
 	HandleRawBidderResponseHook(
 		context.Context,
@@ -26,4 +28,12 @@ type RawBidderResponse interface {
 type RawBidderResponsePayload struct {
 	BidderResponse *adapters.BidderResponse
 	Bidder         string
+	RejectedBids   []RejectedBid
+}
+
+// RejectedBid is a bid removed from the bidder response by a hook,
+// along with the seat non bid reason it is reported with.
+type RejectedBid struct {
+	Bid          *adapters.TypedBid
+	NonBidReason int
 }
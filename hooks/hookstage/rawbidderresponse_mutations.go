@@ -2,6 +2,7 @@ package hookstage
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/prebid/prebid-server/v3/adapters"
 )
@@ -42,3 +43,21 @@ func (c ChangeSetBids[T]) UpdateBids(bids []*adapters.TypedBid) {
 		return p, errors.New("failed to cast RawBidderResponsePayload")
 	}, MutationUpdate, "bids")
 }
+
+// RejectBids removes the bids from the bidder-response using mutations,
+// the bids are reported as seat non bids with their reason.
+func (c ChangeSetBids[T]) RejectBids(rejected []RejectedBid) {
+	c.changeSetRawBidderResponse.changeSet.AddMutation(func(p T) (T, error) {
+		bidderPayload, err := c.changeSetRawBidderResponse.castPayload(p)
+		if err == nil {
+			bidderPayload.BidderResponse.Bids = slices.DeleteFunc(bidderPayload.BidderResponse.Bids, func(bid *adapters.TypedBid) bool {
+				return slices.ContainsFunc(rejected, func(r RejectedBid) bool { return r.Bid == bid })
+			})
+			bidderPayload.RejectedBids = append(bidderPayload.RejectedBids, rejected...)
+		}
+		if payload, ok := any(bidderPayload).(T); ok {
+			return payload, nil
+		}
+		return p, errors.New("failed to cast RawBidderResponsePayload")
+	}, MutationDelete, "bids")
+}
@@ -1,6 +1,7 @@
 package hooks
 
 import (
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
@@ -31,6 +32,12 @@ func (s Stage) IsRejectable() bool {
 		s != StageAuctionResponse && s != StageExitpoint
 }
 
+// SupportsAsync tells whether hooks can be run asynchronously at the stage,
+// the exitpoint payload holds the response writer which is not usable once the response is sent.
+func (s Stage) SupportsAsync() bool {
+	return s != StageExitpoint
+}
+
 // ExecutionPlanBuilder is the interface that provides methods
 // for retrieving hooks grouped and sorted in the established order
 // according to the hook execution plan intended for run at a certain stage.
@@ -67,6 +74,8 @@ type HookWrapper[T any] struct {
 	Code string
 	// Hook is an instance of the specific hook interface.
 	Hook T
+	// Async marks an observe-only hook run with a copy of the payload once the stage proceeds.
+	Async bool
 }
 
 // NewExecutionPlanBuilder returns a new instance of the ExecutionPlanBuilder interface.
@@ -191,7 +200,7 @@ func getMergedPlan[T any](
 func getPlan[T any](getHookFn hookFn[T], cfg config.HookExecutionPlan, endpoint string, stage Stage) Plan[T] {
 	plan := make(Plan[T], 0, len(cfg.Endpoints[endpoint].Stages[stage.String()].Groups))
 	for _, groupCfg := range cfg.Endpoints[endpoint].Stages[stage.String()].Groups {
-		group := getGroup(getHookFn, groupCfg)
+		group := getGroup(getHookFn, groupCfg, stage)
 		if len(group.Hooks) > 0 {
 			plan = append(plan, group)
 		}
@@ -200,15 +209,27 @@ func getPlan[T any](getHookFn hookFn[T], cfg config.HookExecutionPlan, endpoint
 	return plan
 }
 
-func getGroup[T any](getHookFn hookFn[T], cfg config.HookExecutionGroup) Group[T] {
+// asyncNotSupportedWarning logs a single warning for the hooks marked async at a stage not supporting it.
+// The host execution plans are validated with the config but the account ones are not, and plans are built
+// for every request.
+var asyncNotSupportedWarning sync.Once
+
+func getGroup[T any](getHookFn hookFn[T], cfg config.HookExecutionGroup, stage Stage) Group[T] {
 	group := Group[T]{
 		Timeout: time.Duration(cfg.Timeout) * time.Millisecond,
 		Hooks:   make([]HookWrapper[T], 0, len(cfg.HookSequence)),
 	}
 
 	for _, hookCfg := range cfg.HookSequence {
+		if hookCfg.Async && !stage.SupportsAsync() {
+			asyncNotSupportedWarning.Do(func() {
+				glog.Warningf("Async hook not supported at %s stage while building hook execution plan, such hooks are skipped without further warning: %s %s", stage, hookCfg.ModuleCode, hookCfg.HookImplCode)
+			})
+			continue
+		}
+
 		if h, ok := getHookFn(hookCfg.ModuleCode); ok {
-			group.Hooks = append(group.Hooks, HookWrapper[T]{Module: hookCfg.ModuleCode, Code: hookCfg.HookImplCode, Hook: h})
+			group.Hooks = append(group.Hooks, HookWrapper[T]{Module: hookCfg.ModuleCode, Code: hookCfg.HookImplCode, Hook: h, Async: hookCfg.Async})
 		} else {
 			glog.Warningf("Not found hook while building hook execution plan: %s %s", hookCfg.ModuleCode, hookCfg.HookImplCode)
 		}
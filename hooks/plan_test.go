@@ -122,6 +122,21 @@ func TestPlanForEntrypointStage(t *testing.T) {
 			givenHooks:                  nil,
 			expectedPlan:                Plan[hookstage.Entrypoint]{},
 		},
+		"Async hooks marked in plan": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(`{"endpoints": {"/openrtb2/auction": {"stages": {"entrypoint": {"groups": [{"timeout": 5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo", "async": true}, {"module_code": "foobar", "hook_impl_code": "bar"}]}]}}}}}`),
+			givenDefaultAccountPlanData: []byte(`{}`),
+			givenHooks:                  map[string]interface{}{"foobar": fakeEntrypointHook{}},
+			expectedPlan: Plan[hookstage.Entrypoint]{
+				Group[hookstage.Entrypoint]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Entrypoint]{
+						{Module: "foobar", Code: "foo", Hook: fakeEntrypointHook{}, Async: true},
+						{Module: "foobar", Code: "bar", Hook: fakeEntrypointHook{}},
+					},
+				},
+			},
+		},
 	}
 
 	for name, test := range testCases {
@@ -858,6 +873,21 @@ func TestPlanForExitpointStage(t *testing.T) {
 				},
 			},
 		},
+		"Async hooks not supported": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(`{"endpoints": {"/openrtb2/auction": {"stages": {"exitpoint": {"groups": [{"timeout": 5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo", "async": true}, {"module_code": "foobar", "hook_impl_code": "bar"}]}]}}}}}`),
+			givenDefaultAccountPlanData: []byte(`{}`),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.Exitpoint]{
+				Group[hookstage.Exitpoint]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Exitpoint]{
+						{Module: "foobar", Code: "bar", Hook: fakeExitpointHook{}},
+					},
+				},
+			},
+		},
 	}
 
 	for name, test := range testCases {
@@ -299,6 +299,12 @@ func (me *MultiMetricsEngine) RecordGvlListRequest() {
 	}
 }
 
+func (me *MultiMetricsEngine) RecordGvlListFallback() {
+	for _, thisME := range *me {
+		thisME.RecordGvlListFallback()
+	}
+}
+
 func (me *MultiMetricsEngine) RecordAdsCertReq(success bool) {
 	for _, thisME := range *me {
 		thisME.RecordAdsCertReq(success)
@@ -377,6 +383,34 @@ func (me *MultiMetricsEngine) RecordModuleTimeout(labels metrics.ModuleLabels) {
 	}
 }
 
+// RecordModuleAsyncDropped across all engines
+func (me *MultiMetricsEngine) RecordModuleAsyncDropped(labels metrics.ModuleLabels) {
+	for _, thisME := range *me {
+		thisME.RecordModuleAsyncDropped(labels)
+	}
+}
+
+// RecordModuleModelGroup across all engines
+func (me *MultiMetricsEngine) RecordModuleModelGroup(labels metrics.ModuleModelGroupLabels) {
+	for _, thisME := range *me {
+		thisME.RecordModuleModelGroup(labels)
+	}
+}
+
+// RecordModuleConfigReload across all engines
+func (me *MultiMetricsEngine) RecordModuleConfigReload(labels metrics.ModuleConfigReloadLabels) {
+	for _, thisME := range *me {
+		thisME.RecordModuleConfigReload(labels)
+	}
+}
+
+// RecordModuleIVT across all engines
+func (me *MultiMetricsEngine) RecordModuleIVT(labels metrics.ModuleIVTLabels) {
+	for _, thisME := range *me {
+		thisME.RecordModuleIVT(labels)
+	}
+}
+
 // RecordAdapterThrottled across all engines
 func (me *MultiMetricsEngine) RecordAdapterThrottled(adapter openrtb_ext.BidderName) {
 	for _, thisME := range *me {
@@ -384,6 +418,27 @@ func (me *MultiMetricsEngine) RecordAdapterThrottled(adapter openrtb_ext.BidderN
 	}
 }
 
+// RecordAdapterRateLimited across all engines
+func (me *MultiMetricsEngine) RecordAdapterRateLimited(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterRateLimited(adapter)
+	}
+}
+
+// RecordAdapterCircuitBreakerState across all engines
+func (me *MultiMetricsEngine) RecordAdapterCircuitBreakerState(adapter openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterCircuitBreakerState(adapter, state)
+	}
+}
+
+// RecordAdapterRequestWin across all engines
+func (me *MultiMetricsEngine) RecordAdapterRequestWin(adapter openrtb_ext.BidderName, attempt metrics.RequestAttempt) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterRequestWin(adapter, attempt)
+	}
+}
+
 func (me *MultiMetricsEngine) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
 	for _, thisME := range *me {
 		thisME.RecordAdapterConnectionDialError(adapterName)
@@ -534,6 +589,9 @@ func (me *NilMetricsEngine) RecordStoredResponse(pubId string) {
 func (me *NilMetricsEngine) RecordGvlListRequest() {
 }
 
+func (me *NilMetricsEngine) RecordGvlListFallback() {
+}
+
 func (me *NilMetricsEngine) RecordAdsCertReq(success bool) {
 
 }
@@ -575,10 +633,38 @@ func (me *NilMetricsEngine) RecordModuleExecutionError(labels metrics.ModuleLabe
 func (me *NilMetricsEngine) RecordModuleTimeout(labels metrics.ModuleLabels) {
 }
 
+// RecordModuleAsyncDropped as a noop
+func (me *NilMetricsEngine) RecordModuleAsyncDropped(labels metrics.ModuleLabels) {
+}
+
+// RecordModuleModelGroup as a noop
+func (me *NilMetricsEngine) RecordModuleModelGroup(labels metrics.ModuleModelGroupLabels) {
+}
+
+// RecordModuleConfigReload as a noop
+func (me *NilMetricsEngine) RecordModuleConfigReload(labels metrics.ModuleConfigReloadLabels) {
+}
+
+// RecordModuleIVT as a noop
+func (me *NilMetricsEngine) RecordModuleIVT(labels metrics.ModuleIVTLabels) {
+}
+
 // RecordAdapterThrottled as a noop
 func (me *NilMetricsEngine) RecordAdapterThrottled(adapter openrtb_ext.BidderName) {
 }
 
+// RecordAdapterRateLimited as a noop
+func (me *NilMetricsEngine) RecordAdapterRateLimited(adapter openrtb_ext.BidderName) {
+}
+
+// RecordAdapterCircuitBreakerState as a noop
+func (me *NilMetricsEngine) RecordAdapterCircuitBreakerState(adapter openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+}
+
+// RecordAdapterRequestWin as a noop
+func (me *NilMetricsEngine) RecordAdapterRequestWin(adapter openrtb_ext.BidderName, attempt metrics.RequestAttempt) {
+}
+
 func (me *NilMetricsEngine) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
 }
 
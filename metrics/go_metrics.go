@@ -36,6 +36,7 @@ type Metrics struct {
 	BidderServerResponseTimer      metrics.Timer
 	StoredResponsesMeter           metrics.Meter
 	GvlListRequestsMeter           metrics.Meter
+	GvlListFallbacksMeter          metrics.Meter
 
 	// Metrics for OpenRTB requests specifically
 	RequestStatuses       map[RequestType]map[RequestStatus]metrics.Meter
@@ -106,6 +107,7 @@ type AdapterMetrics struct {
 	BuyerUIDScrubbed   metrics.Meter
 	GDPRRequestBlocked metrics.Meter
 	ThrottledMeter     metrics.Meter
+	RateLimitedMeter   metrics.Meter
 
 	BidValidationCreativeSizeErrorMeter metrics.Meter
 	BidValidationCreativeSizeWarnMeter  metrics.Meter
@@ -188,6 +190,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
 		StoredResponsesMeter:           blankMeter,
 		GvlListRequestsMeter:           blankMeter,
+		GvlListFallbacksMeter:          blankMeter,
 
 		ImpsTypeBanner: blankMeter,
 		ImpsTypeVideo:  blankMeter,
@@ -314,6 +317,7 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 	newMetrics.PrebidCacheRequestTimerError = metrics.GetOrRegisterTimer("prebid_cache_request_time.err", registry)
 	newMetrics.StoredResponsesMeter = metrics.GetOrRegisterMeter("stored_responses", registry)
 	newMetrics.GvlListRequestsMeter = metrics.GetOrRegisterMeter("gvl_requests", registry)
+	newMetrics.GvlListFallbacksMeter = metrics.GetOrRegisterMeter("gvl_fallbacks", registry)
 	newMetrics.OverheadTimer = makeOverheadTimerMetrics(registry)
 	newMetrics.BidderServerResponseTimer = metrics.GetOrRegisterTimer("bidder_server_response_time_seconds", registry)
 
@@ -420,6 +424,7 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
 		ThrottledMeter:    blankMeter,
+		RateLimitedMeter:  blankMeter,
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -515,6 +520,7 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	am.BuyerUIDScrubbed = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.buyeruid_scrubbed", adapterOrAccount, exchange), registry)
 	am.GDPRRequestBlocked = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.gdpr_request_blocked", adapterOrAccount, exchange), registry)
 	am.ThrottledMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.throttled", adapterOrAccount, exchange), registry)
+	am.RateLimitedMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.ratelimited", adapterOrAccount, exchange), registry)
 
 	am.BidValidationCreativeSizeErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.err", adapterOrAccount, exchange), registry)
 	am.BidValidationCreativeSizeWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.size.warn", adapterOrAccount, exchange), registry)
@@ -658,6 +664,10 @@ func (me *Metrics) RecordGvlListRequest() {
 	me.GvlListRequestsMeter.Mark(1)
 }
 
+func (me *Metrics) RecordGvlListFallback() {
+	me.GvlListFallbacksMeter.Mark(1)
+}
+
 func (me *Metrics) RecordImps(labels ImpLabels) {
 	me.ImpMeter.Mark(int64(1))
 	if labels.BannerImps {
@@ -1196,6 +1206,49 @@ func (me *Metrics) RecordModuleTimeout(labels ModuleLabels) {
 	}
 }
 
+func (me *Metrics) RecordModuleAsyncDropped(labels ModuleLabels) {
+	if _, err := me.getModuleMetric(labels); err != nil {
+		return
+	}
+
+	name := fmt.Sprintf("modules.module.%s.stage.%s.async_dropped", labels.Module, labels.Stage)
+	metrics.GetOrRegisterCounter(name, me.MetricsRegistry).Inc(1)
+}
+
+func (me *Metrics) RecordModuleModelGroup(labels ModuleModelGroupLabels) {
+	if _, ok := me.ModuleMetrics[labels.Module]; !ok {
+		glog.Errorf("Trying to run module %s model group metrics: module metrics not found", labels.Module)
+		return
+	}
+
+	name := fmt.Sprintf("modules.module.%s.ruleset.%s.model.%s.selected", labels.Module, labels.RuleSet, labels.ModelVersion)
+	metrics.GetOrRegisterCounter(name, me.MetricsRegistry).Inc(1)
+}
+
+func (me *Metrics) RecordModuleConfigReload(labels ModuleConfigReloadLabels) {
+	if _, ok := me.ModuleMetrics[labels.Module]; !ok {
+		glog.Errorf("Trying to run module %s config reload metrics: module metrics not found", labels.Module)
+		return
+	}
+
+	outcome := "failure"
+	if labels.Success {
+		outcome = "success"
+	}
+	name := fmt.Sprintf("modules.module.%s.config_reload.%s", labels.Module, outcome)
+	metrics.GetOrRegisterCounter(name, me.MetricsRegistry).Inc(1)
+}
+
+func (me *Metrics) RecordModuleIVT(labels ModuleIVTLabels) {
+	if _, ok := me.ModuleMetrics[labels.Module]; !ok {
+		glog.Errorf("Trying to run module %s invalid traffic metrics: module metrics not found", labels.Module)
+		return
+	}
+
+	name := fmt.Sprintf("modules.module.%s.ivt.%s.%s", labels.Module, labels.Reason, labels.Action)
+	metrics.GetOrRegisterCounter(name, me.MetricsRegistry).Inc(1)
+}
+
 func (me *Metrics) getModuleMetric(labels ModuleLabels) (*ModuleMetrics, error) {
 	mm, ok := me.ModuleMetrics[labels.Module][labels.Stage]
 	if !ok {
@@ -1217,3 +1270,45 @@ func (me *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 
 	am.ThrottledMeter.Mark(1)
 }
+
+func (me *Metrics) RecordAdapterRateLimited(adapterName openrtb_ext.BidderName) {
+	adapterStr := adapterName.String()
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to log adapter rate limited metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	am.RateLimitedMeter.Mark(1)
+}
+
+// RecordAdapterCircuitBreakerState records the state of the circuit breaker of an adapter as 0 when closed,
+// 1 when half open and 2 when open
+func (me *Metrics) RecordAdapterCircuitBreakerState(adapterName openrtb_ext.BidderName, state CircuitBreakerState) {
+	adapterStr := strings.ToLower(adapterName.String())
+	if _, ok := me.AdapterMetrics[adapterStr]; !ok {
+		glog.Errorf("Trying to log adapter circuit breaker metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	var value int64
+	switch state {
+	case CircuitBreakerHalfOpen:
+		value = 1
+	case CircuitBreakerOpen:
+		value = 2
+	}
+	metrics.GetOrRegisterGauge(fmt.Sprintf("adapter.%s.circuit_breaker", adapterStr), me.MetricsRegistry).Update(value)
+}
+
+// RecordAdapterRequestWin counts the calls to an adapter by the attempt which got the response used
+func (me *Metrics) RecordAdapterRequestWin(adapterName openrtb_ext.BidderName, attempt RequestAttempt) {
+	adapterStr := strings.ToLower(adapterName.String())
+	if _, ok := me.AdapterMetrics[adapterStr]; !ok {
+		glog.Errorf("Trying to log adapter request win metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	name := fmt.Sprintf("adapter.%s.requests.wins.%s", adapterStr, attempt)
+	metrics.GetOrRegisterMeter(name, me.MetricsRegistry).Mark(1)
+}
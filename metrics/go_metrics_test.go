@@ -37,6 +37,7 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "setuid_requests.syncer_unknown", m.SetUidStatusMeter[SetUidSyncerUnknown])
 	ensureContains(t, registry, "stored_responses", m.StoredResponsesMeter)
 	ensureContains(t, registry, "gvl_requests", m.GvlListRequestsMeter)
+	ensureContains(t, registry, "gvl_fallbacks", m.GvlListFallbacksMeter)
 
 	ensureContains(t, registry, "prebid_cache_request_time.ok", m.PrebidCacheRequestTimerSuccess)
 	ensureContains(t, registry, "prebid_cache_request_time.err", m.PrebidCacheRequestTimerError)
@@ -1128,6 +1129,76 @@ func TestRecordModuleAccountMetrics(t *testing.T) {
 	}
 }
 
+func TestRecordModuleAsyncDropped(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, nil, config.DisabledMetrics{}, nil, map[string][]string{"foobar": {"processed_auction_request"}})
+
+	m.RecordModuleAsyncDropped(ModuleLabels{Module: "foobar", Stage: "processed_auction_request"})
+	m.RecordModuleAsyncDropped(ModuleLabels{Module: "foobar", Stage: "bidder_request"})
+	m.RecordModuleAsyncDropped(ModuleLabels{Module: "unknown", Stage: "processed_auction_request"})
+
+	assert.Equal(t, int64(1), metrics.GetOrRegisterCounter("modules.module.foobar.stage.processed_auction_request.async_dropped", registry).Count())
+	assert.Nil(t, registry.Get("modules.module.foobar.stage.bidder_request.async_dropped"))
+	assert.Nil(t, registry.Get("modules.module.unknown.stage.processed_auction_request.async_dropped"))
+}
+
+func TestRecordModuleModelGroup(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, nil, config.DisabledMetrics{}, nil, map[string][]string{"foobar": {"processed_auction_request"}})
+
+	m.RecordModuleModelGroup(ModuleModelGroupLabels{Module: "foobar", RuleSet: "ruleset-1", ModelVersion: "v1"})
+	m.RecordModuleModelGroup(ModuleModelGroupLabels{Module: "foobar", RuleSet: "ruleset-1", ModelVersion: "v1"})
+	m.RecordModuleModelGroup(ModuleModelGroupLabels{Module: "unknown", RuleSet: "ruleset-1", ModelVersion: "v1"})
+
+	assert.Equal(t, int64(2), metrics.GetOrRegisterCounter("modules.module.foobar.ruleset.ruleset-1.model.v1.selected", registry).Count())
+	assert.Nil(t, registry.Get("modules.module.unknown.ruleset.ruleset-1.model.v1.selected"))
+}
+
+func TestRecordModuleConfigReload(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, nil, config.DisabledMetrics{}, nil, map[string][]string{"foobar": {"processed_auction_request"}})
+
+	m.RecordModuleConfigReload(ModuleConfigReloadLabels{Module: "foobar", Success: true})
+	m.RecordModuleConfigReload(ModuleConfigReloadLabels{Module: "foobar", Success: true})
+	m.RecordModuleConfigReload(ModuleConfigReloadLabels{Module: "foobar", Success: false})
+	m.RecordModuleConfigReload(ModuleConfigReloadLabels{Module: "unknown", Success: true})
+
+	assert.Equal(t, int64(2), metrics.GetOrRegisterCounter("modules.module.foobar.config_reload.success", registry).Count())
+	assert.Equal(t, int64(1), metrics.GetOrRegisterCounter("modules.module.foobar.config_reload.failure", registry).Count())
+	assert.Nil(t, registry.Get("modules.module.unknown.config_reload.success"))
+}
+
+func TestRecordModuleIVT(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, nil, config.DisabledMetrics{}, nil, map[string][]string{"foobar": {"raw_auction_request"}})
+
+	m.RecordModuleIVT(ModuleIVTLabels{Module: "foobar", Reason: "user_agent", Action: "reject"})
+	m.RecordModuleIVT(ModuleIVTLabels{Module: "foobar", Reason: "user_agent", Action: "reject"})
+	m.RecordModuleIVT(ModuleIVTLabels{Module: "foobar", Reason: "datacenter_ip", Action: "mark"})
+	m.RecordModuleIVT(ModuleIVTLabels{Module: "unknown", Reason: "user_agent", Action: "reject"})
+
+	assert.Equal(t, int64(2), metrics.GetOrRegisterCounter("modules.module.foobar.ivt.user_agent.reject", registry).Count())
+	assert.Equal(t, int64(1), metrics.GetOrRegisterCounter("modules.module.foobar.ivt.datacenter_ip.mark", registry).Count())
+	assert.Nil(t, registry.Get("modules.module.unknown.ivt.user_agent.reject"))
+}
+
+func TestRecordAdapterCircuitBreakerState(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderAppnexus, CircuitBreakerOpen)
+	assert.Equal(t, int64(2), metrics.GetOrRegisterGauge("adapter.appnexus.circuit_breaker", registry).Value())
+
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderAppnexus, CircuitBreakerHalfOpen)
+	assert.Equal(t, int64(1), metrics.GetOrRegisterGauge("adapter.appnexus.circuit_breaker", registry).Value())
+
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderAppnexus, CircuitBreakerClosed)
+	assert.Equal(t, int64(0), metrics.GetOrRegisterGauge("adapter.appnexus.circuit_breaker", registry).Value())
+
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderName("unknown"), CircuitBreakerOpen)
+	assert.Nil(t, registry.Get("adapter.unknown.circuit_breaker"))
+}
+
 func TestRecordOverheadTime(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -1317,3 +1388,26 @@ func TestRecordAdapterRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordAdapterRequestWin(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterRequestWin(openrtb_ext.BidderAppnexus, RequestAttemptFirst)
+	m.RecordAdapterRequestWin(openrtb_ext.BidderAppnexus, RequestAttemptRetry)
+	m.RecordAdapterRequestWin(openrtb_ext.BidderAppnexus, RequestAttemptRetry)
+
+	assert.Equal(t, int64(1), metrics.GetOrRegisterMeter("adapter.appnexus.requests.wins.first", registry).Count())
+	assert.Equal(t, int64(2), metrics.GetOrRegisterMeter("adapter.appnexus.requests.wins.retry", registry).Count())
+	assert.Equal(t, int64(0), metrics.GetOrRegisterMeter("adapter.appnexus.requests.wins.hedge", registry).Count())
+}
+
+func TestRecordAdapterRateLimited(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterRateLimited(openrtb_ext.BidderAppnexus)
+	m.RecordAdapterRateLimited(openrtb_ext.BidderAppnexus)
+
+	assert.Equal(t, int64(2), m.AdapterMetrics[string(openrtb_ext.BidderAppnexus)].RateLimitedMeter.Count())
+}
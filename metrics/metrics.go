@@ -76,6 +76,27 @@ type ModuleLabels struct {
 	AccountID string
 }
 
+// ModuleModelGroupLabels defines metrics describing the model group a module selected to run for a rule set.
+// Modules must bound the rule set and model version values to the ones the host allows.
+type ModuleModelGroupLabels struct {
+	Module       string
+	RuleSet      string
+	ModelVersion string
+}
+
+// ModuleIVTLabels defines metrics describing the invalid traffic a module detected.
+type ModuleIVTLabels struct {
+	Module string
+	Reason string
+	Action string
+}
+
+// ModuleConfigReloadLabels defines metrics describing the outcome of a module reloading its configuration from an external source.
+type ModuleConfigReloadLabels struct {
+	Module  string
+	Success bool
+}
+
 type StoredDataType string
 
 const (
@@ -160,6 +181,12 @@ type AdapterError string
 // CacheResult : Cache hit/miss
 type CacheResult string
 
+// CircuitBreakerState : The state of the circuit breaker of a bidder
+type CircuitBreakerState string
+
+// RequestAttempt : The attempt of a call to a bidder which got the response used
+type RequestAttempt string
+
 // PublisherUnknown : Default value for Labels.PubID
 const PublisherUnknown = "unknown"
 
@@ -274,6 +301,7 @@ const (
 	RequestStatusBlockedApp       RequestStatus = "blockedapp"
 	RequestStatusQueueTimeout     RequestStatus = "queuetimeout"
 	RequestStatusAccountConfigErr RequestStatus = "acctconfigerr"
+	RequestStatusRateLimited      RequestStatus = "ratelimited"
 )
 
 func RequestStatuses() []RequestStatus {
@@ -285,6 +313,7 @@ func RequestStatuses() []RequestStatus {
 		RequestStatusBlockedApp,
 		RequestStatusQueueTimeout,
 		RequestStatusAccountConfigErr,
+		RequestStatusRateLimited,
 	}
 }
 
@@ -301,6 +330,36 @@ func AdapterBids() []AdapterBid {
 	}
 }
 
+// Circuit breaker states
+const (
+	CircuitBreakerClosed   CircuitBreakerState = "closed"
+	CircuitBreakerHalfOpen CircuitBreakerState = "half_open"
+	CircuitBreakerOpen     CircuitBreakerState = "open"
+)
+
+func CircuitBreakerStates() []CircuitBreakerState {
+	return []CircuitBreakerState{
+		CircuitBreakerClosed,
+		CircuitBreakerHalfOpen,
+		CircuitBreakerOpen,
+	}
+}
+
+// Request attempts
+const (
+	RequestAttemptFirst RequestAttempt = "first"
+	RequestAttemptRetry RequestAttempt = "retry"
+	RequestAttemptHedge RequestAttempt = "hedge"
+)
+
+func RequestAttempts() []RequestAttempt {
+	return []RequestAttempt{
+		RequestAttemptFirst,
+		RequestAttemptRetry,
+		RequestAttemptHedge,
+	}
+}
+
 // Adapter execution status
 const (
 	AdapterErrorBadInput            AdapterError = "badinput"
@@ -497,6 +556,7 @@ type MetricsEngine interface {
 	RecordDebugRequest(debugEnabled bool, pubId string)
 	RecordStoredResponse(pubId string)
 	RecordGvlListRequest()
+	RecordGvlListFallback()
 	RecordAdsCertReq(success bool)
 	RecordAdsCertSignTime(adsCertSignTime time.Duration)
 	RecordBidValidationCreativeSizeError(adapter openrtb_ext.BidderName, account string)
@@ -510,7 +570,14 @@ type MetricsEngine interface {
 	RecordModuleSuccessRejected(labels ModuleLabels)
 	RecordModuleExecutionError(labels ModuleLabels)
 	RecordModuleTimeout(labels ModuleLabels)
+	RecordModuleAsyncDropped(labels ModuleLabels)
+	RecordModuleModelGroup(labels ModuleModelGroupLabels)
+	RecordModuleConfigReload(labels ModuleConfigReloadLabels)
+	RecordModuleIVT(labels ModuleIVTLabels)
 	RecordAdapterThrottled(adapterName openrtb_ext.BidderName)
+	RecordAdapterRateLimited(adapterName openrtb_ext.BidderName)
+	RecordAdapterCircuitBreakerState(adapterName openrtb_ext.BidderName, state CircuitBreakerState)
+	RecordAdapterRequestWin(adapterName openrtb_ext.BidderName, attempt RequestAttempt)
 	RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName)
 	RecordAdapterConnectionDialTime(adapterName openrtb_ext.BidderName, dialStartTime time.Duration)
 }
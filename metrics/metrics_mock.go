@@ -179,6 +179,10 @@ func (me *MetricsEngineMock) RecordGvlListRequest() {
 	me.Called()
 }
 
+func (me *MetricsEngineMock) RecordGvlListFallback() {
+	me.Called()
+}
+
 func (me *MetricsEngineMock) RecordAdsCertReq(success bool) {
 	me.Called(success)
 }
@@ -231,10 +235,38 @@ func (me *MetricsEngineMock) RecordModuleTimeout(labels ModuleLabels) {
 	me.Called(labels)
 }
 
+func (me *MetricsEngineMock) RecordModuleAsyncDropped(labels ModuleLabels) {
+	me.Called(labels)
+}
+
+func (me *MetricsEngineMock) RecordModuleModelGroup(labels ModuleModelGroupLabels) {
+	me.Called(labels)
+}
+
+func (me *MetricsEngineMock) RecordModuleConfigReload(labels ModuleConfigReloadLabels) {
+	me.Called(labels)
+}
+
+func (me *MetricsEngineMock) RecordModuleIVT(labels ModuleIVTLabels) {
+	me.Called(labels)
+}
+
 func (me *MetricsEngineMock) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 	me.Called(adapterName)
 }
 
+func (me *MetricsEngineMock) RecordAdapterRateLimited(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
+func (me *MetricsEngineMock) RecordAdapterCircuitBreakerState(adapterName openrtb_ext.BidderName, state CircuitBreakerState) {
+	me.Called(adapterName, state)
+}
+
+func (me *MetricsEngineMock) RecordAdapterRequestWin(adapterName openrtb_ext.BidderName, attempt RequestAttempt) {
+	me.Called(adapterName, attempt)
+}
+
 func (me *MetricsEngineMock) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
 	me.Called()
 }
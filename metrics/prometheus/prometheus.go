@@ -55,6 +55,7 @@ type Metrics struct {
 	privacyTCF                   *prometheus.CounterVec
 	storedResponses              prometheus.Counter
 	gvlListRequests              prometheus.Counter
+	gvlListFallbacks             prometheus.Counter
 	storedResponsesFetchTimer    *prometheus.HistogramVec
 	storedResponsesErrors        *prometheus.CounterVec
 	adsCertRequests              *prometheus.CounterVec
@@ -79,6 +80,9 @@ type Metrics struct {
 	adapterBidResponseSecureMarkupError   *prometheus.CounterVec
 	adapterBidResponseSecureMarkupWarn    *prometheus.CounterVec
 	adapterThrottled                      *prometheus.CounterVec
+	adapterRateLimited                    *prometheus.CounterVec
+	adapterCircuitBreakerState            *prometheus.GaugeVec
+	adapterRequestWins                    *prometheus.CounterVec
 	adapterConnectionDialErrors           *prometheus.CounterVec
 	adapterConnectionDialTime             *prometheus.HistogramVec
 
@@ -104,6 +108,10 @@ type Metrics struct {
 	moduleSuccessRejects  map[string]*prometheus.CounterVec
 	moduleExecutionErrors map[string]*prometheus.CounterVec
 	moduleTimeouts        map[string]*prometheus.CounterVec
+	moduleAsyncDrops      map[string]*prometheus.CounterVec
+	moduleModelGroups     map[string]*prometheus.CounterVec
+	moduleIVT             map[string]*prometheus.CounterVec
+	moduleConfigReloads   map[string]*prometheus.CounterVec
 
 	metricsDisabled config.DisabledMetrics
 }
@@ -113,6 +121,7 @@ const (
 	actionLabel          = "action"
 	adapterErrorLabel    = "adapter_error"
 	adapterLabel         = "adapter"
+	attemptLabel         = "attempt"
 	bidTypeLabel         = "bid_type"
 	cacheResultLabel     = "cache_result"
 	connectionErrorLabel = "connection_error"
@@ -123,13 +132,17 @@ const (
 	isNativeLabel        = "native"
 	isVideoLabel         = "video"
 	markupDeliveryLabel  = "delivery"
+	modelVersionLabel    = "model_version"
 	optOutLabel          = "opt_out"
 	overheadTypeLabel    = "overhead_type"
 	privacyBlockedLabel  = "privacy_blocked"
 	requestStatusLabel   = "request_status"
 	requestTypeLabel     = "request_type"
 	requestEndpointLabel = "request_size"
+	reasonLabel          = "reason"
+	ruleSetLabel         = "ruleset"
 	stageLabel           = "stage"
+	stateLabel           = "state"
 	statusLabel          = "status"
 	successLabel         = "success"
 	syncerLabel          = "syncer"
@@ -379,6 +392,10 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"gvl_requests",
 		"Count number of times GVL list is fetched")
 
+	metrics.gvlListFallbacks = newCounterWithoutLabels(cfg, reg,
+		"gvl_fallbacks",
+		"Count number of times a GVL list version is not loaded and the latest known version is used instead")
+
 	metrics.adapterBids = newCounter(cfg, reg,
 		"adapter_bids",
 		"Count of bids labeled by adapter and markup delivery type (adm or nurl).",
@@ -461,6 +478,21 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of requests throttled labeled by adapter.",
 		[]string{adapterLabel})
 
+	metrics.adapterRateLimited = newCounter(cfg, reg,
+		"adapter_rate_limited",
+		"Count of requests not sent to the adapter over its rate limit labeled by adapter.",
+		[]string{adapterLabel})
+
+	metrics.adapterCircuitBreakerState = newGauge(cfg, reg,
+		"adapter_circuit_breaker_state",
+		"Circuit breaker state of the adapters, 1 for the current state and 0 for the others, labeled by adapter and state.",
+		[]string{adapterLabel, stateLabel})
+
+	metrics.adapterRequestWins = newCounter(cfg, reg,
+		"adapter_request_wins",
+		"Count of the calls to adapters with retries or hedging labeled by adapter and attempt which got the response used.",
+		[]string{adapterLabel, attemptLabel})
+
 	metrics.overheadTimer = newHistogramVec(cfg, reg,
 		"overhead_time_seconds",
 		"Seconds to prepare adapter request or resolve adapter response",
@@ -569,6 +601,10 @@ func createModulesMetrics(cfg config.PrometheusMetrics, registry *prometheus.Reg
 	m.moduleSuccessRejects = make(map[string]*prometheus.CounterVec, l)
 	m.moduleExecutionErrors = make(map[string]*prometheus.CounterVec, l)
 	m.moduleTimeouts = make(map[string]*prometheus.CounterVec, l)
+	m.moduleAsyncDrops = make(map[string]*prometheus.CounterVec, l)
+	m.moduleModelGroups = make(map[string]*prometheus.CounterVec, l)
+	m.moduleConfigReloads = make(map[string]*prometheus.CounterVec, l)
+	m.moduleIVT = make(map[string]*prometheus.CounterVec, l)
 
 	// create for each registered module its own metric
 	for module := range moduleStageNames {
@@ -612,6 +648,26 @@ func createModulesMetrics(cfg config.PrometheusMetrics, registry *prometheus.Reg
 			fmt.Sprintf("modules_%s_timeouts", module),
 			"Count of module timeouts labeled by stage name.",
 			[]string{stageLabel})
+
+		m.moduleAsyncDrops[module] = newCounter(cfg, registry,
+			fmt.Sprintf("modules_%s_async_drops", module),
+			"Count of module async hooks dropped as the async execution queue is full labeled by stage name.",
+			[]string{stageLabel})
+
+		m.moduleModelGroups[module] = newCounter(cfg, registry,
+			fmt.Sprintf("modules_%s_model_groups", module),
+			"Count of model groups a module selected labeled by rule set name and model version.",
+			[]string{ruleSetLabel, modelVersionLabel})
+
+		m.moduleConfigReloads[module] = newCounter(cfg, registry,
+			fmt.Sprintf("modules_%s_config_reloads", module),
+			"Count of module configuration reloads from an external source labeled by success or failure.",
+			[]string{successLabel})
+
+		m.moduleIVT[module] = newCounter(cfg, registry,
+			fmt.Sprintf("modules_%s_ivt", module),
+			"Count of invalid traffic a module detected labeled by reason and action taken.",
+			[]string{reasonLabel, actionLabel})
 	}
 }
 
@@ -627,6 +683,18 @@ func newCounter(cfg config.PrometheusMetrics, registry *prometheus.Registry, nam
 	return counter
 }
 
+func newGauge(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string) *prometheus.GaugeVec {
+	opts := prometheus.GaugeOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      name,
+		Help:      help,
+	}
+	gauge := prometheus.NewGaugeVec(opts, labels)
+	registry.MustRegister(gauge)
+	return gauge
+}
+
 func newCounterWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string) prometheus.Counter {
 	opts := prometheus.CounterOpts{
 		Namespace: cfg.Namespace,
@@ -739,6 +807,10 @@ func (m *Metrics) RecordGvlListRequest() {
 	m.gvlListRequests.Inc()
 }
 
+func (m *Metrics) RecordGvlListFallback() {
+	m.gvlListFallbacks.Inc()
+}
+
 func (m *Metrics) RecordImps(labels metrics.ImpLabels) {
 	m.impressions.With(prometheus.Labels{
 		isBannerLabel: strconv.FormatBool(labels.BannerImps),
@@ -1135,12 +1207,76 @@ func (m *Metrics) RecordModuleTimeout(labels metrics.ModuleLabels) {
 	}).Inc()
 }
 
+func (m *Metrics) RecordModuleAsyncDropped(labels metrics.ModuleLabels) {
+	m.moduleAsyncDrops[labels.Module].With(prometheus.Labels{
+		stageLabel: labels.Stage,
+	}).Inc()
+}
+
+func (m *Metrics) RecordModuleModelGroup(labels metrics.ModuleModelGroupLabels) {
+	counter, ok := m.moduleModelGroups[labels.Module]
+	if !ok {
+		return
+	}
+	counter.With(prometheus.Labels{
+		ruleSetLabel:      labels.RuleSet,
+		modelVersionLabel: labels.ModelVersion,
+	}).Inc()
+}
+
+func (m *Metrics) RecordModuleConfigReload(labels metrics.ModuleConfigReloadLabels) {
+	counter, ok := m.moduleConfigReloads[labels.Module]
+	if !ok {
+		return
+	}
+	counter.With(prometheus.Labels{
+		successLabel: strconv.FormatBool(labels.Success),
+	}).Inc()
+}
+
+func (m *Metrics) RecordModuleIVT(labels metrics.ModuleIVTLabels) {
+	counter, ok := m.moduleIVT[labels.Module]
+	if !ok {
+		return
+	}
+	counter.With(prometheus.Labels{
+		reasonLabel: labels.Reason,
+		actionLabel: labels.Action,
+	}).Inc()
+}
+
 func (m *Metrics) RecordAdapterThrottled(adapterName openrtb_ext.BidderName) {
 	m.adapterThrottled.With(prometheus.Labels{
 		adapterLabel: strings.ToLower(string(adapterName)),
 	}).Inc()
 }
 
+func (m *Metrics) RecordAdapterRateLimited(adapterName openrtb_ext.BidderName) {
+	m.adapterRateLimited.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterCircuitBreakerState(adapterName openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	for _, s := range metrics.CircuitBreakerStates() {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		m.adapterCircuitBreakerState.With(prometheus.Labels{
+			adapterLabel: strings.ToLower(string(adapterName)),
+			stateLabel:   string(s),
+		}).Set(value)
+	}
+}
+
+func (m *Metrics) RecordAdapterRequestWin(adapterName openrtb_ext.BidderName, attempt metrics.RequestAttempt) {
+	m.adapterRequestWins.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+		attemptLabel: string(attempt),
+	}).Inc()
+}
+
 func (m *Metrics) RecordAdapterConnectionDialError(adapterName openrtb_ext.BidderName) {
 	m.adapterConnectionDialErrors.With(prometheus.Labels{
 		adapterLabel: strings.ToLower(string(adapterName)),
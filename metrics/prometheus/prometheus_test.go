@@ -1953,6 +1953,14 @@ func TestRecordGvlListRequest(t *testing.T) {
 	assertCounterValue(t, "Record instance of fetched GVL list", "success", m.gvlListRequests, 1.00)
 }
 
+func TestRecordGvlListFallback(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordGvlListFallback()
+
+	assertCounterValue(t, "Record instance of GVL list fallback", "fallback", m.gvlListFallbacks, 1.00)
+}
+
 func TestRecordAdsCertReqMetric(t *testing.T) {
 	testCases := []struct {
 		description                  string
@@ -2072,6 +2080,10 @@ func TestRecordModuleMetrics(t *testing.T) {
 				Module: module,
 				Stage:  stage,
 			})
+			m.RecordModuleAsyncDropped(metrics.ModuleLabels{
+				Module: module,
+				Stage:  stage,
+			})
 
 			// now check that the values are correct
 			result, found := getHistogramFromHistogramVec(m.moduleDuration[module], stageLabel, stage)
@@ -2084,6 +2096,82 @@ func TestRecordModuleMetrics(t *testing.T) {
 			assertCounterVecValue(t, "Module success reject action", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleSuccessRejects[module], 1, prometheus.Labels{stageLabel: stage})
 			assertCounterVecValue(t, "Module execution error", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleExecutionErrors[module], 1, prometheus.Labels{stageLabel: stage})
 			assertCounterVecValue(t, "Module timeout", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleTimeouts[module], 1, prometheus.Labels{stageLabel: stage})
+			assertCounterVecValue(t, "Module async drop", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleAsyncDrops[module], 1, prometheus.Labels{stageLabel: stage})
 		}
 	}
 }
+
+func TestRecordModuleModelGroup(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordModuleModelGroup(metrics.ModuleModelGroupLabels{Module: "foobar", RuleSet: "ruleset-1", ModelVersion: "v1"})
+	m.RecordModuleModelGroup(metrics.ModuleModelGroupLabels{Module: "foobar", RuleSet: "ruleset-1", ModelVersion: "v1"})
+	m.RecordModuleModelGroup(metrics.ModuleModelGroupLabels{Module: "foobar", RuleSet: "ruleset-1", ModelVersion: "v2"})
+	m.RecordModuleModelGroup(metrics.ModuleModelGroupLabels{Module: "unknown", RuleSet: "ruleset-1", ModelVersion: "v1"})
+
+	assertCounterVecValue(t, "", "model group v1", m.moduleModelGroups["foobar"], 2, prometheus.Labels{ruleSetLabel: "ruleset-1", modelVersionLabel: "v1"})
+	assertCounterVecValue(t, "", "model group v2", m.moduleModelGroups["foobar"], 1, prometheus.Labels{ruleSetLabel: "ruleset-1", modelVersionLabel: "v2"})
+	assertCounterVecValue(t, "", "other module", m.moduleModelGroups["another_module"], 0, prometheus.Labels{ruleSetLabel: "ruleset-1", modelVersionLabel: "v1"})
+}
+
+func TestRecordModuleConfigReload(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordModuleConfigReload(metrics.ModuleConfigReloadLabels{Module: "foobar", Success: true})
+	m.RecordModuleConfigReload(metrics.ModuleConfigReloadLabels{Module: "foobar", Success: true})
+	m.RecordModuleConfigReload(metrics.ModuleConfigReloadLabels{Module: "foobar", Success: false})
+	m.RecordModuleConfigReload(metrics.ModuleConfigReloadLabels{Module: "unknown", Success: true})
+
+	assertCounterVecValue(t, "", "successful reloads", m.moduleConfigReloads["foobar"], 2, prometheus.Labels{successLabel: "true"})
+	assertCounterVecValue(t, "", "failed reloads", m.moduleConfigReloads["foobar"], 1, prometheus.Labels{successLabel: "false"})
+}
+
+func TestRecordModuleIVT(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordModuleIVT(metrics.ModuleIVTLabels{Module: "foobar", Reason: "user_agent", Action: "reject"})
+	m.RecordModuleIVT(metrics.ModuleIVTLabels{Module: "foobar", Reason: "user_agent", Action: "reject"})
+	m.RecordModuleIVT(metrics.ModuleIVTLabels{Module: "foobar", Reason: "datacenter_ip", Action: "mark"})
+	m.RecordModuleIVT(metrics.ModuleIVTLabels{Module: "unknown", Reason: "user_agent", Action: "reject"})
+
+	assertCounterVecValue(t, "", "rejected bots", m.moduleIVT["foobar"], 2, prometheus.Labels{reasonLabel: "user_agent", actionLabel: "reject"})
+	assertCounterVecValue(t, "", "marked datacenter traffic", m.moduleIVT["foobar"], 1, prometheus.Labels{reasonLabel: "datacenter_ip", actionLabel: "mark"})
+}
+
+func TestRecordAdapterCircuitBreakerState(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderAppnexus, metrics.CircuitBreakerHalfOpen)
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderRubicon, metrics.CircuitBreakerOpen)
+
+	assertGaugeVecValue(t, "closed", m.adapterCircuitBreakerState, 0, prometheus.Labels{adapterLabel: "appnexus", stateLabel: "closed"})
+	assertGaugeVecValue(t, "half open", m.adapterCircuitBreakerState, 1, prometheus.Labels{adapterLabel: "appnexus", stateLabel: "half_open"})
+	assertGaugeVecValue(t, "no longer open", m.adapterCircuitBreakerState, 0, prometheus.Labels{adapterLabel: "appnexus", stateLabel: "open"})
+	assertGaugeVecValue(t, "other adapter open", m.adapterCircuitBreakerState, 1, prometheus.Labels{adapterLabel: "rubicon", stateLabel: "open"})
+}
+
+func assertGaugeVecValue(t *testing.T, description string, gaugeVec *prometheus.GaugeVec, expected float64, labels prometheus.Labels) {
+	m := dto.Metric{}
+	gaugeVec.With(labels).Write(&m)
+	assert.Equal(t, expected, m.GetGauge().GetValue(), description)
+}
+
+func TestRecordAdapterRequestWin(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterRequestWin(openrtb_ext.BidderAppnexus, metrics.RequestAttemptFirst)
+	m.RecordAdapterRequestWin(openrtb_ext.BidderAppnexus, metrics.RequestAttemptHedge)
+	m.RecordAdapterRequestWin(openrtb_ext.BidderAppnexus, metrics.RequestAttemptHedge)
+
+	assertCounterVecValue(t, "", "first attempt wins", m.adapterRequestWins, 1, prometheus.Labels{adapterLabel: "appnexus", attemptLabel: "first"})
+	assertCounterVecValue(t, "", "hedge wins", m.adapterRequestWins, 2, prometheus.Labels{adapterLabel: "appnexus", attemptLabel: "hedge"})
+}
+
+func TestRecordAdapterRateLimited(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterRateLimited(openrtb_ext.BidderAppnexus)
+
+	assertCounterVecValue(t, "", "rate limited", m.adapterRateLimited, 1, prometheus.Labels{adapterLabel: "appnexus"})
+}
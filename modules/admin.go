@@ -0,0 +1,51 @@
+package modules
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+)
+
+// AdminEndpointer is an interface that defines a method for modules exposing endpoints on the admin server.
+type AdminEndpointer interface {
+	// AdminEndpoints returns the module handlers keyed by their path relative to the module admin path.
+	AdminEndpoints(deps moduledeps.AdminDeps) map[string]http.HandlerFunc
+}
+
+// AdminModules is a struct that holds the AdminEndpointer modules keyed by their "vendor.module_name" ID.
+type AdminModules struct {
+	modules map[string]AdminEndpointer
+}
+
+// NewAdminModules creates a new AdminModules instance from a map of modules.
+// It filters the modules to include only those that implement the AdminEndpointer interface.
+func NewAdminModules(modules map[string]interface{}) *AdminModules {
+	am := AdminModules{
+		modules: make(map[string]AdminEndpointer),
+	}
+
+	for id, module := range modules {
+		if v, ok := module.(AdminEndpointer); ok {
+			am.modules[id] = v
+		}
+	}
+	return &am
+}
+
+// Endpoints collects the admin handlers of all modules. Each handler is mounted under
+// "/modules/{vendor}/{module_name}/" followed by the path returned by the module.
+func (a *AdminModules) Endpoints(deps moduledeps.AdminDeps) map[string]http.HandlerFunc {
+	endpoints := make(map[string]http.HandlerFunc)
+	if a == nil {
+		return endpoints
+	}
+
+	for id, module := range a.modules {
+		prefix := "/modules/" + strings.Replace(id, ".", "/", 1) + "/"
+		for path, handler := range module.AdminEndpoints(deps) {
+			endpoints[prefix+strings.TrimPrefix(path, "/")] = handler
+		}
+	}
+	return endpoints
+}
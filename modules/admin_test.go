@@ -0,0 +1,92 @@
+package modules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAdminModule is a test implementation of the AdminEndpointer interface
+type mockAdminModule struct {
+	paths []string
+}
+
+func (m *mockAdminModule) AdminEndpoints(_ moduledeps.AdminDeps) map[string]http.HandlerFunc {
+	endpoints := make(map[string]http.HandlerFunc)
+	for _, path := range m.paths {
+		body := path
+		endpoints[path] = func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte(body))
+		}
+	}
+	return endpoints
+}
+
+func TestNewAdminModules(t *testing.T) {
+	tests := []struct {
+		name        string
+		modules     map[string]interface{}
+		expectedIDs []string
+	}{
+		{
+			name:        "nil-modules",
+			modules:     nil,
+			expectedIDs: []string{},
+		},
+		{
+			name: "admin-and-non-admin-modules",
+			modules: map[string]interface{}{
+				"vendor.module1": &mockAdminModule{},
+				"vendor.module2": &nonShutdownModule{name: "module2"},
+			},
+			expectedIDs: []string{"vendor.module1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewAdminModules(tt.modules)
+
+			assert.NotNil(t, result)
+
+			ids := make([]string, 0, len(result.modules))
+			for id := range result.modules {
+				ids = append(ids, id)
+			}
+			assert.ElementsMatch(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestAdminModules_Endpoints(t *testing.T) {
+	am := NewAdminModules(map[string]interface{}{
+		"acme.foo": &mockAdminModule{paths: []string{"explain", "/status"}},
+		"acme.bar": &mockAdminModule{paths: []string{"explain"}},
+	})
+
+	endpoints := am.Endpoints(moduledeps.AdminDeps{})
+
+	expected := map[string]string{
+		"/modules/acme/foo/explain": "explain",
+		"/modules/acme/foo/status":  "/status",
+		"/modules/acme/bar/explain": "explain",
+	}
+	assert.Len(t, endpoints, len(expected))
+	for path, body := range expected {
+		handler, ok := endpoints[path]
+		if assert.True(t, ok, path) {
+			recorder := httptest.NewRecorder()
+			handler(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, body, recorder.Body.String(), path)
+		}
+	}
+}
+
+func TestAdminModules_EndpointsNil(t *testing.T) {
+	var am *AdminModules
+
+	assert.Empty(t, am.Endpoints(moduledeps.AdminDeps{}))
+}
@@ -2,8 +2,12 @@ package modules
 
 import (
 	fiftyonedegreesDevicedetection "github.com/prebid/prebid-server/v3/modules/fiftyonedegrees/devicedetection"
+	prebidCreativescan "github.com/prebid/prebid-server/v3/modules/prebid/creativescan"
+	prebidGeolocation "github.com/prebid/prebid-server/v3/modules/prebid/geolocation"
+	prebidIvt "github.com/prebid/prebid-server/v3/modules/prebid/ivt"
 	prebidOrtb2blocking "github.com/prebid/prebid-server/v3/modules/prebid/ortb2blocking"
 	prebidRulesengine "github.com/prebid/prebid-server/v3/modules/prebid/rulesengine"
+	prebidWasm "github.com/prebid/prebid-server/v3/modules/prebid/wasm"
 	scope3Rtd "github.com/prebid/prebid-server/v3/modules/scope3/rtd"
 )
 
@@ -15,8 +19,12 @@ func builders() ModuleBuilders {
 			"devicedetection": fiftyonedegreesDevicedetection.Builder,
 		},
 		"prebid": {
+			"creativescan":  prebidCreativescan.Builder,
+			"geolocation":   prebidGeolocation.Builder,
+			"ivt":           prebidIvt.Builder,
 			"ortb2blocking": prebidOrtb2blocking.Builder,
 			"rulesengine":   prebidRulesengine.Builder,
+			"wasm":          prebidWasm.Builder,
 		},
 		"scope3": {
 			"rtd": scope3Rtd.Builder,
@@ -2,8 +2,12 @@ package moduledeps
 
 import (
 	"net/http"
+	"sync/atomic"
 
+	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/stored_requests"
 )
 
 // ModuleDeps provides dependencies that custom modules may need for hooks execution.
@@ -11,4 +15,47 @@ import (
 type ModuleDeps struct {
 	HTTPClient    *http.Client
 	RateConvertor *currency.RateConverter
+	MetricsEngine *MetricsEngineRef
+}
+
+// AdminDeps provides dependencies that modules may need to serve their admin endpoints.
+// They are only available once the modules are built, which is why they are not part of ModuleDeps.
+type AdminDeps struct {
+	Config         *config.Configuration
+	AccountFetcher stored_requests.AccountFetcher
+	MetricsEngine  metrics.MetricsEngine
+}
+
+// MetricsEngineRef references the metrics engine modules record metrics of their own to.
+// The metrics engine depends on the stages modules provide hooks for, so it is only set
+// once all the modules are built. It is safe to read while it is being set.
+type MetricsEngineRef struct {
+	engine atomic.Pointer[metricsEngineHolder]
+}
+
+type metricsEngineHolder struct {
+	me metrics.MetricsEngine
+}
+
+// NewMetricsEngineRef returns a reference to the given metrics engine, which may be nil if it is set later.
+func NewMetricsEngineRef(me metrics.MetricsEngine) *MetricsEngineRef {
+	ref := &MetricsEngineRef{}
+	ref.Set(me)
+	return ref
+}
+
+// Set sets the metrics engine referenced.
+func (r *MetricsEngineRef) Set(me metrics.MetricsEngine) {
+	r.engine.Store(&metricsEngineHolder{me: me})
+}
+
+// Get returns the metrics engine referenced or nil if it is not set yet or the reference is nil.
+func (r *MetricsEngineRef) Get() metrics.MetricsEngine {
+	if r == nil {
+		return nil
+	}
+	if h := r.engine.Load(); h != nil {
+		return h.me
+	}
+	return nil
 }
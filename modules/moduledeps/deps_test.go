@@ -0,0 +1,20 @@
+package moduledeps
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsEngineRef(t *testing.T) {
+	var nilRef *MetricsEngineRef
+	assert.Nil(t, nilRef.Get(), "nil reference")
+
+	ref := NewMetricsEngineRef(nil)
+	assert.Nil(t, ref.Get(), "engine not set yet")
+
+	me := &metrics.MetricsEngineMock{}
+	ref.Set(me)
+	assert.Same(t, me, ref.Get(), "engine set")
+}
@@ -23,9 +23,10 @@ func NewBuilder() Builder {
 type Builder interface {
 	// Build initializes existing hook modules passing them config and other dependencies.
 	// It returns hook repository created based on the implemented hook interfaces by modules
-	// and a map of modules to a list of stage names for which module provides hooks
+	// and a map of modules to a list of stage names for which module provides hooks,
+	// the modules to shut down and the modules exposing admin endpoints
 	// or an error encountered during module initialization.
-	Build(cfg config.Modules, client moduledeps.ModuleDeps) (hooks.HookRepository, map[string][]string, *ShutdownModules, error)
+	Build(cfg config.Modules, client moduledeps.ModuleDeps) (hooks.HookRepository, map[string][]string, *ShutdownModules, *AdminModules, error)
 }
 
 type (
@@ -49,7 +50,7 @@ type builder struct {
 func (m *builder) Build(
 	cfg config.Modules,
 	deps moduledeps.ModuleDeps,
-) (hooks.HookRepository, map[string][]string, *ShutdownModules, error) {
+) (hooks.HookRepository, map[string][]string, *ShutdownModules, *AdminModules, error) {
 	modules := make(map[string]interface{})
 	for vendor, moduleBuilders := range m.builders {
 		for moduleName, builder := range moduleBuilders {
@@ -60,7 +61,7 @@ func (m *builder) Build(
 			id := fmt.Sprintf("%s.%s", vendor, moduleName)
 			if data, ok := cfg[vendor][moduleName]; ok {
 				if conf, err = jsonutil.Marshal(data); err != nil {
-					return nil, nil, nil, fmt.Errorf(`failed to marshal "%s" module config: %s`, id, err)
+					return nil, nil, nil, nil, fmt.Errorf(`failed to marshal "%s" module config: %s`, id, err)
 				}
 
 				if values, ok := data.(map[string]interface{}); ok {
@@ -77,7 +78,7 @@ func (m *builder) Build(
 
 			module, err := builder(conf, deps)
 			if err != nil {
-				return nil, nil, nil, fmt.Errorf(`failed to init "%s" module: %s`, id, err)
+				return nil, nil, nil, nil, fmt.Errorf(`failed to init "%s" module: %s`, id, err)
 			}
 
 			modules[id] = module
@@ -86,12 +87,13 @@ func (m *builder) Build(
 
 	collection, err := createModuleStageNamesCollection(modules)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	repo, err := hooks.NewHookRepository(modules)
 
 	sdm := NewShutdownModules(modules)
+	adm := NewAdminModules(modules)
 
-	return repo, collection, sdm, err
+	return repo, collection, sdm, adm, err
 }
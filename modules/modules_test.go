@@ -36,6 +36,7 @@ func TestModuleBuilderBuild(t *testing.T) {
 		expectedHookRepo        hooks.HookRepository
 		expectedModulesStages   map[string][]string
 		expectedShutdownModules *ShutdownModules
+		expectedAdminModules    *AdminModules
 		expectedErr             error
 	}{
 		"Can build module with config": {
@@ -44,6 +45,7 @@ func TestModuleBuilderBuild(t *testing.T) {
 			expectedModulesStages:   map[string][]string{vendor + "_" + moduleName: {hooks.StageEntrypoint.String(), hooks.StageAuctionResponse.String()}},
 			expectedHookRepo:        defaultHookRepository,
 			expectedShutdownModules: &ShutdownModules{modules: []Shutdowner{module{}}},
+			expectedAdminModules:    &AdminModules{modules: map[string]AdminEndpointer{}},
 			expectedErr:             nil,
 		},
 		"Module is not added to hook repository if it's disabled": {
@@ -52,6 +54,7 @@ func TestModuleBuilderBuild(t *testing.T) {
 			expectedModulesStages:   map[string][]string{},
 			expectedHookRepo:        emptyHookRepository,
 			expectedShutdownModules: &ShutdownModules{modules: []Shutdowner{}},
+			expectedAdminModules:    &AdminModules{modules: map[string]AdminEndpointer{}},
 			expectedErr:             nil,
 		},
 		"Module considered disabled if status property not defined in module config": {
@@ -60,6 +63,7 @@ func TestModuleBuilderBuild(t *testing.T) {
 			expectedHookRepo:        emptyHookRepository,
 			expectedModulesStages:   map[string][]string{},
 			expectedShutdownModules: &ShutdownModules{modules: []Shutdowner{}},
+			expectedAdminModules:    &AdminModules{modules: map[string]AdminEndpointer{}},
 			expectedErr:             nil,
 		},
 		"Module considered disabled if its config not provided and as a result skipped from execution": {
@@ -68,6 +72,7 @@ func TestModuleBuilderBuild(t *testing.T) {
 			expectedHookRepo:        emptyHookRepository,
 			expectedModulesStages:   map[string][]string{},
 			expectedShutdownModules: &ShutdownModules{modules: []Shutdowner{}},
+			expectedAdminModules:    &AdminModules{modules: map[string]AdminEndpointer{}},
 			expectedErr:             nil,
 		},
 		"Fails if module does not implement any hook interface": {
@@ -109,10 +114,11 @@ func TestModuleBuilderBuild(t *testing.T) {
 				},
 			}
 
-			repo, modulesStages, shutdownModules, err := builder.Build(test.givenConfig, moduledeps.ModuleDeps{HTTPClient: http.DefaultClient})
+			repo, modulesStages, shutdownModules, adminModules, err := builder.Build(test.givenConfig, moduledeps.ModuleDeps{HTTPClient: http.DefaultClient})
 			assert.Equal(t, test.expectedErr, err)
 			assert.Equal(t, test.expectedModulesStages, modulesStages)
 			assert.Equal(t, test.expectedShutdownModules, shutdownModules)
+			assert.Equal(t, test.expectedAdminModules, adminModules)
 			assert.Equal(t, test.expectedHookRepo, repo)
 		})
 	}
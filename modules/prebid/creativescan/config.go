@@ -0,0 +1,118 @@
+package creativescan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config: %s", err)
+	}
+	return cfg, nil
+}
+
+// config holds the rules the markup of the bids is checked against,
+// the host configuration being the default of the accounts
+type config struct {
+	BlockedDomains     []string `json:"blocked_domains"`
+	BlockedScriptHosts []string `json:"blocked_script_hosts"`
+	AllowedScriptHosts []string `json:"allowed_script_hosts"`
+	RequireHTTPS       bool     `json:"require_https"`
+	MaxAdmBytes        int      `json:"max_adm_bytes"`
+}
+
+// accountConfig overrides the host configuration with the fields set in the account config
+func (c config) accountConfig(data json.RawMessage) (config, error) {
+	if len(data) == 0 {
+		return c, nil
+	}
+
+	// the host lists are shared by all the requests, they must not be overwritten in place
+	c.BlockedDomains = slices.Clone(c.BlockedDomains)
+	c.BlockedScriptHosts = slices.Clone(c.BlockedScriptHosts)
+	c.AllowedScriptHosts = slices.Clone(c.AllowedScriptHosts)
+	if err := jsonutil.UnmarshalValid(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse account config: %s", err)
+	}
+	if err := c.validate(); err != nil {
+		return c, fmt.Errorf("invalid account config: %s", err)
+	}
+	return c, nil
+}
+
+func (c config) validate() error {
+	if c.MaxAdmBytes < 0 {
+		return errors.New("max_adm_bytes must be >= 0")
+	}
+	return nil
+}
+
+// rules returns the config with the domains normalized for matching
+func (c config) rules() rules {
+	return rules{
+		blockedDomains:     newDomainSet(c.BlockedDomains),
+		blockedScriptHosts: newDomainSet(c.BlockedScriptHosts),
+		allowedScriptHosts: newDomainSet(c.AllowedScriptHosts),
+		requireHTTPS:       c.RequireHTTPS,
+		maxAdmBytes:        c.MaxAdmBytes,
+	}
+}
+
+type rules struct {
+	blockedDomains     domainSet
+	blockedScriptHosts domainSet
+	allowedScriptHosts domainSet
+	requireHTTPS       bool
+	maxAdmBytes        int
+}
+
+// domainSet matches hosts to domains, a domain matching itself and all its subdomains
+type domainSet map[string]struct{}
+
+func newDomainSet(domains []string) domainSet {
+	set := make(domainSet, len(domains))
+	for _, domain := range domains {
+		if domain = normalizeHost(domain); domain != "" {
+			set[domain] = struct{}{}
+		}
+	}
+	return set
+}
+
+// match returns the domain of the set the host belongs to
+func (s domainSet) match(host string) (string, bool) {
+	for host != "" {
+		if _, ok := s[host]; ok {
+			return host, true
+		}
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			break
+		}
+		host = host[i+1:]
+	}
+	return "", false
+}
+
+// normalizeHost lower cases the host and removes its port and trailing dot
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	return strings.TrimSuffix(host, ".")
+}
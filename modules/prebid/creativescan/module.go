@@ -0,0 +1,101 @@
+package creativescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+)
+
+// scanActivity is the only analytics activity of the module
+const scanActivity = "creative_scan"
+
+// Keys of the analytics values of the rejected bids
+const (
+	ruleAnalyticKey  = "rule"
+	matchAnalyticKey = "match"
+)
+
+// Builder reads the host configuration, the default of the accounts
+func Builder(cfg json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	c, err := newConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return Module{cfg: c, rules: c.rules()}, nil
+}
+
+var _ hookstage.RawBidderResponse = Module{}
+
+// Module rejects the bids whose markup loads resources from blocked domains or script hosts,
+// loads insecure resources or is too large
+type Module struct {
+	cfg   config
+	rules rules
+}
+
+// HandleRawBidderResponseHook scans the markup of the bids of the bidder and rejects the ones breaking a rule.
+// The rejected bids are reported as seat non bids and the broken rule through the analytics tags.
+func (m Module) HandleRawBidderResponseHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawBidderResponsePayload,
+) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	result := hookstage.HookResult[hookstage.RawBidderResponsePayload]{}
+	if payload.BidderResponse == nil {
+		return result, nil
+	}
+
+	r := m.rules
+	if len(miCtx.AccountConfig) != 0 {
+		cfg, err := m.cfg.accountConfig(miCtx.AccountConfig)
+		if err != nil {
+			return result, err
+		}
+		r = cfg.rules()
+	}
+
+	activity := hookanalytics.Activity{Name: scanActivity, Status: hookanalytics.ActivityStatusSuccess}
+	var rejected []hookstage.RejectedBid
+	for _, bid := range payload.BidderResponse.Bids {
+		if bid == nil || bid.Bid == nil {
+			continue
+		}
+
+		v, ok := r.scan(bid.Bid.AdM)
+		if !ok {
+			activity.Results = append(activity.Results, newResult(hookanalytics.ResultStatusAllow, payload.Bidder, bid, nil))
+			continue
+		}
+
+		rejected = append(rejected, hookstage.RejectedBid{Bid: bid, NonBidReason: v.nonBidReason})
+		activity.Results = append(activity.Results, newResult(hookanalytics.ResultStatusBlock, payload.Bidder, bid, map[string]interface{}{
+			ruleAnalyticKey:  v.rule,
+			matchAnalyticKey: v.match,
+		}))
+		result.DebugMessages = append(result.DebugMessages, fmt.Sprintf("Bid %s of bidder %s rejected by creative scan rule %s: %s", bid.Bid.ID, payload.Bidder, v.rule, v.match))
+	}
+
+	result.AnalyticsTags = hookanalytics.Analytics{Activities: []hookanalytics.Activity{activity}}
+	if len(rejected) > 0 {
+		result.ChangeSet.RawBidderResponse().Bids().RejectBids(rejected)
+	}
+	return result, nil
+}
+
+func newResult(status hookanalytics.ResultStatus, bidder string, bid *adapters.TypedBid, values map[string]interface{}) hookanalytics.Result {
+	return hookanalytics.Result{
+		Status: status,
+		Values: values,
+		AppliedTo: hookanalytics.AppliedTo{
+			Bidder: bidder,
+			BidIds: []string{bid.Bid.ID},
+			ImpIds: []string{bid.Bid.ImpID},
+		},
+	}
+}
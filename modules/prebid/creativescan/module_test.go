@@ -0,0 +1,157 @@
+package creativescan
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "no_config",
+		},
+		{
+			name: "valid_config",
+			cfg:  `{"blocked_domains": ["malware.com"], "require_https": true, "max_adm_bytes": 100000}`,
+		},
+		{
+			name:        "malformed_config",
+			cfg:         `{"blocked_domains": "malware.com"}`,
+			expectedErr: "failed to parse config",
+		},
+		{
+			name:        "negative_size",
+			cfg:         `{"max_adm_bytes": -1}`,
+			expectedErr: "invalid config: max_adm_bytes must be >= 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Builder(json.RawMessage(tc.cfg), moduledeps.ModuleDeps{})
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	m, err := Builder(json.RawMessage(`{"blocked_domains": ["malware.com"], "require_https": true}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+
+	secureBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1", AdM: `<img src="https://cdn.example.com/ad.png">`}}
+	insecureBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp-2", AdM: `<img src="http://cdn.example.com/ad.png">`}}
+	blockedBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid-3", ImpID: "imp-3", AdM: `<img src="https://malware.com/ad.png">`}}
+
+	testCases := []struct {
+		name                 string
+		accountConfig        string
+		expectedBids         []*adapters.TypedBid
+		expectedRejectedBids []hookstage.RejectedBid
+		expectedResults      []hookanalytics.Result
+	}{
+		{
+			name:         "host_config",
+			expectedBids: []*adapters.TypedBid{secureBid},
+			expectedRejectedBids: []hookstage.RejectedBid{
+				{Bid: insecureBid, NonBidReason: nonBidCreativeNotSecure},
+				{Bid: blockedBid, NonBidReason: nonBidInvalidCreative},
+			},
+			expectedResults: []hookanalytics.Result{
+				{
+					Status:    hookanalytics.ResultStatusAllow,
+					AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-1"}, ImpIds: []string{"imp-1"}},
+				},
+				{
+					Status:    hookanalytics.ResultStatusBlock,
+					Values:    map[string]interface{}{"rule": ruleInsecureResource, "match": "http://cdn.example.com"},
+					AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-2"}, ImpIds: []string{"imp-2"}},
+				},
+				{
+					Status:    hookanalytics.ResultStatusBlock,
+					Values:    map[string]interface{}{"rule": ruleBlockedDomain, "match": "malware.com"},
+					AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-3"}, ImpIds: []string{"imp-3"}},
+				},
+			},
+		},
+		{
+			name:          "account_config",
+			accountConfig: `{"require_https": false, "blocked_domains": ["example.com"]}`,
+			expectedBids:  []*adapters.TypedBid{blockedBid},
+			expectedRejectedBids: []hookstage.RejectedBid{
+				{Bid: secureBid, NonBidReason: nonBidInvalidCreative},
+				{Bid: insecureBid, NonBidReason: nonBidInvalidCreative},
+			},
+			expectedResults: []hookanalytics.Result{
+				{
+					Status:    hookanalytics.ResultStatusBlock,
+					Values:    map[string]interface{}{"rule": ruleBlockedDomain, "match": "example.com"},
+					AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-1"}, ImpIds: []string{"imp-1"}},
+				},
+				{
+					Status:    hookanalytics.ResultStatusBlock,
+					Values:    map[string]interface{}{"rule": ruleBlockedDomain, "match": "example.com"},
+					AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-2"}, ImpIds: []string{"imp-2"}},
+				},
+				{
+					Status:    hookanalytics.ResultStatusAllow,
+					AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-3"}, ImpIds: []string{"imp-3"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := hookstage.RawBidderResponsePayload{
+				Bidder:         "appnexus",
+				BidderResponse: &adapters.BidderResponse{Bids: []*adapters.TypedBid{secureBid, insecureBid, blockedBid}},
+			}
+			miCtx := hookstage.ModuleInvocationContext{AccountConfig: json.RawMessage(tc.accountConfig)}
+
+			result, err := m.(Module).HandleRawBidderResponseHook(context.Background(), miCtx, payload)
+			require.NoError(t, err)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedBids, payload.BidderResponse.Bids)
+			assert.Equal(t, tc.expectedRejectedBids, payload.RejectedBids)
+
+			require.Len(t, result.AnalyticsTags.Activities, 1)
+			assert.Equal(t, scanActivity, result.AnalyticsTags.Activities[0].Name)
+			assert.Equal(t, tc.expectedResults, result.AnalyticsTags.Activities[0].Results)
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHookInvalidAccountConfig(t *testing.T) {
+	m, err := Builder(json.RawMessage(`{"blocked_domains": ["malware.com"]}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	module := m.(Module)
+
+	miCtx := hookstage.ModuleInvocationContext{AccountConfig: json.RawMessage(`{"blocked_domains": ["other.com"], "max_adm_bytes": -1}`)}
+	payload := hookstage.RawBidderResponsePayload{BidderResponse: &adapters.BidderResponse{}}
+
+	_, err = module.HandleRawBidderResponseHook(context.Background(), miCtx, payload)
+
+	assert.EqualError(t, err, "invalid account config: max_adm_bytes must be >= 0")
+	assert.Equal(t, []string{"malware.com"}, module.cfg.BlockedDomains, "account config does not change the host config")
+}
@@ -0,0 +1,145 @@
+package creativescan
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Rules a bid markup can break
+const (
+	ruleMarkupSize       = "markup_size"
+	ruleInsecureResource = "insecure_resource"
+	ruleBlockedDomain    = "blocked_domain"
+	ruleScriptHost       = "script_host"
+)
+
+// Seat non bid reasons of the bids rejected by the module
+const (
+	nonBidInvalidCreative   = 350 // Response Rejected - Invalid Creative
+	nonBidCreativeNotSecure = 352 // Response Rejected - Invalid Creative (Not Secure)
+)
+
+var (
+	// urlPattern matches the absolute and protocol relative URLs whose host is a domain or an IP address,
+	// capturing their scheme and host
+	urlPattern = regexp.MustCompile(`(?i)(https?:)?//((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z][a-z0-9-]*[a-z0-9]|\d{1,3}(?:\.\d{1,3}){3}|\[[0-9a-f:.]+\])(?::\d+)?`)
+
+	// schemePattern matches the URLs starting with a scheme
+	schemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+	// namespacePattern matches the XML namespaces and the document type definitions,
+	// their http URLs are identifiers never loaded by the browsers
+	namespacePattern = regexp.MustCompile(`(?is)\bxmlns(?::[\w-]+)?\s*=\s*(?:"[^"]*"|'[^']*')|\bxsi:(?:noNamespaceSchemaLocation|schemaLocation)\s*=\s*(?:"[^"]*"|'[^']*')|<!DOCTYPE[^>]*>`)
+
+	// scriptSrcPattern matches the source of the HTML scripts
+	scriptSrcPattern = regexp.MustCompile(`(?is)<script\b[^>]*?\bsrc\s*=\s*["']?([^"'\s>]+)`)
+
+	// vastScriptPattern matches the VAST script resources, OMID verification scripts and VPAID JavaScript media files
+	vastScriptPattern = regexp.MustCompile(`(?is)<(JavaScriptResource|MediaFile)\b([^>]*)>\s*(?:<!\[CDATA\[)?\s*([^<\]\s]+)`)
+)
+
+// violation is the first rule a markup breaks along with the value breaking it
+type violation struct {
+	rule         string
+	match        string
+	nonBidReason int
+}
+
+// scan checks the markup against the rules, returning the first rule broken if any
+func (r rules) scan(adm string) (violation, bool) {
+	if r.maxAdmBytes > 0 && len(adm) > r.maxAdmBytes {
+		return violation{rule: ruleMarkupSize, match: strconv.Itoa(len(adm)), nonBidReason: nonBidInvalidCreative}, true
+	}
+
+	// the URLs of native markups are JSON strings which may escape their slashes
+	adm = strings.ReplaceAll(adm, `\/`, "/")
+
+	if r.requireHTTPS || len(r.blockedDomains) > 0 {
+		for _, u := range urlPattern.FindAllStringSubmatch(namespacePattern.ReplaceAllString(adm, ""), -1) {
+			if r.requireHTTPS && strings.EqualFold(u[1], "http:") {
+				return violation{rule: ruleInsecureResource, match: u[0], nonBidReason: nonBidCreativeNotSecure}, true
+			}
+			if domain, ok := r.blockedDomains.match(normalizeHost(u[2])); ok {
+				return violation{rule: ruleBlockedDomain, match: domain, nonBidReason: nonBidInvalidCreative}, true
+			}
+		}
+	}
+
+	if r.requireHTTPS || len(r.blockedScriptHosts) > 0 || len(r.allowedScriptHosts) > 0 {
+		for _, script := range scriptSources(adm) {
+			scheme, host, absolute := scriptHost(script)
+			if !absolute {
+				continue
+			}
+			if r.requireHTTPS && scheme == "http" {
+				return violation{rule: ruleInsecureResource, match: script, nonBidReason: nonBidCreativeNotSecure}, true
+			}
+			if len(r.blockedScriptHosts) == 0 && len(r.allowedScriptHosts) == 0 {
+				continue
+			}
+			// scripts whose host is unknown could be loaded from any host
+			if host == "" {
+				return violation{rule: ruleScriptHost, match: script, nonBidReason: nonBidInvalidCreative}, true
+			}
+			if _, ok := r.blockedScriptHosts.match(host); ok {
+				return violation{rule: ruleScriptHost, match: host, nonBidReason: nonBidInvalidCreative}, true
+			}
+			if _, ok := r.allowedScriptHosts.match(host); !ok && len(r.allowedScriptHosts) > 0 {
+				return violation{rule: ruleScriptHost, match: host, nonBidReason: nonBidInvalidCreative}, true
+			}
+		}
+	}
+
+	return violation{}, false
+}
+
+// scriptSources returns the URLs of the scripts loaded by HTML, VAST and native markups
+func scriptSources(adm string) []string {
+	var sources []string
+	switch trimmed := strings.TrimSpace(adm); {
+	case strings.HasPrefix(trimmed, "{"):
+		native := gjson.Parse(trimmed)
+		if native.Get("native").Exists() {
+			native = native.Get("native")
+		}
+		native.Get("eventtrackers").ForEach(func(_, tracker gjson.Result) bool {
+			// method 2 is the JavaScript tracking
+			if tracker.Get("method").Int() == 2 {
+				sources = append(sources, tracker.Get("url").String())
+			}
+			return true
+		})
+		for _, m := range scriptSrcPattern.FindAllStringSubmatch(native.Get("jstracker").String(), -1) {
+			sources = append(sources, m[1])
+		}
+	default:
+		for _, m := range scriptSrcPattern.FindAllStringSubmatch(adm, -1) {
+			sources = append(sources, m[1])
+		}
+		for _, m := range vastScriptPattern.FindAllStringSubmatch(adm, -1) {
+			if strings.EqualFold(m[1], "MediaFile") && !strings.Contains(strings.ToLower(m[2]), "javascript") {
+				continue
+			}
+			sources = append(sources, m[3])
+		}
+	}
+	return sources
+}
+
+// scriptHost returns the lower cased scheme and host of an absolute or protocol relative script URL, and false for
+// relative URLs. The host is empty if it cannot be determined, such as the host of a data URL or an invalid URL.
+func scriptHost(script string) (string, string, bool) {
+	// browsers read the backslashes of URLs as slashes
+	script = strings.ReplaceAll(strings.TrimSpace(script), `\`, "/")
+	absolute := strings.HasPrefix(script, "//") || schemePattern.MatchString(script)
+
+	u, err := url.Parse(script)
+	if err != nil {
+		return "", "", absolute
+	}
+	return strings.ToLower(u.Scheme), strings.TrimSuffix(strings.ToLower(u.Hostname()), "."), absolute
+}
@@ -0,0 +1,173 @@
+package creativescan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	testCases := []struct {
+		name              string
+		cfg               config
+		adm               string
+		expectedViolation violation
+		expectedOk        bool
+	}{
+		{
+			name: "no_rules",
+			adm:  `<script src="http://evil.com/tag.js"></script>`,
+		},
+		{
+			name:              "markup_size",
+			cfg:               config{MaxAdmBytes: 10},
+			adm:               `<div>too large</div>`,
+			expectedViolation: violation{rule: ruleMarkupSize, match: "20", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name:              "insecure_image",
+			cfg:               config{RequireHTTPS: true},
+			adm:               `<a href="https://click.com"><img src="HTTP://cdn.example.com:8080/ad.png"></a>`,
+			expectedViolation: violation{rule: ruleInsecureResource, match: "HTTP://cdn.example.com:8080", nonBidReason: nonBidCreativeNotSecure},
+			expectedOk:        true,
+		},
+		{
+			name: "xml_namespaces_and_doctype_not_insecure",
+			cfg:  config{RequireHTTPS: true},
+			adm: `<!DOCTYPE html PUBLIC "-//W3C//DTD HTML 4.01//EN" "http://www.w3.org/TR/html4/strict.dtd">` +
+				`<VAST version="4.0" xmlns="http://www.iab.com/VAST" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+				`xsi:noNamespaceSchemaLocation="http://www.iab.com/vast.xsd"><MediaFile><![CDATA[https://cdn.example.com/v.mp4]]></MediaFile></VAST>`,
+		},
+		{
+			name:              "insecure_native_url",
+			cfg:               config{RequireHTTPS: true},
+			adm:               `{"native":{"assets":[{"img":{"url":"http:\/\/cdn.example.com\/ad.png"}}]}}`,
+			expectedViolation: violation{rule: ruleInsecureResource, match: "http://cdn.example.com", nonBidReason: nonBidCreativeNotSecure},
+			expectedOk:        true,
+		},
+		{
+			name:              "blocked_subdomain",
+			cfg:               config{BlockedDomains: []string{"Malware.com"}},
+			adm:               `<img src="//track.malware.com/p.gif">`,
+			expectedViolation: violation{rule: ruleBlockedDomain, match: "malware.com", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name: "domain_suffix_not_blocked",
+			cfg:  config{BlockedDomains: []string{"malware.com"}},
+			adm:  `<img src="https://notmalware.com/p.gif">`,
+		},
+		{
+			name:              "blocked_html_script_host",
+			cfg:               config{BlockedScriptHosts: []string{"miner.io"}},
+			adm:               `<script type="text/javascript" src='https://cdn.miner.io/m.js'></script>`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "cdn.miner.io", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name:              "script_host_not_allowed_in_vast",
+			cfg:               config{AllowedScriptHosts: []string{"verify.example.com"}},
+			adm:               `<VAST><Verification><JavaScriptResource apiFramework="omid"><![CDATA[https://verify.example.com/omid.js]]></JavaScriptResource></Verification><MediaFile type="application/javascript" apiFramework="VPAID"><![CDATA[https://vpaid.other.com/v.js]]></MediaFile></VAST>`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "vpaid.other.com", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name: "video_media_file_not_a_script",
+			cfg:  config{AllowedScriptHosts: []string{"verify.example.com"}},
+			adm:  `<VAST><MediaFile type="video/mp4"><![CDATA[https://cdn.other.com/v.mp4]]></MediaFile></VAST>`,
+		},
+		{
+			name:              "native_javascript_event_tracker",
+			cfg:               config{AllowedScriptHosts: []string{"example.com"}},
+			adm:               `{"eventtrackers":[{"event":1,"method":1,"url":"https://pixel.other.com/p"},{"event":1,"method":2,"url":"https:\/\/js.other.com\/t.js"}]}`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "js.other.com", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name:              "native_jstracker",
+			cfg:               config{BlockedScriptHosts: []string{"other.com"}},
+			adm:               `{"native":{"jstracker":"<script src=\"https://js.other.com/t.js\"></script>"}}`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "js.other.com", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name:              "insecure_ip_address",
+			cfg:               config{RequireHTTPS: true},
+			adm:               `<img src="http://203.0.113.5:8080/ad.png">`,
+			expectedViolation: violation{rule: ruleInsecureResource, match: "http://203.0.113.5:8080", nonBidReason: nonBidCreativeNotSecure},
+			expectedOk:        true,
+		},
+		{
+			name:              "insecure_single_label_script_host",
+			cfg:               config{RequireHTTPS: true},
+			adm:               `<script src="http://intranet/x.js"></script>`,
+			expectedViolation: violation{rule: ruleInsecureResource, match: "http://intranet/x.js", nonBidReason: nonBidCreativeNotSecure},
+			expectedOk:        true,
+		},
+		{
+			name:              "ipv4_script_host_not_allowed",
+			cfg:               config{AllowedScriptHosts: []string{"example.com"}},
+			adm:               `<script src="http://203.0.113.5/x.js"></script>`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "203.0.113.5", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name:              "ipv6_script_host_not_allowed",
+			cfg:               config{AllowedScriptHosts: []string{"example.com"}},
+			adm:               `<script src="//[::1]/x.js"></script>`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "::1", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name:              "backslashed_script_host_not_allowed",
+			cfg:               config{AllowedScriptHosts: []string{"example.com"}},
+			adm:               `<script src="/\evil.com/x.js"></script>`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "evil.com", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name:              "data_script_host_unknown",
+			cfg:               config{BlockedScriptHosts: []string{"miner.io"}},
+			adm:               `<script src="data:text/javascript,alert(1)"></script>`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "data:text/javascript,alert(1)", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name:              "invalid_script_host_unknown",
+			cfg:               config{AllowedScriptHosts: []string{"example.com"}},
+			adm:               `<script src="https://[::1/x.js"></script>`,
+			expectedViolation: violation{rule: ruleScriptHost, match: "https://[::1/x.js", nonBidReason: nonBidInvalidCreative},
+			expectedOk:        true,
+		},
+		{
+			name: "relative_script_allowed",
+			cfg:  config{AllowedScriptHosts: []string{"example.com"}},
+			adm:  `<script src="/local.js"></script><script src="https://cdn.example.com/a.js"></script>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, ok := tc.cfg.rules().scan(tc.adm)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedViolation, v)
+		})
+	}
+}
+
+func TestDomainSetMatch(t *testing.T) {
+	set := newDomainSet([]string{"Example.com.", " ads.net ", ""})
+
+	for host, expected := range map[string]string{
+		"example.com":         "example.com",
+		"a.b.example.com":     "example.com",
+		"ads.net":             "ads.net",
+		"myexample.com":       "",
+		"example.com.evil.io": "",
+	} {
+		domain, ok := set.match(host)
+		assert.Equal(t, expected, domain, host)
+		assert.Equal(t, expected != "", ok, host)
+	}
+}
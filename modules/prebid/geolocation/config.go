@@ -0,0 +1,42 @@
+package geolocation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const defaultRefreshRateSeconds = 60
+
+func newConfig(data json.RawMessage) (config, error) {
+	cfg := config{RefreshRateSeconds: defaultRefreshRateSeconds}
+	if len(data) > 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	if cfg.DatabasePath == "" {
+		return cfg, errors.New("database_path is required")
+	}
+	if cfg.RefreshRateSeconds < 0 {
+		return cfg, fmt.Errorf("refresh_rate_seconds must be >= 0. Got %d", cfg.RefreshRateSeconds)
+	}
+	return cfg, nil
+}
+
+type config struct {
+	// DatabasePath is the path of the City database, a Country database can be used when only the country is needed
+	DatabasePath string `json:"database_path"`
+	// ASNDatabasePath is the optional path of the ASN or ISP database
+	ASNDatabasePath string `json:"asn_database_path"`
+	// RefreshRateSeconds is how often the database files are checked for changes, 0 disables the reload
+	RefreshRateSeconds int `json:"refresh_rate_seconds"`
+}
+
+func (c config) refreshRate() time.Duration {
+	return time.Duration(c.RefreshRateSeconds) * time.Second
+}
@@ -0,0 +1,97 @@
+package geolocation
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// database is a MaxMind DB file reloaded whenever its modification time or size changes
+type database struct {
+	path     string
+	reader   atomic.Pointer[maxminddb.Reader]
+	modTime  time.Time
+	size     int64
+	readFile func(string) ([]byte, error)
+	statFile func(string) (os.FileInfo, error)
+}
+
+func newDatabase(path string) (*database, error) {
+	db := &database{path: path, readFile: os.ReadFile, statFile: os.Stat}
+	if err := db.load(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// lookup returns the record of the IP address, nil when the address is not in the database
+func (db *database) lookup(ip net.IP) (map[string]any, error) {
+	var record map[string]any
+	if err := db.reader.Load().Lookup(ip, &record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// load reads the database file, the reader in use is kept when the file is invalid
+func (db *database) load() error {
+	info, err := db.statFile(db.path)
+	if err != nil {
+		return fmt.Errorf("failed to read database %s: %s", db.path, err)
+	}
+
+	buffer, err := db.readFile(db.path)
+	if err != nil {
+		return fmt.Errorf("failed to read database %s: %s", db.path, err)
+	}
+
+	reader, err := maxminddb.FromBytes(buffer)
+	if err != nil {
+		return fmt.Errorf("failed to load database %s: %s", db.path, err)
+	}
+
+	db.reader.Store(reader)
+	db.modTime = info.ModTime()
+	db.size = info.Size()
+	return nil
+}
+
+// refresh reloads the database file if it changed since it was last loaded
+func (db *database) refresh() {
+	info, err := db.statFile(db.path)
+	if err != nil {
+		glog.Errorf("geolocation: failed to check database %s: %s", db.path, err)
+		return
+	}
+	if info.ModTime().Equal(db.modTime) && info.Size() == db.size {
+		return
+	}
+
+	if err := db.load(); err != nil {
+		glog.Errorf("geolocation: %s", err)
+		return
+	}
+	glog.Infof("geolocation: database %s reloaded", db.path)
+}
+
+// watch refreshes the databases at the refresh rate until done is closed
+func watch(databases []*database, refreshRate time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(refreshRate)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, db := range databases {
+				db.refresh()
+			}
+		case <-done:
+			return
+		}
+	}
+}
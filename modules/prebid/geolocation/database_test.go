@@ -0,0 +1,264 @@
+package geolocation
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metadataMarker precedes the metadata map at the end of a MaxMind DB file
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the size of the zeroed bytes between the search tree and the data section
+const dataSectionSeparator = 16
+
+// Types of the data section fields written by encodeMMDB
+const (
+	typeString  = 2
+	typeDouble  = 3
+	typeUint32  = 6
+	typeMap     = 7
+	typeUint64  = 9
+	typeArray   = 11
+	typeBoolean = 14
+)
+
+// testNetwork is a network of a database written by buildMMDB
+type testNetwork struct {
+	cidr   string
+	record map[string]any
+}
+
+// testNode is a node of the search tree built by buildMMDB, children being either nodes or records
+type testNode struct {
+	children [2]*testNode
+	records  [2]map[string]any
+}
+
+// buildMMDB writes an IPv6 database with 24 bit records, IPv4 networks being stored as IPv4-compatible addresses
+func buildMMDB(t *testing.T, databaseType string, networks ...testNetwork) []byte {
+	root := &testNode{}
+	nodes := []*testNode{root}
+
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network.cidr)
+		require.NoError(t, err)
+
+		ip := ipNet.IP.To16()
+		ones, _ := ipNet.Mask.Size()
+		if ipNet.IP.To4() != nil {
+			ip = append(make(net.IP, 12), ipNet.IP.To4()...)
+			ones += 96
+		}
+
+		node := root
+		for i := 0; i < ones; i++ {
+			bit := (ip[i/8] >> (7 - uint(i%8))) & 1
+			if i == ones-1 {
+				node.records[bit] = network.record
+				break
+			}
+			if node.children[bit] == nil {
+				node.children[bit] = &testNode{}
+				nodes = append(nodes, node.children[bit])
+			}
+			node = node.children[bit]
+		}
+	}
+
+	var data bytes.Buffer
+	offsets := make(map[*testNode][2]uint)
+	for _, node := range nodes {
+		var recordOffsets [2]uint
+		for bit, record := range node.records {
+			if record != nil {
+				recordOffsets[bit] = uint(data.Len())
+				data.Write(encodeMMDB(t, record))
+			}
+		}
+		offsets[node] = recordOffsets
+	}
+
+	index := make(map[*testNode]uint, len(nodes))
+	for i, node := range nodes {
+		index[node] = uint(i)
+	}
+
+	nodeCount := uint(len(nodes))
+	var buffer bytes.Buffer
+	for _, node := range nodes {
+		for bit := 0; bit < 2; bit++ {
+			value := nodeCount
+			if node.children[bit] != nil {
+				value = index[node.children[bit]]
+			} else if node.records[bit] != nil {
+				value = nodeCount + dataSectionSeparator + offsets[node][bit]
+			}
+			buffer.Write([]byte{byte(value >> 16), byte(value >> 8), byte(value)})
+		}
+	}
+	buffer.Write(make([]byte, dataSectionSeparator))
+	buffer.Write(data.Bytes())
+	buffer.Write(metadataMarker)
+	buffer.Write(encodeMMDB(t, map[string]any{
+		"node_count":    uint64(nodeCount),
+		"record_size":   uint64(24),
+		"ip_version":    uint64(6),
+		"database_type": databaseType,
+	}))
+
+	return buffer.Bytes()
+}
+
+// encodeMMDB encodes the value as a field of the data section
+func encodeMMDB(t *testing.T, value any) []byte {
+	var buffer bytes.Buffer
+	switch v := value.(type) {
+	case map[string]any:
+		buffer.Write(controlMMDB(typeMap, len(v)))
+		for key, value := range v {
+			buffer.Write(encodeMMDB(t, key))
+			buffer.Write(encodeMMDB(t, value))
+		}
+	case []any:
+		buffer.Write(controlMMDB(typeArray, len(v)))
+		for _, value := range v {
+			buffer.Write(encodeMMDB(t, value))
+		}
+	case string:
+		buffer.Write(controlMMDB(typeString, len(v)))
+		buffer.WriteString(v)
+	case uint64:
+		b := big.NewInt(0).SetUint64(v).Bytes()
+		fieldType := typeUint32
+		if len(b) > 4 {
+			fieldType = typeUint64
+		}
+		buffer.Write(controlMMDB(fieldType, len(b)))
+		buffer.Write(b)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		buffer.Write(controlMMDB(typeBoolean, size))
+	case float64:
+		buffer.Write(controlMMDB(typeDouble, 8))
+		bits := math.Float64bits(v)
+		for i := 7; i >= 0; i-- {
+			buffer.WriteByte(byte(bits >> (8 * uint(i))))
+		}
+	default:
+		require.Failf(t, "unsupported value", "%T", value)
+	}
+	return buffer.Bytes()
+}
+
+func controlMMDB(fieldType int, size int) []byte {
+	var control []byte
+	switch {
+	case size < 29:
+		control = []byte{byte(size)}
+	case size < 285:
+		control = []byte{29, byte(size - 29)}
+	default:
+		size -= 285
+		control = []byte{30, byte(size >> 8), byte(size)}
+	}
+
+	if fieldType <= typeMap {
+		control[0] |= byte(fieldType << 5)
+		return control
+	}
+	return append([]byte{control[0]}, append([]byte{byte(fieldType - 7)}, control[1:]...)...)
+}
+
+func TestDatabaseLookup(t *testing.T) {
+	path := writeDatabase(t, t.TempDir(), "city.mmdb", buildMMDB(t, "GeoIP2-City",
+		testNetwork{cidr: "1.2.3.0/24", record: map[string]any{"country": map[string]any{"iso_code": "FR"}}},
+		testNetwork{cidr: "2001:db8::/32", record: map[string]any{"country": map[string]any{"iso_code": "DE"}}},
+	))
+
+	db, err := newDatabase(path)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		ip             string
+		expectedRecord map[string]any
+	}{
+		{
+			name:           "ipv4",
+			ip:             "1.2.3.4",
+			expectedRecord: map[string]any{"country": map[string]any{"iso_code": "FR"}},
+		},
+		{
+			name:           "ipv6",
+			ip:             "2001:db8::1",
+			expectedRecord: map[string]any{"country": map[string]any{"iso_code": "DE"}},
+		},
+		{
+			name: "ipv4_not_found",
+			ip:   "1.2.4.4",
+		},
+		{
+			name: "ipv6_not_found",
+			ip:   "2001:db9::1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			record, err := db.lookup(net.ParseIP(tc.ip))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedRecord, record)
+		})
+	}
+}
+
+func TestNewDatabaseErrors(t *testing.T) {
+	metadata := func(recordSize, nodeCount uint64) []byte {
+		db := append(make([]byte, dataSectionSeparator), metadataMarker...)
+		return append(db, encodeMMDB(t, map[string]any{
+			"node_count":  nodeCount,
+			"record_size": recordSize,
+			"ip_version":  uint64(6),
+		})...)
+	}
+
+	testCases := []struct {
+		name        string
+		db          []byte
+		expectedErr string
+	}{
+		{
+			name:        "no_metadata",
+			db:          []byte("not a database"),
+			expectedErr: "invalid MaxMind DB file",
+		},
+		{
+			name:        "unsupported_record_size",
+			db:          metadata(16, 0),
+			expectedErr: "unknown record size: 16",
+		},
+		{
+			name:        "truncated_search_tree",
+			db:          metadata(24, 10),
+			expectedErr: "invalid metadata",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeDatabase(t, t.TempDir(), "invalid.mmdb", tc.db)
+			_, err := newDatabase(path)
+			assert.ErrorContains(t, err, "failed to load database "+path)
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package geolocation
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+)
+
+// Builder loads the databases and starts watching their files for changes
+func Builder(cfg json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	c, err := newConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Module{done: make(chan struct{})}
+	if m.geo, err = newDatabase(c.DatabasePath); err != nil {
+		return nil, err
+	}
+	databases := []*database{m.geo}
+
+	if c.ASNDatabasePath != "" {
+		if m.asn, err = newDatabase(c.ASNDatabasePath); err != nil {
+			return nil, err
+		}
+		databases = append(databases, m.asn)
+	}
+
+	if c.RefreshRateSeconds > 0 {
+		go watch(databases, c.refreshRate(), m.done)
+	}
+	return m, nil
+}
+
+var _ hookstage.ProcessedAuctionRequest = (*Module)(nil)
+
+// Module fills the geo of the device from its IP address
+type Module struct {
+	geo      *database
+	asn      *database
+	done     chan struct{}
+	shutdown sync.Once
+}
+
+// Shutdown stops watching the database files
+func (m *Module) Shutdown() error {
+	m.shutdown.Do(func() { close(m.done) })
+	return nil
+}
+
+// location is the subset of a database record the request is updated with
+type location struct {
+	country string
+	region  string
+	metro   string
+	city    string
+	zip     string
+	asn     uint64
+	isp     string
+}
+
+// HandleProcessedAuctionHook fills the missing fields of device.geo along with the ASN and ISP in device.ext.
+// City, metro and zip are left out when the transmitPreciseGeo activity is not allowed.
+func (m *Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+	if payload.Request == nil || payload.Request.Device == nil {
+		return result, nil
+	}
+
+	ip := deviceIP(payload.Request.Device)
+	if ip == nil {
+		return result, nil
+	}
+
+	preciseGeo := miCtx.ActivityControl.Allow(
+		privacy.ActivityTransmitPreciseGeo,
+		privacy.Component{Type: privacy.ComponentTypeGeneral, Name: miCtx.HookImplCode},
+		privacy.NewRequestFromBidRequest(*payload.Request),
+	)
+
+	loc, err := m.locate(ip, preciseGeo)
+	if err != nil {
+		return result, hookexecution.NewFailure("failed to look up device ip: %s", err)
+	}
+	if loc == (location{}) {
+		result.DebugMessages = append(result.DebugMessages, "device ip not found in database")
+		return result, nil
+	}
+
+	result.ChangeSet.AddMutation(
+		func(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+			return payload, updateDevice(payload, loc)
+		}, hookstage.MutationUpdate, "device",
+	)
+	return result, nil
+}
+
+func deviceIP(device *openrtb2.Device) net.IP {
+	if device.IP != "" {
+		return net.ParseIP(device.IP)
+	}
+	if device.IPv6 != "" {
+		return net.ParseIP(device.IPv6)
+	}
+	return nil
+}
+
+func (m *Module) locate(ip net.IP, preciseGeo bool) (location, error) {
+	var loc location
+
+	record, err := m.geo.lookup(ip)
+	if err != nil {
+		return loc, err
+	}
+
+	loc.country = countryAlpha3(stringAt(record, "country", "iso_code"))
+	if subdivisions, ok := record["subdivisions"].([]any); ok && len(subdivisions) > 0 {
+		loc.region = stringAt(subdivisions[0], "iso_code")
+	}
+	if preciseGeo {
+		if metro := uintAt(record, "location", "metro_code"); metro > 0 {
+			loc.metro = strconv.FormatUint(metro, 10)
+		}
+		loc.city = stringAt(record, "city", "names", "en")
+		loc.zip = stringAt(record, "postal", "code")
+	}
+
+	// ISP databases hold the ASN along with the ISP, City databases may hold both in the traits of the record
+	asnRecord, asnParent := record, "traits"
+	if m.asn != nil {
+		if asnRecord, err = m.asn.lookup(ip); err != nil {
+			return loc, err
+		}
+		asnParent = ""
+	}
+	loc.asn = uintAt(asnRecord, asnParent, "autonomous_system_number")
+	if loc.isp = stringAt(asnRecord, asnParent, "isp"); loc.isp == "" {
+		loc.isp = stringAt(asnRecord, asnParent, "autonomous_system_organization")
+	}
+
+	return loc, nil
+}
+
+// updateDevice sets the fields of the device not already set by the request
+func updateDevice(payload hookstage.ProcessedAuctionRequestPayload, loc location) error {
+	if payload.Request == nil || payload.Request.Device == nil {
+		return nil
+	}
+
+	device := ptrutil.Clone(payload.Request.Device)
+	geo := ptrutil.Clone(device.Geo)
+	if geo == nil {
+		geo = &openrtb2.Geo{}
+	}
+
+	updated := setIfEmpty(&geo.Country, loc.country)
+	updated = setIfEmpty(&geo.Region, loc.region) || updated
+	updated = setIfEmpty(&geo.Metro, loc.metro) || updated
+	updated = setIfEmpty(&geo.City, loc.city) || updated
+	updated = setIfEmpty(&geo.ZIP, loc.zip) || updated
+	if updated {
+		if geo.Type == 0 {
+			geo.Type = adcom1.LocationIP
+		}
+		device.Geo = geo
+	}
+	payload.Request.Device = device
+
+	return updateDeviceExt(payload, loc)
+}
+
+func updateDeviceExt(payload hookstage.ProcessedAuctionRequestPayload, loc location) error {
+	if loc.asn == 0 && loc.isp == "" {
+		return nil
+	}
+
+	deviceExt, err := payload.Request.GetDeviceExt()
+	if err != nil {
+		return err
+	}
+
+	ext := deviceExt.GetExt()
+	updated := false
+	if _, ok := ext["asn"]; !ok && loc.asn > 0 {
+		ext["asn"] = json.RawMessage(strconv.FormatUint(loc.asn, 10))
+		updated = true
+	}
+	if _, ok := ext["isp"]; !ok && loc.isp != "" {
+		isp, err := jsonutil.Marshal(loc.isp)
+		if err != nil {
+			return err
+		}
+		ext["isp"] = isp
+		updated = true
+	}
+	if updated {
+		deviceExt.SetExt(ext)
+	}
+	return nil
+}
+
+func setIfEmpty(field *string, value string) bool {
+	if *field != "" || value == "" {
+		return false
+	}
+	*field = value
+	return true
+}
+
+// stringAt returns the string at the path of nested maps, empty path elements being skipped
+func stringAt(v any, path ...string) string {
+	s, _ := valueAt(v, path...).(string)
+	return s
+}
+
+// uintAt returns the unsigned integer at the path of nested maps, empty path elements being skipped
+func uintAt(v any, path ...string) uint64 {
+	u, _ := valueAt(v, path...).(uint64)
+	return u
+}
+
+func valueAt(v any, path ...string) any {
+	for _, key := range path {
+		if key == "" {
+			continue
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
@@ -0,0 +1,321 @@
+package geolocation
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	pbsconfig "github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var parisRecord = map[string]any{
+	"country":      map[string]any{"iso_code": "FR"},
+	"subdivisions": []any{map[string]any{"iso_code": "IDF"}},
+	"city":         map[string]any{"names": map[string]any{"en": "Paris", "fr": "Paris"}},
+	"postal":       map[string]any{"code": "75001"},
+	"location":     map[string]any{"latitude": 48.85, "longitude": 2.35},
+}
+
+var newYorkRecord = map[string]any{
+	"country":      map[string]any{"iso_code": "US"},
+	"subdivisions": []any{map[string]any{"iso_code": "NY"}},
+	"city":         map[string]any{"names": map[string]any{"en": "New York"}},
+	"postal":       map[string]any{"code": "10001"},
+	"location":     map[string]any{"metro_code": uint64(501)},
+	"traits":       map[string]any{"autonomous_system_number": uint64(7018), "autonomous_system_organization": "Verizon Business"},
+}
+
+// writeDatabase writes the database to the directory and returns its path
+func writeDatabase(t *testing.T, dir, name string, db []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, db, 0644))
+	return path
+}
+
+func newTestModule(t *testing.T) *Module {
+	dir := t.TempDir()
+	geo := writeDatabase(t, dir, "city.mmdb", buildMMDB(t, "GeoIP2-City",
+		testNetwork{cidr: "1.2.3.0/24", record: parisRecord},
+		testNetwork{cidr: "2001:db8::/32", record: newYorkRecord},
+	))
+
+	m, err := Builder(json.RawMessage(`{"database_path":"`+geo+`","refresh_rate_seconds":0}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	return m.(*Module)
+}
+
+func denyPreciseGeo() privacy.ActivityControl {
+	return privacy.NewActivityControl(&pbsconfig.AccountPrivacy{
+		AllowActivities: &pbsconfig.AllowActivities{
+			TransmitPreciseGeo: pbsconfig.Activity{Rules: []pbsconfig.ActivityRule{{Allow: false}}},
+		},
+	})
+}
+
+// denyPreciseGeoForGPPSID denies the transmitPreciseGeo activity to the requests of the GPP section 7, US national
+func denyPreciseGeoForGPPSID() privacy.ActivityControl {
+	return privacy.NewActivityControl(&pbsconfig.AccountPrivacy{
+		AllowActivities: &pbsconfig.AllowActivities{
+			TransmitPreciseGeo: pbsconfig.Activity{Rules: []pbsconfig.ActivityRule{{
+				Condition: pbsconfig.ActivityCondition{GPPSID: []int8{7}},
+				Allow:     false,
+			}}},
+		},
+	})
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	m := newTestModule(t)
+
+	testCases := []struct {
+		name            string
+		device          *openrtb2.Device
+		regs            *openrtb2.Regs
+		activityControl privacy.ActivityControl
+		expectedDevice  *openrtb2.Device
+	}{
+		{
+			name:   "geo_filled_from_ipv4",
+			device: &openrtb2.Device{IP: "1.2.3.4"},
+			expectedDevice: &openrtb2.Device{
+				IP:  "1.2.3.4",
+				Geo: &openrtb2.Geo{Country: "FRA", Region: "IDF", City: "Paris", ZIP: "75001", Type: adcom1.LocationIP},
+			},
+		},
+		{
+			name:   "geo_and_asn_filled_from_ipv6",
+			device: &openrtb2.Device{IPv6: "2001:db8::1"},
+			expectedDevice: &openrtb2.Device{
+				IPv6: "2001:db8::1",
+				Geo:  &openrtb2.Geo{Country: "USA", Region: "NY", Metro: "501", City: "New York", ZIP: "10001", Type: adcom1.LocationIP},
+				Ext:  json.RawMessage(`{"asn":7018,"isp":"Verizon Business"}`),
+			},
+		},
+		{
+			name: "fields_set_by_request_kept",
+			device: &openrtb2.Device{
+				IPv6: "2001:db8::1",
+				Geo:  &openrtb2.Geo{Country: "CAN", Type: adcom1.LocationGPS},
+				Ext:  json.RawMessage(`{"asn":1}`),
+			},
+			expectedDevice: &openrtb2.Device{
+				IPv6: "2001:db8::1",
+				Geo:  &openrtb2.Geo{Country: "CAN", Region: "NY", Metro: "501", City: "New York", ZIP: "10001", Type: adcom1.LocationGPS},
+				Ext:  json.RawMessage(`{"asn":1,"isp":"Verizon Business"}`),
+			},
+		},
+		{
+			name:            "precise_geo_not_allowed",
+			device:          &openrtb2.Device{IPv6: "2001:db8::1"},
+			activityControl: denyPreciseGeo(),
+			expectedDevice: &openrtb2.Device{
+				IPv6: "2001:db8::1",
+				Geo:  &openrtb2.Geo{Country: "USA", Region: "NY", Type: adcom1.LocationIP},
+				Ext:  json.RawMessage(`{"asn":7018,"isp":"Verizon Business"}`),
+			},
+		},
+		{
+			name:            "precise_geo_not_allowed_for_gpp_sid",
+			device:          &openrtb2.Device{IPv6: "2001:db8::1"},
+			regs:            &openrtb2.Regs{GPPSID: []int8{7}},
+			activityControl: denyPreciseGeoForGPPSID(),
+			expectedDevice: &openrtb2.Device{
+				IPv6: "2001:db8::1",
+				Geo:  &openrtb2.Geo{Country: "USA", Region: "NY", Type: adcom1.LocationIP},
+				Ext:  json.RawMessage(`{"asn":7018,"isp":"Verizon Business"}`),
+			},
+		},
+		{
+			name:            "precise_geo_allowed_for_other_gpp_sid",
+			device:          &openrtb2.Device{IPv6: "2001:db8::1"},
+			regs:            &openrtb2.Regs{GPPSID: []int8{2}},
+			activityControl: denyPreciseGeoForGPPSID(),
+			expectedDevice: &openrtb2.Device{
+				IPv6: "2001:db8::1",
+				Geo:  &openrtb2.Geo{Country: "USA", Region: "NY", Metro: "501", City: "New York", ZIP: "10001", Type: adcom1.LocationIP},
+				Ext:  json.RawMessage(`{"asn":7018,"isp":"Verizon Business"}`),
+			},
+		},
+		{
+			name:           "ip_not_found",
+			device:         &openrtb2.Device{IP: "5.6.7.8"},
+			expectedDevice: &openrtb2.Device{IP: "5.6.7.8"},
+		},
+		{
+			name:           "invalid_ip",
+			device:         &openrtb2.Device{IP: "invalid"},
+			expectedDevice: &openrtb2.Device{IP: "invalid"},
+		},
+		{
+			name:           "no_ip",
+			device:         &openrtb2.Device{UA: "ua"},
+			expectedDevice: &openrtb2.Device{UA: "ua"},
+		},
+		{
+			name: "no_device",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: tc.device, Regs: tc.regs}}}
+			miCtx := hookstage.ModuleInvocationContext{HookImplCode: "geolocation", ActivityControl: tc.activityControl}
+
+			result, err := m.HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+			require.NoError(t, err)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				_, err := mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			require.NoError(t, payload.Request.RebuildRequest())
+
+			assert.Equal(t, tc.expectedDevice, payload.Request.Device)
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHookWithASNDatabase(t *testing.T) {
+	dir := t.TempDir()
+	geo := writeDatabase(t, dir, "city.mmdb", buildMMDB(t, "GeoIP2-City", testNetwork{cidr: "1.2.3.0/24", record: parisRecord}))
+	asn := writeDatabase(t, dir, "isp.mmdb", buildMMDB(t, "GeoIP2-ISP", testNetwork{cidr: "1.2.0.0/16", record: map[string]any{
+		"autonomous_system_number":       uint64(3215),
+		"autonomous_system_organization": "Orange",
+		"isp":                            "Orange S.A.",
+	}}))
+
+	m, err := Builder(json.RawMessage(`{"database_path":"`+geo+`","asn_database_path":"`+asn+`"}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	defer m.(*Module).Shutdown()
+
+	payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{IP: "1.2.3.4"}}}}
+	result, err := m.(*Module).HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+	require.NoError(t, err)
+
+	mutations := result.ChangeSet.Mutations()
+	require.Len(t, mutations, 1)
+	assert.Equal(t, []string{"device"}, mutations[0].Key())
+	_, err = mutations[0].Apply(payload)
+	require.NoError(t, err)
+	require.NoError(t, payload.Request.RebuildRequest())
+
+	assert.Equal(t, "FRA", payload.Request.Device.Geo.Country)
+	assert.JSONEq(t, `{"asn":3215,"isp":"Orange S.A."}`, string(payload.Request.Device.Ext))
+}
+
+func TestBuilder(t *testing.T) {
+	dir := t.TempDir()
+	geo := writeDatabase(t, dir, "city.mmdb", buildMMDB(t, "GeoIP2-City"))
+	invalid := writeDatabase(t, dir, "invalid.mmdb", []byte("invalid"))
+
+	testCases := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name:        "no_config",
+			expectedErr: "database_path is required",
+		},
+		{
+			name:        "malformed_config",
+			cfg:         `{"database_path":1}`,
+			expectedErr: "failed to parse config",
+		},
+		{
+			name:        "negative_refresh_rate",
+			cfg:         `{"database_path":"` + geo + `","refresh_rate_seconds":-1}`,
+			expectedErr: "refresh_rate_seconds must be >= 0. Got -1",
+		},
+		{
+			name:        "missing_database",
+			cfg:         `{"database_path":"` + filepath.Join(dir, "missing.mmdb") + `"}`,
+			expectedErr: "failed to read database",
+		},
+		{
+			name:        "invalid_asn_database",
+			cfg:         `{"database_path":"` + geo + `","asn_database_path":"` + invalid + `"}`,
+			expectedErr: "failed to load database " + invalid + ": error opening database: invalid MaxMind DB file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Builder(json.RawMessage(tc.cfg), moduledeps.ModuleDeps{})
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestDatabaseRefresh(t *testing.T) {
+	dir := t.TempDir()
+	path := writeDatabase(t, dir, "city.mmdb", buildMMDB(t, "GeoIP2-City", testNetwork{cidr: "1.2.3.0/24", record: parisRecord}))
+
+	db, err := newDatabase(path)
+	require.NoError(t, err)
+
+	record, err := db.lookup(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, parisRecord, record)
+
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	db.refresh()
+
+	record, err = db.lookup(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, parisRecord, record, "the database in use is kept when the file is invalid")
+
+	modTime = modTime.Add(time.Minute)
+	writeDatabase(t, dir, "city.mmdb", buildMMDB(t, "GeoIP2-City", testNetwork{cidr: "1.2.3.0/24", record: newYorkRecord}))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	db.refresh()
+
+	record, err = db.lookup(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, newYorkRecord, record, "the database is reloaded when the file changes")
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := writeDatabase(t, dir, "city.mmdb", buildMMDB(t, "GeoIP2-City", testNetwork{cidr: "1.2.3.0/24", record: parisRecord}))
+
+	db, err := newDatabase(path)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		watch([]*database{db}, time.Millisecond, done)
+		close(stopped)
+	}()
+
+	modTime := time.Now().Add(time.Minute)
+	writeDatabase(t, dir, "city.mmdb", buildMMDB(t, "GeoIP2-City", testNetwork{cidr: "1.2.3.0/24", record: newYorkRecord}))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	assert.Eventually(t, func() bool {
+		record, err := db.lookup(net.ParseIP("1.2.3.4"))
+		return err == nil && record["postal"].(map[string]any)["code"] == "10001"
+	}, time.Second, time.Millisecond)
+
+	close(done)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		assert.Fail(t, "watch did not stop")
+	}
+}
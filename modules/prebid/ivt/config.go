@@ -0,0 +1,95 @@
+package ivt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// Reasons a request is scored as invalid traffic
+const (
+	reasonUserAgent    = "user_agent"
+	reasonDatacenterIP = "datacenter_ip"
+	reasonAppBundle    = "app_bundle"
+)
+
+var defaultScores = scores{
+	UserAgent:    100,
+	DatacenterIP: 50,
+	AppBundle:    100,
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	cfg := config{Scores: defaultScores}
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+	if err := cfg.DefaultThresholds.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid default_thresholds: %s", err)
+	}
+	return cfg, nil
+}
+
+// config is the host configuration of the module
+type config struct {
+	UserAgentPatterns list       `json:"user_agent_patterns"`
+	DatacenterIPs     list       `json:"datacenter_ips"`
+	AppBundles        list       `json:"app_bundles"`
+	Scores            scores     `json:"scores"`
+	DefaultThresholds thresholds `json:"default_thresholds"`
+}
+
+// list holds the entries of a list inline and/or in a local file with one entry per line
+type list struct {
+	Values []string `json:"values"`
+	File   string   `json:"file"`
+}
+
+// scores are added up for each reason a request matches
+type scores struct {
+	UserAgent    int `json:"user_agent"`
+	DatacenterIP int `json:"datacenter_ip"`
+	AppBundle    int `json:"app_bundle"`
+}
+
+// thresholds are the scores from which the actions are taken, 0 disables an action
+type thresholds struct {
+	Reject       int      `json:"reject_threshold"`
+	Strip        int      `json:"strip_threshold"`
+	StripBidders []string `json:"strip_bidders"`
+	Mark         int      `json:"mark_threshold"`
+}
+
+// accountThresholds overrides the default thresholds with the ones set in the account config
+func (t thresholds) accountThresholds(data json.RawMessage) (thresholds, error) {
+	if len(data) == 0 {
+		return t, nil
+	}
+
+	// the default bidders are shared by all the requests, they must not be overwritten in place
+	t.StripBidders = slices.Clone(t.StripBidders)
+	if err := jsonutil.UnmarshalValid(data, &t); err != nil {
+		return t, fmt.Errorf("failed to parse account config: %s", err)
+	}
+	if err := t.validate(); err != nil {
+		return t, fmt.Errorf("invalid account config: %s", err)
+	}
+	return t, nil
+}
+
+func (t thresholds) validate() error {
+	if t.Reject < 0 || t.Strip < 0 || t.Mark < 0 {
+		return errors.New("thresholds must be >= 0")
+	}
+	if t.Strip > 0 && len(t.StripBidders) == 0 {
+		return errors.New("strip_bidders is required when strip_threshold is set")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package ivt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// detector scores requests against the lists of the host configuration
+type detector struct {
+	userAgents *regexp.Regexp
+	ipv4       []*net.IPNet
+	ipv6       []*net.IPNet
+	appBundles map[string]struct{}
+	scores     scores
+}
+
+// signals are the request fields requests are scored on
+type signals struct {
+	userAgent string
+	ip        net.IP
+	appBundle string
+}
+
+func newDetector(cfg config, readFile func(string) ([]byte, error)) (*detector, error) {
+	d := &detector{scores: cfg.Scores, appBundles: make(map[string]struct{})}
+
+	patterns, err := loadList(cfg.UserAgentPatterns, readFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user_agent_patterns: %s", err)
+	}
+	if d.userAgents, err = compilePatterns(patterns); err != nil {
+		return nil, fmt.Errorf("failed to load user_agent_patterns: %s", err)
+	}
+
+	cidrs, err := loadList(cfg.DatacenterIPs, readFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load datacenter_ips: %s", err)
+	}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load datacenter_ips: %s", err)
+		}
+		if network.IP.To4() != nil {
+			d.ipv4 = append(d.ipv4, network)
+		} else {
+			d.ipv6 = append(d.ipv6, network)
+		}
+	}
+
+	bundles, err := loadList(cfg.AppBundles, readFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load app_bundles: %s", err)
+	}
+	for _, bundle := range bundles {
+		d.appBundles[strings.ToLower(bundle)] = struct{}{}
+	}
+
+	return d, nil
+}
+
+// score returns the sum of the scores of the reasons the request matches along with the reasons
+func (d *detector) score(s signals) (int, []string) {
+	var score int
+	var reasons []string
+
+	if s.userAgent != "" && d.userAgents != nil && d.userAgents.MatchString(s.userAgent) {
+		score += d.scores.UserAgent
+		reasons = append(reasons, reasonUserAgent)
+	}
+	if s.ip != nil && d.isDatacenterIP(s.ip) {
+		score += d.scores.DatacenterIP
+		reasons = append(reasons, reasonDatacenterIP)
+	}
+	if _, ok := d.appBundles[strings.ToLower(s.appBundle)]; ok && s.appBundle != "" {
+		score += d.scores.AppBundle
+		reasons = append(reasons, reasonAppBundle)
+	}
+
+	return score, reasons
+}
+
+func (d *detector) isDatacenterIP(ip net.IP) bool {
+	networks := d.ipv6
+	if ip.To4() != nil {
+		networks = d.ipv4
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePatterns compiles the patterns into a single case insensitive expression, nil when there are no patterns
+func compilePatterns(patterns []string) (*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	groups := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, err
+		}
+		groups = append(groups, "(?:"+pattern+")")
+	}
+	return regexp.Compile("(?i)" + strings.Join(groups, "|"))
+}
+
+// loadList returns the inline values of the list followed by the lines of its file,
+// blank lines and lines starting with # being skipped
+func loadList(l list, readFile func(string) ([]byte, error)) ([]string, error) {
+	values := make([]string, 0, len(l.Values))
+	for _, v := range l.Values {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	if l.File == "" {
+		return values, nil
+	}
+
+	data, err := readFile(l.File)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values = append(values, line)
+	}
+	return values, scanner.Err()
+}
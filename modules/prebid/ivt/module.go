@@ -0,0 +1,213 @@
+package ivt
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/httputil"
+	"github.com/prebid/prebid-server/v3/util/iputil"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// metricsModuleName is the name of the module in the metrics, as reported by the hook execution
+const metricsModuleName = "prebid_ivt"
+
+// Actions taken on a request scored as invalid traffic
+const (
+	actionReject = "reject"
+	actionStrip  = "strip"
+	actionMark   = "mark"
+	actionNone   = "none"
+)
+
+// Keys of the request signals the entrypoint hook passes to the raw auction request hook
+const (
+	userAgentKey = "ivt.user_agent"
+	ipKey        = "ivt.ip"
+)
+
+// stripBiddersKey is the key of the bidders the raw auction request hook passes to the processed auction request
+// hook to remove, once the stored requests are merged into the request
+const stripBiddersKey = "ivt.strip_bidders"
+
+// Builder loads the lists of the host configuration
+func Builder(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	c, err := newConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := newDetector(c, os.ReadFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Module{detector: d, thresholds: c.DefaultThresholds, metricsEngine: deps.MetricsEngine}, nil
+}
+
+var (
+	_ hookstage.Entrypoint              = (*Module)(nil)
+	_ hookstage.RawAuctionRequest       = (*Module)(nil)
+	_ hookstage.ProcessedAuctionRequest = (*Module)(nil)
+)
+
+// Module scores requests as invalid traffic and rejects, strips bidders from or marks the requests reaching the account thresholds
+type Module struct {
+	detector      *detector
+	thresholds    thresholds
+	metricsEngine *moduledeps.MetricsEngineRef
+}
+
+// HandleEntrypointHook keeps the user agent and IP address of the HTTP request,
+// they are used when the request does not set device.ua and device.ip.
+func (m *Module) HandleEntrypointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	payload hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	result := hookstage.HookResult[hookstage.EntrypointPayload]{}
+	if payload.Request == nil {
+		return result, nil
+	}
+
+	result.ModuleContext = hookstage.ModuleContext{userAgentKey: payload.Request.UserAgent()}
+	if ip, _ := httputil.FindIP(payload.Request, iputil.PublicNetworkIPValidator{}); ip != nil {
+		result.ModuleContext[ipKey] = ip.String()
+	}
+	return result, nil
+}
+
+// HandleRawAuctionHook scores the request and takes the actions of the account thresholds the score reaches.
+// The bidders to strip are removed by the processed auction request hook.
+func (m *Module) HandleRawAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.RawAuctionRequestPayload]{}
+
+	th, err := m.thresholds.accountThresholds(miCtx.AccountConfig)
+	if err != nil {
+		return result, err
+	}
+
+	score, reasons := m.detector.score(requestSignals(payload, miCtx.ModuleContext))
+	if score == 0 {
+		return result, nil
+	}
+
+	if th.Reject > 0 && score >= th.Reject {
+		m.recordIVT(reasons, actionReject)
+		result.Reject = true
+		result.NbrCode = int(noBidReason(reasons))
+		result.Message = "request rejected as invalid traffic with a score of " + strconv.Itoa(score)
+		return result, nil
+	}
+
+	var actions []string
+	if th.Strip > 0 && score >= th.Strip {
+		actions = append(actions, actionStrip)
+		result.ModuleContext = hookstage.ModuleContext{stripBiddersKey: th.StripBidders}
+	}
+	if th.Mark > 0 && score >= th.Mark {
+		actions = append(actions, actionMark)
+		ivt := &openrtb_ext.ExtRequestPrebidIVT{Score: score, Reasons: reasons}
+		result.ChangeSet.AddMutation(func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
+			return markRequest(payload, ivt)
+		}, hookstage.MutationUpdate, "ext", "prebid", "ivt")
+	}
+	if len(actions) == 0 {
+		actions = append(actions, actionNone)
+	}
+
+	for _, action := range actions {
+		m.recordIVT(reasons, action)
+	}
+	return result, nil
+}
+
+// HandleProcessedAuctionHook removes from the imps the bidders the raw auction request hook decided to strip,
+// including the ones set by the stored requests.
+func (m *Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	_ hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+
+	bidders, _ := miCtx.ModuleContext[stripBiddersKey].([]string)
+	if len(bidders) == 0 {
+		return result, nil
+	}
+
+	toDelete := make(map[string]struct{}, len(bidders))
+	for _, bidder := range bidders {
+		toDelete[bidder] = struct{}{}
+	}
+	result.ChangeSet.ProcessedAuctionRequest().Bidders().Delete(toDelete)
+	return result, nil
+}
+
+func (m *Module) recordIVT(reasons []string, action string) {
+	me := m.metricsEngine.Get()
+	if me == nil {
+		return
+	}
+	for _, reason := range reasons {
+		me.RecordModuleIVT(metrics.ModuleIVTLabels{Module: metricsModuleName, Reason: reason, Action: action})
+	}
+}
+
+// requestSignals reads the signals from the request, falling back to the HTTP request ones kept by the entrypoint hook
+func requestSignals(payload hookstage.RawAuctionRequestPayload, moduleCtx hookstage.ModuleContext) signals {
+	request := gjson.ParseBytes(payload)
+	s := signals{
+		userAgent: request.Get("device.ua").String(),
+		appBundle: request.Get("app.bundle").String(),
+	}
+	if s.userAgent == "" {
+		s.userAgent, _ = moduleCtx[userAgentKey].(string)
+	}
+
+	ip := request.Get("device.ip").String()
+	if ip == "" {
+		ip = request.Get("device.ipv6").String()
+	}
+	if ip == "" {
+		ip, _ = moduleCtx[ipKey].(string)
+	}
+	s.ip, _ = iputil.ParseIP(ip)
+
+	return s
+}
+
+// noBidReason returns the no bid reason of the most specific reason the request matches
+func noBidReason(reasons []string) openrtb3.NoBidReason {
+	switch {
+	case slices.Contains(reasons, reasonUserAgent):
+		return openrtb3.NoBidCrawler
+	case slices.Contains(reasons, reasonDatacenterIP):
+		return openrtb3.NoBidProxy
+	default:
+		return openrtb3.NoBidNonHuman
+	}
+}
+
+// markRequest sets the score and reasons in ext.prebid.ivt for use by the later stages
+func markRequest(payload hookstage.RawAuctionRequestPayload, ivt *openrtb_ext.ExtRequestPrebidIVT) (hookstage.RawAuctionRequestPayload, error) {
+	value, err := jsonutil.Marshal(ivt)
+	if err != nil {
+		return payload, err
+	}
+	return sjson.SetRawBytes(payload, "ext.prebid.ivt", value)
+}
@@ -0,0 +1,288 @@
+package ivt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `{
+  "user_agent_patterns": {"values": ["bot", "crawl(er)?", "^curl/"]},
+  "datacenter_ips": {"values": ["52.0.0.0/8", "2600:1f00::/24"]},
+  "app_bundles": {"values": ["com.bad.app"]},
+  "default_thresholds": {"reject_threshold": 100, "strip_threshold": 50, "strip_bidders": ["bidderA"], "mark_threshold": 1}
+}`
+
+func newTestModule(t *testing.T, me metrics.MetricsEngine) *Module {
+	m, err := Builder(json.RawMessage(testConfig), moduledeps.ModuleDeps{MetricsEngine: moduledeps.NewMetricsEngineRef(me)})
+	require.NoError(t, err)
+
+	return m.(*Module)
+}
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "no_config",
+		},
+		{
+			name:        "malformed_config",
+			cfg:         `{"scores": []}`,
+			expectedErr: "failed to parse config",
+		},
+		{
+			name:        "invalid_pattern",
+			cfg:         `{"user_agent_patterns": {"values": ["bot("]}}`,
+			expectedErr: "failed to load user_agent_patterns: error parsing regexp",
+		},
+		{
+			name:        "invalid_cidr",
+			cfg:         `{"datacenter_ips": {"values": ["52.0.0.0"]}}`,
+			expectedErr: "failed to load datacenter_ips: invalid CIDR address: 52.0.0.0",
+		},
+		{
+			name:        "missing_file",
+			cfg:         `{"app_bundles": {"file": "/nonexistent/bundles.txt"}}`,
+			expectedErr: "failed to load app_bundles: open /nonexistent/bundles.txt",
+		},
+		{
+			name:        "strip_without_bidders",
+			cfg:         `{"default_thresholds": {"strip_threshold": 50}}`,
+			expectedErr: "invalid default_thresholds: strip_bidders is required when strip_threshold is set",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Builder(json.RawMessage(tc.cfg), moduledeps.ModuleDeps{})
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestNewDetectorWithFiles(t *testing.T) {
+	files := map[string]string{
+		"ua.txt":      "# IAB spiders and robots\n\nHeadlessChrome\n",
+		"ips.txt":     "10.0.0.0/8\n",
+		"bundles.txt": "com.Fraud.App\n",
+	}
+	readFile := func(path string) ([]byte, error) {
+		if content, ok := files[path]; ok {
+			return []byte(content), nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	cfg, err := newConfig(json.RawMessage(`{
+	  "user_agent_patterns": {"values": ["bot"], "file": "ua.txt"},
+	  "datacenter_ips": {"file": "ips.txt"},
+	  "app_bundles": {"file": "bundles.txt"},
+	  "scores": {"user_agent": 10, "datacenter_ip": 20, "app_bundle": 30}
+	}`))
+	require.NoError(t, err)
+
+	d, err := newDetector(cfg, readFile)
+	require.NoError(t, err)
+
+	score, reasons := d.score(signals{userAgent: "Mozilla/5.0 headlesschrome/120", ip: []byte{10, 1, 2, 3}, appBundle: "com.fraud.app"})
+	assert.Equal(t, 60, score)
+	assert.Equal(t, []string{reasonUserAgent, reasonDatacenterIP, reasonAppBundle}, reasons)
+
+	score, reasons = d.score(signals{userAgent: "Mozilla/5.0 Chrome/120", ip: []byte{11, 1, 2, 3}, appBundle: "com.good.app"})
+	assert.Zero(t, score)
+	assert.Empty(t, reasons)
+}
+
+func TestHandleEntrypointHook(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	require.NoError(t, err)
+	request.Header.Set("User-Agent", "Googlebot/2.1")
+	request.Header.Set("X-Forwarded-For", "52.1.2.3, 10.0.0.1")
+
+	result, err := newTestModule(t, nil).HandleEntrypointHook(context.Background(), hookstage.ModuleInvocationContext{}, hookstage.EntrypointPayload{Request: request})
+
+	require.NoError(t, err)
+	assert.Equal(t, hookstage.ModuleContext{userAgentKey: "Googlebot/2.1", ipKey: "52.1.2.3"}, result.ModuleContext)
+}
+
+func TestHandleRawAuctionHook(t *testing.T) {
+	testCases := []struct {
+		name              string
+		request           string
+		moduleCtx         hookstage.ModuleContext
+		accountConfig     string
+		expectedReject    bool
+		expectedNbr       int
+		expectedRequest   string
+		expectedModuleCtx hookstage.ModuleContext
+		expectedIVTLabels []metrics.ModuleIVTLabels
+	}{
+		{
+			name:            "valid_traffic",
+			request:         `{"device":{"ua":"Mozilla/5.0","ip":"1.2.3.4"},"imp":[{"ext":{"prebid":{"bidder":{"bidderA":{}}}}}]}`,
+			expectedRequest: `{"device":{"ua":"Mozilla/5.0","ip":"1.2.3.4"},"imp":[{"ext":{"prebid":{"bidder":{"bidderA":{}}}}}]}`,
+		},
+		{
+			name:           "bot_rejected",
+			request:        `{"device":{"ua":"Mozilla/5.0 (compatible; Googlebot/2.1)"}}`,
+			expectedReject: true,
+			expectedNbr:    int(openrtb3.NoBidCrawler),
+			expectedIVTLabels: []metrics.ModuleIVTLabels{
+				{Module: "prebid_ivt", Reason: reasonUserAgent, Action: actionReject},
+			},
+		},
+		{
+			name:           "bad_app_rejected",
+			request:        `{"app":{"bundle":"COM.BAD.APP"}}`,
+			expectedReject: true,
+			expectedNbr:    int(openrtb3.NoBidNonHuman),
+			expectedIVTLabels: []metrics.ModuleIVTLabels{
+				{Module: "prebid_ivt", Reason: reasonAppBundle, Action: actionReject},
+			},
+		},
+		{
+			name:              "datacenter_ip_stripped_and_marked",
+			request:           `{"device":{"ipv6":"2600:1f00::1"},"imp":[{"ext":{"prebid":{"bidder":{"bidderA":{},"bidderB":{}}}}},{"ext":{"bidderA":{},"bidderB":{}}}]}`,
+			expectedRequest:   `{"device":{"ipv6":"2600:1f00::1"},"imp":[{"ext":{"prebid":{"bidder":{"bidderA":{},"bidderB":{}}}}},{"ext":{"bidderA":{},"bidderB":{}}}],"ext":{"prebid":{"ivt":{"score":50,"reasons":["datacenter_ip"]}}}}`,
+			expectedModuleCtx: hookstage.ModuleContext{stripBiddersKey: []string{"bidderA"}},
+			expectedIVTLabels: []metrics.ModuleIVTLabels{
+				{Module: "prebid_ivt", Reason: reasonDatacenterIP, Action: actionStrip},
+				{Module: "prebid_ivt", Reason: reasonDatacenterIP, Action: actionMark},
+			},
+		},
+		{
+			name:            "signals_of_http_request_used",
+			request:         `{"imp":[]}`,
+			moduleCtx:       hookstage.ModuleContext{userAgentKey: "Mozilla/5.0", ipKey: "52.1.2.3"},
+			accountConfig:   `{"strip_threshold": 0}`,
+			expectedRequest: `{"imp":[],"ext":{"prebid":{"ivt":{"score":50,"reasons":["datacenter_ip"]}}}}`,
+			expectedIVTLabels: []metrics.ModuleIVTLabels{
+				{Module: "prebid_ivt", Reason: reasonDatacenterIP, Action: actionMark},
+			},
+		},
+		{
+			name:            "account_thresholds",
+			request:         `{"device":{"ua":"curl/8.0","ip":"52.1.2.3"},"imp":[]}`,
+			accountConfig:   `{"reject_threshold": 0, "strip_threshold": 0, "mark_threshold": 500}`,
+			expectedRequest: `{"device":{"ua":"curl/8.0","ip":"52.1.2.3"},"imp":[]}`,
+			expectedIVTLabels: []metrics.ModuleIVTLabels{
+				{Module: "prebid_ivt", Reason: reasonUserAgent, Action: actionNone},
+				{Module: "prebid_ivt", Reason: reasonDatacenterIP, Action: actionNone},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			for _, labels := range tc.expectedIVTLabels {
+				me.On("RecordModuleIVT", labels).Once()
+			}
+			m := newTestModule(t, me)
+
+			miCtx := hookstage.ModuleInvocationContext{ModuleContext: tc.moduleCtx, AccountConfig: json.RawMessage(tc.accountConfig)}
+			result, err := m.HandleRawAuctionHook(context.Background(), miCtx, hookstage.RawAuctionRequestPayload(tc.request))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedReject, result.Reject)
+			assert.Equal(t, tc.expectedNbr, result.NbrCode)
+			assert.Equal(t, tc.expectedModuleCtx, result.ModuleContext)
+			me.AssertExpectations(t)
+			if tc.expectedReject {
+				return
+			}
+
+			payload := hookstage.RawAuctionRequestPayload(tc.request)
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.JSONEq(t, tc.expectedRequest, string(payload))
+		})
+	}
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	testCases := []struct {
+		name            string
+		moduleCtx       hookstage.ModuleContext
+		request         *openrtb2.BidRequest
+		expectedBidders []map[string]json.RawMessage
+	}{
+		{
+			name:      "no_bidders_to_strip",
+			moduleCtx: hookstage.ModuleContext{userAgentKey: "Mozilla/5.0"},
+			request: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+				{ID: "imp1", Ext: json.RawMessage(`{"prebid":{"bidder":{"bidderA":{},"bidderB":{}}}}`)},
+			}},
+			expectedBidders: []map[string]json.RawMessage{
+				{"bidderA": json.RawMessage(`{}`), "bidderB": json.RawMessage(`{}`)},
+			},
+		},
+		{
+			// the bidders of imp2 come from a stored request merged after the raw auction request stage
+			name:      "bidders_stripped",
+			moduleCtx: hookstage.ModuleContext{stripBiddersKey: []string{"bidderA"}},
+			request: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+				{ID: "imp1", Ext: json.RawMessage(`{"prebid":{"bidder":{"bidderA":{},"bidderB":{}}}}`)},
+				{ID: "imp2", Ext: json.RawMessage(`{"prebid":{"bidder":{"bidderA":{"placement":1}}}}`)},
+			}},
+			expectedBidders: []map[string]json.RawMessage{
+				{"bidderB": json.RawMessage(`{}`)},
+				{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			miCtx := hookstage.ModuleInvocationContext{ModuleContext: tc.moduleCtx}
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: tc.request}}
+
+			result, err := newTestModule(t, nil).HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+			require.NoError(t, err)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+
+			imps := payload.Request.GetImp()
+			require.Len(t, imps, len(tc.expectedBidders))
+			for i, imp := range imps {
+				impExt, err := imp.GetImpExt()
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedBidders[i], impExt.GetPrebid().Bidder)
+			}
+		})
+	}
+}
+
+func TestHandleRawAuctionHookInvalidAccountConfig(t *testing.T) {
+	m := newTestModule(t, nil)
+	miCtx := hookstage.ModuleInvocationContext{AccountConfig: json.RawMessage(`{"strip_threshold": -1}`)}
+
+	_, err := m.HandleRawAuctionHook(context.Background(), miCtx, hookstage.RawAuctionRequestPayload(`{}`))
+
+	assert.EqualError(t, err, "invalid account config: thresholds must be >= 0")
+	assert.Equal(t, []string{"bidderA"}, m.thresholds.StripBidders, "account config does not change the default thresholds")
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/prebid/prebid-server/v3/hooks"
@@ -16,10 +17,14 @@ import (
 type hash = string
 
 type cacheEntry struct {
-	enabled                                 bool
-	timestamp                               time.Time
-	hashedConfig                            hash
-	ruleSetsForProcessedAuctionRequestStage []cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]
+	enabled                                  bool
+	timestamp                                time.Time
+	hashedConfig                             hash
+	stickyBy                                 string
+	ruleSetsForProcessedAuctionRequestStage  []cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]
+	impRuleSetsForProcessedAuctionRequest    []cacheRuleSet[rules.ImpWrapper, ImpHookResult]
+	ruleSetsForRawBidderResponseStage        []cacheRuleSet[rules.BidWrapper, BidHookResult]
+	ruleSetsForAllProcessedBidResponsesStage []cacheRuleSet[rules.BidWrapper, BidHookResult]
 }
 type cacheRuleSet[T1 any, T2 any] struct {
 	name        string
@@ -33,66 +38,121 @@ type cacheModelGroup[T1 any, T2 any] struct {
 }
 
 // NewCacheEntry creates a new cache object for the given configuration
-// It builds the tree structures for the rule sets for the processed auction request stage
-// and stores them in the cache object
+// It builds the tree structures for the rule sets for the processed auction request, raw bidder
+// response and all processed bid responses stages and stores them in the cache object
+// Rule sets whose trees cannot be built are left out of the cache object
 func NewCacheEntry(cfg *config.PbRulesEngine, cfgRaw *json.RawMessage) (cacheEntry, error) {
+	newCacheObj, _, err := buildCacheEntry(cfg, cfgRaw)
+	return newCacheObj, err
+}
+
+// buildCacheEntry creates a new cache object for the given configuration the same way NewCacheEntry
+// does, also returning the errors of the rule sets left out of the cache object
+func buildCacheEntry(cfg *config.PbRulesEngine, cfgRaw *json.RawMessage) (cacheEntry, []error, error) {
 	if cfg == nil {
-		return cacheEntry{}, errors.New("no rules engine configuration provided")
+		return cacheEntry{}, nil, errors.New("no rules engine configuration provided")
 	}
 
 	idHash := hashConfig(cfgRaw)
 	if idHash == "" {
-		return cacheEntry{}, errors.New("Can't create identifier hash from empty raw json configuration")
+		return cacheEntry{}, nil, errors.New("Can't create identifier hash from empty raw json configuration")
 	}
 
 	newCacheObj := cacheEntry{
 		enabled:      cfg.Enabled,
 		timestamp:    time.Now(),
 		hashedConfig: idHash,
+		stickyBy:     cfg.StickyBy,
 	}
 
+	var ruleSetErrs []error
 	for _, ruleSet := range cfg.RuleSets {
-		if ruleSet.Stage != hooks.StageProcessedAuctionRequest {
-			// TODO: log error / metric --> stage not supported
-			continue
-		}
-		crs, err := createCacheRuleSet(&ruleSet)
-		if err != nil {
-			// TODO: log error / metric -->
-			continue
+		switch ruleSet.Stage {
+		case hooks.StageProcessedAuctionRequest:
+			if ruleSet.Scope == config.ScopeImp {
+				crs, err := createImpCacheRuleSet(&ruleSet)
+				if err != nil {
+					ruleSetErrs = append(ruleSetErrs, fmt.Errorf("rule set %s: %w", ruleSet.Name, err))
+					continue
+				}
+				newCacheObj.impRuleSetsForProcessedAuctionRequest = append(newCacheObj.impRuleSetsForProcessedAuctionRequest, crs)
+				continue
+			}
+			crs, err := createCacheRuleSet(&ruleSet)
+			if err != nil {
+				ruleSetErrs = append(ruleSetErrs, fmt.Errorf("rule set %s: %w", ruleSet.Name, err))
+				continue
+			}
+			newCacheObj.ruleSetsForProcessedAuctionRequestStage = append(newCacheObj.ruleSetsForProcessedAuctionRequestStage, crs)
+		case hooks.StageRawBidderResponse:
+			crs, err := createBidResponseCacheRuleSet(&ruleSet)
+			if err != nil {
+				ruleSetErrs = append(ruleSetErrs, fmt.Errorf("rule set %s: %w", ruleSet.Name, err))
+				continue
+			}
+			newCacheObj.ruleSetsForRawBidderResponseStage = append(newCacheObj.ruleSetsForRawBidderResponseStage, crs)
+		case hooks.StageAllProcessedBidResponses:
+			crs, err := createBidResponseCacheRuleSet(&ruleSet)
+			if err != nil {
+				ruleSetErrs = append(ruleSetErrs, fmt.Errorf("rule set %s: %w", ruleSet.Name, err))
+				continue
+			}
+			newCacheObj.ruleSetsForAllProcessedBidResponsesStage = append(newCacheObj.ruleSetsForAllProcessedBidResponsesStage, crs)
+		default:
+			ruleSetErrs = append(ruleSetErrs, fmt.Errorf("rule set %s: stage %s is not supported", ruleSet.Name, ruleSet.Stage))
 		}
-
-		newCacheObj.ruleSetsForProcessedAuctionRequestStage = append(newCacheObj.ruleSetsForProcessedAuctionRequestStage, crs)
 	}
 
-	return newCacheObj, nil
+	return newCacheObj, ruleSetErrs, nil
 }
 
-// createCacheRuleSet creates a new cache rule set for the given configuration
-// It builds the tree structures for the model groups and stores them in the cache rule set
+// createCacheRuleSet creates a new cache rule set for the given processed auction request stage configuration
 func createCacheRuleSet(cfg *config.RuleSet) (cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult], error) {
+	return newCacheRuleSet(cfg, rules.NewRequestSchemaFunction, NewProcessedAuctionRequestResultFunction)
+}
+
+// createImpCacheRuleSet creates a new cache rule set for the given imp scoped processed auction request
+// stage configuration
+func createImpCacheRuleSet(cfg *config.RuleSet) (cacheRuleSet[rules.ImpWrapper, ImpHookResult], error) {
+	return newCacheRuleSet(cfg, rules.NewImpSchemaFunction, NewProcessedAuctionImpResultFunction)
+}
+
+// createBidResponseCacheRuleSet creates a new cache rule set for the given raw bidder response or
+// all processed bid responses stage configuration
+func createBidResponseCacheRuleSet(cfg *config.RuleSet) (cacheRuleSet[rules.BidWrapper, BidHookResult], error) {
+	return newCacheRuleSet(cfg, rules.NewResponseSchemaFunction, NewBidResponseResultFunction)
+}
+
+// newCacheRuleSet creates a new cache rule set for the given configuration
+// It builds the tree structures for the model groups using the provided schema and result
+// function factories and stores them in the cache rule set
+func newCacheRuleSet[T1 any, T2 any](
+	cfg *config.RuleSet,
+	schemaFuncFactory rules.SchemaFuncFactory[T1],
+	resultFuncFactory rules.ResultFuncFactory[T1, T2],
+) (cacheRuleSet[T1, T2], error) {
 	if cfg == nil {
-		return cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{}, errors.New("no rules engine configuration provided")
+		return cacheRuleSet[T1, T2]{}, errors.New("no rules engine configuration provided")
 	}
 
-	crs := cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{
+	crs := cacheRuleSet[T1, T2]{
 		name:        cfg.Name,
-		modelGroups: []cacheModelGroup[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{},
+		modelGroups: []cacheModelGroup[T1, T2]{},
 	}
 
 	for _, modelGroup := range cfg.ModelGroups {
-		tree, err := rules.NewTree[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult](
-			&treeBuilder[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{
+		tree, err := rules.NewTree[T1, T2](
+			&treeBuilder[T1, T2]{
 				Config:            modelGroup,
-				SchemaFuncFactory: rules.NewRequestSchemaFunction,
-				ResultFuncFactory: NewProcessedAuctionRequestResultFunction,
+				SchemaFuncFactory: schemaFuncFactory,
+				ResultFuncFactory: resultFuncFactory,
 			},
 		)
 		if err != nil {
 			return crs, err
 		}
 
-		cmg := cacheModelGroup[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{
+		cmg := cacheModelGroup[T1, T2]{
 			weight:       modelGroup.Weight,
 			version:      modelGroup.Version,
 			analyticsKey: modelGroup.AnalyticsKey,
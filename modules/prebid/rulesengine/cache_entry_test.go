@@ -175,6 +175,36 @@ func TestNewCacheEntry(t *testing.T) {
 	}
 }
 
+func TestNewCacheEntryBidResponseStages(t *testing.T) {
+	rejectBidsRuleSet := func(stage hooks.Stage, resultFunc string) config.RuleSet {
+		return config.RuleSet{
+			Stage: stage,
+			ModelGroups: []config.ModelGroup{
+				{
+					Default: []config.Result{{Func: resultFunc}},
+				},
+			},
+		}
+	}
+
+	cfg := &config.PbRulesEngine{
+		RuleSets: []config.RuleSet{
+			rejectBidsRuleSet(hooks.StageRawBidderResponse, RejectBidsName),
+			rejectBidsRuleSet(hooks.StageAllProcessedBidResponses, RejectBidsName),
+			rejectBidsRuleSet(hooks.StageAllProcessedBidResponses, RejectBidsName),
+			// request stage result functions are not allowed at the bid response stages
+			rejectBidsRuleSet(hooks.StageRawBidderResponse, ExcludeBiddersName),
+		},
+	}
+
+	cacheEntry, err := NewCacheEntry(cfg, getValidJsonConfig())
+
+	assert.NoError(t, err)
+	assert.Empty(t, cacheEntry.ruleSetsForProcessedAuctionRequestStage)
+	assert.Len(t, cacheEntry.ruleSetsForRawBidderResponseStage, 1)
+	assert.Len(t, cacheEntry.ruleSetsForAllProcessedBidResponsesStage, 2)
+}
+
 func TestCreateCacheRuleSet(t *testing.T) {
 	testCases := []struct {
 		name            string
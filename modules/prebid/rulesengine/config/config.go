@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/prebid/prebid-server/v3/hooks"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -15,16 +18,111 @@ import (
 const RulesEngineSchemaFile = "rules-engine-schema.json"
 const RulesEngineSchemaFilePath = "modules/prebid/rulesengine/config/" + RulesEngineSchemaFile
 
+// Rule set scopes. Request scoped rule sets run their trees once per request while imp scoped
+// rule sets run them once per impression.
+const (
+	ScopeRequest = "request"
+	ScopeImp     = "imp"
+)
+
+// Request fields model groups can be assigned by. A request is assigned the same model group of a rule set
+// as every other request sharing the value of the field.
+const (
+	StickyBySourceTID = "source.tid"
+	StickyByUserID    = "user.id"
+	StickyByDeviceIFA = "device.ifa"
+)
+
 type PbRulesEngine struct {
-	Enabled   bool      `json:"enabled,omitempty"`
-	Timestamp string    `json:"timestamp,omitempty"`
-	RuleSets  []RuleSet `json:"rulesets,omitempty"`
+	Enabled   bool           `json:"enabled,omitempty"`
+	Timestamp string         `json:"timestamp,omitempty"`
+	StickyBy  string         `json:"stickyby,omitempty"`
+	Source    *RuleSetSource `json:"source,omitempty"`
+	RuleSets  []RuleSet      `json:"rulesets,omitempty"`
+}
+
+// RuleSetSource is the local file or the URL the rule sets are loaded from and periodically reloaded.
+// The source holds a JSON object whose rulesets replace the ones found in the account configuration.
+type RuleSetSource struct {
+	Path               string `json:"path,omitempty"`
+	URL                string `json:"url,omitempty"`
+	RefreshRateSeconds int    `json:"refreshrateseconds,omitempty"`
+	TimeoutMs          int    `json:"timeoutms,omitempty"`
+}
+
+// SourceAllowlist holds the directories and URL prefixes of the host configuration the rule set sources
+// of the accounts are restricted to. Account sources are rejected unless they are found in one of them.
+type SourceAllowlist struct {
+	Paths       []string `json:"paths,omitempty"`
+	URLPrefixes []string `json:"urlprefixes,omitempty"`
+}
+
+// Validate checks the URL prefixes are absolute http or https URLs so they can be matched by host
+func (a SourceAllowlist) Validate() error {
+	for _, prefix := range a.URLPrefixes {
+		u, err := url.Parse(prefix)
+		if err != nil {
+			return fmt.Errorf("invalid source url prefix %q: %v", prefix, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return fmt.Errorf("invalid source url prefix %q: an http or https url is expected", prefix)
+		}
+	}
+	return nil
+}
+
+// allowsPath returns true if the file is located in one of the allowed directories or their subdirectories
+func (a SourceAllowlist) allowsPath(p string) bool {
+	absPath, err := filepath.Abs(p)
+	if err != nil {
+		return false
+	}
+	for _, dir := range a.Paths {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(absDir, absPath)
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsURL returns true if the URL has the scheme and host of one of the allowed prefixes and its path
+// is found under the prefix path. URLs are compared once parsed so that a prefix is not matched by a
+// different host sharing its leading characters.
+func (a SourceAllowlist) allowsURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	urlPath := path.Clean("/" + u.Path)
+	for _, prefix := range a.URLPrefixes {
+		p, err := url.Parse(prefix)
+		if err != nil {
+			continue
+		}
+		if u.Scheme != p.Scheme || u.Host != p.Host {
+			continue
+		}
+		prefixPath := path.Clean("/" + p.Path)
+		if prefixPath == "/" || urlPath == prefixPath || strings.HasPrefix(urlPath, prefixPath+"/") {
+			return true
+		}
+	}
+	return false
 }
 
 type RuleSet struct {
 	Stage       hooks.Stage  `json:"stage,omitempty"`
 	Name        string       `json:"name,omitempty"`
 	Version     string       `json:"version,omitempty"`
+	Scope       string       `json:"scope,omitempty"`
 	ModelGroups []ModelGroup `json:"modelgroups,omitempty"`
 }
 
@@ -61,6 +159,53 @@ type ResultFuncParams struct {
 	IfSyncedId     bool     `json:"ifsyncedid,omitempty"`
 }
 
+// RejectBidsParams is a struct that holds parameters for the RejectBids result function.
+type RejectBidsParams struct {
+	SeatNonBid     int    `json:"seatnonbid,omitempty"`
+	AnalyticsValue string `json:"analyticsvalue,omitempty"`
+}
+
+// AdjustBidPriceParams is a struct that holds parameters for the AdjustBidPrice result function.
+type AdjustBidPriceParams struct {
+	Factor         float64 `json:"factor,omitempty"`
+	AnalyticsValue string  `json:"analyticsvalue,omitempty"`
+}
+
+// TagBidParams is a struct that holds parameters for the TagBid result function.
+type TagBidParams struct {
+	AnalyticsValue string `json:"analyticsvalue,omitempty"`
+}
+
+// BidFloorParams is a struct that holds parameters for the SetBidFloor and RaiseBidFloor result functions.
+type BidFloorParams struct {
+	BidFloor       float64 `json:"bidfloor,omitempty"`
+	BidFloorCur    string  `json:"bidfloorcur,omitempty"`
+	AnalyticsValue string  `json:"analyticsvalue,omitempty"`
+}
+
+// CapTmaxParams is a struct that holds parameters for the CapTmax result function.
+type CapTmaxParams struct {
+	Tmax           int64  `json:"tmax,omitempty"`
+	AnalyticsValue string `json:"analyticsvalue,omitempty"`
+}
+
+// SetTargetingParams is a struct that holds parameters for the SetTargeting result function.
+type SetTargetingParams struct {
+	Keys           map[string]string `json:"keys,omitempty"`
+	AnalyticsValue string            `json:"analyticsvalue,omitempty"`
+}
+
+// SetBidderParamsParams is a struct that holds parameters for the SetBidderParams result function.
+type SetBidderParamsParams struct {
+	BidderParams   map[string]json.RawMessage `json:"bidderparams,omitempty"`
+	AnalyticsValue string                     `json:"analyticsvalue,omitempty"`
+}
+
+// LogATagParams is a struct that holds parameters for the LogATag result function.
+type LogATagParams struct {
+	AnalyticsValue string `json:"analyticsvalue,omitempty"`
+}
+
 func CreateSchemaValidator(jsonSchemaFile string) (*gojsonschema.Schema, error) {
 	jsonSchemaFilePath, err := filepath.Abs(jsonSchemaFile)
 	if err != nil {
@@ -87,7 +232,29 @@ func validateConfig(rawCfg json.RawMessage, schemaValidator *gojsonschema.Schema
 	return nil
 }
 
+// validateSource checks the source sets either a path or a url, and that the host configuration allows it
+// since account configurations are not trusted to read local files or to send requests to any url
+func validateSource(s *RuleSetSource, allowlist SourceAllowlist) error {
+	if len(s.Path) > 0 && len(s.URL) > 0 {
+		return errors.New("source path and url are mutually exclusive")
+	}
+	if len(s.Path) == 0 && len(s.URL) == 0 {
+		return errors.New("source requires a path or a url")
+	}
+	if len(s.Path) > 0 && !allowlist.allowsPath(s.Path) {
+		return fmt.Errorf("source path %s is not allowed by the host configuration", s.Path)
+	}
+	if len(s.URL) > 0 && !allowlist.allowsURL(s.URL) {
+		return fmt.Errorf("source url %s is not allowed by the host configuration", s.URL)
+	}
+	return nil
+}
+
 func validateRuleSet(r *RuleSet) error {
+	if r.Scope == ScopeImp && r.Stage != hooks.StageProcessedAuctionRequest {
+		return fmt.Errorf("imp scope is not supported at stage %s", r.Stage)
+	}
+
 	for i := 0; i < len(r.ModelGroups); i++ {
 		// Modelgroup weight defaults to 100
 		if r.ModelGroups[i].Weight == 0 {
@@ -112,7 +279,9 @@ func validateRuleSet(r *RuleSet) error {
 	return nil
 }
 
-func NewConfig(jsonCfg json.RawMessage, validator *gojsonschema.Schema) (*PbRulesEngine, error) {
+// NewConfig parses and validates the account configuration. The rule set source it sets must be allowed by
+// the host source allowlist.
+func NewConfig(jsonCfg json.RawMessage, validator *gojsonschema.Schema, allowlist SourceAllowlist) (*PbRulesEngine, error) {
 	cfg := &PbRulesEngine{}
 
 	if err := validateConfig(jsonCfg, validator); err != nil {
@@ -123,6 +292,12 @@ func NewConfig(jsonCfg json.RawMessage, validator *gojsonschema.Schema) (*PbRule
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	if cfg.Source != nil {
+		if err := validateSource(cfg.Source, allowlist); err != nil {
+			return nil, err
+		}
+	}
+
 	for i := 0; i < len(cfg.RuleSets); i++ {
 		if err := validateRuleSet(&cfg.RuleSets[i]); err != nil {
 			return nil, fmt.Errorf("Ruleset no %d is invalid: %s", i, err.Error())
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/prebid/prebid-server/v3/hooks"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,6 +17,7 @@ func TestNewConfig(t *testing.T) {
 	testCases := []struct {
 		name         string
 		inCfg        json.RawMessage
+		allowlist    SourceAllowlist
 		expectedConf *PbRulesEngine
 		expectedErr  error
 	}{
@@ -32,13 +34,49 @@ func TestNewConfig(t *testing.T) {
 		{
 			name:        "valid-input-config-fails-schema-validation",
 			inCfg:       json.RawMessage(`{}`),
-			expectedErr: errors.New("JSON schema validation: [(root): enabled is required] [(root): rulesets is required] "),
+			expectedErr: errors.New("JSON schema validation: [(root): Must validate at least one schema (anyOf)] [(root): rulesets is required] [(root): enabled is required] "),
 		},
 		{
 			name:        "valid-input-config-fails-rule-set-validation",
 			inCfg:       getInvalidRuleSetConfig(),
 			expectedErr: errors.New("Ruleset no 0 is invalid: ModelGroup 0 number of schema functions differ from number of conditions of rule 0"),
 		},
+		{
+			name:        "source-without-path-or-url",
+			inCfg:       json.RawMessage(`{"enabled": true, "source": {"refreshrateseconds": 60}}`),
+			expectedErr: errors.New("source requires a path or a url"),
+		},
+		{
+			name:        "source-with-path-and-url",
+			inCfg:       json.RawMessage(`{"enabled": true, "source": {"path": "rules.json", "url": "http://rules.example.com/rules.json"}}`),
+			expectedErr: errors.New("source path and url are mutually exclusive"),
+		},
+		{
+			name:        "source-path-not-allowed",
+			inCfg:       json.RawMessage(`{"enabled": true, "source": {"path": "/etc/passwd"}}`),
+			allowlist:   SourceAllowlist{Paths: []string{"/etc/rules"}},
+			expectedErr: errors.New("source path /etc/passwd is not allowed by the host configuration"),
+		},
+		{
+			name:        "source-url-not-allowed",
+			inCfg:       json.RawMessage(`{"enabled": true, "source": {"url": "http://169.254.169.254/latest/meta-data"}}`),
+			allowlist:   SourceAllowlist{URLPrefixes: []string{"http://rules.example.com/"}},
+			expectedErr: errors.New("source url http://169.254.169.254/latest/meta-data is not allowed by the host configuration"),
+		},
+		{
+			name:        "source-without-allowlist",
+			inCfg:       json.RawMessage(`{"enabled": true, "source": {"path": "rules.json"}}`),
+			expectedErr: errors.New("source path rules.json is not allowed by the host configuration"),
+		},
+		{
+			name:      "source",
+			inCfg:     json.RawMessage(`{"enabled": true, "source": {"path": "rules.json", "refreshrateseconds": 60}}`),
+			allowlist: SourceAllowlist{Paths: []string{"."}},
+			expectedConf: &PbRulesEngine{
+				Enabled: true,
+				Source:  &RuleSetSource{Path: "rules.json", RefreshRateSeconds: 60},
+			},
+		},
 		{
 			name:         "success",
 			inCfg:        getValidJsonConfig(),
@@ -48,7 +86,7 @@ func TestNewConfig(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			actualConf, err := NewConfig(tc.inCfg, validator)
+			actualConf, err := NewConfig(tc.inCfg, validator, tc.allowlist)
 
 			assert.Equal(t, tc.expectedConf, actualConf)
 			assert.Equal(t, tc.expectedErr, err)
@@ -56,6 +94,89 @@ func TestNewConfig(t *testing.T) {
 	}
 }
 
+func TestSourceAllowlistAllowsPath(t *testing.T) {
+	allowlist := SourceAllowlist{Paths: []string{"/etc/rules", "/var/rules/"}}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{name: "in_directory", path: "/etc/rules/rules.json", expected: true},
+		{name: "in_subdirectory", path: "/var/rules/account/rules.json", expected: true},
+		{name: "outside_directories", path: "/etc/passwd", expected: false},
+		{name: "directory_sharing_prefix", path: "/etc/rules-other/rules.json", expected: false},
+		{name: "traversal", path: "/etc/rules/../passwd", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, allowlist.allowsPath(tc.path))
+		})
+	}
+}
+
+func TestSourceAllowlistAllowsURL(t *testing.T) {
+	allowlist := SourceAllowlist{URLPrefixes: []string{"https://rules.example.com/accounts", "http://localhost:8080"}}
+
+	testCases := []struct {
+		name     string
+		url      string
+		expected bool
+	}{
+		{name: "under_prefix", url: "https://rules.example.com/accounts/1/rules.json", expected: true},
+		{name: "prefix", url: "https://rules.example.com/accounts", expected: true},
+		{name: "host_prefix", url: "http://localhost:8080/rules.json", expected: true},
+		{name: "different_scheme", url: "http://rules.example.com/accounts/1/rules.json", expected: false},
+		{name: "different_port", url: "http://localhost:9090/rules.json", expected: false},
+		{name: "host_sharing_prefix", url: "https://rules.example.com.attacker.com/accounts/rules.json", expected: false},
+		{name: "userinfo", url: "https://rules.example.com@attacker.com/accounts/rules.json", expected: false},
+		{name: "path_sharing_prefix", url: "https://rules.example.com/accounts-other/rules.json", expected: false},
+		{name: "traversal", url: "https://rules.example.com/accounts/../admin", expected: false},
+		{name: "malformed", url: "https://rules.example.com/%zz", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, allowlist.allowsURL(tc.url))
+		})
+	}
+}
+
+func TestSourceAllowlistValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		allowlist   SourceAllowlist
+		expectedErr string
+	}{
+		{
+			name:      "valid",
+			allowlist: SourceAllowlist{Paths: []string{"/etc/rules"}, URLPrefixes: []string{"https://rules.example.com/"}},
+		},
+		{
+			name:        "relative_url_prefix",
+			allowlist:   SourceAllowlist{URLPrefixes: []string{"rules.example.com"}},
+			expectedErr: `invalid source url prefix "rules.example.com": an http or https url is expected`,
+		},
+		{
+			name:        "file_url_prefix",
+			allowlist:   SourceAllowlist{URLPrefixes: []string{"file:///etc"}},
+			expectedErr: `invalid source url prefix "file:///etc": an http or https url is expected`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.allowlist.Validate()
+			if len(tc.expectedErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
 func TestCreateSchemaValidator(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -115,11 +236,11 @@ func TestValidateConfig(t *testing.T) {
 			[]testInput{
 				{ //0
 					json.RawMessage(`{}`),
-					"[(root): enabled is required] [(root): rulesets is required] ",
+					"[(root): Must validate at least one schema (anyOf)] [(root): rulesets is required] [(root): enabled is required] ",
 				},
 				{ //1
 					json.RawMessage(`{"enabled": true}`),
-					"[(root): rulesets is required] ",
+					"[(root): Must validate at least one schema (anyOf)] [(root): rulesets is required] ",
 				},
 				{ //2
 					json.RawMessage(`{"enabled": true, "rulesets": []}`),
@@ -138,14 +259,18 @@ func TestValidateConfig(t *testing.T) {
 					"[rulesets.0: name is required] [rulesets.0: modelgroups is required] ",
 				},
 				{ //6
+					json.RawMessage(`{"enabled": true, "stickyby": "site.page", "rulesets": [{"stage":"entrypoint","name":"n","modelgroups":[{"schema":[{"function":"channel"}],"rules":[{"conditions":["web"],"results":[{"function":"excludeBidders"}]}]}]}]}`),
+					"[stickyby: stickyby must be one of the following: \"source.tid\", \"user.id\", \"device.ifa\"] ",
+				},
+				{ //7
 					json.RawMessage(`{"enabled": true, "rulesets": [{"stage":"entrypoint","name":"n"}]}`),
 					"[rulesets.0: modelgroups is required] ",
 				},
-				{ //7
+				{ //8
 					json.RawMessage(`{"enabled": true, "rulesets": [{"stage":"entrypoint","name":"n","modelgroups":[]}]}`),
 					"[rulesets.0.modelgroups: Array must have at least 1 items] ",
 				},
-				{ //8
+				{ //9
 					json.RawMessage(`
                     {
                       "enabled": true,
@@ -171,7 +296,7 @@ func TestValidateConfig(t *testing.T) {
 					`),
 					"[rulesets.0.modelgroups.0.weight: Must be less than or equal to 100] ",
 				},
-				{ //9
+				{ //10
 					json.RawMessage(`
                     {
                       "enabled": true,
@@ -197,7 +322,7 @@ func TestValidateConfig(t *testing.T) {
 					`),
 					"[rulesets.0.modelgroups.0.weight: Must be greater than or equal to 1] ",
 				},
-				{ //10
+				{ //11
 					json.RawMessage(`
                     {
                       "enabled": true,
@@ -222,7 +347,7 @@ func TestValidateConfig(t *testing.T) {
 					`),
 					"",
 				},
-				{ //11
+				{ //12
 					json.RawMessage(`
                     {
                       "enabled": true,
@@ -242,7 +367,7 @@ func TestValidateConfig(t *testing.T) {
 					`),
 					"",
 				},
-				{ //12
+				{ //13
 					json.RawMessage(`
                     {
                       "enabled": true,
@@ -265,9 +390,9 @@ func TestValidateConfig(t *testing.T) {
                       ]
                     }
 					`),
-					"[rulesets.0.modelgroups.0.schema.0.function: rulesets.0.modelgroups.0.schema.0.function must be one of the following: \"channel\", \"dataCenter\", \"dataCenterIn\", \"deviceCountry\", \"deviceCountryIn\", \"eidAvailable\", \"eidIn\", \"fpdAvailable\", \"gppSidAvailable\", \"gppSidIn\", \"percent\", \"tcfInScope\", \"userFpdAvailable\"] ",
+					"[rulesets.0.modelgroups.0.schema.0.function: rulesets.0.modelgroups.0.schema.0.function must be one of the following: \"channel\", \"dataCenter\", \"dataCenterIn\", \"deviceCountry\", \"deviceCountryIn\", \"eidAvailable\", \"eidIn\", \"fpdAvailable\", \"gppSidAvailable\", \"gppSidIn\", \"percent\", \"tcfInScope\", \"userFpdAvailable\", \"deviceType\", \"domainIn\", \"bundleIn\", \"userAgentFamily\", \"coppaInScope\", \"deviceLmt\", \"dayOfWeek\", \"hourOfDay\", \"pagePathPrefix\", \"integration\", \"tmaxBucket\", \"adomain\", \"adomainIn\", \"bidPriceBucket\", \"dealId\", \"mediaType\", \"seat\", \"seatIn\", \"adUnitCode\", \"gpid\", \"impBidFloor\", \"impMediaType\", \"impSize\"] ",
 				},
-				{ //13
+				{ //14
 					json.RawMessage(`
                     {
                       "enabled": true,
@@ -292,7 +417,7 @@ func TestValidateConfig(t *testing.T) {
 					`),
 					"[rulesets.0.modelgroups.0.rules.0.conditions: Array must have at least 1 items] ",
 				},
-				{ //14
+				{ //15
 					json.RawMessage(`
                     {
                       "enabled": true,
@@ -317,7 +442,7 @@ func TestValidateConfig(t *testing.T) {
 					`),
 					"",
 				},
-				{ //15
+				{ //16
 					json.RawMessage(`
                     {
                       "enabled": true,
@@ -340,13 +465,24 @@ func TestValidateConfig(t *testing.T) {
                       ]
                     }
 					`),
-					"[rulesets.0.modelgroups.0.rules.0.results.0.function: rulesets.0.modelgroups.0.rules.0.results.0.function must be one of the following: \"excludeBidders\", \"includeBidders\", \"logATag\"] ",
+					"[rulesets.0.modelgroups.0.rules.0.results.0.function: rulesets.0.modelgroups.0.rules.0.results.0.function must be one of the following: \"excludeBidders\", \"includeBidders\", \"logATag\", \"rejectBids\", \"adjustBidPrice\", \"tagBid\", \"setBidFloor\", \"raiseBidFloor\", \"capTmax\", \"setTargeting\", \"setBidderParams\"] ",
+				},
+				{ //14
+					json.RawMessage(`{"enabled": true, "source": {"url": "", "refreshrateseconds": 60}}`),
+					"[source.url: String length must be greater than or equal to 1] ",
+				},
+				{ //15
+					json.RawMessage(`{"enabled": true, "source": {"url": "http://rules.example.com/rules.json", "refreshrateseconds": 0}}`),
+					"[source.refreshrateseconds: Must be greater than or equal to 1] ",
 				},
 			},
 		},
 		{
 			"successful rules engine schema validation",
-			[]testInput{{getValidJsonConfig(), ""}},
+			[]testInput{
+				{getValidJsonConfig(), ""},
+				{json.RawMessage(`{"enabled": true, "source": {"url": "http://rules.example.com/rules.json", "refreshrateseconds": 60}}`), ""},
+			},
 		},
 	}
 
@@ -461,6 +597,28 @@ func TestValidateRuleSet(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			desc: "imp-scope-at-processed-auction-request-stage",
+			ruleSet: &RuleSet{
+				Stage: hooks.StageProcessedAuctionRequest,
+				Scope: ScopeImp,
+				ModelGroups: []ModelGroup{
+					{
+						Schema: []Schema{{Func: "impMediaType"}},
+						Rules:  []Rule{{Conditions: []string{"video"}}},
+					},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			desc: "imp-scope-at-unsupported-stage",
+			ruleSet: &RuleSet{
+				Stage: hooks.StageRawBidderResponse,
+				Scope: ScopeImp,
+			},
+			expectedErr: errors.New("imp scope is not supported at stage raw_bidder_response"),
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
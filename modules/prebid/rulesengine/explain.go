@@ -0,0 +1,277 @@
+package rulesengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	"github.com/prebid/prebid-server/v3/account"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	moduleName      = "prebid.rulesengine"
+	explainEndpoint = "explain"
+)
+
+// explainRequest is the body expected by the explain endpoint. Request bidders are read from
+// imp.ext.prebid.bidder as they are found at the processed auction request stage.
+type explainRequest struct {
+	AccountID  string               `json:"account"`
+	BidRequest *openrtb2.BidRequest `json:"bidrequest"`
+}
+
+// explainResponse describes how the account rule sets evaluate the processed auction request
+type explainResponse struct {
+	AccountID string                  `json:"account"`
+	RuleSets  []ruleSetExplanation    `json:"rulesets"`
+	Bidders   []impBiddersExplanation `json:"bidders"`
+	Errors    []string                `json:"errors,omitempty"`
+}
+
+// ruleSetExplanation holds the path taken through the tree of the selected model group of a rule set.
+// Imp scoped rule sets are explained once per impression.
+type ruleSetExplanation struct {
+	Name            string            `json:"name"`
+	Scope           string            `json:"scope"`
+	ImpID           string            `json:"impid,omitempty"`
+	ModelVersion    string            `json:"modelversion,omitempty"`
+	AnalyticsKey    string            `json:"analyticskey,omitempty"`
+	Path            []nodeExplanation `json:"path"`
+	RuleFired       string            `json:"rulefired"`
+	Default         bool              `json:"default"`
+	ResultFunctions []string          `json:"resultfunctions"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// nodeExplanation holds a schema function result and the condition it matched, which is empty
+// if no child of the node matched the result
+type nodeExplanation struct {
+	Function  string `json:"function"`
+	Value     string `json:"value"`
+	Condition string `json:"condition,omitempty"`
+}
+
+// impBiddersExplanation holds the bidders left in and removed from an impression by the rule sets
+type impBiddersExplanation struct {
+	ImpID    string   `json:"impid"`
+	Allowed  []string `json:"allowed"`
+	Excluded []string `json:"excluded"`
+}
+
+// AdminEndpoints exposes the explain endpoint that evaluates the account rule sets against a sample
+// request without running an auction.
+func (m Module) AdminEndpoints(deps moduledeps.AdminDeps) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		explainEndpoint: m.newExplainHandler(deps),
+	}
+}
+
+func (m Module) newExplainHandler(deps moduledeps.AdminDeps) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeExplainError(w, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %s", err))
+			return
+		}
+
+		var req explainRequest
+		if err := jsonutil.UnmarshalValid(body, &req); err != nil {
+			writeExplainError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+			return
+		}
+		if len(req.AccountID) == 0 || req.BidRequest == nil {
+			writeExplainError(w, http.StatusBadRequest, "account and bidrequest are required")
+			return
+		}
+
+		co, err := m.explainCacheEntry(r.Context(), deps, req.AccountID)
+		if err != nil {
+			writeExplainError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		resp := explain(co, &openrtb_ext.RequestWrapper{BidRequest: req.BidRequest})
+		resp.AccountID = req.AccountID
+
+		jsonOutput, err := jsonutil.Marshal(resp)
+		if err != nil {
+			glog.Errorf("rules engine explain: critical error when trying to marshal the response: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
+
+// explainError is the body written by the explain endpoint when the rule sets cannot be explained
+type explainError struct {
+	Error string `json:"error"`
+}
+
+func writeExplainError(w http.ResponseWriter, status int, msg string) {
+	jsonOutput, err := jsonutil.Marshal(explainError{Error: msg})
+	if err != nil {
+		glog.Errorf("rules engine explain: critical error when trying to marshal the error: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonOutput)
+}
+
+// explainCacheEntry returns the cache entry holding the account trees if it was built from the current account
+// configuration. Otherwise the trees are built into a throw-away entry that is not stored so explaining the rule
+// sets never changes the cache or the sources the tree manager watches. The rule sets of an account configuration
+// setting a source are loaded from the source, as the hook runs them once the source is loaded.
+func (m Module) explainCacheEntry(ctx context.Context, deps moduledeps.AdminDeps, accountID string) (*cacheEntry, error) {
+	acct, errs := account.GetAccount(ctx, deps.Config, deps.AccountFetcher, accountID, deps.MetricsEngine)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to get account %s: %s", accountID, errors.Join(errs...))
+	}
+
+	accountConfig, err := acct.Hooks.Modules.ModuleConfig(moduleName)
+	if err != nil {
+		return nil, err
+	}
+	if len(accountConfig) == 0 {
+		return nil, fmt.Errorf("rules engine is not configured for account %s", accountID)
+	}
+
+	if co := m.Cache.Get(accountID); co != nil && !configChanged(co.hashedConfig, &accountConfig) {
+		if !co.enabled {
+			return nil, fmt.Errorf("rules engine is disabled for account %s", accountID)
+		}
+		return co, nil
+	}
+
+	parsedCfg, err := config.NewConfig(accountConfig, m.TreeManager.schemaValidator, m.TreeManager.sourceAllowlist)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules engine configuration for account %s: %s", accountID, err)
+	}
+	if !parsedCfg.Enabled {
+		return nil, fmt.Errorf("rules engine is disabled for account %s", accountID)
+	}
+
+	if parsedCfg.Source != nil {
+		co, err := m.TreeManager.buildFromSourceOnce(accountID, parsedCfg, accountConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules engine configuration for account %s: %s", accountID, err)
+		}
+		return co, nil
+	}
+
+	co, err := NewCacheEntry(parsedCfg, &accountConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules engine configuration for account %s: %s", accountID, err)
+	}
+	return &co, nil
+}
+
+// explain runs the processed auction request rule sets of the cache entry against the request through the
+// same path as the processed auction hook, recording the path taken through each tree and applying the
+// resulting mutations to find out the bidders allowed and excluded for each impression
+func explain(co *cacheEntry, request *openrtb_ext.RequestWrapper) explainResponse {
+	resp := explainResponse{
+		RuleSets: make([]ruleSetExplanation, 0),
+		Bidders:  make([]impBiddersExplanation, 0),
+	}
+	biddersBefore := impBidders(request)
+
+	selector := newModelGroupSelector(stickyKey(co.stickyBy, request))
+	selector.tracer = func(ruleSet, scope, impID string, trace rules.Trace, err error) {
+		resp.RuleSets = append(resp.RuleSets, newRuleSetExplanation(ruleSet, scope, impID, trace, err))
+	}
+
+	payload := hs.ProcessedAuctionRequestPayload{Request: request}
+	result, err := runProcessedAuctionRuleSets(co, payload, selector)
+	if err != nil {
+		resp.Errors = append(resp.Errors, err.Error())
+	}
+	resp.Errors = append(resp.Errors, result.Errors...)
+
+	for _, mut := range result.ChangeSet.Mutations() {
+		if _, err := mut.Apply(payload); err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("failed to apply mutation: %s", err))
+		}
+	}
+
+	biddersAfter := impBidders(request)
+	for _, imp := range request.GetImp() {
+		allowed := biddersAfter[imp.ID]
+		excluded := make([]string, 0)
+		for _, bidder := range biddersBefore[imp.ID] {
+			if !slices.Contains(allowed, bidder) {
+				excluded = append(excluded, bidder)
+			}
+		}
+		resp.Bidders = append(resp.Bidders, impBiddersExplanation{ImpID: imp.ID, Allowed: allowed, Excluded: excluded})
+	}
+
+	return resp
+}
+
+func newRuleSetExplanation(name, scope, impID string, trace rules.Trace, err error) ruleSetExplanation {
+	e := ruleSetExplanation{
+		Name:            name,
+		Scope:           scope,
+		ImpID:           impID,
+		ModelVersion:    trace.ModelVersion,
+		AnalyticsKey:    trace.AnalyticsKey,
+		Path:            make([]nodeExplanation, 0, len(trace.SchemaFunctionResults)),
+		RuleFired:       trace.RuleFired,
+		Default:         trace.Default,
+		ResultFunctions: trace.ResultFunctions,
+	}
+	for i, step := range trace.SchemaFunctionResults {
+		node := nodeExplanation{Function: step.FuncName, Value: step.FuncResult}
+		if i < len(trace.MatchedConditions) {
+			node.Condition = trace.MatchedConditions[i]
+		}
+		e.Path = append(e.Path, node)
+	}
+	if e.ResultFunctions == nil {
+		e.ResultFunctions = make([]string, 0)
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// impBidders returns the sorted names of the bidders found in the ext.prebid.bidder object of each impression
+func impBidders(request *openrtb_ext.RequestWrapper) map[string][]string {
+	bidders := make(map[string][]string)
+	for _, imp := range request.GetImp() {
+		names := make([]string, 0)
+		if impExt, err := imp.GetImpExt(); err == nil && impExt.GetPrebid() != nil {
+			for bidder := range impExt.GetPrebid().Bidder {
+				names = append(names, bidder)
+			}
+		}
+		sort.Strings(names)
+		bidders[imp.ID] = names
+	}
+	return bidders
+}
@@ -0,0 +1,270 @@
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	rulesconfig "github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const explainRulesEngineConfig = `{
+  "enabled": true,
+  "rulesets": [
+    {
+      "stage": "processed_auction_request",
+      "name": "exclude-in-north-america",
+      "modelgroups": [
+        {
+          "analyticsKey": "na-experiment",
+          "version": "1.0",
+          "schema": [{"function": "deviceCountry"}],
+          "rules": [
+            {
+              "conditions": ["in:USA,CAN"],
+              "results": [{"function": "excludeBidders", "args": {"bidders": ["bidderA"]}}]
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "stage": "processed_auction_request",
+      "name": "exclude-from-video",
+      "scope": "imp",
+      "modelgroups": [
+        {
+          "schema": [{"function": "impMediaType"}],
+          "rules": [
+            {
+              "conditions": ["video"],
+              "results": [{"function": "excludeBidders", "args": {"bidders": ["bidderB"]}}]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+type explainAccountFetcher struct {
+	accounts map[string]json.RawMessage
+}
+
+func (f *explainAccountFetcher) FetchAccount(_ context.Context, _ json.RawMessage, accountID string) (json.RawMessage, []error) {
+	return f.accounts[accountID], nil
+}
+
+// discardLogger keeps the tree manager from writing to glog whose line counts other tests assert on
+type discardLogger struct{}
+
+func (logger *discardLogger) logError(msg string) {}
+
+func (logger *discardLogger) logInfo(msg string) {}
+
+func newExplainModule(t *testing.T) Module {
+	validator, err := rulesconfig.CreateSchemaValidator("config/" + rulesconfig.RulesEngineSchemaFile)
+	require.NoError(t, err)
+
+	return Module{
+		Cache: NewCache(0),
+		TreeManager: &treeManager{
+			done:            make(chan struct{}),
+			requests:        make(chan buildInstruction),
+			schemaValidator: validator,
+			monitor:         &discardLogger{},
+		},
+	}
+}
+
+func TestExplainHandler(t *testing.T) {
+	fetcher := &explainAccountFetcher{
+		accounts: map[string]json.RawMessage{
+			"configured":   json.RawMessage(`{"hooks":{"modules":{"prebid":{"rulesengine":` + explainRulesEngineConfig + `}}}}`),
+			"disabled":     json.RawMessage(`{"hooks":{"modules":{"prebid":{"rulesengine":` + strings.Replace(explainRulesEngineConfig, `"enabled": true`, `"enabled": false`, 1) + `}}}}`),
+			"invalid":      json.RawMessage(`{"hooks":{"modules":{"prebid":{"rulesengine":{"enabled":true,"rulesets":[{"stage":"unknown"}]}}}}}`),
+			"unconfigured": json.RawMessage(`{}`),
+		},
+	}
+	deps := moduledeps.AdminDeps{Config: &config.Configuration{}, AccountFetcher: fetcher}
+
+	bidRequest := `{
+	  "id": "request-id",
+	  "device": {"geo": {"country": "USA"}},
+	  "imp": [
+	    {"id": "video-imp", "video": {}, "ext": {"prebid": {"bidder": {"bidderA": {}, "bidderB": {}, "bidderC": {}}}}},
+	    {"id": "banner-imp", "banner": {}, "ext": {"prebid": {"bidder": {"bidderA": {}, "bidderC": {}}}}}
+	  ]
+	}`
+
+	testCases := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "method_not_allowed",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "malformed_body",
+			method:         http.MethodPost,
+			body:           `{"account":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing_bid_request",
+			method:         http.MethodPost,
+			body:           `{"account":"configured"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"account and bidrequest are required"}`,
+		},
+		{
+			name:           "rules_engine_not_configured",
+			method:         http.MethodPost,
+			body:           `{"account":"unconfigured","bidrequest":` + bidRequest + `}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"rules engine is not configured for account unconfigured"}`,
+		},
+		{
+			name:           "rules_engine_disabled",
+			method:         http.MethodPost,
+			body:           `{"account":"disabled","bidrequest":` + bidRequest + `}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"rules engine is disabled for account disabled"}`,
+		},
+		{
+			name:           "invalid_rules_engine_config",
+			method:         http.MethodPost,
+			body:           `{"account":"invalid","bidrequest":` + bidRequest + `}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "explained",
+			method:         http.MethodPost,
+			body:           `{"account":"configured","bidrequest":` + bidRequest + `}`,
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+			  "account": "configured",
+			  "rulesets": [
+			    {
+			      "name": "exclude-in-north-america",
+			      "scope": "request",
+			      "modelversion": "1.0",
+			      "analyticskey": "na-experiment",
+			      "path": [{"function": "deviceCountry", "value": "USA", "condition": "in:USA,CAN"}],
+			      "rulefired": "in:USA,CAN",
+			      "default": false,
+			      "resultfunctions": ["excludeBidders"]
+			    },
+			    {
+			      "name": "exclude-from-video",
+			      "scope": "imp",
+			      "impid": "video-imp",
+			      "path": [{"function": "impMediaType", "value": "video", "condition": "video"}],
+			      "rulefired": "video",
+			      "default": false,
+			      "resultfunctions": ["excludeBidders"]
+			    },
+			    {
+			      "name": "exclude-from-video",
+			      "scope": "imp",
+			      "impid": "banner-imp",
+			      "path": [{"function": "impMediaType", "value": "banner"}],
+			      "rulefired": "default",
+			      "default": true,
+			      "resultfunctions": []
+			    }
+			  ],
+			  "bidders": [
+			    {"impid": "video-imp", "allowed": ["bidderC"], "excluded": ["bidderA", "bidderB"]},
+			    {"impid": "banner-imp", "allowed": ["bidderC"], "excluded": ["bidderA"]}
+			  ]
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := newExplainModule(t).AdminEndpoints(deps)[explainEndpoint]
+			require.NotNil(t, handler)
+
+			recorder := httptest.NewRecorder()
+			handler(recorder, httptest.NewRequest(tc.method, "/modules/prebid/rulesengine/explain", strings.NewReader(tc.body)))
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, tc.expectedBody, recorder.Body.String())
+			} else if len(tc.expectedBody) > 0 {
+				assert.JSONEq(t, tc.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestExplainCacheEntryIsReadOnly(t *testing.T) {
+	fetcher := &explainAccountFetcher{
+		accounts: map[string]json.RawMessage{
+			"configured": json.RawMessage(`{"hooks":{"modules":{"prebid":{"rulesengine":` + explainRulesEngineConfig + `}}}}`),
+		},
+	}
+	deps := moduledeps.AdminDeps{Config: &config.Configuration{}, AccountFetcher: fetcher}
+	m := newExplainModule(t)
+
+	built, err := m.explainCacheEntry(context.Background(), deps, "configured")
+	require.NoError(t, err)
+	require.NotNil(t, built)
+	assert.Nil(t, m.Cache.Get("configured"), "trees built to explain the rule sets are not stored")
+
+	cfg := json.RawMessage(explainRulesEngineConfig)
+	stored := &cacheEntry{enabled: true, hashedConfig: hashConfig(&cfg)}
+	m.Cache.Set("configured", stored)
+
+	cached, err := m.explainCacheEntry(context.Background(), deps, "configured")
+	require.NoError(t, err)
+	assert.Same(t, stored, cached, "trees cached for the current account configuration are explained")
+
+	m.Cache.Set("configured", &cacheEntry{enabled: true, hashedConfig: "stale"})
+
+	rebuilt, err := m.explainCacheEntry(context.Background(), deps, "configured")
+	require.NoError(t, err)
+	assert.NotSame(t, m.Cache.Get("configured"), rebuilt, "stale trees are neither explained nor replaced")
+	assert.Equal(t, hash("stale"), m.Cache.Get("configured").hashedConfig)
+}
+
+func TestExplainCacheEntryFromSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	writeSourceFile(t, path, sourceRuleSets)
+
+	fetcher := &explainAccountFetcher{
+		accounts: map[string]json.RawMessage{
+			"source":         json.RawMessage(`{"hooks":{"modules":{"prebid":{"rulesengine":{"enabled":true,"source":{"path":"` + path + `"}}}}}}`),
+			"missing-source": json.RawMessage(`{"hooks":{"modules":{"prebid":{"rulesengine":{"enabled":true,"source":{"path":"` + filepath.Join(dir, "missing.json") + `"}}}}}}`),
+		},
+	}
+	deps := moduledeps.AdminDeps{Config: &config.Configuration{}, AccountFetcher: fetcher}
+	m := newExplainModule(t)
+	m.TreeManager.sourceAllowlist = rulesconfig.SourceAllowlist{Paths: []string{dir}}
+
+	built, err := m.explainCacheEntry(context.Background(), deps, "source")
+	require.NoError(t, err)
+	require.NotNil(t, built)
+	assert.Len(t, built.ruleSetsForProcessedAuctionRequestStage, 1, "the rule sets of the source are explained")
+	assert.Nil(t, m.Cache.Get("source"), "trees built to explain the rule sets are not stored")
+	assert.Empty(t, m.TreeManager.sources.m, "the source is not watched")
+
+	_, err = m.explainCacheEntry(context.Background(), deps, "missing-source")
+	assert.ErrorContains(t, err, "invalid rules engine configuration for account missing-source: failed to load rule sets from source")
+}
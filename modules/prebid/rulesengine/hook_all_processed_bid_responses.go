@@ -0,0 +1,88 @@
+package rulesengine
+
+import (
+	"sort"
+
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+)
+
+func handleAllProcessedBidResponsesHook(
+	ruleSets []cacheRuleSet[rules.BidWrapper, BidHookResult],
+	payload hs.AllProcessedBidResponsesPayload,
+	selector *modelGroupSelector) (hs.HookResult[hs.AllProcessedBidResponsesPayload], error) {
+
+	result := hs.HookResult[hs.AllProcessedBidResponsesPayload]{}
+
+	// walk bidders in a deterministic order so analytics results are stable across requests
+	bidderNames := make([]openrtb_ext.BidderName, 0, len(payload.Responses))
+	for bidderName := range payload.Responses {
+		bidderNames = append(bidderNames, bidderName)
+	}
+	sort.Slice(bidderNames, func(i, j int) bool { return bidderNames[i] < bidderNames[j] })
+
+	var analyticsResults []hookanalytics.Result
+	var rejected []hs.RejectedProcessedBid
+	for _, bidderName := range bidderNames {
+		seatBid := payload.Responses[bidderName]
+		if seatBid == nil || len(seatBid.Bids) == 0 {
+			continue
+		}
+
+		seat := bidderName.String()
+		if len(seatBid.Seat) > 0 {
+			seat = seatBid.Seat
+		}
+
+		repriced := false
+		bids := make([]*entities.PbsOrtbBid, 0, len(seatBid.Bids))
+
+		for _, pbsBid := range seatBid.Bids {
+			if pbsBid == nil || pbsBid.Bid == nil {
+				bids = append(bids, pbsBid)
+				continue
+			}
+
+			bidWrapper := rules.BidWrapper{
+				Seat:    seat,
+				Bid:     pbsBid.Bid,
+				BidType: pbsBid.BidType,
+			}
+
+			bidResult, errs := runBidRuleSets(ruleSets, &bidWrapper, selector)
+			result.Errors = append(result.Errors, errs...)
+			analyticsResults = append(analyticsResults, bidResult.AnalyticsResults...)
+
+			if !bidResult.modified() {
+				bids = append(bids, pbsBid)
+				continue
+			}
+			if bidResult.Rejected {
+				// rejected bids are kept in the updated bids so the rejection mutation finds them
+				bids = append(bids, pbsBid)
+				rejected = append(rejected, hs.RejectedProcessedBid{Bidder: bidderName, Bid: pbsBid, NonBidReason: bidResult.NonBidReason})
+				continue
+			}
+			repriced = true
+
+			adjustedBid := *pbsBid.Bid
+			adjustedBid.Price *= bidResult.PriceFactor
+			adjustedPbsBid := *pbsBid
+			adjustedPbsBid.Bid = &adjustedBid
+			bids = append(bids, &adjustedPbsBid)
+		}
+
+		if repriced {
+			result.ChangeSet.AllProcessedBidResponses().Bids().UpdateBids(bidderName, bids)
+		}
+	}
+	if len(rejected) > 0 {
+		result.ChangeSet.AllProcessedBidResponses().Bids().RejectBids(rejected)
+	}
+	result.AnalyticsTags = newBidResponsesAnalytics(analyticsResults)
+
+	return result, nil
+}
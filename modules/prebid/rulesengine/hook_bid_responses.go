@@ -0,0 +1,83 @@
+package rulesengine
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/rules"
+)
+
+const bidResponsesActivityName = "rules-engine-bid-responses"
+
+// BidHookResult holds the decisions the bid response result functions took for a single bid
+type BidHookResult struct {
+	Rejected         bool
+	NonBidReason     int
+	PriceFactor      float64
+	AnalyticsResults []hookanalytics.Result
+}
+
+func newBidHookResult() BidHookResult {
+	return BidHookResult{PriceFactor: 1}
+}
+
+func (r *BidHookResult) appendAnalyticsResult(status hookanalytics.ResultStatus, values map[string]interface{}, bid *rules.BidWrapper) {
+	result := hookanalytics.Result{
+		Status: status,
+		Values: values,
+	}
+	if bid != nil {
+		result.AppliedTo.Bidder = bid.Seat
+		if bid.Bid != nil {
+			result.AppliedTo.BidIds = []string{bid.Bid.ID}
+			result.AppliedTo.ImpIds = []string{bid.Bid.ImpID}
+		}
+	}
+	r.AnalyticsResults = append(r.AnalyticsResults, result)
+}
+
+// modified returns true if the result functions require the bid to be removed or updated
+func (r *BidHookResult) modified() bool {
+	return r.Rejected || r.PriceFactor != 1
+}
+
+// runBidRuleSets runs the tree of a model group selected for every rule set against the given bid
+// returning the combined decisions along with any errors encountered
+func runBidRuleSets(ruleSets []cacheRuleSet[rules.BidWrapper, BidHookResult], bid *rules.BidWrapper, selector *modelGroupSelector) (BidHookResult, []string) {
+	var errs []string
+	result := newBidHookResult()
+
+	for _, ruleSet := range ruleSets {
+		selectedGroup, err := selectRuleSetModelGroup(selector, ruleSet)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to select model group: %s", err))
+			continue
+		}
+
+		if err = selectedGroup.tree.Run(bid, &result); err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		if result.Rejected {
+			break
+		}
+	}
+
+	return result, errs
+}
+
+// newBidResponsesAnalytics wraps the analytics results collected across bids in a single activity
+func newBidResponsesAnalytics(results []hookanalytics.Result) hookanalytics.Analytics {
+	if len(results) == 0 {
+		return hookanalytics.Analytics{}
+	}
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:    bidResponsesActivityName,
+				Status:  hookanalytics.ActivityStatusSuccess,
+				Results: results,
+			},
+		},
+	}
+}
@@ -0,0 +1,220 @@
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/hooks"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mediaTypeRuleSets rejects video bids, halves the price of banner bids and leaves any other bid untouched
+func mediaTypeRuleSets(t *testing.T) []cacheRuleSet[rules.BidWrapper, BidHookResult] {
+	crs, err := createBidResponseCacheRuleSet(&config.RuleSet{
+		Stage: hooks.StageRawBidderResponse,
+		Name:  "media-type",
+		ModelGroups: []config.ModelGroup{
+			{
+				Schema: []config.Schema{{Func: rules.MediaType}},
+				Rules: []config.Rule{
+					{
+						Conditions: []string{"video"},
+						Results:    []config.Result{{Func: RejectBidsName, Args: json.RawMessage(`{"seatnonbid":301}`)}},
+					},
+					{
+						Conditions: []string{"banner"},
+						Results:    []config.Result{{Func: AdjustBidPriceName, Args: json.RawMessage(`{"factor":0.5}`)}},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return []cacheRuleSet[rules.BidWrapper, BidHookResult]{crs}
+}
+
+func TestRunBidRuleSets(t *testing.T) {
+	tests := []struct {
+		name             string
+		ruleSets         []cacheRuleSet[rules.BidWrapper, BidHookResult]
+		bid              rules.BidWrapper
+		expectedRejected bool
+		expectedFactor   float64
+		expectedErrs     []string
+	}{
+		{
+			name:           "no-rule-sets",
+			bid:            rules.BidWrapper{Bid: &openrtb2.Bid{}, BidType: openrtb_ext.BidTypeVideo},
+			expectedFactor: 1,
+		},
+		{
+			name:             "video-bid-rejected",
+			ruleSets:         mediaTypeRuleSets(t),
+			bid:              rules.BidWrapper{Bid: &openrtb2.Bid{}, BidType: openrtb_ext.BidTypeVideo},
+			expectedRejected: true,
+			expectedFactor:   1,
+		},
+		{
+			name:           "banner-bid-adjusted",
+			ruleSets:       mediaTypeRuleSets(t),
+			bid:            rules.BidWrapper{Bid: &openrtb2.Bid{}, BidType: openrtb_ext.BidTypeBanner},
+			expectedFactor: 0.5,
+		},
+		{
+			name: "no-model-groups",
+			ruleSets: []cacheRuleSet[rules.BidWrapper, BidHookResult]{
+				{modelGroups: []cacheModelGroup[rules.BidWrapper, BidHookResult]{}},
+			},
+			bid:            rules.BidWrapper{Bid: &openrtb2.Bid{}},
+			expectedFactor: 1,
+			expectedErrs:   []string{"failed to select model group: no model groups available"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, errs := runBidRuleSets(tt.ruleSets, &tt.bid, newModelGroupSelector(""))
+			assert.Equal(t, tt.expectedRejected, result.Rejected)
+			assert.Equal(t, tt.expectedFactor, result.PriceFactor)
+			assert.Equal(t, tt.expectedErrs, errs)
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	videoBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "video", Price: 1}, BidType: openrtb_ext.BidTypeVideo}
+	bannerBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "banner", Price: 4}, BidType: openrtb_ext.BidTypeBanner}
+	nativeBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "native", Price: 3}, BidType: openrtb_ext.BidTypeNative}
+
+	tests := []struct {
+		name             string
+		ruleSets         []cacheRuleSet[rules.BidWrapper, BidHookResult]
+		bids             []*adapters.TypedBid
+		expectedBids     []*adapters.TypedBid
+		expectedRejected []hs.RejectedBid
+		expectedMuts     int
+		expectedAudit    int
+	}{
+		{
+			name:         "nil-bidder-response-bids",
+			ruleSets:     mediaTypeRuleSets(t),
+			expectedMuts: 0,
+		},
+		{
+			name:         "no-bid-matches-rules",
+			ruleSets:     mediaTypeRuleSets(t),
+			bids:         []*adapters.TypedBid{nativeBid},
+			expectedBids: []*adapters.TypedBid{nativeBid},
+			expectedMuts: 0,
+		},
+		{
+			name:     "video-rejected-banner-adjusted-native-kept",
+			ruleSets: mediaTypeRuleSets(t),
+			bids:     []*adapters.TypedBid{videoBid, bannerBid, nativeBid},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "banner", Price: 2}, BidType: openrtb_ext.BidTypeBanner},
+				nativeBid,
+			},
+			expectedRejected: []hs.RejectedBid{{Bid: videoBid, NonBidReason: 301}},
+			expectedMuts:     2,
+			expectedAudit:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := hs.RawBidderResponsePayload{
+				Bidder:         "bidder1",
+				BidderResponse: &adapters.BidderResponse{Bids: tt.bids},
+			}
+
+			result, err := handleRawBidderResponseHook(tt.ruleSets, payload, newModelGroupSelector(""))
+			require.NoError(t, err)
+			require.Len(t, result.ChangeSet.Mutations(), tt.expectedMuts)
+			if tt.expectedAudit > 0 {
+				require.Len(t, result.AnalyticsTags.Activities, 1)
+				assert.Len(t, result.AnalyticsTags.Activities[0].Results, tt.expectedAudit)
+			} else {
+				assert.Empty(t, result.AnalyticsTags.Activities)
+			}
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			if tt.expectedBids != nil {
+				assert.Equal(t, tt.expectedBids, payload.BidderResponse.Bids)
+			}
+			assert.Equal(t, tt.expectedRejected, payload.RejectedBids)
+			// original bids must not be altered
+			assert.Equal(t, 4.0, bannerBid.Bid.Price)
+		})
+	}
+}
+
+func TestHandleAllProcessedBidResponsesHook(t *testing.T) {
+	videoBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "video", Price: 1}, BidType: openrtb_ext.BidTypeVideo}
+	bannerBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "banner", Price: 4}, BidType: openrtb_ext.BidTypeBanner}
+	nativeBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "native", Price: 3}, BidType: openrtb_ext.BidTypeNative}
+
+	payload := hs.AllProcessedBidResponsesPayload{
+		Responses: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"bidder1": {Bids: []*entities.PbsOrtbBid{videoBid, bannerBid}},
+			"bidder2": {Bids: []*entities.PbsOrtbBid{nativeBid}},
+			"bidder3": nil,
+		},
+	}
+
+	result, err := handleAllProcessedBidResponsesHook(mediaTypeRuleSets(t), payload, newModelGroupSelector(""))
+	require.NoError(t, err)
+	require.Len(t, result.ChangeSet.Mutations(), 2)
+	require.Len(t, result.AnalyticsTags.Activities, 1)
+	assert.Len(t, result.AnalyticsTags.Activities[0].Results, 2)
+
+	for _, mut := range result.ChangeSet.Mutations() {
+		payload, err = mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []hs.RejectedProcessedBid{{Bidder: "bidder1", Bid: videoBid, NonBidReason: 301}}, payload.RejectedBids)
+
+	expectedBidder1Bids := []*entities.PbsOrtbBid{
+		{Bid: &openrtb2.Bid{ID: "banner", Price: 2}, BidType: openrtb_ext.BidTypeBanner},
+	}
+	assert.Equal(t, expectedBidder1Bids, payload.Responses["bidder1"].Bids)
+	assert.Equal(t, []*entities.PbsOrtbBid{nativeBid}, payload.Responses["bidder2"].Bids)
+	assert.Equal(t, 4.0, bannerBid.Bid.Price)
+}
+
+func TestBidResponseHooksModelGroupKey(t *testing.T) {
+	accountConfig := json.RawMessage(`{"enabled": true}`)
+	cache := NewCache(0)
+	cache.Set("account-id", &cacheEntry{
+		enabled:                                  true,
+		hashedConfig:                             hashConfig(&accountConfig),
+		ruleSetsForRawBidderResponseStage:        mediaTypeRuleSets(t),
+		ruleSetsForAllProcessedBidResponsesStage: mediaTypeRuleSets(t),
+	})
+	m := Module{Cache: cache, TreeManager: &treeManager{}, Metrics: &moduleMetrics{}}
+
+	withoutKey := hs.ModuleInvocationContext{AccountID: "account-id", AccountConfig: accountConfig}
+	_, err := m.HandleRawBidderResponseHook(context.Background(), withoutKey, hs.RawBidderResponsePayload{})
+	assert.EqualError(t, err, "model group key not found, the processed auction request hook of the module must run before its bid response hooks")
+	_, err = m.HandleAllProcessedBidResponsesHook(context.Background(), withoutKey, hs.AllProcessedBidResponsesPayload{})
+	assert.EqualError(t, err, "model group key not found, the processed auction request hook of the module must run before its bid response hooks")
+
+	withKey := withoutKey
+	withKey.ModuleContext = hs.ModuleContext{modelGroupKeyContextKey: "key"}
+	_, err = m.HandleRawBidderResponseHook(context.Background(), withKey, hs.RawBidderResponsePayload{})
+	assert.NoError(t, err)
+	_, err = m.HandleAllProcessedBidResponsesHook(context.Background(), withKey, hs.AllProcessedBidResponsesPayload{})
+	assert.NoError(t, err)
+}
@@ -3,7 +3,9 @@ package rulesengine
 import (
 	"fmt"
 
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/randomutil"
 )
@@ -11,14 +13,25 @@ import (
 type RequestWrapper = openrtb_ext.RequestWrapper
 type ModelGroup = cacheModelGroup[RequestWrapper, ProcessedAuctionHookResult]
 
+const processedAuctionActivityName = "rules-engine-processed-auction-request"
+
 type ProcessedAuctionHookResult struct {
-	HookResult     hs.HookResult[hs.ProcessedAuctionRequestPayload]
-	AllowedBidders map[string]struct{}
+	HookResult       hs.HookResult[hs.ProcessedAuctionRequestPayload]
+	AllowedBidders   map[string]struct{}
+	AnalyticsResults []hookanalytics.Result
+}
+
+func (r *ProcessedAuctionHookResult) appendAnalyticsResult(status hookanalytics.ResultStatus, values map[string]interface{}) {
+	r.AnalyticsResults = append(r.AnalyticsResults, hookanalytics.Result{
+		Status: status,
+		Values: values,
+	})
 }
 
 func handleProcessedAuctionHook(
 	ruleSets []cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult],
-	payload hs.ProcessedAuctionRequestPayload) (hs.HookResult[hs.ProcessedAuctionRequestPayload], error) {
+	payload hs.ProcessedAuctionRequestPayload,
+	selector *modelGroupSelector) (hs.HookResult[hs.ProcessedAuctionRequestPayload], error) {
 
 	result := ProcessedAuctionHookResult{
 		HookResult: hs.HookResult[hs.ProcessedAuctionRequestPayload]{
@@ -28,13 +41,13 @@ func handleProcessedAuctionHook(
 	}
 
 	for _, ruleSet := range ruleSets {
-		selectedGroup, err := selectModelGroup(ruleSet.modelGroups, randomutil.RandomNumberGenerator{})
+		selectedGroup, err := selectRuleSetModelGroup(selector, ruleSet)
 		if err != nil {
 			result.HookResult.Errors = append(result.HookResult.Errors, fmt.Sprintf("failed to select model group: %s", err))
 			continue
 		}
 
-		if err = selectedGroup.tree.Run(payload.Request, &result); err != nil {
+		if err = runModelGroupTree(selector, ruleSet.name, config.ScopeRequest, "", selectedGroup, payload.Request, &result); err != nil {
 			//TODO: classify errors as warnings or errors
 			result.HookResult.Errors = append(result.HookResult.Errors, err.Error())
 		}
@@ -44,12 +57,20 @@ func handleProcessedAuctionHook(
 		}
 	}
 
+	if len(result.AnalyticsResults) > 0 {
+		result.HookResult.AnalyticsTags.Activities = append(result.HookResult.AnalyticsTags.Activities, hookanalytics.Activity{
+			Name:    processedAuctionActivityName,
+			Status:  hookanalytics.ActivityStatusSuccess,
+			Results: result.AnalyticsResults,
+		})
+	}
+
 	return result.HookResult, nil
 }
 
-func selectModelGroup(modelGroups []ModelGroup, rg randomutil.RandomGenerator) (ModelGroup, error) {
+func selectModelGroup[T1 any, T2 any](modelGroups []cacheModelGroup[T1, T2], rg randomutil.RandomGenerator) (cacheModelGroup[T1, T2], error) {
 	if len(modelGroups) == 0 {
-		return ModelGroup{}, fmt.Errorf("no model groups available")
+		return cacheModelGroup[T1, T2]{}, fmt.Errorf("no model groups available")
 	}
 
 	if len(modelGroups) == 1 {
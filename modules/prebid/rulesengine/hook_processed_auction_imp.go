@@ -0,0 +1,109 @@
+package rulesengine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/rules"
+)
+
+const processedAuctionImpActivityName = "rules-engine-processed-auction-imp"
+
+// ImpHookResult holds the bidders the imp scoped result functions allowed or excluded for a single impression
+// along with the floor to apply to it and the analytics results collected
+type ImpHookResult struct {
+	AllowedBidders   map[string]struct{}
+	ExcludedBidders  map[string]struct{}
+	BidFloor         *impBidFloor
+	AnalyticsResults []hookanalytics.Result
+}
+
+// impBidFloor holds the floor the imp scoped result functions set or raise the impression floor to
+type impBidFloor struct {
+	args  config.BidFloorParams
+	raise bool
+}
+
+func (r *ImpHookResult) appendAnalyticsResult(status hookanalytics.ResultStatus, values map[string]interface{}, imp *rules.ImpWrapper) {
+	result := hookanalytics.Result{
+		Status: status,
+		Values: values,
+	}
+	if imp != nil && imp.Imp != nil {
+		result.AppliedTo.ImpIds = []string{imp.Imp.ID}
+	}
+	r.AnalyticsResults = append(r.AnalyticsResults, result)
+}
+
+// handleProcessedAuctionImpHook runs the imp scoped rule sets once per impression adding to the hook result
+// the mutations that restrict the bidders of each impression
+func handleProcessedAuctionImpHook(
+	ruleSets []cacheRuleSet[rules.ImpWrapper, ImpHookResult],
+	payload hs.ProcessedAuctionRequestPayload,
+	result hs.HookResult[hs.ProcessedAuctionRequestPayload],
+	selector *modelGroupSelector) (hs.HookResult[hs.ProcessedAuctionRequestPayload], error) {
+
+	if len(ruleSets) == 0 || payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, nil
+	}
+
+	var analyticsResults []hookanalytics.Result
+	for _, imp := range payload.Request.GetImp() {
+		impResult := ImpHookResult{
+			AllowedBidders:  make(map[string]struct{}),
+			ExcludedBidders: make(map[string]struct{}),
+		}
+		impPayload := rules.ImpWrapper{Request: payload.Request, Imp: imp}
+
+		for _, ruleSet := range ruleSets {
+			selectedGroup, err := selectRuleSetModelGroup(selector, ruleSet)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to select model group: %s", err))
+				continue
+			}
+
+			if err = runModelGroupTree(selector, ruleSet.name, config.ScopeImp, imp.ID, selectedGroup, &impPayload, &impResult); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("imp %s: %s", imp.ID, err))
+			}
+		}
+
+		if len(impResult.AllowedBidders) > 0 {
+			result.ChangeSet.ProcessedAuctionRequest().Bidders().AddForImp(imp.ID, impResult.AllowedBidders)
+		}
+		if len(impResult.ExcludedBidders) > 0 {
+			result.ChangeSet.ProcessedAuctionRequest().Bidders().DeleteForImp(imp.ID, impResult.ExcludedBidders)
+		}
+		if impResult.BidFloor != nil {
+			addImpBidFloorMutation(&result.ChangeSet, imp.ID, *impResult.BidFloor)
+		}
+		analyticsResults = append(analyticsResults, impResult.AnalyticsResults...)
+	}
+
+	if len(analyticsResults) > 0 {
+		result.AnalyticsTags.Activities = append(result.AnalyticsTags.Activities, hookanalytics.Activity{
+			Name:    processedAuctionImpActivityName,
+			Status:  hookanalytics.ActivityStatusSuccess,
+			Results: analyticsResults,
+		})
+	}
+
+	return result, nil
+}
+
+// addImpBidFloorMutation adds a mutation that sets or raises the floor of the imp with the given ID
+func addImpBidFloorMutation(changeSet *hs.ChangeSet[hs.ProcessedAuctionRequestPayload], impID string, floor impBidFloor) {
+	changeSet.AddMutation(func(payload hs.ProcessedAuctionRequestPayload) (hs.ProcessedAuctionRequestPayload, error) {
+		if payload.Request == nil || payload.Request.BidRequest == nil {
+			return payload, errors.New("payload contains a nil bid request")
+		}
+		for _, imp := range payload.Request.GetImp() {
+			if imp.ID == impID {
+				applyBidFloor(imp, floor.args, floor.raise)
+			}
+		}
+		return payload, nil
+	}, hs.MutationUpdate, "bidrequest", "imp", impID, "bidfloor")
+}
@@ -0,0 +1,230 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProcessedAuctionImpResultFunction(t *testing.T) {
+	tests := []struct {
+		name       string
+		funcName   string
+		params     json.RawMessage
+		expectType ProcessedAuctionImpResultFunc
+		expectErr  bool
+	}{
+		{
+			name:       "valid_excludeBidders",
+			funcName:   ExcludeBiddersName,
+			params:     json.RawMessage(`{"bidders":["bidder1"]}`),
+			expectType: &ImpExcludeBidders{},
+		},
+		{
+			name:       "valid_includeBidders",
+			funcName:   IncludeBiddersName,
+			params:     json.RawMessage(`{"bidders":["bidder1"]}`),
+			expectType: &ImpIncludeBidders{},
+		},
+		{
+			name:      "excludeBidders_empty_bidders",
+			funcName:  ExcludeBiddersName,
+			params:    json.RawMessage(`{"bidders":[]}`),
+			expectErr: true,
+		},
+		{
+			name:      "includeBidders_invalid_json",
+			funcName:  IncludeBiddersName,
+			params:    json.RawMessage(`invalid-json`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_setBidFloor",
+			funcName:   SetBidFloorName,
+			params:     json.RawMessage(`{"bidfloor":1}`),
+			expectType: &ImpSetBidFloor{},
+		},
+		{
+			name:       "valid_raiseBidFloor",
+			funcName:   RaiseBidFloorName,
+			params:     json.RawMessage(`{"bidfloor":1}`),
+			expectType: &ImpRaiseBidFloor{},
+		},
+		{
+			name:      "raiseBidFloor_missing_floor",
+			funcName:  RaiseBidFloorName,
+			params:    json.RawMessage(`{}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_logATag",
+			funcName:   LogATagName,
+			params:     json.RawMessage(`{"analyticsvalue":"tagged"}`),
+			expectType: &ImpLogATag{},
+		},
+		{
+			name:      "invalid_function_name",
+			funcName:  RejectBidsName,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewProcessedAuctionImpResultFunction(tt.funcName, tt.params)
+			if tt.expectErr {
+				assert.Error(t, err, "expected error but got nil")
+			} else {
+				assert.NoError(t, err)
+				assert.IsType(t, tt.expectType, v)
+				assert.Equal(t, tt.funcName, v.Name())
+			}
+		})
+	}
+}
+
+func TestHandleProcessedAuctionImpHook(t *testing.T) {
+	// video imps exclude bidderA, banner imps only allow bidderB
+	impRuleSet, err := createImpCacheRuleSet(&config.RuleSet{
+		Stage: hooks.StageProcessedAuctionRequest,
+		Scope: config.ScopeImp,
+		ModelGroups: []config.ModelGroup{
+			{
+				Schema: []config.Schema{{Func: rules.ImpMediaType}},
+				Rules: []config.Rule{
+					{
+						Conditions: []string{"video"},
+						Results:    []config.Result{{Func: ExcludeBiddersName, Args: json.RawMessage(`{"bidders":["bidderA"]}`)}},
+					},
+					{
+						Conditions: []string{"banner"},
+						Results:    []config.Result{{Func: IncludeBiddersName, Args: json.RawMessage(`{"bidders":["bidderB"]}`)}},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	impBidders := json.RawMessage(`{"prebid":{"bidder":{"bidderA":{},"bidderB":{},"bidderC":{}}}}`)
+	request := &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			Imp: []openrtb2.Imp{
+				{ID: "video-imp", Video: &openrtb2.Video{}, Ext: impBidders},
+				{ID: "banner-imp", Banner: &openrtb2.Banner{}, Ext: impBidders},
+				{ID: "native-imp", Native: &openrtb2.Native{}, Ext: impBidders},
+			},
+		},
+	}
+	payload := hs.ProcessedAuctionRequestPayload{Request: request}
+
+	result, err := handleProcessedAuctionImpHook(
+		[]cacheRuleSet[rules.ImpWrapper, ImpHookResult]{impRuleSet},
+		payload,
+		hs.HookResult[hs.ProcessedAuctionRequestPayload]{},
+		newModelGroupSelector(""),
+	)
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	require.Len(t, result.ChangeSet.Mutations(), 2)
+
+	for _, mut := range result.ChangeSet.Mutations() {
+		_, err := mut.Apply(payload)
+		require.NoError(t, err)
+	}
+
+	expectedBidders := map[string][]string{
+		"video-imp":  {"bidderB", "bidderC"},
+		"banner-imp": {"bidderB"},
+		"native-imp": {"bidderA", "bidderB", "bidderC"},
+	}
+	for _, imp := range request.GetImp() {
+		impExt, err := imp.GetImpExt()
+		require.NoError(t, err)
+
+		bidders := make([]string, 0)
+		for bidder := range impExt.GetPrebid().Bidder {
+			bidders = append(bidders, bidder)
+		}
+		assert.ElementsMatch(t, expectedBidders[imp.ID], bidders, imp.ID)
+	}
+}
+
+func TestHandleProcessedAuctionImpHookBidFloors(t *testing.T) {
+	// video imps have their floor raised to 2 USD, banner imps are tagged
+	impRuleSet, err := createImpCacheRuleSet(&config.RuleSet{
+		Stage: hooks.StageProcessedAuctionRequest,
+		Scope: config.ScopeImp,
+		ModelGroups: []config.ModelGroup{
+			{
+				Schema: []config.Schema{{Func: rules.ImpMediaType}},
+				Rules: []config.Rule{
+					{
+						Conditions: []string{"video"},
+						Results:    []config.Result{{Func: RaiseBidFloorName, Args: json.RawMessage(`{"bidfloor":2}`)}},
+					},
+					{
+						Conditions: []string{"banner"},
+						Results:    []config.Result{{Func: LogATagName, Args: json.RawMessage(`{"analyticsvalue":"banner"}`)}},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	request := &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			Imp: []openrtb2.Imp{
+				{ID: "video-imp", Video: &openrtb2.Video{}, BidFloor: 1},
+				{ID: "banner-imp", Banner: &openrtb2.Banner{}, BidFloor: 1},
+			},
+		},
+	}
+	payload := hs.ProcessedAuctionRequestPayload{Request: request}
+
+	result, err := handleProcessedAuctionImpHook(
+		[]cacheRuleSet[rules.ImpWrapper, ImpHookResult]{impRuleSet},
+		payload,
+		hs.HookResult[hs.ProcessedAuctionRequestPayload]{},
+		newModelGroupSelector(""),
+	)
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	require.Len(t, result.ChangeSet.Mutations(), 1)
+
+	_, err = result.ChangeSet.Mutations()[0].Apply(payload)
+	require.NoError(t, err)
+
+	imps := request.GetImp()
+	assert.Equal(t, 2.0, imps[0].BidFloor)
+	assert.Equal(t, "USD", imps[0].BidFloorCur)
+	assert.Equal(t, 1.0, imps[1].BidFloor)
+
+	require.Len(t, result.AnalyticsTags.Activities, 1)
+	activity := result.AnalyticsTags.Activities[0]
+	assert.Equal(t, processedAuctionImpActivityName, activity.Name)
+	require.Len(t, activity.Results, 2)
+	assert.Equal(t, hookanalytics.ResultStatusModify, activity.Results[0].Status)
+	assert.Equal(t, []string{"video-imp"}, activity.Results[0].AppliedTo.ImpIds)
+	assert.Equal(t, hookanalytics.ResultStatusAllow, activity.Results[1].Status)
+	assert.Equal(t, []string{"banner-imp"}, activity.Results[1].AppliedTo.ImpIds)
+}
+
+func TestHandleProcessedAuctionImpHookNoRuleSets(t *testing.T) {
+	in := hs.HookResult[hs.ProcessedAuctionRequestPayload]{Message: "unchanged"}
+
+	result, err := handleProcessedAuctionImpHook(nil, hs.ProcessedAuctionRequestPayload{}, in, newModelGroupSelector(""))
+
+	assert.NoError(t, err)
+	assert.Equal(t, in, result)
+}
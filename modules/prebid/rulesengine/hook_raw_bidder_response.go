@@ -0,0 +1,73 @@
+package rulesengine
+
+import (
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/rules"
+)
+
+func handleRawBidderResponseHook(
+	ruleSets []cacheRuleSet[rules.BidWrapper, BidHookResult],
+	payload hs.RawBidderResponsePayload,
+	selector *modelGroupSelector) (hs.HookResult[hs.RawBidderResponsePayload], error) {
+
+	result := hs.HookResult[hs.RawBidderResponsePayload]{}
+	if payload.BidderResponse == nil || len(payload.BidderResponse.Bids) == 0 {
+		return result, nil
+	}
+
+	var analyticsResults []hookanalytics.Result
+	var rejected []hs.RejectedBid
+	repriced := false
+	bids := make([]*adapters.TypedBid, 0, len(payload.BidderResponse.Bids))
+
+	for _, typedBid := range payload.BidderResponse.Bids {
+		if typedBid == nil || typedBid.Bid == nil {
+			bids = append(bids, typedBid)
+			continue
+		}
+
+		seat := payload.Bidder
+		if len(typedBid.Seat) > 0 {
+			seat = typedBid.Seat.String()
+		}
+		bidWrapper := rules.BidWrapper{
+			Seat:    seat,
+			Bid:     typedBid.Bid,
+			BidType: typedBid.BidType,
+		}
+
+		bidResult, errs := runBidRuleSets(ruleSets, &bidWrapper, selector)
+		result.Errors = append(result.Errors, errs...)
+		analyticsResults = append(analyticsResults, bidResult.AnalyticsResults...)
+
+		if !bidResult.modified() {
+			bids = append(bids, typedBid)
+			continue
+		}
+		if bidResult.Rejected {
+			// rejected bids are kept in the updated bids so the rejection mutation finds them
+			bids = append(bids, typedBid)
+			rejected = append(rejected, hs.RejectedBid{Bid: typedBid, NonBidReason: bidResult.NonBidReason})
+			continue
+		}
+		repriced = true
+
+		adjustedBid := *typedBid.Bid
+		adjustedBid.Price *= bidResult.PriceFactor
+		adjustedTypedBid := *typedBid
+		adjustedTypedBid.Bid = &adjustedBid
+		bids = append(bids, &adjustedTypedBid)
+	}
+
+	if repriced {
+		result.ChangeSet.RawBidderResponse().Bids().UpdateBids(bids)
+	}
+	if len(rejected) > 0 {
+		result.ChangeSet.RawBidderResponse().Bids().RejectBids(rejected)
+	}
+	result.AnalyticsTags = newBidResponsesAnalytics(analyticsResults)
+
+	return result, nil
+}
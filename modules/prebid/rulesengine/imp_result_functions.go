@@ -0,0 +1,170 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// ProcessedAuctionImpResultFunc is a type alias for a result function that runs once per impression
+// in the processed auction request stage.
+type ProcessedAuctionImpResultFunc = rules.ResultFunction[rules.ImpWrapper, ImpHookResult]
+
+// NewProcessedAuctionImpResultFunction is a factory function that creates a new imp scoped result function
+// based on the provided name and parameters.
+// It returns an error if the function name is not recognized or if there is an issue with the parameters.
+func NewProcessedAuctionImpResultFunction(name string, params json.RawMessage) (ProcessedAuctionImpResultFunc, error) {
+	switch name {
+	case ExcludeBiddersName:
+		return NewImpExcludeBidders(params)
+	case IncludeBiddersName:
+		return NewImpIncludeBidders(params)
+	case SetBidFloorName:
+		return NewImpSetBidFloor(params)
+	case RaiseBidFloorName:
+		return NewImpRaiseBidFloor(params)
+	case LogATagName:
+		return NewImpLogATag(params)
+	default:
+		return nil, fmt.Errorf("result function %s was not created", name)
+	}
+}
+
+// NewImpExcludeBidders is a factory function that creates a new ImpExcludeBidders result function.
+func NewImpExcludeBidders(params json.RawMessage) (ProcessedAuctionImpResultFunc, error) {
+	var excludeBiddersParams config.ResultFuncParams
+	if err := jsonutil.Unmarshal(params, &excludeBiddersParams); err != nil {
+		return nil, err
+	}
+
+	if len(excludeBiddersParams.Bidders) == 0 {
+		return nil, errors.New("excludeBidders requires at least one bidder to be specified")
+	}
+	return &ImpExcludeBidders{Args: excludeBiddersParams}, nil
+}
+
+// ImpExcludeBidders is a struct that holds parameters for excluding bidders from a single impression.
+type ImpExcludeBidders struct {
+	Args config.ResultFuncParams
+}
+
+// Call records the bidders to remove from the ext.prebid.bidder object of the impression being evaluated.
+func (eb *ImpExcludeBidders) Call(imp *rules.ImpWrapper, result *ImpHookResult, meta rules.ResultFunctionMeta) error {
+	for _, bidderName := range eb.Args.Bidders {
+		result.ExcludedBidders[bidderName] = struct{}{}
+	}
+	return nil
+}
+
+func (eb *ImpExcludeBidders) Name() string {
+	return ExcludeBiddersName
+}
+
+// NewImpIncludeBidders is a factory function that creates a new ImpIncludeBidders result function.
+func NewImpIncludeBidders(params json.RawMessage) (ProcessedAuctionImpResultFunc, error) {
+	var includeBiddersParams config.ResultFuncParams
+	if err := jsonutil.Unmarshal(params, &includeBiddersParams); err != nil {
+		return nil, err
+	}
+
+	if len(includeBiddersParams.Bidders) == 0 {
+		return nil, errors.New("includeBidders requires at least one bidder to be specified")
+	}
+	return &ImpIncludeBidders{Args: includeBiddersParams}, nil
+}
+
+// ImpIncludeBidders is a struct that holds parameters for including bidders in a single impression.
+type ImpIncludeBidders struct {
+	Args config.ResultFuncParams
+}
+
+// Call records the bidders allowed to stay in the ext.prebid.bidder object of the impression being evaluated.
+func (ib *ImpIncludeBidders) Call(imp *rules.ImpWrapper, result *ImpHookResult, meta rules.ResultFunctionMeta) error {
+	for _, bidderName := range ib.Args.Bidders {
+		result.AllowedBidders[bidderName] = struct{}{}
+	}
+	return nil
+}
+
+func (ib *ImpIncludeBidders) Name() string {
+	return IncludeBiddersName
+}
+
+// NewImpSetBidFloor is a factory function that creates a new ImpSetBidFloor result function.
+func NewImpSetBidFloor(params json.RawMessage) (ProcessedAuctionImpResultFunc, error) {
+	bidFloorParams, err := newBidFloorParams(params, SetBidFloorName)
+	if err != nil {
+		return nil, err
+	}
+	return &ImpSetBidFloor{Args: bidFloorParams}, nil
+}
+
+// ImpSetBidFloor is a struct that holds parameters for overriding the floor of a single impression.
+type ImpSetBidFloor struct {
+	Args config.BidFloorParams
+}
+
+// Call records the floor to set on the impression being evaluated.
+func (sbf *ImpSetBidFloor) Call(imp *rules.ImpWrapper, result *ImpHookResult, meta rules.ResultFunctionMeta) error {
+	result.BidFloor = &impBidFloor{args: sbf.Args}
+	result.appendAnalyticsResult(hookanalytics.ResultStatusModify, bidFloorAnalyticsValues(meta, sbf.Args), imp)
+	return nil
+}
+
+func (sbf *ImpSetBidFloor) Name() string {
+	return SetBidFloorName
+}
+
+// NewImpRaiseBidFloor is a factory function that creates a new ImpRaiseBidFloor result function.
+func NewImpRaiseBidFloor(params json.RawMessage) (ProcessedAuctionImpResultFunc, error) {
+	bidFloorParams, err := newBidFloorParams(params, RaiseBidFloorName)
+	if err != nil {
+		return nil, err
+	}
+	return &ImpRaiseBidFloor{Args: bidFloorParams}, nil
+}
+
+// ImpRaiseBidFloor is a struct that holds parameters for raising the floor of a single impression.
+type ImpRaiseBidFloor struct {
+	Args config.BidFloorParams
+}
+
+// Call records the floor to raise the impression being evaluated to.
+func (rbf *ImpRaiseBidFloor) Call(imp *rules.ImpWrapper, result *ImpHookResult, meta rules.ResultFunctionMeta) error {
+	result.BidFloor = &impBidFloor{args: rbf.Args, raise: true}
+	result.appendAnalyticsResult(hookanalytics.ResultStatusModify, bidFloorAnalyticsValues(meta, rbf.Args), imp)
+	return nil
+}
+
+func (rbf *ImpRaiseBidFloor) Name() string {
+	return RaiseBidFloorName
+}
+
+// NewImpLogATag is a factory function that creates a new ImpLogATag result function.
+func NewImpLogATag(params json.RawMessage) (ProcessedAuctionImpResultFunc, error) {
+	logATagParams, err := newLogATagParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return &ImpLogATag{Args: logATagParams}, nil
+}
+
+// ImpLogATag is a struct that holds parameters for tagging a single impression.
+type ImpLogATag struct {
+	Args config.LogATagParams
+}
+
+// Call reports the configured analytics value for the impression being evaluated without altering it.
+func (lat *ImpLogATag) Call(imp *rules.ImpWrapper, result *ImpHookResult, meta rules.ResultFunctionMeta) error {
+	result.appendAnalyticsResult(hookanalytics.ResultStatusAllow, analyticsValues(meta, lat.Args.AnalyticsValue), imp)
+	return nil
+}
+
+func (lat *ImpLogATag) Name() string {
+	return LogATagName
+}
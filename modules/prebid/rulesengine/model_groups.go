@@ -0,0 +1,236 @@
+package rulesengine
+
+import (
+	"errors"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/randomutil"
+)
+
+const (
+	modelGroupsActivityName = "rules-engine-model-groups"
+	modelGroupKeyContextKey = "modelgroupkey"
+	metricsModuleName       = "prebid_rulesengine"
+	otherMetricsLabel       = "other"
+)
+
+// modelGroupSelector picks the model group each rule set runs from a key so the same key is always assigned
+// the same model group of a rule set. The key is the value of the request field the account makes assignments
+// sticky by or a random value drawn for the request otherwise. It is passed from the processed auction request
+// stage to the bid response stages through the module context so all the stages of a request agree.
+type modelGroupSelector struct {
+	key        string
+	selections []modelGroupSelection
+	tracer     ruleSetTracer
+}
+
+// ruleSetTracer receives the path taken through the tree of the model group selected for a rule set.
+// It is only set on the selector when the rule sets are explained.
+type ruleSetTracer func(ruleSet, scope, impID string, trace rules.Trace, err error)
+
+// modelGroupSelection identifies the model group selected for a rule set
+type modelGroupSelection struct {
+	ruleSet      string
+	version      string
+	analyticsKey string
+}
+
+func newModelGroupSelector(key string) *modelGroupSelector {
+	if len(key) == 0 {
+		key = strconv.FormatInt(randomutil.RandomNumberGenerator{}.GenerateInt63(), 36)
+	}
+	return &modelGroupSelector{key: key}
+}
+
+// selectRuleSetModelGroup selects the model group of the rule set assigned to the selector key
+// according to the model group weights and records the selection
+func selectRuleSetModelGroup[T1 any, T2 any](s *modelGroupSelector, ruleSet cacheRuleSet[T1, T2]) (cacheModelGroup[T1, T2], error) {
+	group, err := selectModelGroup(ruleSet.modelGroups, newKeyGenerator(ruleSet.name+"|"+s.key))
+	if err != nil {
+		return group, err
+	}
+
+	selection := modelGroupSelection{ruleSet: ruleSet.name, version: group.version, analyticsKey: group.analyticsKey}
+	for _, sel := range s.selections {
+		if sel == selection {
+			return group, nil
+		}
+	}
+	s.selections = append(s.selections, selection)
+	return group, nil
+}
+
+// runModelGroupTree runs the tree of the selected model group of a rule set, reporting the path taken
+// to the selector tracer if any
+func runModelGroupTree[T1 any, T2 any](s *modelGroupSelector, ruleSet, scope, impID string, group cacheModelGroup[T1, T2], payload *T1, result *T2) error {
+	if s.tracer == nil {
+		return group.tree.Run(payload, result)
+	}
+	trace, err := group.tree.Explain(payload, result)
+	s.tracer(ruleSet, scope, impID, trace, err)
+	return err
+}
+
+// appendAnalytics adds an activity reporting the model groups selected to the given analytics
+func (s *modelGroupSelector) appendAnalytics(analytics hookanalytics.Analytics) hookanalytics.Analytics {
+	if len(s.selections) == 0 {
+		return analytics
+	}
+
+	results := make([]hookanalytics.Result, 0, len(s.selections))
+	for _, sel := range s.selections {
+		results = append(results, hookanalytics.Result{
+			Status: hookanalytics.ResultStatusAllow,
+			Values: map[string]interface{}{
+				"ruleset":      sel.ruleSet,
+				"modelversion": sel.version,
+				"analyticskey": sel.analyticsKey,
+			},
+		})
+	}
+	analytics.Activities = append(analytics.Activities, hookanalytics.Activity{
+		Name:    modelGroupsActivityName,
+		Status:  hookanalytics.ActivityStatusSuccess,
+		Results: results,
+	})
+	return analytics
+}
+
+// stickyKey returns the value of the request field model groups are assigned by or an empty string
+// if assignments are not sticky or the field is not set
+func stickyKey(stickyBy string, request *openrtb_ext.RequestWrapper) string {
+	if request == nil || request.BidRequest == nil {
+		return ""
+	}
+
+	switch stickyBy {
+	case config.StickyBySourceTID:
+		if request.Source != nil {
+			return request.Source.TID
+		}
+	case config.StickyByUserID:
+		if request.User != nil {
+			return request.User.ID
+		}
+	case config.StickyByDeviceIFA:
+		if request.Device != nil {
+			return request.Device.IFA
+		}
+	}
+	return ""
+}
+
+// contextModelGroupKey returns the model group key the processed auction request stage stored in the module context
+func contextModelGroupKey(miCtx hs.ModuleInvocationContext) string {
+	if key, ok := miCtx.ModuleContext[modelGroupKeyContextKey].(string); ok {
+		return key
+	}
+	return ""
+}
+
+// contextModelGroupSelector returns a selector of the model groups assigned to the key the processed auction
+// request stage passed through the module context. A new key would assign the bid response stages other model
+// groups than the ones the request was assigned, so an error is returned if the key is missing.
+func contextModelGroupSelector(miCtx hs.ModuleInvocationContext) (*modelGroupSelector, error) {
+	key := contextModelGroupKey(miCtx)
+	if len(key) == 0 {
+		return nil, errors.New("model group key not found, the processed auction request hook of the module must run before its bid response hooks")
+	}
+	return newModelGroupSelector(key), nil
+}
+
+// keyGenerator is a random generator deterministically derived from a key
+type keyGenerator struct {
+	sum uint64
+}
+
+func newKeyGenerator(key string) keyGenerator {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return keyGenerator{sum: h.Sum64()}
+}
+
+func (g keyGenerator) GenerateInt63() int64 {
+	return int64(g.sum >> 1)
+}
+
+func (g keyGenerator) Intn(n int) int {
+	return int(g.sum % uint64(n))
+}
+
+// moduleMetrics records the rules engine metrics once the metrics engine is set
+type moduleMetrics struct {
+	engine        *moduledeps.MetricsEngineRef
+	ruleSets      map[string]struct{}
+	modelVersions map[string]struct{}
+}
+
+func newModuleMetrics(engine *moduledeps.MetricsEngineRef, cfg metricsConfig) *moduleMetrics {
+	mm := &moduleMetrics{
+		engine:        engine,
+		ruleSets:      make(map[string]struct{}, len(cfg.RuleSets)),
+		modelVersions: make(map[string]struct{}, len(cfg.ModelVersions)),
+	}
+	for _, ruleSet := range cfg.RuleSets {
+		mm.ruleSets[ruleSet] = struct{}{}
+	}
+	for _, version := range cfg.ModelVersions {
+		mm.modelVersions[version] = struct{}{}
+	}
+	return mm
+}
+
+// metricsLabel returns the value if the host allows it to be reported or otherMetricsLabel otherwise
+func metricsLabel(allowed map[string]struct{}, value string) string {
+	if _, ok := allowed[value]; ok {
+		return value
+	}
+	return otherMetricsLabel
+}
+
+func (mm *moduleMetrics) metricsEngine() metrics.MetricsEngine {
+	if mm == nil {
+		return nil
+	}
+	return mm.engine.Get()
+}
+
+func (mm *moduleMetrics) recordModelGroups(selections []modelGroupSelection) {
+	me := mm.metricsEngine()
+	if me == nil {
+		return
+	}
+	for _, sel := range selections {
+		me.RecordModuleModelGroup(metrics.ModuleModelGroupLabels{
+			Module:       metricsModuleName,
+			RuleSet:      metricsLabel(mm.ruleSets, sel.ruleSet),
+			ModelVersion: metricsLabel(mm.modelVersions, sel.version),
+		})
+	}
+}
+
+func (mm *moduleMetrics) recordConfigReload(success bool) {
+	me := mm.metricsEngine()
+	if me == nil {
+		return
+	}
+	me.RecordModuleConfigReload(metrics.ModuleConfigReloadLabels{
+		Module:  metricsModuleName,
+		Success: success,
+	})
+}
+
+// reportModelGroups adds the model groups selected while running a hook to the hook analytics tags and metrics
+func reportModelGroups[T any](mm *moduleMetrics, s *modelGroupSelector, result hs.HookResult[T]) hs.HookResult[T] {
+	result.AnalyticsTags = s.appendAnalytics(result.AnalyticsTags)
+	mm.recordModelGroups(s.selections)
+	return result
+}
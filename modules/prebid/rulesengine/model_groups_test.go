@@ -0,0 +1,184 @@
+package rulesengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func abRuleSet() cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult] {
+	return cacheRuleSet[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{
+		name: "ab-test",
+		modelGroups: []cacheModelGroup[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]{
+			{weight: 90, version: "control", analyticsKey: "ab-control"},
+			{weight: 10, version: "candidate", analyticsKey: "ab-candidate"},
+		},
+	}
+}
+
+func TestSelectRuleSetModelGroupIsSticky(t *testing.T) {
+	ruleSet := abRuleSet()
+
+	first, err := selectRuleSetModelGroup(newModelGroupSelector("user-1"), ruleSet)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		group, err := selectRuleSetModelGroup(newModelGroupSelector("user-1"), ruleSet)
+		require.NoError(t, err)
+		assert.Equal(t, first.version, group.version)
+	}
+}
+
+func TestSelectRuleSetModelGroupWeights(t *testing.T) {
+	ruleSet := abRuleSet()
+
+	selected := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		group, err := selectRuleSetModelGroup(newModelGroupSelector(fmt.Sprintf("user-%d", i)), ruleSet)
+		require.NoError(t, err)
+		selected[group.version]++
+	}
+
+	assert.InDelta(t, 9000, selected["control"], 300)
+	assert.InDelta(t, 1000, selected["candidate"], 300)
+}
+
+func TestSelectRuleSetModelGroupRecordsSelections(t *testing.T) {
+	ruleSet := abRuleSet()
+	s := newModelGroupSelector("user-1")
+
+	group, err := selectRuleSetModelGroup(s, ruleSet)
+	require.NoError(t, err)
+	_, err = selectRuleSetModelGroup(s, ruleSet)
+	require.NoError(t, err)
+
+	expected := []modelGroupSelection{{ruleSet: "ab-test", version: group.version, analyticsKey: group.analyticsKey}}
+	assert.Equal(t, expected, s.selections, "a rule set run more than once is recorded once")
+}
+
+func TestNewModelGroupSelector(t *testing.T) {
+	assert.Equal(t, "user-1", newModelGroupSelector("user-1").key)
+	assert.NotEmpty(t, newModelGroupSelector("").key)
+}
+
+func TestStickyKey(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			Source: &openrtb2.Source{TID: "tid"},
+			User:   &openrtb2.User{ID: "user"},
+			Device: &openrtb2.Device{IFA: "ifa"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		stickyBy string
+		request  *openrtb_ext.RequestWrapper
+		expected string
+	}{
+		{name: "source_tid", stickyBy: config.StickyBySourceTID, request: request, expected: "tid"},
+		{name: "user_id", stickyBy: config.StickyByUserID, request: request, expected: "user"},
+		{name: "device_ifa", stickyBy: config.StickyByDeviceIFA, request: request, expected: "ifa"},
+		{name: "not_sticky", stickyBy: "", request: request, expected: ""},
+		{
+			name:     "field_not_set",
+			stickyBy: config.StickyByUserID,
+			request:  &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}},
+			expected: "",
+		},
+		{name: "nil_request", stickyBy: config.StickyByUserID, request: nil, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, stickyKey(tc.stickyBy, tc.request))
+		})
+	}
+}
+
+func TestContextModelGroupKey(t *testing.T) {
+	miCtx := hs.ModuleInvocationContext{ModuleContext: hs.ModuleContext{modelGroupKeyContextKey: "key"}}
+	assert.Equal(t, "key", contextModelGroupKey(miCtx))
+	assert.Empty(t, contextModelGroupKey(hs.ModuleInvocationContext{}))
+}
+
+func TestReportModelGroups(t *testing.T) {
+	s := &modelGroupSelector{
+		key: "user-1",
+		selections: []modelGroupSelection{
+			{ruleSet: "ab-test", version: "candidate", analyticsKey: "ab-candidate"},
+			{ruleSet: "unlisted", version: "v9"},
+		},
+	}
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordModuleModelGroup", metrics.ModuleModelGroupLabels{
+		Module:       "prebid_rulesengine",
+		RuleSet:      "ab-test",
+		ModelVersion: "candidate",
+	}).Return()
+	me.On("RecordModuleModelGroup", metrics.ModuleModelGroupLabels{
+		Module:       "prebid_rulesengine",
+		RuleSet:      "other",
+		ModelVersion: "other",
+	}).Return()
+
+	mm := newModuleMetrics(moduledeps.NewMetricsEngineRef(me), metricsConfig{RuleSets: []string{"ab-test"}, ModelVersions: []string{"candidate"}})
+	result := reportModelGroups(mm, s, hs.HookResult[hs.ProcessedAuctionRequestPayload]{})
+
+	expectedTags := hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:   "rules-engine-model-groups",
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{
+					{
+						Status: hookanalytics.ResultStatusAllow,
+						Values: map[string]interface{}{
+							"ruleset":      "ab-test",
+							"modelversion": "candidate",
+							"analyticskey": "ab-candidate",
+						},
+					},
+					{
+						Status: hookanalytics.ResultStatusAllow,
+						Values: map[string]interface{}{
+							"ruleset":      "unlisted",
+							"modelversion": "v9",
+							"analyticskey": "",
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expectedTags, result.AnalyticsTags)
+	me.AssertExpectations(t)
+}
+
+func TestReportModelGroupsWithoutSelections(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+
+	result := reportModelGroups(&moduleMetrics{engine: moduledeps.NewMetricsEngineRef(me)}, newModelGroupSelector(""), hs.HookResult[hs.ProcessedAuctionRequestPayload]{})
+
+	assert.Empty(t, result.AnalyticsTags.Activities)
+	me.AssertNotCalled(t, "RecordModuleModelGroup")
+}
+
+func TestReportModelGroupsWithoutMetricsEngine(t *testing.T) {
+	s := &modelGroupSelector{selections: []modelGroupSelection{{ruleSet: "ab-test", version: "control"}}}
+
+	assert.NotPanics(t, func() {
+		reportModelGroups(nil, s, hs.HookResult[hs.ProcessedAuctionRequestPayload]{})
+		reportModelGroups(&moduleMetrics{}, s, hs.HookResult[hs.ProcessedAuctionRequestPayload]{})
+	})
+}
@@ -11,22 +11,36 @@ import (
 	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/moduledeps"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
 
 // Builder configures the rules engine module initiating an in-memory cache and kicking
 // off a go routine that builds tree structures that represent rule sets optimized for finding
 // a rule to applies for a given request.
-func Builder(cfg json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+func Builder(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
 	schemaValidator, err := config.CreateSchemaValidator(config.RulesEngineSchemaFilePath)
 	if err != nil {
 		return nil, err
 	}
 
+	metricsCfg, err := getMetricsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sourceAllowlist, err := getSourceAllowlist(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mm := newModuleMetrics(deps.MetricsEngine, metricsCfg)
 	tm := treeManager{
 		done:            make(chan struct{}),
 		requests:        make(chan buildInstruction),
 		schemaValidator: schemaValidator,
 		monitor:         &treeManagerLogger{},
+		httpClient:      deps.HTTPClient,
+		metrics:         mm,
+		sourceAllowlist: sourceAllowlist,
 	}
 
 	refreshRate, err := getRefreshRate(cfg)
@@ -41,6 +55,7 @@ func Builder(cfg json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error)
 	return Module{
 		Cache:       c,
 		TreeManager: &tm,
+		Metrics:     mm,
 	}, nil
 }
 
@@ -48,6 +63,7 @@ func Builder(cfg json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error)
 type Module struct {
 	Cache       cacher
 	TreeManager *treeManager
+	Metrics     *moduleMetrics
 }
 
 // HandleProcessedAuctionHook updates field on openrtb2.BidRequest.
@@ -57,12 +73,104 @@ func (m Module) HandleProcessedAuctionHook(
 	miCtx hs.ModuleInvocationContext,
 	payload hs.ProcessedAuctionRequestPayload,
 ) (hs.HookResult[hs.ProcessedAuctionRequestPayload], error) {
-	result := hs.HookResult[hs.ProcessedAuctionRequestPayload]{}
+	co, message := m.cacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{Message: message}, nil
+	}
+
+	selector := newModelGroupSelector(stickyKey(co.stickyBy, payload.Request))
+
+	result, err := runProcessedAuctionRuleSets(co, payload, selector)
+	if err != nil {
+		return result, err
+	}
+
+	// later stages select the model groups assigned to the same key
+	result.ModuleContext = hs.ModuleContext{modelGroupKeyContextKey: selector.key}
+
+	return reportModelGroups(m.Metrics, selector, result), nil
+}
+
+// runProcessedAuctionRuleSets runs the request scoped and then the imp scoped rule sets of the processed
+// auction request stage against the request
+func runProcessedAuctionRuleSets(
+	co *cacheEntry,
+	payload hs.ProcessedAuctionRequestPayload,
+	selector *modelGroupSelector,
+) (hs.HookResult[hs.ProcessedAuctionRequestPayload], error) {
+	result, err := handleProcessedAuctionHook(co.ruleSetsForProcessedAuctionRequestStage, payload, selector)
+	if err != nil {
+		return result, err
+	}
+	return handleProcessedAuctionImpHook(co.impRuleSetsForProcessedAuctionRequest, payload, result, selector)
+}
+
+// HandleRawBidderResponseHook rejects, reprices or tags the bids of a single bidder response.
+// Bids are updated only if they satisfy conditions provided by the module config.
+func (m Module) HandleRawBidderResponseHook(
+	_ context.Context,
+	miCtx hs.ModuleInvocationContext,
+	payload hs.RawBidderResponsePayload,
+) (hs.HookResult[hs.RawBidderResponsePayload], error) {
+	co, message := m.cacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.RawBidderResponsePayload]{Message: message}, nil
+	}
+
+	ruleSets := co.ruleSetsForRawBidderResponseStage
+	if len(ruleSets) == 0 {
+		return hs.HookResult[hs.RawBidderResponsePayload]{}, nil
+	}
+	selector, err := contextModelGroupSelector(miCtx)
+	if err != nil {
+		return hs.HookResult[hs.RawBidderResponsePayload]{}, err
+	}
+
+	result, err := handleRawBidderResponseHook(ruleSets, payload, selector)
+	if err != nil {
+		return result, err
+	}
+
+	return reportModelGroups(m.Metrics, selector, result), nil
+}
+
+// HandleAllProcessedBidResponsesHook rejects, reprices or tags the bids of all processed bidder responses.
+// Bids are updated only if they satisfy conditions provided by the module config.
+func (m Module) HandleAllProcessedBidResponsesHook(
+	_ context.Context,
+	miCtx hs.ModuleInvocationContext,
+	payload hs.AllProcessedBidResponsesPayload,
+) (hs.HookResult[hs.AllProcessedBidResponsesPayload], error) {
+	co, message := m.cacheEntry(miCtx)
+	if co == nil {
+		return hs.HookResult[hs.AllProcessedBidResponsesPayload]{Message: message}, nil
+	}
+
+	ruleSets := co.ruleSetsForAllProcessedBidResponsesStage
+	if len(ruleSets) == 0 {
+		return hs.HookResult[hs.AllProcessedBidResponsesPayload]{}, nil
+	}
+	selector, err := contextModelGroupSelector(miCtx)
+	if err != nil {
+		return hs.HookResult[hs.AllProcessedBidResponsesPayload]{}, err
+	}
 
+	result, err := handleAllProcessedBidResponsesHook(ruleSets, payload, selector)
+	if err != nil {
+		return result, err
+	}
+
+	return reportModelGroups(m.Metrics, selector, result), nil
+}
+
+// cacheEntry returns the enabled cache entry holding the account trees. On a cache miss or when the
+// cached trees are stale, it asks the tree manager to build them for future requests. A nil entry is
+// returned along with a message explaining why the hook is skipped if no trees are to be run.
+func (m Module) cacheEntry(miCtx hs.ModuleInvocationContext) (*cacheEntry, string) {
 	// AccountConfig will either be an account-specific config or the default account config
 	// AccountConfig only contains the config block for this module
 	if len(miCtx.AccountConfig) == 0 {
-		return result, nil
+		return nil, ""
 	}
 
 	co := m.Cache.Get(miCtx.AccountID)
@@ -76,9 +184,7 @@ func (m Module) HandleProcessedAuctionHook(
 		m.TreeManager.requests <- bi
 
 		// TODO: return with reject or no reject, possible config option
-		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{
-			Message: "skipped, loading rules engine account configuration for future requests",
-		}, nil
+		return nil, "skipped, loading rules engine account configuration for future requests"
 	}
 	// cache hit
 	if rebuildTrees(co, &miCtx.AccountConfig, m.Cache) {
@@ -90,14 +196,10 @@ func (m Module) HandleProcessedAuctionHook(
 	}
 
 	if !co.enabled {
-		return hs.HookResult[hs.ProcessedAuctionRequestPayload]{
-			Message: "skipped, rules engine is disabled for this account",
-		}, nil
+		return nil, "skipped, rules engine is disabled for this account"
 	}
 
-	ruleSets := co.ruleSetsForProcessedAuctionRequestStage
-
-	return handleProcessedAuctionHook(ruleSets, payload)
+	return co, ""
 }
 
 // Shutdown signals the module to stop processing and waits for the tree manager to finish
@@ -141,3 +243,48 @@ func getRefreshRate(jsonCfg json.RawMessage) (int, error) {
 	}
 	return int(updateFrequency), nil
 }
+
+// metricsConfig holds the rule set names and model versions the model groups selected are reported with.
+// Both come from the account configuration, so any other value is reported as otherMetricsLabel to keep
+// the number of metrics bounded.
+type metricsConfig struct {
+	RuleSets      []string `json:"rulesets"`
+	ModelVersions []string `json:"modelversions"`
+}
+
+func getMetricsConfig(jsonCfg json.RawMessage) (metricsConfig, error) {
+	var cfg metricsConfig
+
+	data, _, _, err := jsonparser.Get(jsonCfg, "metrics")
+	if err == jsonparser.KeyPathNotFoundError {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// getSourceAllowlist returns the directories and URL prefixes the account rule set sources are restricted to.
+// Account sources are all rejected if the host configuration does not set any.
+func getSourceAllowlist(jsonCfg json.RawMessage) (config.SourceAllowlist, error) {
+	var allowlist config.SourceAllowlist
+
+	data, _, _, err := jsonparser.Get(jsonCfg, "sources")
+	if err == jsonparser.KeyPathNotFoundError {
+		return allowlist, nil
+	}
+	if err != nil {
+		return allowlist, err
+	}
+	if err := jsonutil.UnmarshalValid(data, &allowlist); err != nil {
+		return allowlist, err
+	}
+	if err := allowlist.Validate(); err != nil {
+		return allowlist, err
+	}
+	return allowlist, nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -162,3 +163,86 @@ func TestGetRefreshRate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMetricsConfig(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inData         json.RawMessage
+		expectedConfig metricsConfig
+		expectErr      bool
+	}{
+		{
+			name:   "metrics_not_configured",
+			inData: json.RawMessage(`{"enabled": true}`),
+		},
+		{
+			name:   "metrics_configured",
+			inData: json.RawMessage(`{"enabled": true, "metrics": {"rulesets": ["ab-test"], "modelversions": ["control", "candidate"]}}`),
+			expectedConfig: metricsConfig{
+				RuleSets:      []string{"ab-test"},
+				ModelVersions: []string{"control", "candidate"},
+			},
+		},
+		{
+			name:      "malformed_metrics",
+			inData:    json.RawMessage(`{"enabled": true, "metrics": {"rulesets": "ab-test"}}`),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := getMetricsConfig(tc.inData)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedConfig, cfg)
+		})
+	}
+}
+
+func TestGetSourceAllowlist(t *testing.T) {
+	testCases := []struct {
+		name              string
+		inData            json.RawMessage
+		expectedAllowlist config.SourceAllowlist
+		expectErr         bool
+	}{
+		{
+			name:   "sources_not_configured",
+			inData: json.RawMessage(`{"enabled": true}`),
+		},
+		{
+			name:   "sources_configured",
+			inData: json.RawMessage(`{"enabled": true, "sources": {"paths": ["/etc/rules"], "urlprefixes": ["https://rules.example.com/"]}}`),
+			expectedAllowlist: config.SourceAllowlist{
+				Paths:       []string{"/etc/rules"},
+				URLPrefixes: []string{"https://rules.example.com/"},
+			},
+		},
+		{
+			name:      "malformed_sources",
+			inData:    json.RawMessage(`{"enabled": true, "sources": {"paths": "/etc/rules"}}`),
+			expectErr: true,
+		},
+		{
+			name:      "invalid_url_prefix",
+			inData:    json.RawMessage(`{"enabled": true, "sources": {"urlprefixes": ["rules.example.com"]}}`),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			allowlist, err := getSourceAllowlist(tc.inData)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAllowlist, allowlist)
+		})
+	}
+}
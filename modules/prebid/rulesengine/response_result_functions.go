@@ -0,0 +1,153 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// BidResponseResultFunc is a type alias for a result function that runs in the raw bidder response
+// and all processed bid responses stages.
+type BidResponseResultFunc = rules.ResultFunction[rules.BidWrapper, BidHookResult]
+
+const (
+	RejectBidsName     = "rejectBids"
+	AdjustBidPriceName = "adjustBidPrice"
+	TagBidName         = "tagBid"
+)
+
+// nonBidResponseRejectedGeneral is the seat non bid reason of the rejected bids if the account does not set one
+const nonBidResponseRejectedGeneral = 300 // Response Rejected - General
+
+// NewBidResponseResultFunction is a factory function that creates a new result function based on the provided name and parameters.
+// It returns an error if the function name is not recognized or if there is an issue with the parameters.
+// The function returns a rules.ResultFunction that records the decisions taken for a single bid in a BidHookResult.
+func NewBidResponseResultFunction(name string, params json.RawMessage) (BidResponseResultFunc, error) {
+	switch name {
+	case RejectBidsName:
+		return NewRejectBids(params)
+	case AdjustBidPriceName:
+		return NewAdjustBidPrice(params)
+	case TagBidName:
+		return NewTagBid(params)
+	default:
+		return nil, fmt.Errorf("result function %s was not created", name)
+	}
+}
+
+// NewRejectBids is a factory function that creates a new RejectBids result function.
+func NewRejectBids(params json.RawMessage) (BidResponseResultFunc, error) {
+	var rejectBidsParams config.RejectBidsParams
+	if len(params) > 0 {
+		if err := jsonutil.Unmarshal(params, &rejectBidsParams); err != nil {
+			return nil, err
+		}
+	}
+	return &RejectBids{Args: rejectBidsParams}, nil
+}
+
+// RejectBids is a struct that holds parameters for rejecting bids in the rules engine.
+type RejectBids struct {
+	Args config.RejectBidsParams
+}
+
+// Call marks the bid as rejected so it gets removed from the bidder response and reported as a seat non bid
+// with the configured reason.
+func (rb *RejectBids) Call(bid *rules.BidWrapper, result *BidHookResult, meta rules.ResultFunctionMeta) error {
+	result.Rejected = true
+	result.NonBidReason = nonBidResponseRejectedGeneral
+	if rb.Args.SeatNonBid > 0 {
+		result.NonBidReason = rb.Args.SeatNonBid
+	}
+
+	values := analyticsValues(meta, rb.Args.AnalyticsValue)
+	if rb.Args.SeatNonBid > 0 {
+		values["seatnonbid"] = rb.Args.SeatNonBid
+	}
+	result.appendAnalyticsResult(hookanalytics.ResultStatusBlock, values, bid)
+	return nil
+}
+
+func (rb *RejectBids) Name() string {
+	return RejectBidsName
+}
+
+// NewAdjustBidPrice is a factory function that creates a new AdjustBidPrice result function.
+// It returns an error if the factor is not a positive number.
+func NewAdjustBidPrice(params json.RawMessage) (BidResponseResultFunc, error) {
+	var adjustBidPriceParams config.AdjustBidPriceParams
+	if err := jsonutil.Unmarshal(params, &adjustBidPriceParams); err != nil {
+		return nil, err
+	}
+
+	if adjustBidPriceParams.Factor <= 0 {
+		return nil, errors.New("adjustBidPrice requires a positive factor to be specified")
+	}
+	return &AdjustBidPrice{Args: adjustBidPriceParams}, nil
+}
+
+// AdjustBidPrice is a struct that holds parameters for adjusting bid prices in the rules engine.
+type AdjustBidPrice struct {
+	Args config.AdjustBidPriceParams
+}
+
+// Call multiplies the price factor to apply to the bid by the configured factor.
+func (abp *AdjustBidPrice) Call(bid *rules.BidWrapper, result *BidHookResult, meta rules.ResultFunctionMeta) error {
+	result.PriceFactor *= abp.Args.Factor
+
+	values := analyticsValues(meta, abp.Args.AnalyticsValue)
+	values["factor"] = abp.Args.Factor
+	result.appendAnalyticsResult(hookanalytics.ResultStatusModify, values, bid)
+	return nil
+}
+
+func (abp *AdjustBidPrice) Name() string {
+	return AdjustBidPriceName
+}
+
+// NewTagBid is a factory function that creates a new TagBid result function.
+// It returns an error if no analytics value is provided.
+func NewTagBid(params json.RawMessage) (BidResponseResultFunc, error) {
+	var tagBidParams config.TagBidParams
+	if err := jsonutil.Unmarshal(params, &tagBidParams); err != nil {
+		return nil, err
+	}
+
+	if len(tagBidParams.AnalyticsValue) == 0 {
+		return nil, errors.New("tagBid requires an analytics value to be specified")
+	}
+	return &TagBid{Args: tagBidParams}, nil
+}
+
+// TagBid is a struct that holds parameters for tagging bids in the rules engine.
+type TagBid struct {
+	Args config.TagBidParams
+}
+
+// Call attaches the configured analytics value to the bid without altering it.
+func (tb *TagBid) Call(bid *rules.BidWrapper, result *BidHookResult, meta rules.ResultFunctionMeta) error {
+	result.appendAnalyticsResult(hookanalytics.ResultStatusAllow, analyticsValues(meta, tb.Args.AnalyticsValue), bid)
+	return nil
+}
+
+func (tb *TagBid) Name() string {
+	return TagBidName
+}
+
+// analyticsValues builds the analytics result values shared by all bid response result functions
+func analyticsValues(meta rules.ResultFunctionMeta, analyticsValue string) map[string]interface{} {
+	values := map[string]interface{}{
+		"analyticskey": meta.AnalyticsKey,
+		"modelversion": meta.ModelVersion,
+		"rulefired":    meta.RuleFired,
+	}
+	if len(analyticsValue) > 0 {
+		values["analyticsvalue"] = analyticsValue
+	}
+	return values
+}
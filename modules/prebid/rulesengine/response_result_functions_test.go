@@ -0,0 +1,177 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/rules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBidResponseResultFunction(t *testing.T) {
+	tests := []struct {
+		name       string
+		funcName   string
+		params     json.RawMessage
+		expectType BidResponseResultFunc
+		expectErr  bool
+	}{
+		{
+			name:       "valid_rejectBids",
+			funcName:   RejectBidsName,
+			params:     json.RawMessage(`{"seatnonbid":300}`),
+			expectType: &RejectBids{},
+		},
+		{
+			name:       "valid_rejectBids_no_params",
+			funcName:   RejectBidsName,
+			expectType: &RejectBids{},
+		},
+		{
+			name:       "valid_adjustBidPrice",
+			funcName:   AdjustBidPriceName,
+			params:     json.RawMessage(`{"factor":0.9}`),
+			expectType: &AdjustBidPrice{},
+		},
+		{
+			name:       "valid_tagBid",
+			funcName:   TagBidName,
+			params:     json.RawMessage(`{"analyticsvalue":"tagged"}`),
+			expectType: &TagBid{},
+		},
+		{
+			name:      "adjustBidPrice_zero_factor",
+			funcName:  AdjustBidPriceName,
+			params:    json.RawMessage(`{"factor":0}`),
+			expectErr: true,
+		},
+		{
+			name:      "tagBid_missing_value",
+			funcName:  TagBidName,
+			params:    json.RawMessage(`{}`),
+			expectErr: true,
+		},
+		{
+			name:      "invalid-reject-bids-params",
+			funcName:  RejectBidsName,
+			params:    json.RawMessage(`invalid-json`),
+			expectErr: true,
+		},
+		{
+			name:      "request_stage_function_name",
+			funcName:  ExcludeBiddersName,
+			params:    json.RawMessage(`{"bidders":["bidder1"]}`),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewBidResponseResultFunction(tt.funcName, tt.params)
+			if tt.expectErr {
+				assert.Error(t, err, "expected error but got nil")
+			} else {
+				assert.NoError(t, err)
+				assert.IsType(t, tt.expectType, v)
+				assert.Equal(t, tt.funcName, v.Name())
+			}
+		})
+	}
+}
+
+func TestBidResponseResultFunctionsCall(t *testing.T) {
+	bid := &rules.BidWrapper{
+		Seat: "bidder1",
+		Bid:  &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 2},
+	}
+	meta := rules.ResultFunctionMeta{AnalyticsKey: "key", ModelVersion: "v1", RuleFired: "video"}
+	appliedTo := hookanalytics.AppliedTo{Bidder: "bidder1", BidIds: []string{"bid1"}, ImpIds: []string{"imp1"}}
+
+	tests := []struct {
+		name           string
+		resultFuncs    []BidResponseResultFunc
+		expectedResult BidHookResult
+	}{
+		{
+			name:        "rejectBids",
+			resultFuncs: []BidResponseResultFunc{&RejectBids{Args: config.RejectBidsParams{SeatNonBid: 301, AnalyticsValue: "rejected"}}},
+			expectedResult: BidHookResult{
+				Rejected:     true,
+				NonBidReason: 301,
+				PriceFactor:  1,
+				AnalyticsResults: []hookanalytics.Result{
+					{
+						Status:    hookanalytics.ResultStatusBlock,
+						Values:    map[string]interface{}{"analyticskey": "key", "modelversion": "v1", "rulefired": "video", "analyticsvalue": "rejected", "seatnonbid": 301},
+						AppliedTo: appliedTo,
+					},
+				},
+			},
+		},
+		{
+			name:        "rejectBids_default_seat_non_bid",
+			resultFuncs: []BidResponseResultFunc{&RejectBids{}},
+			expectedResult: BidHookResult{
+				Rejected:     true,
+				NonBidReason: 300,
+				PriceFactor:  1,
+				AnalyticsResults: []hookanalytics.Result{
+					{
+						Status:    hookanalytics.ResultStatusBlock,
+						Values:    map[string]interface{}{"analyticskey": "key", "modelversion": "v1", "rulefired": "video"},
+						AppliedTo: appliedTo,
+					},
+				},
+			},
+		},
+		{
+			name: "adjustBidPrice_twice_multiplies_factors",
+			resultFuncs: []BidResponseResultFunc{
+				&AdjustBidPrice{Args: config.AdjustBidPriceParams{Factor: 0.5}},
+				&AdjustBidPrice{Args: config.AdjustBidPriceParams{Factor: 0.5}},
+			},
+			expectedResult: BidHookResult{
+				PriceFactor: 0.25,
+				AnalyticsResults: []hookanalytics.Result{
+					{
+						Status:    hookanalytics.ResultStatusModify,
+						Values:    map[string]interface{}{"analyticskey": "key", "modelversion": "v1", "rulefired": "video", "factor": 0.5},
+						AppliedTo: appliedTo,
+					},
+					{
+						Status:    hookanalytics.ResultStatusModify,
+						Values:    map[string]interface{}{"analyticskey": "key", "modelversion": "v1", "rulefired": "video", "factor": 0.5},
+						AppliedTo: appliedTo,
+					},
+				},
+			},
+		},
+		{
+			name:        "tagBid",
+			resultFuncs: []BidResponseResultFunc{&TagBid{Args: config.TagBidParams{AnalyticsValue: "tagged"}}},
+			expectedResult: BidHookResult{
+				PriceFactor: 1,
+				AnalyticsResults: []hookanalytics.Result{
+					{
+						Status:    hookanalytics.ResultStatusAllow,
+						Values:    map[string]interface{}{"analyticskey": "key", "modelversion": "v1", "rulefired": "video", "analyticsvalue": "tagged"},
+						AppliedTo: appliedTo,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newBidHookResult()
+			for _, rf := range tt.resultFuncs {
+				assert.NoError(t, rf.Call(bid, &result, meta))
+			}
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
@@ -4,21 +4,37 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
+	"github.com/prebid/prebid-server/v3/adservertargeting"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/rules"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
 )
 
 // ProcessedAuctionResultFunc is a type alias for a result function that runs in the processed auction request stage.
 type ProcessedAuctionResultFunc = rules.ResultFunction[openrtb_ext.RequestWrapper, ProcessedAuctionHookResult]
 
 const (
-	ExcludeBiddersName = "excludeBidders"
-	IncludeBiddersName = "includeBidders"
+	ExcludeBiddersName  = "excludeBidders"
+	IncludeBiddersName  = "includeBidders"
+	SetBidFloorName     = "setBidFloor"
+	RaiseBidFloorName   = "raiseBidFloor"
+	CapTmaxName         = "capTmax"
+	SetTargetingName    = "setTargeting"
+	SetBidderParamsName = "setBidderParams"
+	LogATagName         = "logATag"
 )
 
+// defaultBidFloorCur is the currency floors are expressed in when the configuration or the impression does not set one
+const defaultBidFloorCur = "USD"
+
 // NewProcessedAuctionRequestResultFunction is a factory function that creates a new result function based on the provided name and parameters.
 // It returns an error if the function name is not recognized or if there is an issue with the parameters.
 // The function name is case insensitive.
@@ -32,6 +48,18 @@ func NewProcessedAuctionRequestResultFunction(name string, params json.RawMessag
 		return NewExcludeBidders(params)
 	case IncludeBiddersName:
 		return NewIncludeBidders(params)
+	case SetBidFloorName:
+		return NewSetBidFloor(params)
+	case RaiseBidFloorName:
+		return NewRaiseBidFloor(params)
+	case CapTmaxName:
+		return NewCapTmax(params)
+	case SetTargetingName:
+		return NewSetTargeting(params)
+	case SetBidderParamsName:
+		return NewSetBidderParams(params)
+	case LogATagName:
+		return NewLogATag(params)
 	default:
 		return nil, fmt.Errorf("result function %s was not created", name)
 	}
@@ -106,3 +134,343 @@ func (ib *IncludeBidders) Call(req *openrtb_ext.RequestWrapper, result *Processe
 func (ib *IncludeBidders) Name() string {
 	return IncludeBiddersName
 }
+
+// NewSetBidFloor is a factory function that creates a new SetBidFloor result function.
+// It returns an error if the floor is not a positive number.
+func NewSetBidFloor(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	bidFloorParams, err := newBidFloorParams(params, SetBidFloorName)
+	if err != nil {
+		return nil, err
+	}
+	return &SetBidFloor{Args: bidFloorParams}, nil
+}
+
+// SetBidFloor is a struct that holds parameters for overriding the floor of every impression in the rules engine.
+type SetBidFloor struct {
+	Args config.BidFloorParams
+}
+
+// Call adds a mutation that sets the bidfloor and bidfloorcur of every impression to the configured values.
+func (sbf *SetBidFloor) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	addBidFloorMutation(result, sbf.Args, false, meta)
+	return nil
+}
+
+func (sbf *SetBidFloor) Name() string {
+	return SetBidFloorName
+}
+
+// NewRaiseBidFloor is a factory function that creates a new RaiseBidFloor result function.
+// It returns an error if the floor is not a positive number.
+func NewRaiseBidFloor(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	bidFloorParams, err := newBidFloorParams(params, RaiseBidFloorName)
+	if err != nil {
+		return nil, err
+	}
+	return &RaiseBidFloor{Args: bidFloorParams}, nil
+}
+
+// RaiseBidFloor is a struct that holds parameters for raising the floor of every impression in the rules engine.
+type RaiseBidFloor struct {
+	Args config.BidFloorParams
+}
+
+// Call adds a mutation that raises the bidfloor of the impressions whose floor is lower than the configured one.
+func (rbf *RaiseBidFloor) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	addBidFloorMutation(result, rbf.Args, true, meta)
+	return nil
+}
+
+func (rbf *RaiseBidFloor) Name() string {
+	return RaiseBidFloorName
+}
+
+func newBidFloorParams(params json.RawMessage, funcName string) (config.BidFloorParams, error) {
+	var bidFloorParams config.BidFloorParams
+	if err := jsonutil.Unmarshal(params, &bidFloorParams); err != nil {
+		return bidFloorParams, err
+	}
+
+	if bidFloorParams.BidFloor <= 0 {
+		return bidFloorParams, fmt.Errorf("%s requires a positive bidfloor to be specified", funcName)
+	}
+	if len(bidFloorParams.BidFloorCur) == 0 {
+		bidFloorParams.BidFloorCur = defaultBidFloorCur
+	}
+	return bidFloorParams, nil
+}
+
+func addBidFloorMutation(result *ProcessedAuctionHookResult, args config.BidFloorParams, raise bool, meta rules.ResultFunctionMeta) {
+	result.HookResult.ChangeSet.AddMutation(func(payload hs.ProcessedAuctionRequestPayload) (hs.ProcessedAuctionRequestPayload, error) {
+		if payload.Request == nil || payload.Request.BidRequest == nil {
+			return payload, errors.New("payload contains a nil bid request")
+		}
+		for _, imp := range payload.Request.GetImp() {
+			applyBidFloor(imp, args, raise)
+		}
+		return payload, nil
+	}, hs.MutationUpdate, "bidrequest", "imp", "bidfloor")
+
+	result.appendAnalyticsResult(hookanalytics.ResultStatusModify, bidFloorAnalyticsValues(meta, args))
+}
+
+func bidFloorAnalyticsValues(meta rules.ResultFunctionMeta, args config.BidFloorParams) map[string]interface{} {
+	values := analyticsValues(meta, args.AnalyticsValue)
+	values["bidfloor"] = args.BidFloor
+	values["bidfloorcur"] = args.BidFloorCur
+	return values
+}
+
+// applyBidFloor sets the floor of the impression to the configured one. When raising, the floor is only
+// updated if the impression floor is lower and expressed in the same currency since no conversion rates
+// are available at the processed auction request stage.
+func applyBidFloor(imp *openrtb_ext.ImpWrapper, args config.BidFloorParams, raise bool) {
+	if raise {
+		impCur := imp.BidFloorCur
+		if len(impCur) == 0 {
+			impCur = defaultBidFloorCur
+		}
+		if !strings.EqualFold(impCur, args.BidFloorCur) || imp.BidFloor >= args.BidFloor {
+			return
+		}
+	}
+	imp.BidFloor = args.BidFloor
+	imp.BidFloorCur = args.BidFloorCur
+}
+
+// NewCapTmax is a factory function that creates a new CapTmax result function.
+// It returns an error if the tmax is not a positive number.
+func NewCapTmax(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var capTmaxParams config.CapTmaxParams
+	if err := jsonutil.Unmarshal(params, &capTmaxParams); err != nil {
+		return nil, err
+	}
+
+	if capTmaxParams.Tmax <= 0 {
+		return nil, errors.New("capTmax requires a positive tmax to be specified")
+	}
+	return &CapTmax{Args: capTmaxParams}, nil
+}
+
+// CapTmax is a struct that holds parameters for capping the request tmax in the rules engine.
+type CapTmax struct {
+	Args config.CapTmaxParams
+}
+
+// Call adds a mutation that lowers the request tmax to the configured one if it is greater or not set.
+// The exchange tightens the auction deadline to the lowered tmax.
+func (ct *CapTmax) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	tmax := ct.Args.Tmax
+	result.HookResult.ChangeSet.AddMutation(func(payload hs.ProcessedAuctionRequestPayload) (hs.ProcessedAuctionRequestPayload, error) {
+		if payload.Request == nil || payload.Request.BidRequest == nil {
+			return payload, errors.New("payload contains a nil bid request")
+		}
+		if payload.Request.TMax <= 0 || payload.Request.TMax > tmax {
+			payload.Request.TMax = tmax
+		}
+		return payload, nil
+	}, hs.MutationUpdate, "bidrequest", "tmax")
+
+	values := analyticsValues(meta, ct.Args.AnalyticsValue)
+	values["tmax"] = tmax
+	result.appendAnalyticsResult(hookanalytics.ResultStatusModify, values)
+	return nil
+}
+
+func (ct *CapTmax) Name() string {
+	return CapTmaxName
+}
+
+// NewSetTargeting is a factory function that creates a new SetTargeting result function.
+// It returns an error if no targeting key is provided.
+func NewSetTargeting(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var setTargetingParams config.SetTargetingParams
+	if err := jsonutil.Unmarshal(params, &setTargetingParams); err != nil {
+		return nil, err
+	}
+
+	if len(setTargetingParams.Keys) == 0 {
+		return nil, errors.New("setTargeting requires at least one key to be specified")
+	}
+	for key := range setTargetingParams.Keys {
+		if len(key) == 0 {
+			return nil, errors.New("setTargeting keys must not be empty")
+		}
+	}
+	return &SetTargeting{Args: setTargetingParams}, nil
+}
+
+// SetTargeting is a struct that holds parameters for adding custom targeting keys in the rules engine.
+type SetTargeting struct {
+	Args config.SetTargetingParams
+}
+
+// Call adds a mutation that sets the configured keys as static ext.prebid.adservertargeting entries so they are
+// returned in the targeting of every bid, replacing any entry already using one of the keys.
+func (st *SetTargeting) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	keys := make([]string, 0, len(st.Args.Keys))
+	for key := range st.Args.Keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result.HookResult.ChangeSet.AddMutation(func(payload hs.ProcessedAuctionRequestPayload) (hs.ProcessedAuctionRequestPayload, error) {
+		prebid, reqExt, err := getRequestPrebid(payload)
+		if err != nil {
+			return payload, err
+		}
+
+		targeting := slices.DeleteFunc(slices.Clone(prebid.AdServerTargeting), func(t openrtb_ext.AdServerTarget) bool {
+			_, found := st.Args.Keys[t.Key]
+			return found
+		})
+		for _, key := range keys {
+			targeting = append(targeting, openrtb_ext.AdServerTarget{
+				Key:    key,
+				Source: string(adservertargeting.SourceStatic),
+				Value:  st.Args.Keys[key],
+			})
+		}
+		prebid.AdServerTargeting = targeting
+		reqExt.SetPrebid(prebid)
+		return payload, nil
+	}, hs.MutationUpdate, "bidrequest", "ext", "prebid", "adservertargeting")
+
+	values := analyticsValues(meta, st.Args.AnalyticsValue)
+	values["keys"] = keys
+	result.appendAnalyticsResult(hookanalytics.ResultStatusModify, values)
+	return nil
+}
+
+func (st *SetTargeting) Name() string {
+	return SetTargetingName
+}
+
+// NewSetBidderParams is a factory function that creates a new SetBidderParams result function.
+// It returns an error if no bidder params are provided or if the params of a bidder are not a JSON object.
+func NewSetBidderParams(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	var setBidderParamsParams config.SetBidderParamsParams
+	if err := jsonutil.Unmarshal(params, &setBidderParamsParams); err != nil {
+		return nil, err
+	}
+
+	if len(setBidderParamsParams.BidderParams) == 0 {
+		return nil, errors.New("setBidderParams requires the params of at least one bidder to be specified")
+	}
+	for bidder, bidderParams := range setBidderParamsParams.BidderParams {
+		var obj map[string]json.RawMessage
+		if err := jsonutil.Unmarshal(bidderParams, &obj); err != nil || obj == nil {
+			return nil, fmt.Errorf("setBidderParams params of bidder %s must be an object", bidder)
+		}
+	}
+	return &SetBidderParams{Args: setBidderParamsParams}, nil
+}
+
+// SetBidderParams is a struct that holds parameters for injecting request level bidder params in the rules engine.
+type SetBidderParams struct {
+	Args config.SetBidderParamsParams
+}
+
+// Call adds a mutation that merges the configured params of each bidder into ext.prebid.bidderparams
+// overriding the values the request already holds for the same fields.
+func (sbp *SetBidderParams) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	bidders := make([]string, 0, len(sbp.Args.BidderParams))
+	for bidder := range sbp.Args.BidderParams {
+		bidders = append(bidders, bidder)
+	}
+	sort.Strings(bidders)
+
+	result.HookResult.ChangeSet.AddMutation(func(payload hs.ProcessedAuctionRequestPayload) (hs.ProcessedAuctionRequestPayload, error) {
+		prebid, reqExt, err := getRequestPrebid(payload)
+		if err != nil {
+			return payload, err
+		}
+
+		bidderParams := make(map[string]json.RawMessage)
+		if len(prebid.BidderParams) > 0 {
+			if err := jsonutil.Unmarshal(prebid.BidderParams, &bidderParams); err != nil {
+				return payload, err
+			}
+		}
+		for _, bidder := range bidders {
+			merged := sbp.Args.BidderParams[bidder]
+			if existing, found := bidderParams[bidder]; found {
+				if merged, err = jsonpatch.MergePatch(existing, merged); err != nil {
+					return payload, err
+				}
+			}
+			bidderParams[bidder] = merged
+		}
+
+		if prebid.BidderParams, err = jsonutil.Marshal(bidderParams); err != nil {
+			return payload, err
+		}
+		reqExt.SetPrebid(prebid)
+		return payload, nil
+	}, hs.MutationUpdate, "bidrequest", "ext", "prebid", "bidderparams")
+
+	values := analyticsValues(meta, sbp.Args.AnalyticsValue)
+	values["bidders"] = bidders
+	result.appendAnalyticsResult(hookanalytics.ResultStatusModify, values)
+	return nil
+}
+
+func (sbp *SetBidderParams) Name() string {
+	return SetBidderParamsName
+}
+
+// NewLogATag is a factory function that creates a new LogATag result function.
+// It returns an error if no analytics value is provided.
+func NewLogATag(params json.RawMessage) (ProcessedAuctionResultFunc, error) {
+	logATagParams, err := newLogATagParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return &LogATag{Args: logATagParams}, nil
+}
+
+// LogATag is a struct that holds parameters for tagging requests in the rules engine.
+type LogATag struct {
+	Args config.LogATagParams
+}
+
+// Call reports the configured analytics value through the hook analytics tags without altering the request.
+func (lat *LogATag) Call(req *openrtb_ext.RequestWrapper, result *ProcessedAuctionHookResult, meta rules.ResultFunctionMeta) error {
+	result.appendAnalyticsResult(hookanalytics.ResultStatusAllow, analyticsValues(meta, lat.Args.AnalyticsValue))
+	return nil
+}
+
+func (lat *LogATag) Name() string {
+	return LogATagName
+}
+
+func newLogATagParams(params json.RawMessage) (config.LogATagParams, error) {
+	var logATagParams config.LogATagParams
+	if err := jsonutil.Unmarshal(params, &logATagParams); err != nil {
+		return logATagParams, err
+	}
+
+	if len(logATagParams.AnalyticsValue) == 0 {
+		return logATagParams, errors.New("logATag requires an analytics value to be specified")
+	}
+	return logATagParams, nil
+}
+
+// getRequestPrebid returns the ext.prebid object of the payload request, which is never nil, along with
+// the request ext it should be set back to once modified
+func getRequestPrebid(payload hs.ProcessedAuctionRequestPayload) (*openrtb_ext.ExtRequestPrebid, *openrtb_ext.RequestExt, error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return nil, nil, errors.New("payload contains a nil bid request")
+	}
+
+	reqExt, err := payload.Request.GetRequestExt()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prebid := reqExt.GetPrebid()
+	if prebid == nil {
+		prebid = &openrtb_ext.ExtRequestPrebid{}
+	}
+	return prebid, reqExt, nil
+}
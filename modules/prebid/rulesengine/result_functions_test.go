@@ -5,11 +5,13 @@ import (
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
 	hs "github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/rules"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewProcessedAuctionRequestResultFunction(t *testing.T) {
@@ -46,6 +48,84 @@ func TestNewProcessedAuctionRequestResultFunction(t *testing.T) {
 			params:    json.RawMessage(`{"bidders":null}`),
 			expectErr: true,
 		},
+		{
+			name:       "valid_setBidFloor",
+			funcName:   SetBidFloorName,
+			params:     json.RawMessage(`{"bidfloor":1.5}`),
+			expectType: &SetBidFloor{},
+		},
+		{
+			name:      "setBidFloor_non_positive_floor",
+			funcName:  SetBidFloorName,
+			params:    json.RawMessage(`{"bidfloor":0}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_raiseBidFloor",
+			funcName:   RaiseBidFloorName,
+			params:     json.RawMessage(`{"bidfloor":1.5,"bidfloorcur":"EUR"}`),
+			expectType: &RaiseBidFloor{},
+		},
+		{
+			name:       "valid_capTmax",
+			funcName:   CapTmaxName,
+			params:     json.RawMessage(`{"tmax":500}`),
+			expectType: &CapTmax{},
+		},
+		{
+			name:      "capTmax_missing_tmax",
+			funcName:  CapTmaxName,
+			params:    json.RawMessage(`{}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_setTargeting",
+			funcName:   SetTargetingName,
+			params:     json.RawMessage(`{"keys":{"hb_segment":"sports"}}`),
+			expectType: &SetTargeting{},
+		},
+		{
+			name:      "setTargeting_no_keys",
+			funcName:  SetTargetingName,
+			params:    json.RawMessage(`{"keys":{}}`),
+			expectErr: true,
+		},
+		{
+			name:      "setTargeting_empty_key",
+			funcName:  SetTargetingName,
+			params:    json.RawMessage(`{"keys":{"":"sports"}}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_setBidderParams",
+			funcName:   SetBidderParamsName,
+			params:     json.RawMessage(`{"bidderparams":{"bidder1":{"placement":"p1"}}}`),
+			expectType: &SetBidderParams{},
+		},
+		{
+			name:      "setBidderParams_no_bidders",
+			funcName:  SetBidderParamsName,
+			params:    json.RawMessage(`{}`),
+			expectErr: true,
+		},
+		{
+			name:      "setBidderParams_params_not_an_object",
+			funcName:  SetBidderParamsName,
+			params:    json.RawMessage(`{"bidderparams":{"bidder1":"p1"}}`),
+			expectErr: true,
+		},
+		{
+			name:       "valid_logATag",
+			funcName:   LogATagName,
+			params:     json.RawMessage(`{"analyticsvalue":"tagged"}`),
+			expectType: &LogATag{},
+		},
+		{
+			name:      "logATag_missing_analytics_value",
+			funcName:  LogATagName,
+			params:    json.RawMessage(`{}`),
+			expectErr: true,
+		},
 		{
 			name:      "invalid_function_name",
 			funcName:  "invalidFunction",
@@ -207,3 +287,157 @@ func mockRequestWrapperWithBidders(t *testing.T, bidders []string) *openrtb_ext.
 
 	return rw
 }
+
+// applyResultFunction runs the result function against the request and applies the resulting mutations to it
+func applyResultFunction(t *testing.T, f ProcessedAuctionResultFunc, req *openrtb_ext.RequestWrapper) ProcessedAuctionHookResult {
+	result := ProcessedAuctionHookResult{
+		HookResult:     hs.HookResult[hs.ProcessedAuctionRequestPayload]{},
+		AllowedBidders: make(map[string]struct{}),
+	}
+	require.NoError(t, f.Call(req, &result, rules.ResultFunctionMeta{AnalyticsKey: "key", ModelVersion: "1.0", RuleFired: "rule"}))
+
+	payload := hs.ProcessedAuctionRequestPayload{Request: req}
+	for _, mut := range result.HookResult.ChangeSet.Mutations() {
+		_, err := mut.Apply(payload)
+		require.NoError(t, err)
+	}
+	require.NoError(t, req.RebuildRequest())
+	return result
+}
+
+func TestBidFloorCall(t *testing.T) {
+	tests := []struct {
+		name          string
+		resultFunc    ProcessedAuctionResultFunc
+		imps          []openrtb2.Imp
+		expectedFloor []float64
+		expectedCur   []string
+	}{
+		{
+			name:       "set_overrides_every_floor",
+			resultFunc: &SetBidFloor{Args: config.BidFloorParams{BidFloor: 1, BidFloorCur: "USD"}},
+			imps: []openrtb2.Imp{
+				{ID: "imp1", BidFloor: 2, BidFloorCur: "EUR"},
+				{ID: "imp2"},
+			},
+			expectedFloor: []float64{1, 1},
+			expectedCur:   []string{"USD", "USD"},
+		},
+		{
+			name:       "raise_only_lower_floors_in_the_same_currency",
+			resultFunc: &RaiseBidFloor{Args: config.BidFloorParams{BidFloor: 1, BidFloorCur: "USD"}},
+			imps: []openrtb2.Imp{
+				{ID: "imp1", BidFloor: 0.5},
+				{ID: "imp2", BidFloor: 2, BidFloorCur: "USD"},
+				{ID: "imp3", BidFloor: 0.5, BidFloorCur: "EUR"},
+			},
+			expectedFloor: []float64{1, 2, 0.5},
+			expectedCur:   []string{"USD", "USD", "EUR"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: tt.imps}}
+
+			result := applyResultFunction(t, tt.resultFunc, req)
+
+			for i, imp := range req.Imp {
+				assert.Equal(t, tt.expectedFloor[i], imp.BidFloor, imp.ID)
+				assert.Equal(t, tt.expectedCur[i], imp.BidFloorCur, imp.ID)
+			}
+			require.Len(t, result.AnalyticsResults, 1)
+			assert.Equal(t, hookanalytics.ResultStatusModify, result.AnalyticsResults[0].Status)
+		})
+	}
+}
+
+func TestCapTmaxCall(t *testing.T) {
+	tests := []struct {
+		name         string
+		tmax         int64
+		expectedTmax int64
+	}{
+		{name: "tmax_above_cap", tmax: 1500, expectedTmax: 800},
+		{name: "tmax_below_cap", tmax: 500, expectedTmax: 500},
+		{name: "tmax_not_set", tmax: 0, expectedTmax: 800},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{TMax: tt.tmax}}
+
+			applyResultFunction(t, &CapTmax{Args: config.CapTmaxParams{Tmax: 800}}, req)
+
+			assert.Equal(t, tt.expectedTmax, req.TMax)
+		})
+	}
+}
+
+func TestSetTargetingCall(t *testing.T) {
+	req := &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			Ext: json.RawMessage(`{"prebid":{"adservertargeting":[{"key":"hb_segment","source":"static","value":"news"},{"key":"hb_other","source":"bidrequest","value":"site.id"}]}}`),
+		},
+	}
+	st := &SetTargeting{Args: config.SetTargetingParams{Keys: map[string]string{"hb_segment": "sports", "hb_tier": "gold"}}}
+
+	result := applyResultFunction(t, st, req)
+
+	assert.JSONEq(t, `{"prebid":{"adservertargeting":[
+		{"key":"hb_other","source":"bidrequest","value":"site.id"},
+		{"key":"hb_segment","source":"static","value":"sports"},
+		{"key":"hb_tier","source":"static","value":"gold"}
+	]}}`, string(req.Ext))
+	require.Len(t, result.AnalyticsResults, 1)
+	assert.Equal(t, []string{"hb_segment", "hb_tier"}, result.AnalyticsResults[0].Values["keys"])
+}
+
+func TestSetBidderParamsCall(t *testing.T) {
+	req := &openrtb_ext.RequestWrapper{
+		BidRequest: &openrtb2.BidRequest{
+			Ext: json.RawMessage(`{"prebid":{"bidderparams":{"bidder1":{"placement":"p1","zone":"z1"},"bidder2":{"site":"s2"}}}}`),
+		},
+	}
+	sbp := &SetBidderParams{Args: config.SetBidderParamsParams{BidderParams: map[string]json.RawMessage{
+		"bidder1": json.RawMessage(`{"placement":"p2"}`),
+		"bidder3": json.RawMessage(`{"account":"a3"}`),
+	}}}
+
+	applyResultFunction(t, sbp, req)
+
+	assert.JSONEq(t, `{"prebid":{"bidderparams":{
+		"bidder1":{"placement":"p2","zone":"z1"},
+		"bidder2":{"site":"s2"},
+		"bidder3":{"account":"a3"}
+	}}}`, string(req.Ext))
+}
+
+func TestLogATagCall(t *testing.T) {
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}
+
+	result := applyResultFunction(t, &LogATag{Args: config.LogATagParams{AnalyticsValue: "tagged"}}, req)
+
+	assert.Empty(t, result.HookResult.ChangeSet.Mutations())
+	expected := []hookanalytics.Result{
+		{
+			Status: hookanalytics.ResultStatusAllow,
+			Values: map[string]interface{}{
+				"analyticskey":   "key",
+				"modelversion":   "1.0",
+				"rulefired":      "rule",
+				"analyticsvalue": "tagged",
+			},
+		},
+	}
+	assert.Equal(t, expected, result.AnalyticsResults)
+}
+
+func TestNewResultFunctionsName(t *testing.T) {
+	assert.Equal(t, SetBidFloorName, (&SetBidFloor{}).Name())
+	assert.Equal(t, RaiseBidFloorName, (&RaiseBidFloor{}).Name())
+	assert.Equal(t, CapTmaxName, (&CapTmax{}).Name())
+	assert.Equal(t, SetTargetingName, (&SetTargeting{}).Name())
+	assert.Equal(t, SetBidderParamsName, (&SetBidderParams{}).Name())
+	assert.Equal(t, LogATagName, (&LogATag{}).Name())
+}
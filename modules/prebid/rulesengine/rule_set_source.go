@@ -0,0 +1,355 @@
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	defaultSourceRefreshRateSeconds = 60
+	defaultSourceTimeoutMs          = 3000
+)
+
+// ruleSetSources holds the rule set source watched for each account whose configuration references one
+type ruleSetSources struct {
+	sync.Mutex
+	m map[accountID]*ruleSetSource
+}
+
+// replace stores the source watched for the account, stopping and returning the one previously watched
+func (ss *ruleSetSources) replace(id accountID, s *ruleSetSource) *ruleSetSource {
+	ss.Lock()
+	defer ss.Unlock()
+
+	if ss.m == nil {
+		ss.m = make(map[accountID]*ruleSetSource)
+	}
+	prev := ss.m[id]
+	if prev != nil {
+		prev.stop()
+	}
+	ss.m[id] = s
+	return prev
+}
+
+// stop stops watching the source of the account if any
+func (ss *ruleSetSources) stop(id accountID) {
+	ss.Lock()
+	defer ss.Unlock()
+
+	if s, exists := ss.m[id]; exists {
+		s.stop()
+		delete(ss.m, id)
+	}
+}
+
+// stopAll stops watching the sources of all the accounts
+func (ss *ruleSetSources) stopAll() {
+	ss.Lock()
+	defer ss.Unlock()
+
+	for id, s := range ss.m {
+		s.stop()
+		delete(ss.m, id)
+	}
+}
+
+// sourceVersion identifies the source contents last fetched
+type sourceVersion struct {
+	etag       string
+	modTime    time.Time
+	size       int64
+	hashedBody hash
+}
+
+// sourceContents is the JSON object a rule set source is expected to hold
+type sourceContents struct {
+	RuleSets json.RawMessage `json:"rulesets"`
+}
+
+// ruleSetSource loads the rule sets of an account from the local file or URL set in the account configuration
+// and checks the source for changes periodically. Files are checked for a different modification time or size
+// while URLs are requested with the ETag of the last response. Rule sets that changed replace the account cache
+// entry only if they are valid and the trees of all of them are built, otherwise the previous trees are kept.
+type ruleSetSource struct {
+	sync.Mutex
+	accountID       accountID
+	cfg             config.RuleSetSource
+	accountConfig   json.RawMessage
+	cache           cacher
+	httpClient      *http.Client
+	schemaValidator *gojsonschema.Schema
+	sourceAllowlist config.SourceAllowlist
+	monitor         RulesEngineObserver
+	metrics         *moduleMetrics
+
+	version  sourceVersion
+	ruleSets json.RawMessage
+	stopped  bool
+	done     chan struct{}
+}
+
+func newRuleSetSource(tm *treeManager, c cacher, id accountID, cfg config.RuleSetSource, accountConfig json.RawMessage) *ruleSetSource {
+	if cfg.RefreshRateSeconds <= 0 {
+		cfg.RefreshRateSeconds = defaultSourceRefreshRateSeconds
+	}
+	if cfg.TimeoutMs <= 0 {
+		cfg.TimeoutMs = defaultSourceTimeoutMs
+	}
+
+	httpClient := tm.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &ruleSetSource{
+		accountID:       id,
+		cfg:             cfg,
+		accountConfig:   accountConfig,
+		cache:           c,
+		httpClient:      httpClient,
+		schemaValidator: tm.schemaValidator,
+		sourceAllowlist: tm.sourceAllowlist,
+		monitor:         tm.monitor,
+		metrics:         tm.metrics,
+		done:            make(chan struct{}),
+	}
+}
+
+// run loads the rule sets from the source and reloads them every time the refresh period elapses until
+// the source is stopped
+func (s *ruleSetSource) run() {
+	s.reload()
+
+	ticker := time.NewTicker(time.Duration(s.cfg.RefreshRateSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.reload()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// stop keeps the source from updating the account cache entry any further
+func (s *ruleSetSource) stop() {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.stopped {
+		s.stopped = true
+		close(s.done)
+	}
+}
+
+// carryOver takes the rule sets last loaded by the previous source of the account if it is watching the
+// same location so they are not lost while the source is replaced following an account configuration change
+func (s *ruleSetSource) carryOver(prev *ruleSetSource) {
+	if prev == nil {
+		return
+	}
+
+	prev.Lock()
+	defer prev.Unlock()
+
+	if prev.cfg.Path != s.cfg.Path || prev.cfg.URL != s.cfg.URL {
+		return
+	}
+	s.version = prev.version
+	s.ruleSets = prev.ruleSets
+}
+
+// initialCacheEntry returns the cache entry serving requests until the rule sets are loaded from the source.
+// Rule sets carried over from a previous source are used if they are valid along with the current account
+// configuration, the rule sets of the account configuration are used otherwise.
+func (s *ruleSetSource) initialCacheEntry(cfg *config.PbRulesEngine) (cacheEntry, error) {
+	if len(s.ruleSets) > 0 {
+		entry, err := s.newCacheEntry(s.ruleSets)
+		if err == nil {
+			return entry, nil
+		}
+		s.monitor.logError(fmt.Sprintf("Rules engine error building rule sets from source for account %s: %v", s.accountID, err))
+		s.version = sourceVersion{}
+		s.ruleSets = nil
+	}
+	return NewCacheEntry(cfg, &s.accountConfig)
+}
+
+// reload fetches the source and swaps the account cache entry for one holding the trees of the source
+// rule sets if they changed and are valid
+func (s *ruleSetSource) reload() {
+	s.Lock()
+	version := s.version
+	s.Unlock()
+
+	body, version, err := s.fetch(version)
+	if err != nil {
+		s.fail(fmt.Sprintf("Rules engine error fetching rule sets from source for account %s: %v", s.accountID, err))
+		return
+	}
+	if body == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stopped {
+		return
+	}
+	// sources that do not support conditional requests or are rewritten with the same contents are
+	// considered unchanged if the contents hash is the same
+	version.hashedBody = hashConfig((*json.RawMessage)(&body))
+	unchanged := version.hashedBody == s.version.hashedBody
+	// the version is kept even if the contents are invalid so they are not validated again until they change
+	s.version = version
+	if unchanged {
+		return
+	}
+
+	ruleSets, err := parseSourceRuleSets(body)
+	if err != nil {
+		s.fail(fmt.Sprintf("Rules engine error parsing rule sets from source for account %s: %v", s.accountID, err))
+		return
+	}
+
+	entry, err := s.newCacheEntry(ruleSets)
+	if err != nil {
+		s.fail(fmt.Sprintf("Rules engine error building rule sets from source for account %s: %v", s.accountID, err))
+		return
+	}
+
+	s.ruleSets = ruleSets
+	s.cache.Set(s.accountID, &entry)
+	s.metrics.recordConfigReload(true)
+	s.monitor.logInfo(fmt.Sprintf("Rules engine rule sets reloaded from source for account %s", s.accountID))
+}
+
+// load fetches the source and returns its rule sets without updating the account cache entry
+func (s *ruleSetSource) load() (json.RawMessage, error) {
+	body, _, err := s.fetch(sourceVersion{})
+	if err != nil {
+		return nil, err
+	}
+	return parseSourceRuleSets(body)
+}
+
+func parseSourceRuleSets(body []byte) (json.RawMessage, error) {
+	var contents sourceContents
+	if err := jsonutil.UnmarshalValid(body, &contents); err != nil {
+		return nil, err
+	}
+	if len(contents.RuleSets) == 0 {
+		return nil, errors.New("rulesets not found")
+	}
+	return contents.RuleSets, nil
+}
+
+func (s *ruleSetSource) fail(msg string) {
+	s.metrics.recordConfigReload(false)
+	s.monitor.logError(msg)
+}
+
+// newCacheEntry builds the trees of the account configuration with its rule sets replaced by the given ones.
+// Unlike building the trees of the account configuration, it fails if the trees of any rule set cannot be built.
+func (s *ruleSetSource) newCacheEntry(ruleSets json.RawMessage) (cacheEntry, error) {
+	var merged map[string]json.RawMessage
+	if err := jsonutil.Unmarshal(s.accountConfig, &merged); err != nil {
+		return cacheEntry{}, err
+	}
+	merged["rulesets"] = ruleSets
+
+	mergedConfig, err := jsonutil.Marshal(merged)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+
+	cfg, err := config.NewConfig(mergedConfig, s.schemaValidator, s.sourceAllowlist)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+
+	entry, ruleSetErrs, err := buildCacheEntry(cfg, &s.accountConfig)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	if len(ruleSetErrs) > 0 {
+		return cacheEntry{}, errors.Join(ruleSetErrs...)
+	}
+	return entry, nil
+}
+
+// fetch returns the source contents along with their version, or nil contents if the source did not change
+// since the given version was fetched
+func (s *ruleSetSource) fetch(version sourceVersion) ([]byte, sourceVersion, error) {
+	if len(s.cfg.Path) > 0 {
+		return s.fetchFile(version)
+	}
+	return s.fetchURL(version)
+}
+
+func (s *ruleSetSource) fetchFile(version sourceVersion) ([]byte, sourceVersion, error) {
+	info, err := os.Stat(s.cfg.Path)
+	if err != nil {
+		return nil, version, err
+	}
+	if info.ModTime().Equal(version.modTime) && info.Size() == version.size {
+		return nil, version, nil
+	}
+
+	body, err := os.ReadFile(s.cfg.Path)
+	if err != nil {
+		return nil, version, err
+	}
+	return body, sourceVersion{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+func (s *ruleSetSource) fetchURL(version sourceVersion) ([]byte, sourceVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.TimeoutMs)*time.Millisecond)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.URL, nil)
+	if err != nil {
+		return nil, version, err
+	}
+	if len(version.etag) > 0 {
+		httpReq.Header.Set("If-None-Match", version.etag)
+	}
+
+	httpResp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, version, err
+	}
+	defer func() {
+		// read the entire response body to ensure full connection reuse
+		io.Copy(io.Discard, httpResp.Body)
+		httpResp.Body.Close()
+	}()
+
+	if httpResp.StatusCode == http.StatusNotModified {
+		return nil, version, nil
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, version, fmt.Errorf("unexpected response status %d", httpResp.StatusCode)
+	}
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, version, err
+	}
+	return body, sourceVersion{etag: httpResp.Header.Get("ETag")}, nil
+}
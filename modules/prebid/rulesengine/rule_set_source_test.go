@@ -0,0 +1,258 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sourceRuleSets = `{
+  "rulesets": [
+    {
+      "stage": "processed_auction_request",
+      "name": "exclude-in-north-america",
+      "modelgroups": [
+        {
+          "schema": [{"function": "deviceCountry"}],
+          "rules": [
+            {
+              "conditions": ["in:USA,CAN"],
+              "results": [{"function": "excludeBidders", "args": {"bidders": ["bidderA"]}}]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+// sourceRuleSetsWithInvalidTree passes schema validation but its tree cannot be built
+const sourceRuleSetsWithInvalidTree = `{
+  "rulesets": [
+    {
+      "stage": "processed_auction_request",
+      "name": "invalid-range",
+      "modelgroups": [
+        {
+          "schema": [{"function": "impBidFloor"}],
+          "rules": [
+            {
+              "conditions": ["range:5,1"],
+              "results": [{"function": "excludeBidders", "args": {"bidders": ["bidderA"]}}]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+func newSourceTreeManager(t *testing.T, me metrics.MetricsEngine, allowlist config.SourceAllowlist) *treeManager {
+	validator, err := config.CreateSchemaValidator("config/" + config.RulesEngineSchemaFile)
+	require.NoError(t, err)
+
+	return &treeManager{
+		done:            make(chan struct{}),
+		requests:        make(chan buildInstruction),
+		schemaValidator: validator,
+		monitor:         &discardLogger{},
+		metrics:         &moduleMetrics{engine: moduledeps.NewMetricsEngineRef(me)},
+		sourceAllowlist: allowlist,
+	}
+}
+
+func newConfigReloadMetricsMock() *metrics.MetricsEngineMock {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordModuleConfigReload", metrics.ModuleConfigReloadLabels{Module: "prebid_rulesengine", Success: true}).Return()
+	me.On("RecordModuleConfigReload", metrics.ModuleConfigReloadLabels{Module: "prebid_rulesengine", Success: false}).Return()
+	return me
+}
+
+func writeSourceFile(t *testing.T, path, contents string) {
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	// make sure the modification time differs from the one of the previous write
+	modTime := time.Now().Add(time.Duration(len(contents)) * time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestRuleSetSourceReloadFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	writeSourceFile(t, path, sourceRuleSets)
+
+	me := newConfigReloadMetricsMock()
+	c := NewCache(0)
+	accountConfig := json.RawMessage(`{"enabled": true, "source": {"path": "` + path + `"}}`)
+	s := newRuleSetSource(newSourceTreeManager(t, me, config.SourceAllowlist{Paths: []string{dir}}), c, "account-id", config.RuleSetSource{Path: path}, accountConfig)
+
+	s.reload()
+	loaded := c.Get("account-id")
+	require.NotNil(t, loaded)
+	assert.Len(t, loaded.ruleSetsForProcessedAuctionRequestStage, 1)
+	assert.Equal(t, hashConfig(&accountConfig), loaded.hashedConfig)
+
+	s.reload()
+	assert.Same(t, loaded, c.Get("account-id"), "unchanged file is not reloaded")
+
+	writeSourceFile(t, path, sourceRuleSetsWithInvalidTree)
+	s.reload()
+	assert.Same(t, loaded, c.Get("account-id"), "previous trees are kept when the reloaded rule sets are invalid")
+
+	os.Remove(path)
+	s.reload()
+	assert.Same(t, loaded, c.Get("account-id"), "previous trees are kept when the file cannot be read")
+
+	me.AssertNumberOfCalls(t, "RecordModuleConfigReload", 3)
+	me.AssertCalled(t, "RecordModuleConfigReload", metrics.ModuleConfigReloadLabels{Module: "prebid_rulesengine", Success: true})
+	me.AssertCalled(t, "RecordModuleConfigReload", metrics.ModuleConfigReloadLabels{Module: "prebid_rulesengine", Success: false})
+}
+
+func TestRuleSetSourceReloadFromURL(t *testing.T) {
+	var mu sync.Mutex
+	etag, contents := `"v1"`, sourceRuleSets
+	var ifNoneMatch []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(contents))
+	}))
+	defer server.Close()
+
+	me := newConfigReloadMetricsMock()
+	c := NewCache(0)
+	accountConfig := json.RawMessage(`{"enabled": true, "source": {"url": "` + server.URL + `"}}`)
+	s := newRuleSetSource(newSourceTreeManager(t, me, config.SourceAllowlist{URLPrefixes: []string{server.URL}}), c, "account-id", config.RuleSetSource{URL: server.URL}, accountConfig)
+
+	s.reload()
+	loaded := c.Get("account-id")
+	require.NotNil(t, loaded)
+	assert.Len(t, loaded.ruleSetsForProcessedAuctionRequestStage, 1)
+
+	s.reload()
+	assert.Same(t, loaded, c.Get("account-id"), "not modified rule sets are not reloaded")
+
+	mu.Lock()
+	etag, contents = `"v2"`, `{"rulesets": []}`
+	mu.Unlock()
+	s.reload()
+	assert.Same(t, loaded, c.Get("account-id"), "previous trees are kept when the reloaded rule sets are invalid")
+
+	mu.Lock()
+	etag, contents = `"v3"`, sourceRuleSets
+	mu.Unlock()
+	s.reload()
+	assert.NotSame(t, loaded, c.Get("account-id"))
+
+	assert.Equal(t, []string{"", `"v1"`, `"v1"`, `"v2"`}, ifNoneMatch)
+	me.AssertNumberOfCalls(t, "RecordModuleConfigReload", 3)
+}
+
+func TestRuleSetSourceReloadUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	me := newConfigReloadMetricsMock()
+	c := NewCache(0)
+	s := newRuleSetSource(newSourceTreeManager(t, me, config.SourceAllowlist{URLPrefixes: []string{server.URL}}), c, "account-id", config.RuleSetSource{URL: server.URL}, json.RawMessage(`{"enabled": true}`))
+
+	s.reload()
+
+	assert.Nil(t, c.Get("account-id"))
+	me.AssertCalled(t, "RecordModuleConfigReload", metrics.ModuleConfigReloadLabels{Module: "prebid_rulesengine", Success: false})
+}
+
+func TestRuleSetSourceStopped(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	writeSourceFile(t, path, sourceRuleSets)
+
+	c := NewCache(0)
+	s := newRuleSetSource(newSourceTreeManager(t, nil, config.SourceAllowlist{Paths: []string{dir}}), c, "account-id", config.RuleSetSource{Path: path}, json.RawMessage(`{"enabled": true}`))
+
+	s.stop()
+	s.reload()
+
+	assert.Nil(t, c.Get("account-id"))
+	assert.NotPanics(t, s.stop)
+}
+
+func TestTreeManagerBuildFromSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	writeSourceFile(t, path, sourceRuleSets)
+
+	tm := newSourceTreeManager(t, nil, config.SourceAllowlist{Paths: []string{dir}})
+	// cache entries are always expired so trees are rebuilt whenever the account configuration changes
+	c := NewCache(1)
+	c.t = expiredTimeUtil{}
+	defer tm.sources.stopAll()
+
+	accountConfig := json.RawMessage(`{"enabled": true, "source": {"path": "` + path + `"}}`)
+	co, err := tm.build(c, buildInstruction{accountID: "account-id", config: &accountConfig})
+	require.NoError(t, err)
+	require.NotNil(t, co)
+	assert.Empty(t, co.ruleSetsForProcessedAuctionRequestStage, "account configuration rule sets serve until the source is loaded")
+
+	assert.Eventually(t, func() bool {
+		co := c.Get("account-id")
+		return co != nil && len(co.ruleSetsForProcessedAuctionRequestStage) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// the rule sets loaded by the previous source are carried over when the account configuration changes
+	updatedConfig := json.RawMessage(`{"enabled": true, "stickyby": "user.id", "source": {"path": "` + path + `"}}`)
+	co, err = tm.build(c, buildInstruction{accountID: "account-id", config: &updatedConfig})
+	require.NoError(t, err)
+	assert.Len(t, co.ruleSetsForProcessedAuctionRequestStage, 1)
+	assert.Equal(t, config.StickyByUserID, co.stickyBy)
+
+	// the source is no longer watched once the rules engine is disabled
+	disabledConfig := json.RawMessage(`{"enabled": false, "source": {"path": "` + path + `"}}`)
+	co, err = tm.build(c, buildInstruction{accountID: "account-id", config: &disabledConfig})
+	require.NoError(t, err)
+	assert.Nil(t, co)
+	assert.Nil(t, c.Get("account-id"))
+	assert.Empty(t, tm.sources.m)
+}
+
+func TestTreeManagerBuildFromSourceNotAllowed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	writeSourceFile(t, path, sourceRuleSets)
+
+	tm := newSourceTreeManager(t, nil, config.SourceAllowlist{Paths: []string{"/etc/rules"}})
+	c := NewCache(0)
+
+	accountConfig := json.RawMessage(`{"enabled": true, "source": {"path": "` + path + `"}}`)
+	co, err := tm.build(c, buildInstruction{accountID: "account-id", config: &accountConfig})
+
+	assert.EqualError(t, err, "source path "+path+" is not allowed by the host configuration")
+	assert.Nil(t, co)
+	assert.Nil(t, c.Get("account-id"))
+	assert.Empty(t, tm.sources.m)
+}
+
+type expiredTimeUtil struct{}
+
+func (mt expiredTimeUtil) Now() time.Time {
+	return time.Now().Add(time.Hour)
+}
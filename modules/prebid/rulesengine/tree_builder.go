@@ -1,6 +1,8 @@
 package rulesengine
 
 import (
+	"fmt"
+
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/prebid/prebid-server/v3/rules"
 )
@@ -25,8 +27,11 @@ type treeBuilder[T1 any, T2 any] struct {
 // The function uses the provided schema and result function factories to create
 // the appropriate functions for each node in the tree.
 // Build function assumes the config is valid and the number of schema functions matches the number of conditions.
+// Pattern conditions (set, range and regex) are validated while building and a malformed one fails the build.
 func (tb *treeBuilder[T1, T2]) Build(tree *rules.Tree[T1, T2]) error {
 	currNode := tree.Root
+	tree.AnalyticsKey = tb.Config.AnalyticsKey
+	tree.ModelVersion = tb.Config.Version
 
 	defaultFunctions, err := tb.buildDefaultFunctions()
 	if err != nil {
@@ -34,11 +39,10 @@ func (tb *treeBuilder[T1, T2]) Build(tree *rules.Tree[T1, T2]) error {
 	}
 	tree.DefaultFunctions = defaultFunctions
 
-	for _, rule := range tb.Config.Rules {
+	for ri, rule := range tb.Config.Rules {
 		for ci, condition := range rule.Conditions {
 
-			if len(currNode.Children) == 0 {
-				currNode.Children = make(map[string]*rules.Node[T1, T2], 0)
+			if currNode.SchemaFunction == nil {
 				f, err := tb.SchemaFuncFactory(tb.Config.Schema[ci].Func, tb.Config.Schema[ci].Args)
 				if err != nil {
 					return err
@@ -46,11 +50,11 @@ func (tb *treeBuilder[T1, T2]) Build(tree *rules.Tree[T1, T2]) error {
 				currNode.SchemaFunction = f
 			}
 
-			_, ok := currNode.Children[condition]
-			if !ok {
-				currNode.Children[condition] = &rules.Node[T1, T2]{}
+			child, err := currNode.AddChild(condition)
+			if err != nil {
+				return fmt.Errorf("rule %d condition %d: %w", ri, ci, err)
 			}
-			currNode = currNode.Children[condition]
+			currNode = child
 		}
 
 		for _, res := range rule.Results {
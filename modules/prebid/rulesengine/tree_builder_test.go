@@ -80,6 +80,59 @@ func TestBuildTree(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "Malformed range condition",
+			modelGroup: config.ModelGroup{
+				Schema: []config.Schema{
+					{
+						Func: rules.Channel,
+					},
+				},
+				Rules: []config.Rule{
+					{
+						Conditions: []string{"range:500,0"},
+						Results: []config.Result{
+							{
+								Func: "excludeBidders",
+								Args: json.RawMessage(`{"bidders":["bidderA"]}`),
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Pattern conditions",
+			modelGroup: config.ModelGroup{
+				Schema: []config.Schema{
+					{
+						Func: rules.DeviceCountry,
+					},
+				},
+				Rules: []config.Rule{
+					{
+						Conditions: []string{"in:USA,CAN"},
+						Results: []config.Result{
+							{
+								Func: "excludeBidders",
+								Args: json.RawMessage(`{"bidders":["bidderA"]}`),
+							},
+						},
+					},
+					{
+						Conditions: []string{"regex:^U"},
+						Results: []config.Result{
+							{
+								Func: "excludeBidders",
+								Args: json.RawMessage(`{"bidders":["bidderB"]}`),
+							},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
 	}
 
 	for _, tt := range tests {
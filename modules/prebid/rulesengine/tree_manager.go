@@ -3,6 +3,7 @@ package rulesengine
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/prebid/prebid-server/v3/modules/prebid/rulesengine/config"
 	"github.com/xeipuuv/gojsonschema"
@@ -20,6 +21,10 @@ type treeManager struct {
 	requests        chan buildInstruction
 	schemaValidator *gojsonschema.Schema
 	monitor         RulesEngineObserver
+	httpClient      *http.Client
+	metrics         *moduleMetrics
+	sources         ruleSetSources
+	sourceAllowlist config.SourceAllowlist
 }
 
 // Run reads build instructions from a channel, and if the trees for the rule sets for a given account
@@ -28,41 +33,93 @@ func (tm *treeManager) Run(c cacher) error {
 	for {
 		select {
 		case req := <-tm.requests:
-			if req.config == nil {
-				break
-			}
-
-			cacheObj := c.Get(req.accountID)
-			if cacheObj != nil && !rebuildTrees(cacheObj, req.config, c) {
-				break
-			}
-
-			parsedCfg, err := config.NewConfig(*req.config, tm.schemaValidator)
-			if err != nil {
-				tm.monitor.logError(fmt.Sprintf("Rules engine error parsing config for account %s: %v", req.accountID, err))
-				break
-			}
-			if !parsedCfg.Enabled {
-				c.Delete(req.accountID)
-				tm.monitor.logInfo(fmt.Sprintf("Rules engine disabled for account %s", req.accountID))
-				break
-			}
-
-			newCacheObj, err := NewCacheEntry(parsedCfg, req.config)
-			if err != nil {
-				tm.monitor.logError(fmt.Sprintf("Rules engine error creating cache entry for account %s: %v", req.accountID, err))
-				break
-			}
-
-			c.Set(req.accountID, &newCacheObj)
+			tm.build(c, req)
 
 		case <-tm.done:
+			tm.sources.stopAll()
 			tm.monitor.logInfo("Rules engine tree manager shutting down")
 			return nil
 		}
 	}
 }
 
+// build rebuilds the trees for the rule sets of the account in the build instruction if needed, storing
+// them in cache. It returns the cache entry holding the account trees, which is nil if the rules engine is
+// disabled for the account, or an error if the account configuration is invalid.
+func (tm *treeManager) build(c cacher, req buildInstruction) (*cacheEntry, error) {
+	if req.config == nil {
+		return nil, nil
+	}
+
+	cacheObj := c.Get(req.accountID)
+	if cacheObj != nil && !rebuildTrees(cacheObj, req.config, c) {
+		return cacheObj, nil
+	}
+
+	parsedCfg, err := config.NewConfig(*req.config, tm.schemaValidator, tm.sourceAllowlist)
+	if err != nil {
+		tm.monitor.logError(fmt.Sprintf("Rules engine error parsing config for account %s: %v", req.accountID, err))
+		return nil, err
+	}
+	if !parsedCfg.Enabled {
+		tm.sources.stop(req.accountID)
+		c.Delete(req.accountID)
+		tm.monitor.logInfo(fmt.Sprintf("Rules engine disabled for account %s", req.accountID))
+		return nil, nil
+	}
+
+	if parsedCfg.Source != nil {
+		return tm.buildFromSource(c, req, parsedCfg)
+	}
+	tm.sources.stop(req.accountID)
+
+	newCacheObj, err := NewCacheEntry(parsedCfg, req.config)
+	if err != nil {
+		tm.monitor.logError(fmt.Sprintf("Rules engine error creating cache entry for account %s: %v", req.accountID, err))
+		return nil, err
+	}
+
+	c.Set(req.accountID, &newCacheObj)
+	return &newCacheObj, nil
+}
+
+// buildFromSource starts watching the source the account rule sets are loaded from, replacing the source
+// watched for the previous account configuration if any. The trees stored in cache until the source is
+// loaded are built from the rule sets the previous source loaded if it watched the same location, or from
+// the rule sets of the account configuration otherwise.
+func (tm *treeManager) buildFromSource(c cacher, req buildInstruction, parsedCfg *config.PbRulesEngine) (*cacheEntry, error) {
+	s := newRuleSetSource(tm, c, req.accountID, *parsedCfg.Source, *req.config)
+	s.carryOver(tm.sources.replace(req.accountID, s))
+
+	newCacheObj, err := s.initialCacheEntry(parsedCfg)
+	if err != nil {
+		tm.sources.stop(req.accountID)
+		tm.monitor.logError(fmt.Sprintf("Rules engine error creating cache entry for account %s: %v", req.accountID, err))
+		return nil, err
+	}
+
+	c.Set(req.accountID, &newCacheObj)
+	go s.run()
+	return &newCacheObj, nil
+}
+
+// buildFromSourceOnce builds the trees for the rule sets the source of the account configuration holds, loading
+// them through the same source as buildFromSource but without storing them in cache or watching the source
+func (tm *treeManager) buildFromSourceOnce(id accountID, parsedCfg *config.PbRulesEngine, accountConfig json.RawMessage) (*cacheEntry, error) {
+	s := newRuleSetSource(tm, nil, id, *parsedCfg.Source, accountConfig)
+
+	ruleSets, err := s.load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rule sets from source: %w", err)
+	}
+
+	newCacheObj, err := s.newCacheEntry(ruleSets)
+	if err != nil {
+		return nil, err
+	}
+	return &newCacheObj, nil
+}
+
 // Shutdown signals the tree manager to stop processing
 func (tm *treeManager) Shutdown() {
 	close(tm.done)
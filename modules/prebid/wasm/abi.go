@@ -0,0 +1,185 @@
+package wasm
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// Functions a plugin exports to handle the stages it implements. Each function takes the pointer to and the
+// length of the JSON encoded callInput the host wrote in the plugin memory through the exported alloc function,
+// and returns the pointer to the JSON encoded callOutput in the upper 32 bits and its length in the lower 32 bits.
+const (
+	entrypointFunction              = "handle_entrypoint"
+	rawAuctionRequestFunction       = "handle_raw_auction_request"
+	processedAuctionRequestFunction = "handle_processed_auction_request"
+	bidderRequestFunction           = "handle_bidder_request"
+	rawBidderResponseFunction       = "handle_raw_bidder_response"
+	auctionResponseFunction         = "handle_auction_response"
+)
+
+// callInput is passed to the plugin function of a stage along with the stage payload
+type callInput[P any] struct {
+	AccountID     string                  `json:"account_id,omitempty"`
+	Endpoint      string                  `json:"endpoint,omitempty"`
+	AccountConfig json.RawMessage         `json:"account_config,omitempty"`
+	ModuleContext hookstage.ModuleContext `json:"module_context,omitempty"`
+	Payload       P                       `json:"payload"`
+}
+
+// callOutput is returned by the plugin function of a stage. The payload is only returned by plugins changing it,
+// in which case it replaces the stage payload as a whole.
+type callOutput[P any] struct {
+	Reject        bool                    `json:"reject,omitempty"`
+	NbrCode       int                     `json:"nbr,omitempty"`
+	Message       string                  `json:"message,omitempty"`
+	Errors        []string                `json:"errors,omitempty"`
+	Warnings      []string                `json:"warnings,omitempty"`
+	DebugMessages []string                `json:"debug_messages,omitempty"`
+	AnalyticsTags hookanalytics.Analytics `json:"analytics_tags,omitempty"`
+	ModuleContext hookstage.ModuleContext `json:"module_context,omitempty"`
+	Payload       *P                      `json:"payload,omitempty"`
+}
+
+type entrypointPayload struct {
+	Body json.RawMessage `json:"body"`
+}
+
+type rawAuctionRequestPayload struct {
+	BidRequest json.RawMessage `json:"bidrequest"`
+}
+
+type bidRequestPayload struct {
+	Bidder     string               `json:"bidder,omitempty"`
+	BidRequest *openrtb2.BidRequest `json:"bidrequest"`
+}
+
+type rawBidderResponsePayload struct {
+	Bidder string     `json:"bidder"`
+	Bids   []typedBid `json:"bids"`
+}
+
+type typedBid struct {
+	Bid          *openrtb2.Bid                  `json:"bid"`
+	BidType      openrtb_ext.BidType            `json:"type"`
+	BidMeta      *openrtb_ext.ExtBidPrebidMeta  `json:"meta,omitempty"`
+	BidVideo     *openrtb_ext.ExtBidPrebidVideo `json:"video,omitempty"`
+	DealPriority int                            `json:"dealpriority,omitempty"`
+	Seat         openrtb_ext.BidderName         `json:"seat,omitempty"`
+}
+
+type auctionResponsePayload struct {
+	BidResponse *openrtb2.BidResponse `json:"bidresponse"`
+}
+
+func newEntrypointPayload(payload hookstage.EntrypointPayload) (entrypointPayload, error) {
+	return entrypointPayload{Body: payload.Body}, nil
+}
+
+func applyEntrypointPayload(payload hookstage.EntrypointPayload, changed entrypointPayload) (hookstage.EntrypointPayload, error) {
+	payload.Body = changed.Body
+	return payload, nil
+}
+
+func newRawAuctionRequestPayload(payload hookstage.RawAuctionRequestPayload) (rawAuctionRequestPayload, error) {
+	return rawAuctionRequestPayload{BidRequest: json.RawMessage(payload)}, nil
+}
+
+func applyRawAuctionRequestPayload(_ hookstage.RawAuctionRequestPayload, changed rawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
+	return hookstage.RawAuctionRequestPayload(changed.BidRequest), nil
+}
+
+func newProcessedAuctionRequestPayload(payload hookstage.ProcessedAuctionRequestPayload) (bidRequestPayload, error) {
+	return newBidRequestPayload(payload.Request, "")
+}
+
+func applyProcessedAuctionRequestPayload(payload hookstage.ProcessedAuctionRequestPayload, changed bidRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+	return payload, replaceBidRequest(payload.Request, changed.BidRequest)
+}
+
+func newBidderRequestPayload(payload hookstage.BidderRequestPayload) (bidRequestPayload, error) {
+	return newBidRequestPayload(payload.Request, payload.Bidder)
+}
+
+func applyBidderRequestPayload(payload hookstage.BidderRequestPayload, changed bidRequestPayload) (hookstage.BidderRequestPayload, error) {
+	return payload, replaceBidRequest(payload.Request, changed.BidRequest)
+}
+
+// replaceBidRequest updates the request wrapper in place as the stages do not take the payload returned by the hooks
+func replaceBidRequest(request *openrtb_ext.RequestWrapper, bidRequest *openrtb2.BidRequest) error {
+	if bidRequest == nil {
+		return errors.New("payload bidrequest is missing")
+	}
+	*request = openrtb_ext.RequestWrapper{BidRequest: bidRequest}
+	return nil
+}
+
+func newBidRequestPayload(request *openrtb_ext.RequestWrapper, bidder string) (bidRequestPayload, error) {
+	if request == nil {
+		return bidRequestPayload{}, errors.New("bid request is missing")
+	}
+	if err := request.RebuildRequest(); err != nil {
+		return bidRequestPayload{}, err
+	}
+	return bidRequestPayload{Bidder: bidder, BidRequest: request.BidRequest}, nil
+}
+
+func newRawBidderResponsePayload(payload hookstage.RawBidderResponsePayload) (rawBidderResponsePayload, error) {
+	p := rawBidderResponsePayload{Bidder: payload.Bidder, Bids: make([]typedBid, 0)}
+	if payload.BidderResponse == nil {
+		return p, nil
+	}
+	for _, bid := range payload.BidderResponse.Bids {
+		if bid == nil {
+			continue
+		}
+		p.Bids = append(p.Bids, typedBid{
+			Bid:          bid.Bid,
+			BidType:      bid.BidType,
+			BidMeta:      bid.BidMeta,
+			BidVideo:     bid.BidVideo,
+			DealPriority: bid.DealPriority,
+			Seat:         bid.Seat,
+		})
+	}
+	return p, nil
+}
+
+func applyRawBidderResponsePayload(payload hookstage.RawBidderResponsePayload, changed rawBidderResponsePayload) (hookstage.RawBidderResponsePayload, error) {
+	if payload.BidderResponse == nil {
+		return payload, nil
+	}
+	bids := make([]*adapters.TypedBid, 0, len(changed.Bids))
+	for _, bid := range changed.Bids {
+		bids = append(bids, &adapters.TypedBid{
+			Bid:          bid.Bid,
+			BidType:      bid.BidType,
+			BidMeta:      bid.BidMeta,
+			BidVideo:     bid.BidVideo,
+			DealPriority: bid.DealPriority,
+			Seat:         bid.Seat,
+		})
+	}
+	payload.BidderResponse.Bids = bids
+	return payload, nil
+}
+
+func newAuctionResponsePayload(payload hookstage.AuctionResponsePayload) (auctionResponsePayload, error) {
+	return auctionResponsePayload{BidResponse: payload.BidResponse}, nil
+}
+
+func applyAuctionResponsePayload(payload hookstage.AuctionResponsePayload, changed auctionResponsePayload) (hookstage.AuctionResponsePayload, error) {
+	if changed.BidResponse == nil {
+		return payload, errors.New("payload bidresponse is missing")
+	}
+	// the bid response is updated in place as the stage does not take the payload returned by the hooks
+	if payload.BidResponse != nil {
+		*payload.BidResponse = *changed.BidResponse
+	}
+	return payload, nil
+}
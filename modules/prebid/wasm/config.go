@@ -0,0 +1,72 @@
+package wasm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	defaultTimeoutMs   = 20
+	defaultFuel        = 10_000_000
+	defaultMemoryPages = 256
+)
+
+type config struct {
+	Plugins []pluginConfig `json:"plugins"`
+}
+
+// pluginConfig describes a WebAssembly binary to load. The plugin name is the hook_impl_code the
+// execution plan uses to run the plugin at the stages it implements.
+type pluginConfig struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	TimeoutMs   int    `json:"timeout_ms"`
+	Fuel        uint64 `json:"fuel"`
+	MemoryPages uint32 `json:"memory_pages"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if len(data) == 0 {
+		return cfg, nil
+	}
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	names := make(map[string]struct{}, len(cfg.Plugins))
+	for i := range cfg.Plugins {
+		p := &cfg.Plugins[i]
+		if len(p.Name) == 0 || len(p.Path) == 0 {
+			return cfg, errors.New("plugins require a name and a path")
+		}
+		if _, exists := names[p.Name]; exists {
+			return cfg, fmt.Errorf("plugin %s is configured more than once", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		if p.TimeoutMs <= 0 {
+			p.TimeoutMs = defaultTimeoutMs
+		}
+		if p.Fuel == 0 {
+			p.Fuel = defaultFuel
+		}
+		if p.MemoryPages == 0 {
+			p.MemoryPages = defaultMemoryPages
+		}
+	}
+
+	return cfg, nil
+}
+
+func (p pluginConfig) timeout() time.Duration {
+	return time.Duration(p.TimeoutMs) * time.Millisecond
+}
+
+func (p pluginConfig) limits() Limits {
+	return Limits{Fuel: p.Fuel, MemoryPages: p.MemoryPages}
+}
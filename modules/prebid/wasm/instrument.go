@@ -0,0 +1,732 @@
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// WebAssembly runtimes do not bound the instructions a call executes, so plugin binaries are instrumented before
+// they are compiled. Fuel is held by a global the instrumented code decrements: a function or loop body is charged
+// all its instructions once entered, including the ones of the branches it does not take, so the fuel consumed is
+// an upper bound of the instructions executed. The bulk memory and table instructions, whose work depends on their
+// length operand, are replaced with a call to a function charging one fuel per byte or element first. memory.grow
+// instructions are replaced with a call to a function checking the plugin memory limit first. Tables are not
+// bounded by the plugin limits so table.grow and table.fill are rejected and tables are capped to maxTableElements.
+// A call running out of fuel or memory sets the exported limitGlobal to the limit exceeded before trapping.
+
+const limitGlobal = "__prebid_limit"
+
+// maxTableElements is the number of elements the tables of a plugin may hold
+const maxTableElements = 10_000
+
+// Values of limitGlobal
+const (
+	limitNone   = 0
+	limitFuel   = 1
+	limitMemory = 2
+)
+
+var (
+	errOutOfFuel      = errors.New("plugin ran out of fuel")
+	errMemoryExceeded = errors.New("plugin exceeded its memory limit")
+)
+
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+const (
+	sectionCustom    = 0
+	sectionType      = 1
+	sectionImport    = 2
+	sectionFunction  = 3
+	sectionTable     = 4
+	sectionMemory    = 5
+	sectionGlobal    = 6
+	sectionExport    = 7
+	sectionStart     = 8
+	sectionElement   = 9
+	sectionCode      = 10
+	sectionData      = 11
+	sectionDataCount = 12
+)
+
+// sectionOrder is the position non custom sections must appear in
+var sectionOrder = map[byte]int{
+	sectionType:      1,
+	sectionImport:    2,
+	sectionFunction:  3,
+	sectionTable:     4,
+	sectionMemory:    5,
+	sectionGlobal:    6,
+	sectionExport:    7,
+	sectionStart:     8,
+	sectionElement:   9,
+	sectionDataCount: 10,
+	sectionCode:      11,
+	sectionData:      12,
+}
+
+const (
+	opUnreachable   = 0x00
+	opBlock         = 0x02
+	opLoop          = 0x03
+	opIf            = 0x04
+	opElse          = 0x05
+	opEnd           = 0x0b
+	opBr            = 0x0c
+	opBrIf          = 0x0d
+	opBrTable       = 0x0e
+	opCall          = 0x10
+	opCallIndirect  = 0x11
+	opSelectTyped   = 0x1c
+	opLocalGet      = 0x20
+	opGlobalGet     = 0x23
+	opGlobalSet     = 0x24
+	opTableGet      = 0x25
+	opTableSet      = 0x26
+	opI32Load       = 0x28
+	opI64Store32    = 0x3e
+	opMemorySize    = 0x3f
+	opMemoryGrow    = 0x40
+	opI32Const      = 0x41
+	opI64Const      = 0x42
+	opF32Const      = 0x43
+	opF64Const      = 0x44
+	opI32Eqz        = 0x45
+	opI64LtU        = 0x54
+	opI64GtU        = 0x56
+	opI64Add        = 0x7c
+	opI64Sub        = 0x7d
+	opI64ExtendI32U = 0xad
+	opI64Extend32S  = 0xc4
+	opRefNull       = 0xd0
+	opRefIsNull     = 0xd1
+	opRefFunc       = 0xd2
+	opPrefixMisc    = 0xfc
+
+	miscMemoryInit = 8
+	miscMemoryCopy = 10
+	miscMemoryFill = 11
+	miscTableInit  = 12
+	miscTableCopy  = 14
+
+	blockTypeEmpty = 0x40
+	valueTypeI32   = 0x7f
+	valueTypeI64   = 0x7e
+	funcType       = 0x60
+	exportGlobal   = 0x03
+)
+
+type section struct {
+	id       byte
+	contents []byte
+}
+
+// instrument returns the binary with the instructions it executes bounded by the fuel limit and its memory bounded
+// by the memory limit. Binaries importing anything are rejected as plugins are not given any host function.
+func instrument(binary []byte, limits Limits) ([]byte, error) {
+	sections, err := readSections(binary)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeCount, functionCount, globalCount uint32
+	hasMemory := false
+	for _, s := range sections {
+		r := &reader{b: s.contents}
+		switch s.id {
+		case sectionImport:
+			if count, err := r.u32(); err != nil || count > 0 {
+				return nil, errors.New("plugins must not import anything")
+			}
+		case sectionType:
+			typeCount, err = r.u32()
+		case sectionFunction:
+			functionCount, err = r.u32()
+		case sectionGlobal:
+			globalCount, err = r.u32()
+		case sectionTable:
+			err = checkTables(r)
+		case sectionMemory:
+			hasMemory = true
+			err = checkMemories(r, limits.MemoryPages)
+		case sectionExport:
+			err = checkExports(r)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !hasMemory {
+		return nil, errors.New("plugins must define their memory")
+	}
+
+	m := &meter{fuelGlobal: globalCount, limitGlobal: globalCount + 1, growFunction: functionCount}
+
+	// the types, functions, globals and export added are appended so the indices of the binary ones do not change
+	sections = appendToSection(sections, sectionType, []byte{funcType, 1, valueTypeI32, 1, valueTypeI32})
+	sections = appendToSection(sections, sectionType, []byte{funcType, 3, valueTypeI32, valueTypeI32, valueTypeI32, 0})
+	sections = appendToSection(sections, sectionFunction, appendU32(nil, typeCount))
+
+	// mutable globals initialized with a constant expression, every call running in a new instance
+	fuel := appendS64([]byte{valueTypeI64, 1, opI64Const}, int64(limits.Fuel))
+	sections = appendToSection(sections, sectionGlobal, append(fuel, opEnd))
+	sections = appendToSection(sections, sectionGlobal, []byte{valueTypeI32, 1, opI32Const, limitNone, opEnd})
+
+	export := appendName(nil, limitGlobal)
+	export = append(export, exportGlobal)
+	export = appendU32(export, m.limitGlobal)
+	sections = appendToSection(sections, sectionExport, export)
+
+	for i, s := range sections {
+		if s.id != sectionCode {
+			continue
+		}
+		contents, err := m.code(s.contents)
+		if err != nil {
+			return nil, err
+		}
+		sections[i].contents = contents
+	}
+	grow := m.growBody(limits.MemoryPages)
+	sections = appendToSection(sections, sectionCode, append(appendU32(nil, uint32(len(grow))), grow...))
+	// the bulk functions follow the grow function, in the order the metered code calls them for the first time
+	for _, instruction := range m.bulkInstructions {
+		sections = appendToSection(sections, sectionFunction, appendU32(nil, typeCount+1))
+		bulk := m.bulkBody(instruction)
+		sections = appendToSection(sections, sectionCode, append(appendU32(nil, uint32(len(bulk))), bulk...))
+	}
+
+	return encodeBinary(sections), nil
+}
+
+func encodeBinary(sections []section) []byte {
+	out := append([]byte{}, wasmHeader...)
+	for _, s := range sections {
+		out = append(out, s.id)
+		out = appendU32(out, uint32(len(s.contents)))
+		out = append(out, s.contents...)
+	}
+	return out
+}
+
+func readSections(binary []byte) ([]section, error) {
+	if !bytes.HasPrefix(binary, wasmHeader) {
+		return nil, errors.New("invalid WebAssembly binary header")
+	}
+
+	r := &reader{b: binary, pos: len(wasmHeader)}
+	var sections []section
+	for !r.done() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		contents, err := r.bytes(int(size))
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %s", id, err)
+		}
+		if _, known := sectionOrder[id]; !known && id != sectionCustom {
+			return nil, fmt.Errorf("unsupported section %d", id)
+		}
+		sections = append(sections, section{id: id, contents: contents})
+	}
+	return sections, nil
+}
+
+// appendToSection appends the encoded entry to the vector the section holds, adding the section if the binary
+// does not have it
+func appendToSection(sections []section, id byte, entry []byte) []section {
+	for i, s := range sections {
+		if s.id != id {
+			continue
+		}
+		r := &reader{b: s.contents}
+		// the section was read successfully before so the count is valid
+		count, _ := r.u32()
+		contents := appendU32(nil, count+1)
+		contents = append(contents, s.contents[r.pos:]...)
+		sections[i].contents = append(contents, entry...)
+		return sections
+	}
+
+	contents := append(appendU32(nil, 1), entry...)
+	at := len(sections)
+	for i, s := range sections {
+		if s.id != sectionCustom && sectionOrder[s.id] > sectionOrder[id] {
+			at = i
+			break
+		}
+	}
+	sections = append(sections, section{})
+	copy(sections[at+1:], sections[at:])
+	sections[at] = section{id: id, contents: contents}
+	return sections
+}
+
+// checkMemories rejects memories whose initial size exceeds the memory limit
+func checkMemories(r *reader, maxPages uint32) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		flags, err := r.byte()
+		if err != nil {
+			return err
+		}
+		min, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flags&1 == 1 {
+			if _, err := r.u32(); err != nil {
+				return err
+			}
+		}
+		if min > maxPages {
+			return fmt.Errorf("plugin memory of %d pages exceeds its limit of %d pages", min, maxPages)
+		}
+	}
+	return nil
+}
+
+// checkTables rejects tables whose initial size exceeds maxTableElements. Tables cannot grow as table.grow is rejected.
+func checkTables(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		// the reference type of the table elements
+		if _, err := r.byte(); err != nil {
+			return err
+		}
+		flags, err := r.byte()
+		if err != nil {
+			return err
+		}
+		min, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flags&1 == 1 {
+			if _, err := r.u32(); err != nil {
+				return err
+			}
+		}
+		if min > maxTableElements {
+			return fmt.Errorf("plugin table of %d elements exceeds the limit of %d elements", min, maxTableElements)
+		}
+	}
+	return nil
+}
+
+// checkExports rejects binaries exporting the name of the global instrumenting adds
+func checkExports(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		name, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+		if string(name) == limitGlobal {
+			return fmt.Errorf("plugins must not export %s", limitGlobal)
+		}
+		if _, err := r.byte(); err != nil {
+			return err
+		}
+		if _, err := r.u32(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// meter instruments the function bodies of the code section
+type meter struct {
+	fuelGlobal   uint32
+	limitGlobal  uint32
+	growFunction uint32
+	// bulkInstructions are the distinct bulk memory and table instructions, immediates included, the code uses.
+	// The function replacing the one at index i is growFunction + 1 + i.
+	bulkInstructions [][]byte
+}
+
+// patch is a change to a function body: either the fuel charged for the body of a function or loop at its start,
+// or a memory.grow or bulk instruction replaced with a call to the function performing it within the limits
+type patch struct {
+	offset int
+	// length of the instruction replaced, 0 when the fuel is charged
+	length int
+	// index of the scope whose cost is charged
+	scope int
+	// function called in place of the instruction replaced
+	function uint32
+}
+
+func (m *meter) code(contents []byte) ([]byte, error) {
+	r := &reader{b: contents}
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+
+	out := appendU32(nil, count)
+	for i := uint32(0); i < count; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		body, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		metered, err := m.body(body)
+		if err != nil {
+			return nil, fmt.Errorf("function %d: %s", i, err)
+		}
+		out = appendU32(out, uint32(len(metered)))
+		out = append(out, metered...)
+	}
+	return out, nil
+}
+
+// body charges the fuel of the function body at its start and of every loop body at the start of each iteration
+func (m *meter) body(body []byte) ([]byte, error) {
+	r := &reader{b: body}
+	// skip the local declarations
+	declarations, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < declarations; i++ {
+		if _, err := r.u32(); err != nil {
+			return nil, err
+		}
+		if _, err := r.byte(); err != nil {
+			return nil, err
+		}
+	}
+
+	costs := []uint64{0}
+	patches := []patch{{offset: r.pos}}
+	// scope charged for the instructions of each enclosing block, loop and if
+	frames := []int{0}
+
+	for len(frames) > 0 {
+		start := r.pos
+		op, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		costs[frames[len(frames)-1]]++
+
+		switch op {
+		case opBlock, opIf:
+			if err := r.blockType(); err != nil {
+				return nil, err
+			}
+			frames = append(frames, frames[len(frames)-1])
+		case opLoop:
+			if err := r.blockType(); err != nil {
+				return nil, err
+			}
+			costs = append(costs, 0)
+			frames = append(frames, len(costs)-1)
+			patches = append(patches, patch{offset: r.pos, scope: len(costs) - 1})
+		case opEnd:
+			frames = frames[:len(frames)-1]
+		case opMemoryGrow:
+			if _, err := r.u32(); err != nil {
+				return nil, err
+			}
+			patches = append(patches, patch{offset: start, length: r.pos - start, function: m.growFunction})
+		case opPrefixMisc:
+			op, err := r.miscImmediates()
+			if err != nil {
+				return nil, err
+			}
+			switch op {
+			case miscMemoryInit, miscMemoryCopy, miscMemoryFill, miscTableInit, miscTableCopy:
+				function := m.bulkFunction(body[start:r.pos])
+				patches = append(patches, patch{offset: start, length: r.pos - start, function: function})
+			}
+		default:
+			if err := r.immediates(op); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !r.done() {
+		return nil, errors.New("unexpected instructions after the end of the function")
+	}
+
+	out := make([]byte, 0, len(body)+len(patches)*32)
+	copied := 0
+	for _, p := range patches {
+		out = append(out, body[copied:p.offset]...)
+		if p.length > 0 {
+			out = append(out, opCall)
+			out = appendU32(out, p.function)
+		} else {
+			out = m.appendCharge(out, costs[p.scope])
+		}
+		copied = p.offset + p.length
+	}
+	return append(out, body[copied:]...), nil
+}
+
+// bulkFunction returns the index of the function replacing the bulk instruction
+func (m *meter) bulkFunction(instruction []byte) uint32 {
+	for i, known := range m.bulkInstructions {
+		if bytes.Equal(known, instruction) {
+			return m.growFunction + 1 + uint32(i)
+		}
+	}
+	m.bulkInstructions = append(m.bulkInstructions, instruction)
+	return m.growFunction + uint32(len(m.bulkInstructions))
+}
+
+// appendCharge appends the instructions subtracting the cost from the fuel, trapping if the fuel left is lower
+func (m meter) appendCharge(out []byte, cost uint64) []byte {
+	out = append(out, opGlobalGet)
+	out = appendU32(out, m.fuelGlobal)
+	out = append(out, opI64Const)
+	out = appendS64(out, int64(cost))
+	out = append(out, opI64LtU, opIf, blockTypeEmpty)
+	out = m.appendTrap(out, limitFuel)
+	out = append(out, opEnd, opGlobalGet)
+	out = appendU32(out, m.fuelGlobal)
+	out = append(out, opI64Const)
+	out = appendS64(out, int64(cost))
+	out = append(out, opI64Sub, opGlobalSet)
+	return appendU32(out, m.fuelGlobal)
+}
+
+func (m meter) appendTrap(out []byte, limit byte) []byte {
+	out = append(out, opI32Const, limit, opGlobalSet)
+	out = appendU32(out, m.limitGlobal)
+	return append(out, opUnreachable)
+}
+
+// growBody returns the body of the function memory.grow instructions are replaced with. It grows the memory by
+// the number of pages it is given, trapping if the memory size would exceed the memory limit.
+func (m meter) growBody(maxPages uint32) []byte {
+	// no local declarations
+	out := []byte{0}
+	out = append(out, opMemorySize, 0, opI64ExtendI32U, opLocalGet, 0, opI64ExtendI32U, opI64Add, opI64Const)
+	out = appendS64(out, int64(maxPages))
+	out = append(out, opI64GtU, opIf, blockTypeEmpty)
+	out = m.appendTrap(out, limitMemory)
+	return append(out, opEnd, opLocalGet, 0, opMemoryGrow, 0, opEnd)
+}
+
+// bulkBody returns the body of the function a bulk memory or table instruction is replaced with. The three i32
+// operands of the instruction are passed to the function, which charges one fuel per byte or element of the
+// length operand, the last one, before performing the instruction.
+func (m meter) bulkBody(instruction []byte) []byte {
+	// no local declarations
+	out := []byte{0, opGlobalGet}
+	out = appendU32(out, m.fuelGlobal)
+	out = append(out, opLocalGet, 2, opI64ExtendI32U, opI64LtU, opIf, blockTypeEmpty)
+	out = m.appendTrap(out, limitFuel)
+	out = append(out, opEnd, opGlobalGet)
+	out = appendU32(out, m.fuelGlobal)
+	out = append(out, opLocalGet, 2, opI64ExtendI32U, opI64Sub, opGlobalSet)
+	out = appendU32(out, m.fuelGlobal)
+	out = append(out, opLocalGet, 0, opLocalGet, 1, opLocalGet, 2)
+	out = append(out, instruction...)
+	return append(out, opEnd)
+}
+
+// reader decodes the WebAssembly binary format
+type reader struct {
+	b   []byte
+	pos int
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of binary")
+
+func (r *reader) done() bool {
+	return r.pos >= len(r.b)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.done() {
+		return 0, errUnexpectedEnd
+	}
+	b := r.b[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.b)-r.pos {
+		return nil, errUnexpectedEnd
+	}
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// u32 decodes an unsigned LEB128 integer of 32 bits at most
+func (r *reader) u32() (uint32, error) {
+	var v uint32
+	for shift := 0; shift < 35; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return 0, errors.New("invalid LEB128 integer")
+}
+
+// leb skips a signed or unsigned LEB128 integer of the given number of bytes at most
+func (r *reader) leb(maxBytes int) error {
+	for i := 0; i < maxBytes; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if b&0x80 == 0 {
+			return nil
+		}
+	}
+	return errors.New("invalid LEB128 integer")
+}
+
+// blockType skips the type of a block, loop or if: either the empty type, a value type or a type index
+func (r *reader) blockType() error {
+	b, err := r.byte()
+	if err != nil {
+		return err
+	}
+	// the empty type and the value types are single byte negative integers
+	if b&0x80 == 0 && b&0x40 != 0 {
+		return nil
+	}
+	r.pos--
+	return r.leb(5)
+}
+
+// immediates skips the immediates of the instruction. Instructions of the proposals the runtime does not enable,
+// SIMD among them, are rejected.
+func (r *reader) immediates(op byte) error {
+	switch {
+	case op == opUnreachable, op == 0x01, op == opElse, op == 0x0f, op == 0x1a, op == 0x1b, op == opRefIsNull,
+		op >= opI32Eqz && op <= opI64Extend32S:
+		return nil
+	case op == opBr, op == opBrIf, op == opCall, op >= opLocalGet && op <= opTableSet, op == opRefFunc,
+		op == opMemorySize:
+		return r.leb(5)
+	case op == opBrTable:
+		count, err := r.u32()
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i <= count; i++ {
+			if err := r.leb(5); err != nil {
+				return err
+			}
+		}
+		return nil
+	case op == opCallIndirect:
+		if err := r.leb(5); err != nil {
+			return err
+		}
+		return r.leb(5)
+	case op == opSelectTyped:
+		count, err := r.u32()
+		if err != nil {
+			return err
+		}
+		_, err = r.bytes(int(count))
+		return err
+	case op >= opI32Load && op <= opI64Store32:
+		if err := r.leb(5); err != nil {
+			return err
+		}
+		return r.leb(5)
+	case op == opI32Const:
+		return r.leb(5)
+	case op == opI64Const:
+		return r.leb(10)
+	case op == opF32Const:
+		_, err := r.bytes(4)
+		return err
+	case op == opF64Const:
+		_, err := r.bytes(8)
+		return err
+	case op == opRefNull:
+		_, err := r.byte()
+		return err
+	case op == opPrefixMisc:
+		_, err := r.miscImmediates()
+		return err
+	}
+	return fmt.Errorf("unsupported instruction 0x%02x", op)
+}
+
+// miscImmediates skips the immediates of the saturating truncation, bulk memory and table instructions, returning
+// the instruction. table.grow and table.fill are rejected.
+func (r *reader) miscImmediates() (uint32, error) {
+	op, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case op <= 7:
+		return op, nil
+	case op == 9, op == miscMemoryFill, op == 13, op == 16:
+		return op, r.leb(5)
+	case op == miscMemoryInit, op == miscMemoryCopy, op == miscTableInit, op == miscTableCopy:
+		if err := r.leb(5); err != nil {
+			return 0, err
+		}
+		return op, r.leb(5)
+	}
+	return 0, fmt.Errorf("unsupported instruction 0xfc %d", op)
+}
+
+func appendU32(b []byte, v uint32) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func appendS64(b []byte, v int64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func appendName(b []byte, name string) []byte {
+	b = appendU32(b, uint32(len(name)))
+	return append(b, name...)
+}
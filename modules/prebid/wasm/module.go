@@ -0,0 +1,198 @@
+// Package wasm implements a Prebid Server module running hooks implemented by WebAssembly plugins
+// loaded at startup, so hook logic can be shipped without rebuilding Prebid Server.
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+var (
+	_ hookstage.Entrypoint              = Module{}
+	_ hookstage.RawAuctionRequest       = Module{}
+	_ hookstage.ProcessedAuctionRequest = Module{}
+	_ hookstage.BidderRequest           = Module{}
+	_ hookstage.RawBidderResponse       = Module{}
+	_ hookstage.AuctionResponse         = Module{}
+)
+
+// Builder loads and compiles the configured plugins
+func Builder(rawCfg json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(rawCfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Plugins) == 0 {
+		return Module{}, nil
+	}
+
+	ctx := context.Background()
+	return newModule(ctx, cfg, newWazeroRuntime(ctx), os.ReadFile)
+}
+
+func newModule(ctx context.Context, cfg config, rt Runtime, readFile func(string) ([]byte, error)) (Module, error) {
+	m := Module{plugins: make(map[string]plugin, len(cfg.Plugins))}
+
+	for _, pc := range cfg.Plugins {
+		binary, err := readFile(pc.Path)
+		if err == nil {
+			var compiled Plugin
+			compiled, err = rt.Compile(ctx, binary, pc.limits())
+			if err == nil {
+				m.plugins[pc.Name] = plugin{Plugin: compiled, name: pc.Name, timeout: pc.timeout()}
+				continue
+			}
+		}
+
+		m.Shutdown()
+		return Module{}, fmt.Errorf("failed to load plugin %s: %s", pc.Name, err)
+	}
+
+	return m, nil
+}
+
+// Module runs the plugin whose name is the hook_impl_code of the hook invoked
+type Module struct {
+	plugins map[string]plugin
+}
+
+type plugin struct {
+	Plugin
+	name    string
+	timeout time.Duration
+}
+
+// Shutdown releases the resources of the compiled plugins
+func (m Module) Shutdown() error {
+	var errs []error
+	for _, p := range m.plugins {
+		if err := p.Close(context.Background()); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", p.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m Module) HandleEntrypointHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return call(ctx, m, miCtx, entrypointFunction, payload, newEntrypointPayload, applyEntrypointPayload)
+}
+
+func (m Module) HandleRawAuctionHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	return call(ctx, m, miCtx, rawAuctionRequestFunction, payload, newRawAuctionRequestPayload, applyRawAuctionRequestPayload)
+}
+
+func (m Module) HandleProcessedAuctionHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	return call(ctx, m, miCtx, processedAuctionRequestFunction, payload, newProcessedAuctionRequestPayload, applyProcessedAuctionRequestPayload)
+}
+
+func (m Module) HandleBidderRequestHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.BidderRequestPayload,
+) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	return call(ctx, m, miCtx, bidderRequestFunction, payload, newBidderRequestPayload, applyBidderRequestPayload)
+}
+
+func (m Module) HandleRawBidderResponseHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawBidderResponsePayload,
+) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	return call(ctx, m, miCtx, rawBidderResponseFunction, payload, newRawBidderResponsePayload, applyRawBidderResponsePayload)
+}
+
+func (m Module) HandleAuctionResponseHook(
+	ctx context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.AuctionResponsePayload,
+) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
+	return call(ctx, m, miCtx, auctionResponseFunction, payload, newAuctionResponsePayload, applyAuctionResponsePayload)
+}
+
+// call runs the stage function of the plugin invoked with the stage payload converted to its ABI representation.
+// A payload returned by the plugin is applied through a mutation of the hook result change set.
+func call[T any, P any](
+	ctx context.Context,
+	m Module,
+	miCtx hookstage.ModuleInvocationContext,
+	function string,
+	payload T,
+	newPayload func(T) (P, error),
+	applyPayload func(T, P) (T, error),
+) (hookstage.HookResult[T], error) {
+	result := hookstage.HookResult[T]{}
+
+	p, ok := m.plugins[miCtx.HookImplCode]
+	if !ok {
+		return result, fmt.Errorf("plugin %s is not loaded", miCtx.HookImplCode)
+	}
+	if !p.Exports(function) {
+		return result, fmt.Errorf("plugin %s does not export %s", p.name, function)
+	}
+
+	abiPayload, err := newPayload(payload)
+	if err != nil {
+		return result, fmt.Errorf("plugin %s: failed to build payload: %s", p.name, err)
+	}
+	input, err := jsonutil.Marshal(callInput[P]{
+		AccountID:     miCtx.AccountID,
+		Endpoint:      miCtx.Endpoint,
+		AccountConfig: miCtx.AccountConfig,
+		ModuleContext: miCtx.ModuleContext,
+		Payload:       abiPayload,
+	})
+	if err != nil {
+		return result, fmt.Errorf("plugin %s: failed to encode input: %s", p.name, err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	rawOutput, err := p.Call(callCtx, function, input)
+	if err != nil {
+		return result, fmt.Errorf("plugin %s: %s failed: %s", p.name, function, err)
+	}
+
+	var output callOutput[P]
+	if err := jsonutil.UnmarshalValid(rawOutput, &output); err != nil {
+		return result, fmt.Errorf("plugin %s: failed to decode output: %s", p.name, err)
+	}
+
+	result.Reject = output.Reject
+	result.NbrCode = output.NbrCode
+	result.Message = output.Message
+	result.Errors = output.Errors
+	result.Warnings = output.Warnings
+	result.DebugMessages = output.DebugMessages
+	result.AnalyticsTags = output.AnalyticsTags
+	result.ModuleContext = output.ModuleContext
+
+	if output.Payload != nil {
+		changed := *output.Payload
+		result.ChangeSet.AddMutation(func(payload T) (T, error) {
+			return applyPayload(payload, changed)
+		}, hookstage.MutationUpdate, "wasm", p.name)
+	}
+
+	return result, nil
+}
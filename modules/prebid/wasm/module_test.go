@@ -0,0 +1,260 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlugin runs Go functions in place of the exported functions of a WebAssembly binary
+type fakePlugin struct {
+	functions map[string]func(ctx context.Context, input []byte) ([]byte, error)
+	closed    bool
+}
+
+func (p *fakePlugin) Exports(function string) bool {
+	_, ok := p.functions[function]
+	return ok
+}
+
+func (p *fakePlugin) Call(ctx context.Context, function string, input []byte) ([]byte, error) {
+	return p.functions[function](ctx, input)
+}
+
+func (p *fakePlugin) Close(_ context.Context) error {
+	p.closed = true
+	return nil
+}
+
+type fakeRuntime struct {
+	plugins map[string]*fakePlugin
+	limits  []Limits
+}
+
+func (rt *fakeRuntime) Compile(_ context.Context, binary []byte, limits Limits) (Plugin, error) {
+	p, ok := rt.plugins[string(binary)]
+	if !ok {
+		return nil, errors.New("invalid binary")
+	}
+	rt.limits = append(rt.limits, limits)
+	return p, nil
+}
+
+// readBinary reads the binary of a plugin as its path so the fake runtime can find the plugin compiled from it
+func readBinary(path string) ([]byte, error) {
+	return []byte(path), nil
+}
+
+func newTestModule(t *testing.T, plugins map[string]*fakePlugin) Module {
+	cfg := config{}
+	for name := range plugins {
+		cfg.Plugins = append(cfg.Plugins, pluginConfig{Name: name, Path: name, TimeoutMs: 50})
+	}
+
+	m, err := newModule(context.Background(), cfg, &fakeRuntime{plugins: plugins}, readBinary)
+	require.NoError(t, err)
+	return m
+}
+
+func TestBuilder(t *testing.T) {
+	m, err := Builder(nil, moduledeps.ModuleDeps{})
+	assert.NoError(t, err)
+	assert.Equal(t, Module{}, m, "no runtime is needed when no plugins are configured")
+
+	m, err = Builder(json.RawMessage(`{"plugins":[{"name":"plugin","path":"testdata/plugin.wasm"}]}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	assert.NoError(t, m.(Module).Shutdown())
+
+	_, err = Builder(json.RawMessage(`{"plugins":[{"name":"plugin","path":"testdata/missing.wasm"}]}`), moduledeps.ModuleDeps{})
+	assert.EqualError(t, err, "failed to load plugin plugin: open testdata/missing.wasm: no such file or directory")
+
+	_, err = Builder(json.RawMessage(`{"plugins":[{"name":"plugin"}]}`), moduledeps.ModuleDeps{})
+	assert.EqualError(t, err, "plugins require a name and a path")
+}
+
+func TestNewModule(t *testing.T) {
+	loaded := &fakePlugin{}
+	rt := &fakeRuntime{plugins: map[string]*fakePlugin{"first.wasm": loaded}}
+	cfg, err := newConfig(json.RawMessage(`{"plugins":[{"name":"first","path":"first.wasm","fuel":100},{"name":"second","path":"second.wasm"}]}`))
+	require.NoError(t, err)
+
+	_, err = newModule(context.Background(), cfg, rt, readBinary)
+
+	assert.EqualError(t, err, "failed to load plugin second: invalid binary")
+	assert.True(t, loaded.closed, "plugins loaded are closed when another plugin fails to load")
+	assert.Equal(t, []Limits{{Fuel: 100, MemoryPages: defaultMemoryPages}}, rt.limits)
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	var input callInput[bidRequestPayload]
+	m := newTestModule(t, map[string]*fakePlugin{
+		"tmax": {
+			functions: map[string]func(ctx context.Context, input []byte) ([]byte, error){
+				processedAuctionRequestFunction: func(_ context.Context, in []byte) ([]byte, error) {
+					if err := json.Unmarshal(in, &input); err != nil {
+						return nil, err
+					}
+					return []byte(`{
+					  "warnings": ["tmax capped"],
+					  "module_context": {"capped": true},
+					  "analytics_tags": {"activities": [{"name": "tmax", "status": "success"}]},
+					  "payload": {"bidrequest": {"id": "request-id", "tmax": 500}}
+					}`), nil
+				},
+			},
+		},
+	})
+
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "request-id", TMax: 1000}}
+	miCtx := hookstage.ModuleInvocationContext{
+		AccountID:     "account-id",
+		Endpoint:      "/openrtb2/auction",
+		AccountConfig: json.RawMessage(`{"cap":500}`),
+		HookImplCode:  "tmax",
+	}
+
+	result, err := m.HandleProcessedAuctionHook(context.Background(), miCtx, hookstage.ProcessedAuctionRequestPayload{Request: request})
+	require.NoError(t, err)
+
+	assert.Equal(t, "account-id", input.AccountID)
+	assert.Equal(t, "/openrtb2/auction", input.Endpoint)
+	assert.JSONEq(t, `{"cap":500}`, string(input.AccountConfig))
+	assert.Equal(t, int64(1000), input.Payload.BidRequest.TMax)
+
+	assert.Equal(t, []string{"tmax capped"}, result.Warnings)
+	assert.Equal(t, hookstage.ModuleContext{"capped": true}, result.ModuleContext)
+	assert.Equal(t, hookanalytics.Analytics{Activities: []hookanalytics.Activity{{Name: "tmax", Status: hookanalytics.ActivityStatusSuccess}}}, result.AnalyticsTags)
+
+	mutations := result.ChangeSet.Mutations()
+	require.Len(t, mutations, 1)
+	assert.Equal(t, []string{"wasm", "tmax"}, mutations[0].Key())
+
+	_, err = mutations[0].Apply(hookstage.ProcessedAuctionRequestPayload{Request: request})
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), request.TMax, "the request is updated in place")
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	m := newTestModule(t, map[string]*fakePlugin{
+		"drop-deals": {
+			functions: map[string]func(ctx context.Context, input []byte) ([]byte, error){
+				rawBidderResponseFunction: func(_ context.Context, in []byte) ([]byte, error) {
+					return []byte(`{"payload": {"bidder": "bidderA", "bids": [{"bid": {"id": "bid-2", "price": 2}, "type": "video"}]}}`), nil
+				},
+			},
+		},
+	})
+
+	payload := hookstage.RawBidderResponsePayload{
+		Bidder: "bidderA",
+		BidderResponse: &adapters.BidderResponse{
+			Bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "bid-1", DealID: "deal"}, BidType: openrtb_ext.BidTypeBanner},
+				{Bid: &openrtb2.Bid{ID: "bid-2", Price: 2}, BidType: openrtb_ext.BidTypeVideo},
+			},
+		},
+	}
+
+	result, err := m.HandleRawBidderResponseHook(context.Background(), hookstage.ModuleInvocationContext{HookImplCode: "drop-deals"}, payload)
+	require.NoError(t, err)
+
+	mutations := result.ChangeSet.Mutations()
+	require.Len(t, mutations, 1)
+	payload, err = mutations[0].Apply(payload)
+	require.NoError(t, err)
+	assert.Equal(t, []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid-2", Price: 2}, BidType: openrtb_ext.BidTypeVideo}}, payload.BidderResponse.Bids)
+}
+
+func TestHandleAuctionResponseHookWithoutPayloadChanges(t *testing.T) {
+	m := newTestModule(t, map[string]*fakePlugin{
+		"reject": {
+			functions: map[string]func(ctx context.Context, input []byte) ([]byte, error){
+				auctionResponseFunction: func(_ context.Context, in []byte) ([]byte, error) {
+					return []byte(`{"reject": true, "nbr": 123, "message": "rejected"}`), nil
+				},
+			},
+		},
+	})
+
+	result, err := m.HandleAuctionResponseHook(context.Background(), hookstage.ModuleInvocationContext{HookImplCode: "reject"}, hookstage.AuctionResponsePayload{BidResponse: &openrtb2.BidResponse{}})
+	require.NoError(t, err)
+
+	assert.True(t, result.Reject)
+	assert.Equal(t, 123, result.NbrCode)
+	assert.Equal(t, "rejected", result.Message)
+	assert.Empty(t, result.ChangeSet.Mutations())
+}
+
+func TestHandleHookErrors(t *testing.T) {
+	m := newTestModule(t, map[string]*fakePlugin{
+		"plugin": {
+			functions: map[string]func(ctx context.Context, input []byte) ([]byte, error){
+				entrypointFunction: func(ctx context.Context, in []byte) ([]byte, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				},
+				rawAuctionRequestFunction: func(_ context.Context, in []byte) ([]byte, error) {
+					return []byte(`malformed`), nil
+				},
+			},
+		},
+	})
+
+	testCases := []struct {
+		name        string
+		run         func() error
+		expectedErr string
+	}{
+		{
+			name: "unknown_plugin",
+			run: func() error {
+				_, err := m.HandleEntrypointHook(context.Background(), hookstage.ModuleInvocationContext{HookImplCode: "unknown"}, hookstage.EntrypointPayload{Body: []byte(`{}`)})
+				return err
+			},
+			expectedErr: "plugin unknown is not loaded",
+		},
+		{
+			name: "stage_not_implemented",
+			run: func() error {
+				_, err := m.HandleAuctionResponseHook(context.Background(), hookstage.ModuleInvocationContext{HookImplCode: "plugin"}, hookstage.AuctionResponsePayload{})
+				return err
+			},
+			expectedErr: "plugin plugin does not export handle_auction_response",
+		},
+		{
+			name: "timeout",
+			run: func() error {
+				_, err := m.HandleEntrypointHook(context.Background(), hookstage.ModuleInvocationContext{HookImplCode: "plugin"}, hookstage.EntrypointPayload{Body: []byte(`{}`)})
+				return err
+			},
+			expectedErr: "plugin plugin: handle_entrypoint failed: context deadline exceeded",
+		},
+		{
+			name: "malformed_output",
+			run: func() error {
+				_, err := m.HandleRawAuctionHook(context.Background(), hookstage.ModuleInvocationContext{HookImplCode: "plugin"}, hookstage.RawAuctionRequestPayload(`{}`))
+				return err
+			},
+			expectedErr: "plugin plugin: failed to decode output",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Now()
+			assert.ErrorContains(t, tc.run(), tc.expectedErr)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Runtime compiles WebAssembly binaries into plugins. Plugins must be sandboxed from the host: a runtime
+// must not provide them any host function, WASI included, and must reject binaries importing any, so the
+// only thing a plugin can do is compute a result from the input it is called with.
+type Runtime interface {
+	Compile(ctx context.Context, binary []byte, limits Limits) (Plugin, error)
+}
+
+// Plugin is a compiled WebAssembly binary whose exported functions follow the module ABI.
+// Call must be safe for concurrent use, every call running in its own instance of the binary.
+type Plugin interface {
+	// Exports returns true if the binary exports the given function
+	Exports(function string) bool
+	// Call passes the input to the exported function and returns its output. The call must be aborted
+	// with an error once it consumes the fuel or memory allowed by the plugin limits or the context is done.
+	Call(ctx context.Context, function string, input []byte) ([]byte, error)
+	Close(ctx context.Context) error
+}
+
+// Limits bounds the resources a plugin call may use
+type Limits struct {
+	// Fuel is the number of instructions a call may execute
+	Fuel uint64
+	// MemoryPages is the number of 64KiB pages of linear memory a call may grow its memory to
+	MemoryPages uint32
+}
+
+const (
+	memoryExport  = "memory"
+	allocFunction = "alloc"
+)
+
+// wazeroRuntime compiles plugins with wazero, a WebAssembly runtime written in Go. Binaries are instrumented
+// to enforce the plugin limits before they are compiled. SIMD instructions are not supported by the
+// instrumentation so the runtime does not enable them.
+type wazeroRuntime struct {
+	runtime wazero.Runtime
+}
+
+func newWazeroRuntime(ctx context.Context) *wazeroRuntime {
+	cfg := wazero.NewRuntimeConfig().
+		WithCoreFeatures(api.CoreFeaturesV2 &^ api.CoreFeatureSIMD).
+		WithCloseOnContextDone(true)
+	return &wazeroRuntime{runtime: wazero.NewRuntimeWithConfig(ctx, cfg)}
+}
+
+func (rt *wazeroRuntime) Compile(ctx context.Context, binary []byte, limits Limits) (Plugin, error) {
+	instrumented, err := instrument(binary, limits)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := rt.runtime.CompileModule(ctx, instrumented)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := compiled.ExportedMemories()[memoryExport]; !ok {
+		compiled.Close(ctx)
+		return nil, fmt.Errorf("plugins must export their %s", memoryExport)
+	}
+	if !exportsFunction(compiled, allocFunction, []api.ValueType{api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}) {
+		compiled.Close(ctx)
+		return nil, fmt.Errorf("plugins must export an %s(size i32) i32 function", allocFunction)
+	}
+
+	return &wazeroPlugin{runtime: rt.runtime, compiled: compiled}, nil
+}
+
+func exportsFunction(compiled wazero.CompiledModule, name string, params, results []api.ValueType) bool {
+	def, ok := compiled.ExportedFunctions()[name]
+	if !ok {
+		return false
+	}
+	return equalValueTypes(def.ParamTypes(), params) && equalValueTypes(def.ResultTypes(), results)
+}
+
+func equalValueTypes(a, b []api.ValueType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type wazeroPlugin struct {
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// Exports returns true if the binary exports the function with the signature of the stage functions
+func (p *wazeroPlugin) Exports(function string) bool {
+	return exportsFunction(p.compiled, function, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI64})
+}
+
+// Call instantiates the binary, writes the input to the memory the plugin allocates for it and calls the
+// function, returning a copy of the output it points to
+func (p *wazeroPlugin) Call(ctx context.Context, function string, input []byte) ([]byte, error) {
+	// instances are not named so the binary can be instantiated concurrently, and no start function is
+	// called other than the start section of the binary
+	mod, err := p.runtime.InstantiateModule(ctx, p.compiled, wazero.NewModuleConfig().WithName("").WithStartFunctions())
+	if err != nil {
+		return nil, limitError(mod, err)
+	}
+	defer mod.Close(ctx)
+
+	allocated, err := mod.ExportedFunction(allocFunction).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, limitError(mod, err)
+	}
+	inputPtr := uint32(allocated[0])
+	if !mod.Memory().Write(inputPtr, input) {
+		return nil, fmt.Errorf("%s returned memory out of range", allocFunction)
+	}
+
+	results, err := mod.ExportedFunction(function).Call(ctx, uint64(inputPtr), uint64(len(input)))
+	if err != nil {
+		return nil, limitError(mod, err)
+	}
+	outputPtr, outputLen := uint32(results[0]>>32), uint32(results[0])
+	output, ok := mod.Memory().Read(outputPtr, outputLen)
+	if !ok {
+		return nil, errors.New("output out of memory range")
+	}
+	// the memory is released with the instance
+	return append([]byte(nil), output...), nil
+}
+
+// limitError returns the error of the plugin limit the instance exceeded if any, the error otherwise
+func limitError(mod api.Module, err error) error {
+	if mod == nil {
+		return err
+	}
+	global := mod.ExportedGlobal(limitGlobal)
+	if global == nil {
+		return err
+	}
+	switch global.Get() {
+	case limitFuel:
+		return errOutOfFuel
+	case limitMemory:
+		return errMemoryExceeded
+	}
+	return err
+}
+
+func (p *wazeroPlugin) Close(ctx context.Context) error {
+	return p.compiled.Close(ctx)
+}
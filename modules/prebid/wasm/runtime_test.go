@@ -0,0 +1,257 @@
+package wasm
+
+import (
+	"context"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureModule loads testdata/plugin.wasm, whose source is testdata/plugin.wat, with the given limits
+func newFixtureModule(t *testing.T, timeoutMs int, limits Limits) Module {
+	cfg := config{Plugins: []pluginConfig{{
+		Name:        "plugin",
+		Path:        "testdata/plugin.wasm",
+		TimeoutMs:   timeoutMs,
+		Fuel:        limits.Fuel,
+		MemoryPages: limits.MemoryPages,
+	}}}
+
+	ctx := context.Background()
+	m, err := newModule(ctx, cfg, newWazeroRuntime(ctx), os.ReadFile)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.Shutdown() })
+	return m
+}
+
+func TestWazeroPluginCall(t *testing.T) {
+	miCtx := hookstage.ModuleInvocationContext{HookImplCode: "plugin"}
+	smallRequest := &openrtb2.BidRequest{ID: "request-id"}
+	// the request does not fit in the single page of memory the plugin starts with
+	largeRequest := &openrtb2.BidRequest{ID: strings.Repeat("a", 100_000)}
+
+	testCases := []struct {
+		name            string
+		timeoutMs       int
+		limits          Limits
+		request         *openrtb2.BidRequest
+		expectedMessage string
+		expectedErr     string
+	}{
+		{
+			name:            "within_limits",
+			timeoutMs:       1000,
+			limits:          Limits{Fuel: defaultFuel, MemoryPages: defaultMemoryPages},
+			request:         largeRequest,
+			expectedMessage: "ok",
+		},
+		{
+			name:        "out_of_fuel",
+			timeoutMs:   1000,
+			limits:      Limits{Fuel: 100, MemoryPages: defaultMemoryPages},
+			request:     smallRequest,
+			expectedErr: "plugin plugin: handle_processed_auction_request failed: plugin ran out of fuel",
+		},
+		{
+			name:        "memory_exceeded",
+			timeoutMs:   1000,
+			limits:      Limits{Fuel: defaultFuel, MemoryPages: 1},
+			request:     largeRequest,
+			expectedErr: "plugin plugin: handle_processed_auction_request failed: plugin exceeded its memory limit",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newFixtureModule(t, tc.timeoutMs, tc.limits)
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: tc.request}}
+
+			result, err := m.HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+
+			if len(tc.expectedErr) > 0 {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, result.Message)
+		})
+	}
+}
+
+func TestWazeroPluginCallNeverReturning(t *testing.T) {
+	miCtx := hookstage.ModuleInvocationContext{HookImplCode: "plugin"}
+	payload := hookstage.BidderRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}}
+
+	m := newFixtureModule(t, 1000, Limits{Fuel: defaultFuel, MemoryPages: defaultMemoryPages})
+	_, err := m.HandleBidderRequestHook(context.Background(), miCtx, payload)
+	assert.EqualError(t, err, "plugin plugin: handle_bidder_request failed: plugin ran out of fuel")
+
+	m = newFixtureModule(t, 20, Limits{Fuel: math.MaxUint64, MemoryPages: defaultMemoryPages})
+	start := time.Now()
+	_, err = m.HandleBidderRequestHook(context.Background(), miCtx, payload)
+	assert.ErrorContains(t, err, "context deadline exceeded")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// bulkPlugin returns a plugin exporting its memory, an alloc function reserving the memory from address 1024 and
+// the stage functions fill and copy, which fill or copy as many bytes as their input holds and return no output
+func bulkPlugin(t *testing.T, limits Limits) Plugin {
+	binary := encodeBinary([]section{
+		{id: sectionType, contents: []byte{0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e}},
+		{id: sectionFunction, contents: []byte{0x03, 0x00, 0x01, 0x01}},
+		{id: sectionMemory, contents: []byte{0x01, 0x00, 0x01}},
+		{id: sectionExport, contents: []byte{
+			0x04,
+			0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+			0x05, 'a', 'l', 'l', 'o', 'c', 0x00, 0x00,
+			0x04, 'f', 'i', 'l', 'l', 0x00, 0x01,
+			0x04, 'c', 'o', 'p', 'y', 0x00, 0x02,
+		}},
+		{id: sectionCode, contents: []byte{
+			0x03,
+			// alloc: i32.const 1024
+			0x05, 0x00, 0x41, 0x80, 0x08, 0x0b,
+			// fill: memory.fill(ptr, 0, len), i64.const 0
+			0x0d, 0x00, 0x20, 0x00, 0x41, 0x00, 0x20, 0x01, 0xfc, 0x0b, 0x00, 0x42, 0x00, 0x0b,
+			// copy: memory.copy(ptr, ptr, len), i64.const 0
+			0x0e, 0x00, 0x20, 0x00, 0x20, 0x00, 0x20, 0x01, 0xfc, 0x0a, 0x00, 0x00, 0x42, 0x00, 0x0b,
+		}},
+	})
+
+	p, err := newWazeroRuntime(context.Background()).Compile(context.Background(), binary, limits)
+	require.NoError(t, err)
+	t.Cleanup(func() { p.Close(context.Background()) })
+	return p
+}
+
+func TestWazeroPluginCallBulkMemory(t *testing.T) {
+	p := bulkPlugin(t, Limits{Fuel: 1000, MemoryPages: 1})
+
+	for _, function := range []string{"fill", "copy"} {
+		t.Run(function, func(t *testing.T) {
+			output, err := p.Call(context.Background(), function, make([]byte, 100))
+			require.NoError(t, err)
+			assert.Empty(t, output)
+
+			// the instructions are charged one fuel per byte
+			_, err = p.Call(context.Background(), function, make([]byte, 10_000))
+			assert.Equal(t, errOutOfFuel, err)
+		})
+	}
+}
+
+func TestWazeroRuntimeCompile(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/plugin.wasm")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		binary      []byte
+		limits      Limits
+		expectedErr string
+	}{
+		{
+			name:   "fixture",
+			binary: fixture,
+			limits: Limits{Fuel: defaultFuel, MemoryPages: 1},
+		},
+		{
+			// a memory and an alloc function returning 1024, without any global nor any stage function
+			name: "sections_added",
+			binary: []byte{
+				0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+				0x01, 0x06, 0x01, 0x60, 0x01, 0x7f, 0x01, 0x7f,
+				0x03, 0x02, 0x01, 0x00,
+				0x05, 0x03, 0x01, 0x00, 0x01,
+				0x07, 0x12, 0x02, 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00, 0x05, 'a', 'l', 'l', 'o', 'c', 0x00, 0x00,
+				0x0a, 0x07, 0x01, 0x05, 0x00, 0x41, 0x80, 0x08, 0x0b,
+			},
+			limits: Limits{Fuel: defaultFuel, MemoryPages: 1},
+		},
+		{
+			name:        "memory_exceeding_limit",
+			binary:      fixture,
+			limits:      Limits{Fuel: defaultFuel, MemoryPages: 0},
+			expectedErr: "plugin memory of 1 pages exceeds its limit of 0 pages",
+		},
+		{
+			name: "host_function_imported",
+			binary: []byte{
+				0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+				0x01, 0x04, 0x01, 0x60, 0x00, 0x00,
+				0x02, 0x07, 0x01, 0x03, 'e', 'n', 'v', 0x01, 'f', 0x00, 0x00,
+			},
+			limits:      Limits{Fuel: defaultFuel, MemoryPages: 1},
+			expectedErr: "plugins must not import anything",
+		},
+		{
+			name: "table_exceeding_limit",
+			binary: encodeBinary([]section{
+				{id: sectionTable, contents: []byte{0x01, 0x70, 0x00, 0xa0, 0x8d, 0x06}},
+				{id: sectionMemory, contents: []byte{0x01, 0x00, 0x01}},
+			}),
+			limits:      Limits{Fuel: defaultFuel, MemoryPages: 1},
+			expectedErr: "plugin table of 100000 elements exceeds the limit of 10000 elements",
+		},
+		{
+			// ref.null func, i32.const 1, table.grow 0, drop
+			name: "table_grow",
+			binary: encodeBinary([]section{
+				{id: sectionType, contents: []byte{0x01, 0x60, 0x00, 0x00}},
+				{id: sectionFunction, contents: []byte{0x01, 0x00}},
+				{id: sectionTable, contents: []byte{0x01, 0x70, 0x00, 0x01}},
+				{id: sectionMemory, contents: []byte{0x01, 0x00, 0x01}},
+				{id: sectionCode, contents: []byte{0x01, 0x09, 0x00, 0xd0, 0x70, 0x41, 0x01, 0xfc, 0x0f, 0x00, 0x1a, 0x0b}},
+			}),
+			limits:      Limits{Fuel: defaultFuel, MemoryPages: 1},
+			expectedErr: "function 0: unsupported instruction 0xfc 15",
+		},
+		{
+			// i32.const 0, ref.null func, i32.const 1, table.fill 0
+			name: "table_fill",
+			binary: encodeBinary([]section{
+				{id: sectionType, contents: []byte{0x01, 0x60, 0x00, 0x00}},
+				{id: sectionFunction, contents: []byte{0x01, 0x00}},
+				{id: sectionTable, contents: []byte{0x01, 0x70, 0x00, 0x01}},
+				{id: sectionMemory, contents: []byte{0x01, 0x00, 0x01}},
+				{id: sectionCode, contents: []byte{0x01, 0x0a, 0x00, 0x41, 0x00, 0xd0, 0x70, 0x41, 0x01, 0xfc, 0x11, 0x00, 0x0b}},
+			}),
+			limits:      Limits{Fuel: defaultFuel, MemoryPages: 1},
+			expectedErr: "function 0: unsupported instruction 0xfc 17",
+		},
+		{
+			name:        "without_memory",
+			binary:      []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00},
+			limits:      Limits{Fuel: defaultFuel, MemoryPages: 1},
+			expectedErr: "plugins must define their memory",
+		},
+		{
+			name:        "not_wasm",
+			binary:      []byte("plugin"),
+			limits:      Limits{Fuel: defaultFuel, MemoryPages: 1},
+			expectedErr: "invalid WebAssembly binary header",
+		},
+	}
+
+	rt := newWazeroRuntime(context.Background())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := rt.Compile(context.Background(), tc.binary, tc.limits)
+			if len(tc.expectedErr) > 0 {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.False(t, p.Exports("alloc"), "alloc is not a stage function")
+			assert.NoError(t, p.Close(context.Background()))
+		})
+	}
+}
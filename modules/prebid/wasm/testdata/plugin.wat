@@ -0,0 +1,66 @@
+;; Source of plugin.wasm. The plugin answers the processed auction request stage with a message once it read
+;; its whole input, and never returns from the bidder request stage.
+(module
+  (memory (export "memory") 1)
+  (global $heap (mut i32) (i32.const 1024))
+  (data (i32.const 0) "{\"message\":\"ok\"}")
+
+  ;; alloc reserves the bytes following the ones reserved before, growing the memory until they fit
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    global.get $heap
+    local.set $ptr
+    local.get $ptr
+    local.get $size
+    i32.add
+    global.set $heap
+    block $fits
+      loop $grow
+        global.get $heap
+        memory.size
+        i32.const 16
+        i32.shl
+        i32.le_u
+        br_if $fits
+        i32.const 1
+        memory.grow
+        i32.const -1
+        i32.eq
+        if
+          unreachable
+        end
+        br $grow
+      end
+    end
+    local.get $ptr)
+
+  (func (export "handle_processed_auction_request") (param $ptr i32) (param $len i32) (result i64)
+    (local $end i32)
+    local.get $ptr
+    local.get $len
+    i32.add
+    local.set $end
+    block $done
+      loop $read
+        local.get $ptr
+        local.get $end
+        i32.ge_u
+        br_if $done
+        local.get $ptr
+        i32.load8_u
+        drop
+        local.get $ptr
+        i32.const 1
+        i32.add
+        local.set $ptr
+        br $read
+      end
+    end
+    ;; the output is 16 bytes long and written at address 0
+    i64.const 16)
+
+  (func (export "handle_bidder_request") (param $ptr i32) (param $len i32) (result i64)
+    loop $spin
+      br $spin
+    end
+    unreachable))
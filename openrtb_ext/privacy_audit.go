@@ -0,0 +1,53 @@
+package openrtb_ext
+
+// PrivacyAudit records the privacy signals of an auction and the privacy decisions taken for each bidder, so that
+// why user data was or was not sent to a bidder can be reconstructed. It is returned in bidresponse.ext.debug.privacy
+// when debug is allowed.
+type PrivacyAudit struct {
+	Signals PrivacyAuditSignals                `json:"signals"`
+	Bidders map[BidderName]*PrivacyAuditBidder `json:"bidders,omitempty"`
+}
+
+// PrivacyAuditSignals are the privacy signals parsed from the request
+type PrivacyAuditSignals struct {
+	// GDPR is the GDPR signal: 1 when GDPR applies, 0 when it does not and -1 when ambiguous
+	GDPR         int  `json:"gdpr"`
+	GDPREnforced bool `json:"gdprenforced,omitempty"`
+	// TCFVersion is the version of the TCF consent string, when GDPR is enforced and the string is valid
+	TCFVersion int `json:"tcfversion,omitempty"`
+	// GPPSections are the IDs of the sections of the GPP string
+	GPPSections  []int  `json:"gppsections,omitempty"`
+	GPPSID       []int8 `json:"gppsid,omitempty"`
+	CCPAProvided bool   `json:"ccpaprovided,omitempty"`
+	CCPAEnforced bool   `json:"ccpaenforced,omitempty"`
+	COPPA        bool   `json:"coppa,omitempty"`
+	LMT          bool   `json:"lmt,omitempty"`
+	GPC          bool   `json:"gpc,omitempty"`
+}
+
+// PrivacyAuditBidder are the privacy decisions taken for a bidder
+type PrivacyAuditBidder struct {
+	// Blocked is why the bidder was not called, "fetchBids" when the activity was denied or "gdpr" when the bid
+	// request was not allowed by TCF
+	Blocked    string                 `json:"blocked,omitempty"`
+	Activities []PrivacyAuditActivity `json:"activities,omitempty"`
+	// GDPR holds the TCF permissions of the bidder, when GDPR is enforced
+	GDPR         *PrivacyAuditGDPR `json:"gdpr,omitempty"`
+	CCPAEnforced bool              `json:"ccpaenforced,omitempty"`
+	// Scrubbers are the privacy scrubbing functions applied to the request of the bidder, in order
+	Scrubbers []string `json:"scrubbers,omitempty"`
+}
+
+// PrivacyAuditActivity is an activity control check and the rule which decided it
+type PrivacyAuditActivity struct {
+	Activity string `json:"activity"`
+	Allowed  bool   `json:"allowed"`
+	Rule     string `json:"rule"`
+}
+
+// PrivacyAuditGDPR are the TCF permissions of a bidder
+type PrivacyAuditGDPR struct {
+	AllowBidRequest bool `json:"allowbidrequest"`
+	PassGeo         bool `json:"passgeo"`
+	PassID          bool `json:"passid"`
+}
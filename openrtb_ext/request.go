@@ -59,6 +59,7 @@ type ExtRequestPrebid struct {
 	Experiment           *Experiment                     `json:"experiment,omitempty"`
 	Floors               *PriceFloorRules                `json:"floors,omitempty"`
 	Integration          string                          `json:"integration,omitempty"`
+	IVT                  *ExtRequestPrebidIVT            `json:"ivt,omitempty"`
 	MultiBid             []*ExtMultiBid                  `json:"multibid,omitempty"`
 	MultiBidMap          map[string]ExtMultiBid          `json:"-"`
 	Passthrough          json.RawMessage                 `json:"passthrough,omitempty"`
@@ -111,6 +112,13 @@ type AdsCert struct {
 	Enabled bool `json:"enabled,omitempty"`
 }
 
+// ExtRequestPrebidIVT holds the invalid traffic score of the request along with the reasons of the score,
+// set by the module detecting invalid traffic for use by the later stages of the auction
+type ExtRequestPrebidIVT struct {
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
 type BidderConfig struct {
 	Bidders []string `json:"bidders,omitempty"`
 	Config  *Config  `json:"config,omitempty"`
@@ -515,6 +523,10 @@ func (erp *ExtRequestPrebid) Clone() *ExtRequestPrebid {
 
 	clone.NoSale = slices.Clone(erp.NoSale)
 
+	if erp.IVT != nil {
+		clone.IVT = &ExtRequestPrebidIVT{Score: erp.IVT.Score, Reasons: slices.Clone(erp.IVT.Reasons)}
+	}
+
 	if erp.AlternateBidderCodes != nil {
 		newAlternateBidderCodes := ExtAlternateBidderCodes{Enabled: erp.AlternateBidderCodes.Enabled}
 		if erp.AlternateBidderCodes.Bidders != nil {
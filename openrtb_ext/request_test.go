@@ -568,6 +568,20 @@ func TestCloneExtRequestPrebid(t *testing.T) {
 				prebid.NoSale = append(prebid.NoSale, "D")
 			},
 		},
+		{
+			name: "IVT",
+			prebid: &ExtRequestPrebid{
+				IVT: &ExtRequestPrebidIVT{Score: 50, Reasons: []string{"datacenter_ip"}},
+			},
+			prebidCopy: &ExtRequestPrebid{
+				IVT: &ExtRequestPrebidIVT{Score: 50, Reasons: []string{"datacenter_ip"}},
+			},
+			mutator: func(t *testing.T, prebid *ExtRequestPrebid) {
+				prebid.IVT.Score = 100
+				prebid.IVT.Reasons[0] = "user_agent"
+				prebid.IVT = nil
+			},
+		},
 		{
 			name: "AlternateBidderCodes",
 			prebid: &ExtRequestPrebid{
@@ -3,6 +3,7 @@ package ortb
 import (
 	"fmt"
 
+	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/openrtb/v20/openrtb2"
 )
 
@@ -30,5 +31,30 @@ func validateVideo(video *openrtb2.Video, impIndex int) error {
 		return fmt.Errorf("request.imp[%d].video.maxbitrate must be a positive number", impIndex)
 	}
 
+	return validateVideoPod(video, impIndex)
+}
+
+// validateVideoPod validates the OpenRTB 2.6 ad pod fields of the video
+func validateVideoPod(video *openrtb2.Video, impIndex int) error {
+	if video.MaxSeq < 0 {
+		return fmt.Errorf("request.imp[%d].video.maxseq must be a positive number", impIndex)
+	}
+	if video.PodDur < 0 {
+		return fmt.Errorf("request.imp[%d].video.poddur must be a positive number", impIndex)
+	}
+	if video.PodSeq < adcom1.PodSeqLast || video.PodSeq > adcom1.PodSeqFirst {
+		return fmt.Errorf("request.imp[%d].video.podseq must be one of -1, 0 or 1", impIndex)
+	}
+	if video.SlotInPod < adcom1.SlotPosLast || video.SlotInPod > adcom1.SlotPosFirstOrLast {
+		return fmt.Errorf("request.imp[%d].video.slotinpod must be one of -1, 0, 1 or 2", impIndex)
+	}
+	// minduration and maxduration are ignored when rqddurs is set rather than rejected, being mutually exclusive
+	// with it in OpenRTB 2.6 while sent alongside it by the players predating it
+	for _, duration := range video.RqdDurs {
+		if duration <= 0 {
+			return fmt.Errorf("request.imp[%d].video.rqddurs must contain positive numbers", impIndex)
+		}
+	}
+
 	return nil
 }
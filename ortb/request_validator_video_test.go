@@ -3,6 +3,7 @@ package ortb
 import (
 	"testing"
 
+	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
@@ -96,6 +97,68 @@ func TestValidateVideo(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "well_formed_dynamic_pod",
+			video: &openrtb2.Video{
+				MIMEs:     []string{"MIME1"},
+				PodID:     "pod1",
+				PodDur:    60,
+				MaxSeq:    4,
+				PodSeq:    adcom1.PodSeqFirst,
+				SlotInPod: adcom1.SlotPosFirstOrLast,
+				RqdDurs:   []int64{15, 30},
+			},
+			wantError: false,
+		},
+		{
+			name: "negative_max_seq",
+			video: &openrtb2.Video{
+				MIMEs:  []string{"MIME1"},
+				MaxSeq: -1,
+			},
+			wantError: true,
+		},
+		{
+			name: "negative_pod_dur",
+			video: &openrtb2.Video{
+				MIMEs:  []string{"MIME1"},
+				PodDur: -1,
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid_pod_seq",
+			video: &openrtb2.Video{
+				MIMEs:  []string{"MIME1"},
+				PodSeq: 2,
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid_slot_in_pod",
+			video: &openrtb2.Video{
+				MIMEs:     []string{"MIME1"},
+				SlotInPod: 3,
+			},
+			wantError: true,
+		},
+		{
+			name: "rqd_durs_with_max_duration",
+			video: &openrtb2.Video{
+				MIMEs:       []string{"MIME1"},
+				RqdDurs:     []int64{15},
+				MaxDuration: 30,
+			},
+			wantError: false,
+		},
+		{
+			name: "non_positive_rqd_durs",
+			video: &openrtb2.Video{
+				MIMEs:   []string{"MIME1"},
+				RqdDurs: []int64{0},
+			},
+			wantError: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -1,6 +1,9 @@
 package privacy
 
 import (
+	"fmt"
+
+	gpplib "github.com/prebid/go-gpp"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
@@ -26,6 +29,19 @@ func NewRequestFromBidRequest(r openrtb_ext.RequestWrapper) ActivityRequest {
 type ActivityRequest struct {
 	policies   *Policies
 	bidRequest *openrtb_ext.RequestWrapper
+	gpp        *parsedGPP
+}
+
+// parsedGPP is the GPP string of an activity request already parsed, along with the errors of its parsing
+type parsedGPP struct {
+	container gpplib.GppContainer
+	errs      []error
+}
+
+// WithGPP returns the request with its GPP string already parsed, which the rules use instead of parsing it again
+func (r ActivityRequest) WithGPP(gpp gpplib.GppContainer, errs []error) ActivityRequest {
+	r.gpp = &parsedGPP{container: gpp, errs: errs}
+	return r
 }
 
 func (r ActivityRequest) IsPolicies() bool {
@@ -45,19 +61,33 @@ type ActivityControl struct {
 func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	ac := ActivityControl{}
 
-	if cfg == nil || cfg.AllowActivities == nil {
+	if cfg == nil || (cfg.AllowActivities == nil && !cfg.USNat.Enabled) {
 		return ac
 	}
 
+	var allowActivities config.AllowActivities
+	if cfg.AllowActivities != nil {
+		allowActivities = *cfg.AllowActivities
+	}
+
 	plans := make(map[Activity]ActivityPlan, 8)
-	plans[ActivitySyncUser] = buildPlan(cfg.AllowActivities.SyncUser)
-	plans[ActivityFetchBids] = buildPlan(cfg.AllowActivities.FetchBids)
-	plans[ActivityEnrichUserFPD] = buildPlan(cfg.AllowActivities.EnrichUserFPD)
-	plans[ActivityReportAnalytics] = buildPlan(cfg.AllowActivities.ReportAnalytics)
-	plans[ActivityTransmitUserFPD] = buildPlan(cfg.AllowActivities.TransmitUserFPD)
-	plans[ActivityTransmitPreciseGeo] = buildPlan(cfg.AllowActivities.TransmitPreciseGeo)
-	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(cfg.AllowActivities.TransmitUniqueRequestIds)
-	plans[ActivityTransmitTIDs] = buildPlan(cfg.AllowActivities.TransmitTids)
+	plans[ActivitySyncUser] = buildPlan(allowActivities.SyncUser)
+	plans[ActivityFetchBids] = buildPlan(allowActivities.FetchBids)
+	plans[ActivityEnrichUserFPD] = buildPlan(allowActivities.EnrichUserFPD)
+	plans[ActivityReportAnalytics] = buildPlan(allowActivities.ReportAnalytics)
+	plans[ActivityTransmitUserFPD] = buildPlan(allowActivities.TransmitUserFPD)
+	plans[ActivityTransmitPreciseGeo] = buildPlan(allowActivities.TransmitPreciseGeo)
+	plans[ActivityTransmitUniqueRequestIDs] = buildPlan(allowActivities.TransmitUniqueRequestIds)
+	plans[ActivityTransmitTIDs] = buildPlan(allowActivities.TransmitTids)
+
+	// the opt-outs of the users are enforced before the rules of the publisher
+	if cfg.USNat.Enabled {
+		for _, activity := range usnatActivities {
+			plan := plans[activity]
+			plan.rules = append([]Rule{USNatRule{activity: activity, cfg: cfg.USNat}}, plan.rules...)
+			plans[activity] = plan
+		}
+	}
 	ac.plans = plans
 
 	ac.IPv4Config = cfg.IPv4Config
@@ -86,6 +116,13 @@ func cfgToRules(rules []config.ActivityRule) []Rule {
 			result:        result,
 			componentName: r.Condition.ComponentName,
 			componentType: r.Condition.ComponentType,
+			gppSID:        r.Condition.GPPSID,
+			geoCountry:    r.Condition.GeoCountry,
+			geoRegion:     r.Condition.GeoRegion,
+			coppa:         r.Condition.COPPA,
+			gdpr:          r.Condition.GDPR,
+			channel:       r.Condition.Channel,
+			idPresent:     r.Condition.IDPresent,
 		}
 		enfRules = append(enfRules, er)
 	}
@@ -100,26 +137,57 @@ func cfgToDefaultResult(activityDefault *bool) bool {
 }
 
 func (e ActivityControl) Allow(activity Activity, target Component, request ActivityRequest) bool {
+	allowed, _ := e.Evaluate(activity, target, request)
+	return allowed
+}
+
+// Evaluate returns whether the activity is allowed, along with the name of the rule which decided it:
+// "rules[i]" for the i-th rule of the account activity config, "usnat" for the GPP US sections or "default"
+// when no rule matched.
+func (e ActivityControl) Evaluate(activity Activity, target Component, request ActivityRequest) (bool, string) {
 	plan, planDefined := e.plans[activity]
 
 	if !planDefined {
-		return defaultActivityResult
+		return defaultActivityResult, RuleNameDefault
 	}
 
-	return plan.Evaluate(target, request)
+	return plan.evaluate(target, request)
 }
 
+// Names of the rules deciding an activity
+const (
+	RuleNameDefault = "default"
+	RuleNameUSNat   = "usnat"
+)
+
 type ActivityPlan struct {
 	defaultResult bool
 	rules         []Rule
 }
 
 func (p ActivityPlan) Evaluate(target Component, request ActivityRequest) bool {
+	allowed, _ := p.evaluate(target, request)
+	return allowed
+}
+
+func (p ActivityPlan) evaluate(target Component, request ActivityRequest) (bool, string) {
+	conditionRules := 0
 	for _, rule := range p.rules {
 		result := rule.Evaluate(target, request)
 		if result == ActivityDeny || result == ActivityAllow {
-			return result == ActivityAllow
+			return result == ActivityAllow, ruleName(rule, conditionRules)
+		}
+		if _, ok := rule.(ConditionRule); ok {
+			conditionRules++
 		}
 	}
-	return p.defaultResult
+	return p.defaultResult, RuleNameDefault
+}
+
+// ruleName returns the name of the rule, the condition rules being named after their index in the account config
+func ruleName(rule Rule, conditionIndex int) string {
+	if _, ok := rule.(USNatRule); ok {
+		return RuleNameUSNat
+	}
+	return fmt.Sprintf("rules[%d]", conditionIndex)
 }
@@ -3,6 +3,7 @@ package privacy
 import (
 	"testing"
 
+	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
@@ -51,6 +52,30 @@ func TestNewActivityControl(t *testing.T) {
 				IPv4Config: config.IPv4{AnonKeepBits: 16},
 			},
 		},
+		{
+			name: "usnat_enabled",
+			privacyConf: config.AccountPrivacy{
+				AllowActivities: &config.AllowActivities{
+					TransmitPreciseGeo: getTestActivityConfig(false),
+				},
+				USNat: config.AccountUSNat{Enabled: true},
+			},
+			activityControl: ActivityControl{
+				plans: map[Activity]ActivityPlan{
+					ActivitySyncUser:        {defaultResult: true, rules: []Rule{USNatRule{activity: ActivitySyncUser, cfg: config.AccountUSNat{Enabled: true}}}},
+					ActivityFetchBids:       {defaultResult: true},
+					ActivityEnrichUserFPD:   {defaultResult: true},
+					ActivityReportAnalytics: {defaultResult: true},
+					ActivityTransmitUserFPD: {defaultResult: true, rules: []Rule{USNatRule{activity: ActivityTransmitUserFPD, cfg: config.AccountUSNat{Enabled: true}}}},
+					ActivityTransmitPreciseGeo: {defaultResult: true, rules: []Rule{
+						USNatRule{activity: ActivityTransmitPreciseGeo, cfg: config.AccountUSNat{Enabled: true}},
+						ConditionRule{result: ActivityDeny, componentName: []string{"bidderA"}, componentType: []string{"bidder"}},
+					}},
+					ActivityTransmitUniqueRequestIDs: {defaultResult: true},
+					ActivityTransmitTIDs:             {defaultResult: true},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -142,6 +167,139 @@ func TestActivityControlAllow(t *testing.T) {
 	}
 }
 
+func TestActivityControlEvaluate(t *testing.T) {
+	usnatOptedOut := NewRequestFromPolicies(Policies{GPP: testUSNatSaleOptedOut, GPPSID: []int8{7}})
+	plan := ActivityPlan{
+		defaultResult: false,
+		rules: []Rule{
+			USNatRule{activity: ActivitySyncUser},
+			ConditionRule{result: ActivityAllow, componentName: []string{"bidderA"}},
+			ConditionRule{result: ActivityDeny, componentName: []string{"bidderB"}},
+		},
+	}
+	activityControl := ActivityControl{plans: map[Activity]ActivityPlan{ActivitySyncUser: plan}}
+
+	testCases := []struct {
+		name            string
+		activity        Activity
+		target          Component
+		request         ActivityRequest
+		expectedAllowed bool
+		expectedRule    string
+	}{
+		{
+			name:            "plan_not_defined",
+			activity:        ActivityFetchBids,
+			target:          Component{Type: "bidder", Name: "bidderA"},
+			expectedAllowed: true,
+			expectedRule:    RuleNameDefault,
+		},
+		{
+			name:            "usnat_rule",
+			activity:        ActivitySyncUser,
+			target:          Component{Type: "bidder", Name: "bidderA"},
+			request:         usnatOptedOut,
+			expectedAllowed: false,
+			expectedRule:    RuleNameUSNat,
+		},
+		{
+			name:            "first_condition_rule",
+			activity:        ActivitySyncUser,
+			target:          Component{Type: "bidder", Name: "bidderA"},
+			expectedAllowed: true,
+			expectedRule:    "rules[0]",
+		},
+		{
+			name:            "second_condition_rule",
+			activity:        ActivitySyncUser,
+			target:          Component{Type: "bidder", Name: "bidderB"},
+			expectedAllowed: false,
+			expectedRule:    "rules[1]",
+		},
+		{
+			name:            "no_rule_matched",
+			activity:        ActivitySyncUser,
+			target:          Component{Type: "bidder", Name: "bidderC"},
+			expectedAllowed: false,
+			expectedRule:    RuleNameDefault,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			allowed, rule := activityControl.Evaluate(test.activity, test.target, test.request)
+			assert.Equal(t, test.expectedAllowed, allowed)
+			assert.Equal(t, test.expectedRule, rule)
+		})
+	}
+}
+
+func TestActivityControlGeoCondition(t *testing.T) {
+	activityControl := NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitPreciseGeo: config.Activity{
+				Rules: []config.ActivityRule{
+					{
+						Condition: config.ActivityCondition{ComponentType: []string{"bidder"}, GeoCountry: []string{"USA"}, GeoRegion: []string{"CA", "VA"}},
+						Allow:     false,
+					},
+				},
+			},
+		},
+	})
+	bidder := Component{Type: "bidder", Name: "bidderA"}
+
+	testCases := []struct {
+		name     string
+		geo      *openrtb2.Geo
+		expected bool
+	}{
+		{name: "listed_state", geo: &openrtb2.Geo{Country: "USA", Region: "VA"}, expected: false},
+		{name: "other_state", geo: &openrtb2.Geo{Country: "USA", Region: "NY"}, expected: true},
+		{name: "no_geo", geo: nil, expected: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{Geo: test.geo}}})
+			assert.Equal(t, test.expected, activityControl.Allow(ActivityTransmitPreciseGeo, bidder, request))
+		})
+	}
+}
+
+func TestActivityControlGPPSIDCondition(t *testing.T) {
+	activityControl := NewActivityControl(&config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			TransmitPreciseGeo: config.Activity{
+				Rules: []config.ActivityRule{
+					{
+						Condition: config.ActivityCondition{GPPSID: []int8{7, 8}},
+						Allow:     false,
+					},
+				},
+			},
+		},
+	})
+	bidder := Component{Type: "bidder", Name: "bidderA"}
+
+	testCases := []struct {
+		name     string
+		regs     *openrtb2.Regs
+		expected bool
+	}{
+		{name: "listed_section", regs: &openrtb2.Regs{GPPSID: []int8{2, 8}}, expected: false},
+		{name: "other_section", regs: &openrtb2.Regs{GPPSID: []int8{2}}, expected: true},
+		{name: "no_regs", regs: nil, expected: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: test.regs}})
+			assert.Equal(t, test.expected, activityControl.Allow(ActivityTransmitPreciseGeo, bidder, request))
+		})
+	}
+}
+
 func TestActivityRequest(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		r := ActivityRequest{}
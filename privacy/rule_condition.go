@@ -1,5 +1,11 @@
 package privacy
 
+import (
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
 // noClausesDefinedResult represents the default return when there is no matching criteria specified.
 const noClausesDefinedResult = true
 
@@ -8,6 +14,12 @@ type ConditionRule struct {
 	componentName []string
 	componentType []string
 	gppSID        []int8
+	geoCountry    []string
+	geoRegion     []string
+	coppa         *bool
+	gdpr          *bool
+	channel       []string
+	idPresent     *bool
 }
 
 func (r ConditionRule) Evaluate(target Component, request ActivityRequest) ActivityResult {
@@ -23,6 +35,10 @@ func (r ConditionRule) Evaluate(target Component, request ActivityRequest) Activ
 		return ActivityAbstain
 	}
 
+	if matched := evaluateRequest(r, request); !matched {
+		return ActivityAbstain
+	}
+
 	return r.result
 }
 
@@ -82,3 +98,79 @@ func getGPPSID(request ActivityRequest) []int8 {
 
 	return nil
 }
+
+// evaluateRequest matches the geo, regs, channel and ID presence clauses, which only a bid request can match
+func evaluateRequest(r ConditionRule, request ActivityRequest) bool {
+	if len(r.geoCountry) == 0 && len(r.geoRegion) == 0 && r.coppa == nil && r.gdpr == nil && len(r.channel) == 0 && r.idPresent == nil {
+		return noClausesDefinedResult
+	}
+	if !request.IsBidRequest() {
+		return false
+	}
+	bidRequest := request.bidRequest.BidRequest
+
+	var geo *openrtb2.Geo
+	if bidRequest.Device != nil {
+		geo = bidRequest.Device.Geo
+	}
+	if len(r.geoCountry) > 0 && (geo == nil || !containsFold(r.geoCountry, geo.Country)) {
+		return false
+	}
+	if len(r.geoRegion) > 0 && (geo == nil || !containsFold(r.geoRegion, geo.Region)) {
+		return false
+	}
+
+	if r.coppa != nil && *r.coppa != (bidRequest.Regs != nil && bidRequest.Regs.COPPA == 1) {
+		return false
+	}
+	if r.gdpr != nil {
+		if bidRequest.Regs == nil || bidRequest.Regs.GDPR == nil || *r.gdpr != (*bidRequest.Regs.GDPR == 1) {
+			return false
+		}
+	}
+
+	if len(r.channel) > 0 && !containsFold(r.channel, getChannel(bidRequest)) {
+		return false
+	}
+
+	if r.idPresent != nil && *r.idPresent != hasIDs(bidRequest) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func getChannel(bidRequest *openrtb2.BidRequest) string {
+	switch {
+	case bidRequest.Site != nil:
+		return "site"
+	case bidRequest.App != nil:
+		return "app"
+	case bidRequest.DOOH != nil:
+		return "dooh"
+	}
+	return ""
+}
+
+// hasIDs returns whether the request holds a user ID or a device ID
+func hasIDs(bidRequest *openrtb2.BidRequest) bool {
+	if user := bidRequest.User; user != nil && (user.ID != "" || user.BuyerUID != "" || len(user.EIDs) > 0) {
+		return true
+	}
+	if device := bidRequest.Device; device != nil {
+		return device.IFA != "" || device.DIDMD5 != "" || device.DIDSHA1 != "" || device.DPIDMD5 != "" ||
+			device.DPIDSHA1 != "" || device.MACMD5 != "" || device.MACSHA1 != ""
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -276,3 +277,135 @@ func TestGetGPPSID(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateRequest(t *testing.T) {
+	californiaSite := &openrtb2.BidRequest{
+		Site:   &openrtb2.Site{},
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA", Region: "CA"}},
+		Regs:   &openrtb2.Regs{COPPA: 1, GDPR: ptrutil.ToPtr[int8](0)},
+	}
+	identifiedApp := &openrtb2.BidRequest{
+		App:    &openrtb2.App{},
+		Device: &openrtb2.Device{IFA: "ifa"},
+		Regs:   &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+	}
+
+	testCases := []struct {
+		name       string
+		condition  ConditionRule
+		bidRequest *openrtb2.BidRequest
+		expected   bool
+	}{
+		{
+			name:       "no_clauses",
+			condition:  ConditionRule{},
+			bidRequest: californiaSite,
+			expected:   true,
+		},
+		{
+			name:       "geo_match",
+			condition:  ConditionRule{geoCountry: []string{"usa"}, geoRegion: []string{"VA", "CA"}},
+			bidRequest: californiaSite,
+			expected:   true,
+		},
+		{
+			name:       "geo_region_mismatch",
+			condition:  ConditionRule{geoCountry: []string{"USA"}, geoRegion: []string{"VA"}},
+			bidRequest: californiaSite,
+			expected:   false,
+		},
+		{
+			name:       "geo_missing",
+			condition:  ConditionRule{geoCountry: []string{"USA"}},
+			bidRequest: identifiedApp,
+			expected:   false,
+		},
+		{
+			name:       "coppa_match",
+			condition:  ConditionRule{coppa: ptrutil.ToPtr(true)},
+			bidRequest: californiaSite,
+			expected:   true,
+		},
+		{
+			name:       "coppa_unset_match",
+			condition:  ConditionRule{coppa: ptrutil.ToPtr(false)},
+			bidRequest: identifiedApp,
+			expected:   true,
+		},
+		{
+			name:       "coppa_mismatch",
+			condition:  ConditionRule{coppa: ptrutil.ToPtr(true)},
+			bidRequest: identifiedApp,
+			expected:   false,
+		},
+		{
+			name:       "gdpr_match",
+			condition:  ConditionRule{gdpr: ptrutil.ToPtr(false)},
+			bidRequest: californiaSite,
+			expected:   true,
+		},
+		{
+			name:       "gdpr_mismatch",
+			condition:  ConditionRule{gdpr: ptrutil.ToPtr(false)},
+			bidRequest: identifiedApp,
+			expected:   false,
+		},
+		{
+			name:       "gdpr_unset",
+			condition:  ConditionRule{gdpr: ptrutil.ToPtr(false)},
+			bidRequest: &openrtb2.BidRequest{},
+			expected:   false,
+		},
+		{
+			name:       "channel_match",
+			condition:  ConditionRule{channel: []string{"app", "dooh"}},
+			bidRequest: identifiedApp,
+			expected:   true,
+		},
+		{
+			name:       "channel_mismatch",
+			condition:  ConditionRule{channel: []string{"app", "dooh"}},
+			bidRequest: californiaSite,
+			expected:   false,
+		},
+		{
+			name:       "id_present_match",
+			condition:  ConditionRule{idPresent: ptrutil.ToPtr(true)},
+			bidRequest: identifiedApp,
+			expected:   true,
+		},
+		{
+			name:       "id_absent_match",
+			condition:  ConditionRule{idPresent: ptrutil.ToPtr(false)},
+			bidRequest: californiaSite,
+			expected:   true,
+		},
+		{
+			name:       "user_id_present",
+			condition:  ConditionRule{idPresent: ptrutil.ToPtr(false)},
+			bidRequest: &openrtb2.BidRequest{User: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "source"}}}},
+			expected:   false,
+		},
+		{
+			name:       "all_clauses_match",
+			condition:  ConditionRule{geoCountry: []string{"USA"}, geoRegion: []string{"CA"}, coppa: ptrutil.ToPtr(true), gdpr: ptrutil.ToPtr(false), channel: []string{"site"}, idPresent: ptrutil.ToPtr(false)},
+			bidRequest: californiaSite,
+			expected:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: test.bidRequest})
+			assert.Equal(t, test.expected, evaluateRequest(test.condition, request))
+		})
+	}
+}
+
+func TestEvaluateRequestPolicies(t *testing.T) {
+	request := NewRequestFromPolicies(Policies{GPPSID: []int8{7}})
+
+	assert.True(t, evaluateRequest(ConditionRule{}, request))
+	assert.False(t, evaluateRequest(ConditionRule{geoCountry: []string{"USA"}}, request))
+	assert.False(t, evaluateRequest(ConditionRule{idPresent: ptrutil.ToPtr(false)}, request))
+}
@@ -0,0 +1,66 @@
+package privacy
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/privacy/usnat"
+)
+
+// usnatActivities are the activities the GPP US National and state sections are enforced on. The
+// transmitUniqueRequestIds activity is left out, as the exchange does not evaluate it when sending the requests to
+// the bidders.
+var usnatActivities = []Activity{
+	ActivitySyncUser,
+	ActivityTransmitUserFPD,
+	ActivityTransmitPreciseGeo,
+}
+
+// USNatRule denies an activity opted out of in the GPP US National and state sections applying to the request,
+// abstaining otherwise for the other rules to decide
+type USNatRule struct {
+	activity Activity
+	cfg      config.AccountUSNat
+}
+
+func (r USNatRule) Evaluate(target Component, request ActivityRequest) ActivityResult {
+	gpp, gppErrs := getGPP(request)
+	consent, ok := usnat.Read(gpp, gppErrs, getGPPSID(request), r.cfg)
+	if !ok {
+		return ActivityAbstain
+	}
+
+	var allowed bool
+	switch r.activity {
+	case ActivitySyncUser:
+		allowed = consent.AllowSyncUser()
+	case ActivityTransmitUserFPD:
+		allowed = consent.AllowTransmitUserFPD()
+	case ActivityTransmitPreciseGeo:
+		allowed = consent.AllowTransmitPreciseGeo()
+	default:
+		return ActivityAbstain
+	}
+
+	if allowed {
+		return ActivityAbstain
+	}
+	return ActivityDeny
+}
+
+func getGPP(request ActivityRequest) (gpplib.GppContainer, []error) {
+	if request.gpp != nil {
+		return request.gpp.container, request.gpp.errs
+	}
+
+	var gpp string
+	if request.IsPolicies() {
+		gpp = request.policies.GPP
+	} else if request.IsBidRequest() && request.bidRequest.Regs != nil {
+		gpp = request.bidRequest.Regs.GPP
+	}
+
+	if gpp == "" {
+		return gpplib.GppContainer{}, nil
+	}
+	return gpplib.Parse(gpp)
+}
@@ -0,0 +1,124 @@
+package privacy
+
+import (
+	"errors"
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// testUSNatAllowed is a GPP string with a US National section opted out of nothing
+	testUSNatAllowed = "DBABLA~BVVqAAAAAWA.QA"
+	// testUSNatSaleOptedOut is a GPP string with a US National section opted out of the sale of the data
+	testUSNatSaleOptedOut = "DBABLA~BVVaAAAAAWA.QA"
+)
+
+func TestUSNatRuleEvaluate(t *testing.T) {
+	bidder := Component{Type: ComponentTypeBidder, Name: "bidderA"}
+	allowedGPP, errs := gpplib.Parse(testUSNatAllowed)
+	assert.Empty(t, errs)
+
+	testCases := []struct {
+		name     string
+		activity Activity
+		cfg      config.AccountUSNat
+		request  ActivityRequest
+		expected ActivityResult
+	}{
+		{
+			name:     "policies_allowed",
+			activity: ActivitySyncUser,
+			request:  NewRequestFromPolicies(Policies{GPP: testUSNatAllowed, GPPSID: []int8{7}}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "policies_opted_out",
+			activity: ActivitySyncUser,
+			request:  NewRequestFromPolicies(Policies{GPP: testUSNatSaleOptedOut, GPPSID: []int8{7}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "activity_not_enforced",
+			activity: ActivityTransmitUniqueRequestIDs,
+			request:  NewRequestFromPolicies(Policies{GPP: testUSNatSaleOptedOut, GPPSID: []int8{7}}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "policies_section_not_applying",
+			activity: ActivitySyncUser,
+			request:  NewRequestFromPolicies(Policies{GPP: testUSNatSaleOptedOut, GPPSID: []int8{2}}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "policies_section_skipped",
+			activity: ActivitySyncUser,
+			cfg:      config.AccountUSNat{SkipSIDs: []int8{7}},
+			request:  NewRequestFromPolicies(Policies{GPP: testUSNatSaleOptedOut, GPPSID: []int8{7}}),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "policies_gpp_missing",
+			activity: ActivityTransmitUserFPD,
+			request:  NewRequestFromPolicies(Policies{GPPSID: []int8{7}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "bid_request_opted_out",
+			activity: ActivityTransmitPreciseGeo,
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPP: testUSNatSaleOptedOut, GPPSID: []int8{7}},
+			}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "bid_request_malformed",
+			activity: ActivityTransmitUserFPD,
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPP: "malformed", GPPSID: []int8{7}},
+			}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "bid_request_parsed_gpp",
+			activity: ActivityTransmitUserFPD,
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPP: testUSNatSaleOptedOut, GPPSID: []int8{7}},
+			}}).WithGPP(allowedGPP, nil),
+			expected: ActivityAbstain,
+		},
+		{
+			name:     "bid_request_parsed_gpp_errors",
+			activity: ActivityTransmitUserFPD,
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPP: testUSNatAllowed, GPPSID: []int8{7}},
+			}}).WithGPP(allowedGPP, []error{errors.New("malformed")}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "bid_request_unparsed_state",
+			activity: ActivitySyncUser,
+			request: NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Regs: &openrtb2.Regs{GPP: testUSNatAllowed, GPPSID: []int8{7, 16}},
+			}}),
+			expected: ActivityDeny,
+		},
+		{
+			name:     "activity_not_enforced",
+			activity: ActivityFetchBids,
+			request:  NewRequestFromPolicies(Policies{GPP: testUSNatSaleOptedOut, GPPSID: []int8{7}}),
+			expected: ActivityAbstain,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			rule := USNatRule{activity: test.activity, cfg: test.cfg}
+			assert.Equal(t, test.expected, rule.Evaluate(bidder, test.request))
+		})
+	}
+}
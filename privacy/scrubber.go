@@ -12,6 +12,16 @@ import (
 	"github.com/prebid/prebid-server/v3/util/iputil"
 )
 
+// Names of the scrubbers applied to the bidder requests, as recorded in the privacy audit of an auction
+const (
+	ScrubberUserFPD                    = "ScrubUserFPD"
+	ScrubberGdprID                     = "ScrubGdprID"
+	ScrubberGeoAndDeviceIP             = "ScrubGeoAndDeviceIP"
+	ScrubberDeviceIDsIPsUserDemoExt    = "ScrubDeviceIDsIPsUserDemoExt"
+	ScrubberDeviceIDsIPsUserDemoExtGeo = "ScrubDeviceIDsIPsUserDemoExt+geo"
+	ScrubberTID                        = "ScrubTID"
+)
+
 type IPConf struct {
 	IPV6 config.IPv6
 	IPV4 config.IPv4
@@ -0,0 +1,260 @@
+package usnat
+
+import (
+	"slices"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+	"github.com/prebid/prebid-server/v3/config"
+	gppPolicy "github.com/prebid/prebid-server/v3/privacy/gpp"
+)
+
+// Values of the GPP US sections fields
+const (
+	// notProvided is the value of the notice fields when the notice was not provided to the user
+	notProvided byte = 2
+	// optedOut is the value of the opt-out fields when the user opted out, and of the consent fields when the user
+	// did not consent
+	optedOut byte = 1
+	// serviceProviderMode is the value of MspaServiceProviderMode when the transaction is in the MSPA service
+	// provider mode, the sale and sharing of the data being then forbidden
+	serviceProviderMode byte = 1
+)
+
+// noPreciseGeolocation is the index of the precise geolocation sensitive data category of the sections without it
+const noPreciseGeolocation = -1
+
+// stateSIDs are the GPP US state sections supported, the other ones not being parsed by the GPP library yet
+var stateSIDs = []gppConstants.SectionID{
+	gppConstants.SectionUSPCA,
+	gppConstants.SectionUSPVA,
+	gppConstants.SectionUSPCO,
+	gppConstants.SectionUSPUT,
+	gppConstants.SectionUSPCT,
+}
+
+// unparsedStateSIDs are the GPP US state sections the GPP library does not parse yet: Florida, Montana, Oregon,
+// Texas, Delaware, Iowa, Nebraska, New Hampshire, New Jersey, Tennessee and Minnesota. Their opt-outs being unknown,
+// a request they apply to is opted out of everything.
+var unparsedStateSIDs = []gppConstants.SectionID{13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23}
+
+// Consent holds the opt-outs of the GPP US sections applying to a request, normalized onto the fields of the US
+// National section. The opt-outs of several sections add up.
+type Consent struct {
+	SaleOptOut                bool `json:"saleOptOut"`
+	SharingOptOut             bool `json:"sharingOptOut"`
+	TargetedAdvertisingOptOut bool `json:"targetedAdvertisingOptOut"`
+	// SensitiveDataOptOut is whether the processing of a category of sensitive data, other than the precise
+	// geolocation, is opted out of or not consented to
+	SensitiveDataOptOut bool `json:"sensitiveDataOptOut"`
+	// PreciseGeolocationOptOut is whether the processing of the precise geolocation is opted out of or not consented to
+	PreciseGeolocationOptOut bool `json:"preciseGeolocationOptOut"`
+	// KnownChild is whether the user is a known child who did not consent to the processing of their data
+	KnownChild          bool `json:"knownChild"`
+	ServiceProviderMode bool `json:"serviceProviderMode"`
+	GPC                 bool `json:"gpc"`
+}
+
+// Read returns the consent of the GPP US sections listed in the SIDs of the request and not skipped, and false
+// when none applies. The state sections are enforced only with the normalize mode. A request whose applying sections
+// could not be parsed, or are not supported, is opted out of everything.
+func Read(gpp gpplib.GppContainer, gppErrs []error, gppSIDs []int8, cfg config.AccountUSNat) (Consent, bool) {
+	applying := make([]gppConstants.SectionID, 0, 1)
+	if isApplying(gppConstants.SectionUSPNAT, gppSIDs, cfg) {
+		applying = append(applying, gppConstants.SectionUSPNAT)
+	}
+	if cfg.Normalization != config.USNatNormalizationNationalOnly {
+		for _, sid := range stateSIDs {
+			if isApplying(sid, gppSIDs, cfg) {
+				applying = append(applying, sid)
+			}
+		}
+		for _, sid := range unparsedStateSIDs {
+			if isApplying(sid, gppSIDs, cfg) {
+				return optedOutOfEverything(), true
+			}
+		}
+	}
+	if len(applying) == 0 {
+		return Consent{}, false
+	}
+
+	if len(gppErrs) > 0 {
+		return optedOutOfEverything(), true
+	}
+
+	var consent Consent
+	for _, sid := range applying {
+		i := gppPolicy.IndexOfSID(gpp, sid)
+		if i < 0 {
+			return optedOutOfEverything(), true
+		}
+		consent = consent.add(normalize(gpp.Sections[i]))
+	}
+	return consent, true
+}
+
+// Normalize returns the opt-outs of a GPP section normalized onto the fields of the US National section, and false
+// when the section is not a supported US section
+func Normalize(section gpplib.Section) (Consent, bool) {
+	sid := section.GetID()
+	if sid != gppConstants.SectionUSPNAT && !slices.Contains(stateSIDs, sid) {
+		return Consent{}, false
+	}
+	return normalize(section), true
+}
+
+func isApplying(sid gppConstants.SectionID, gppSIDs []int8, cfg config.AccountUSNat) bool {
+	return gppPolicy.IsSIDInList(gppSIDs, sid) && !gppPolicy.IsSIDInList(cfg.SkipSIDs, sid)
+}
+
+// AllowSyncUser returns whether the user can be synced, i.e. whether their data can be sold or shared
+func (c Consent) AllowSyncUser() bool {
+	return c.allowTransmitUserData()
+}
+
+// AllowTransmitUserFPD returns whether the user first party data can be sent to the bidders
+func (c Consent) AllowTransmitUserFPD() bool {
+	return c.allowTransmitUserData()
+}
+
+// AllowTransmitPreciseGeo returns whether the precise geolocation of the user can be sent to the bidders
+func (c Consent) AllowTransmitPreciseGeo() bool {
+	return !(c.ServiceProviderMode || c.GPC || c.SaleOptOut || c.SharingOptOut || c.KnownChild || c.PreciseGeolocationOptOut)
+}
+
+func (c Consent) allowTransmitUserData() bool {
+	return !(c.ServiceProviderMode || c.GPC || c.SaleOptOut || c.SharingOptOut || c.TargetedAdvertisingOptOut || c.KnownChild || c.SensitiveDataOptOut)
+}
+
+func (c Consent) add(other Consent) Consent {
+	return Consent{
+		SaleOptOut:                c.SaleOptOut || other.SaleOptOut,
+		SharingOptOut:             c.SharingOptOut || other.SharingOptOut,
+		TargetedAdvertisingOptOut: c.TargetedAdvertisingOptOut || other.TargetedAdvertisingOptOut,
+		SensitiveDataOptOut:       c.SensitiveDataOptOut || other.SensitiveDataOptOut,
+		PreciseGeolocationOptOut:  c.PreciseGeolocationOptOut || other.PreciseGeolocationOptOut,
+		KnownChild:                c.KnownChild || other.KnownChild,
+		ServiceProviderMode:       c.ServiceProviderMode || other.ServiceProviderMode,
+		GPC:                       c.GPC || other.GPC,
+	}
+}
+
+func optedOutOfEverything() Consent {
+	return Consent{
+		SaleOptOut:                true,
+		SharingOptOut:             true,
+		TargetedAdvertisingOptOut: true,
+		SensitiveDataOptOut:       true,
+		PreciseGeolocationOptOut:  true,
+		KnownChild:                true,
+		ServiceProviderMode:       true,
+		GPC:                       true,
+	}
+}
+
+// normalize maps the fields of a US section onto the ones of the US National section. A notice not provided
+// counts as an opt-out of what the notice is about. The sensitive data are opted out of in the opt-out sections,
+// and not consented to in the opt-in ones, with the same value.
+func normalize(section gpplib.Section) Consent {
+	switch s := section.(type) {
+	case uspnat.USPNAT:
+		c := s.CoreSegment
+		sensitiveDataNotProvided := c.SensitiveDataProcessingOptOutNotice == notProvided || c.SensitiveDataLimitUseNotice == notProvided
+		sensitiveData, preciseGeolocation := sensitiveDataOptOuts(c.SensitiveDataProcessing, 7)
+		return Consent{
+			SaleOptOut:                c.SaleOptOut == optedOut || c.SaleOptOutNotice == notProvided,
+			SharingOptOut:             c.SharingOptOut == optedOut || c.SharingOptOutNotice == notProvided || c.SharingNotice == notProvided,
+			TargetedAdvertisingOptOut: c.TargetedAdvertisingOptOut == optedOut || c.TargetedAdvertisingOptOutNotice == notProvided,
+			SensitiveDataOptOut:       sensitiveData || sensitiveDataNotProvided,
+			PreciseGeolocationOptOut:  preciseGeolocation || sensitiveDataNotProvided,
+			KnownChild:                anyOptedOut(c.KnownChildSensitiveDataConsents),
+			ServiceProviderMode:       c.MspaServiceProviderMode == serviceProviderMode,
+			GPC:                       s.GPCSegment.Gpc,
+		}
+	case uspca.USPCA:
+		// the sharing of California is for cross-context behavioral advertising, i.e. targeted advertising
+		c := s.CoreSegment
+		sharingOptOut := c.SharingOptOut == optedOut || c.SharingOptOutNotice == notProvided
+		sensitiveDataNotProvided := c.SensitiveDataLimitUseNotice == notProvided
+		sensitiveData, preciseGeolocation := sensitiveDataOptOuts(c.SensitiveDataProcessing, 2)
+		return Consent{
+			SaleOptOut:                c.SaleOptOut == optedOut || c.SaleOptOutNotice == notProvided,
+			SharingOptOut:             sharingOptOut,
+			TargetedAdvertisingOptOut: sharingOptOut,
+			SensitiveDataOptOut:       sensitiveData || sensitiveDataNotProvided,
+			PreciseGeolocationOptOut:  preciseGeolocation || sensitiveDataNotProvided,
+			KnownChild:                anyOptedOut(c.KnownChildSensitiveDataConsents),
+			ServiceProviderMode:       c.MspaServiceProviderMode == serviceProviderMode,
+			GPC:                       s.GPCSegment.Gpc,
+		}
+	case uspva.USPVA:
+		return normalizeCommon(s.CoreSegment, sections.CommonUSGPCSegment{}, 7)
+	case uspco.USPCO:
+		return normalizeCommon(s.CoreSegment, s.GPCSegment, noPreciseGeolocation)
+	case uspct.USPCT:
+		return normalizeCommon(s.CoreSegment, s.GPCSegment, 7)
+	case usput.USPUT:
+		c := s.CoreSegment
+		sensitiveDataNotProvided := c.SensitiveDataProcessingOptOutNotice == notProvided
+		sensitiveData, preciseGeolocation := sensitiveDataOptOuts(c.SensitiveDataProcessing, 7)
+		return Consent{
+			SaleOptOut:                c.SaleOptOut == optedOut || c.SaleOptOutNotice == notProvided,
+			SharingOptOut:             c.SharingNotice == notProvided,
+			TargetedAdvertisingOptOut: c.TargetedAdvertisingOptOut == optedOut || c.TargetedAdvertisingOptOutNotice == notProvided,
+			SensitiveDataOptOut:       sensitiveData || sensitiveDataNotProvided,
+			PreciseGeolocationOptOut:  preciseGeolocation || sensitiveDataNotProvided,
+			KnownChild:                c.KnownChildSensitiveDataConsents == optedOut,
+			ServiceProviderMode:       c.MspaServiceProviderMode == serviceProviderMode,
+		}
+	}
+	return Consent{}
+}
+
+// normalizeCommon maps the fields of the state sections sharing the same layout
+func normalizeCommon(c sections.CommonUSCoreSegment, gpc sections.CommonUSGPCSegment, preciseGeolocationIndex int) Consent {
+	sensitiveData, preciseGeolocation := sensitiveDataOptOuts(c.SensitiveDataProcessing, preciseGeolocationIndex)
+	return Consent{
+		SaleOptOut:                c.SaleOptOut == optedOut || c.SaleOptOutNotice == notProvided,
+		SharingOptOut:             c.SharingNotice == notProvided,
+		TargetedAdvertisingOptOut: c.TargetedAdvertisingOptOut == optedOut || c.TargetedAdvertisingOptOutNotice == notProvided,
+		SensitiveDataOptOut:       sensitiveData,
+		PreciseGeolocationOptOut:  preciseGeolocation,
+		KnownChild:                anyOptedOut(c.KnownChildSensitiveDataConsents),
+		ServiceProviderMode:       c.MspaServiceProviderMode == serviceProviderMode,
+		GPC:                       gpc.Gpc,
+	}
+}
+
+// sensitiveDataOptOuts returns whether a category of sensitive data other than the precise geolocation is opted
+// out of, and whether the precise geolocation is
+func sensitiveDataOptOuts(categories []byte, preciseGeolocationIndex int) (bool, bool) {
+	var sensitiveData, preciseGeolocation bool
+	for i, value := range categories {
+		if value != optedOut {
+			continue
+		}
+		if i == preciseGeolocationIndex {
+			preciseGeolocation = true
+		} else {
+			sensitiveData = true
+		}
+	}
+	return sensitiveData, preciseGeolocation
+}
+
+func anyOptedOut(values []byte) bool {
+	for _, value := range values {
+		if value == optedOut {
+			return true
+		}
+	}
+	return false
+}
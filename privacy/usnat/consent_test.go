@@ -0,0 +1,248 @@
+package usnat
+
+import (
+	"errors"
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	natOptedOut := uspnat.USPNAT{SectionID: gppConstants.SectionUSPNAT, CoreSegment: uspnat.USPNATCoreSegment{SaleOptOut: 1}}
+	caOptedOut := uspca.USPCA{SectionID: gppConstants.SectionUSPCA, CoreSegment: uspca.USPCACoreSegment{SharingOptOut: 1}}
+	gpp := gpplib.GppContainer{
+		SectionTypes: []gppConstants.SectionID{gppConstants.SectionUSPNAT, gppConstants.SectionUSPCA},
+		Sections:     []gpplib.Section{natOptedOut, caOptedOut},
+	}
+
+	testCases := []struct {
+		name            string
+		gpp             gpplib.GppContainer
+		gppErrs         []error
+		gppSIDs         []int8
+		cfg             config.AccountUSNat
+		expectedConsent Consent
+		expectedOK      bool
+	}{
+		{
+			name:       "no_us_sid",
+			gpp:        gpp,
+			gppSIDs:    []int8{2},
+			expectedOK: false,
+		},
+		{
+			name:            "national",
+			gpp:             gpp,
+			gppSIDs:         []int8{7},
+			expectedConsent: Consent{SaleOptOut: true},
+			expectedOK:      true,
+		},
+		{
+			name:            "national_and_state_add_up",
+			gpp:             gpp,
+			gppSIDs:         []int8{7, 8},
+			expectedConsent: Consent{SaleOptOut: true, SharingOptOut: true, TargetedAdvertisingOptOut: true},
+			expectedOK:      true,
+		},
+		{
+			name:            "state_skipped",
+			gpp:             gpp,
+			gppSIDs:         []int8{7, 8},
+			cfg:             config.AccountUSNat{SkipSIDs: []int8{8}},
+			expectedConsent: Consent{SaleOptOut: true},
+			expectedOK:      true,
+		},
+		{
+			name:       "state_ignored_with_national_only",
+			gpp:        gpp,
+			gppSIDs:    []int8{8},
+			cfg:        config.AccountUSNat{Normalization: config.USNatNormalizationNationalOnly},
+			expectedOK: false,
+		},
+		{
+			name:            "parse_errors",
+			gpp:             gpp,
+			gppErrs:         []error{errors.New("malformed")},
+			gppSIDs:         []int8{7},
+			expectedConsent: optedOutOfEverything(),
+			expectedOK:      true,
+		},
+		{
+			name:            "section_missing",
+			gpp:             gpp,
+			gppSIDs:         []int8{9},
+			expectedConsent: optedOutOfEverything(),
+			expectedOK:      true,
+		},
+		{
+			name:            "unparsed_state",
+			gpp:             gpp,
+			gppSIDs:         []int8{7, 13},
+			expectedConsent: optedOutOfEverything(),
+			expectedOK:      true,
+		},
+		{
+			name:            "unparsed_state_skipped",
+			gpp:             gpp,
+			gppSIDs:         []int8{7, 13},
+			cfg:             config.AccountUSNat{SkipSIDs: []int8{13}},
+			expectedConsent: Consent{SaleOptOut: true},
+			expectedOK:      true,
+		},
+		{
+			name:            "unparsed_state_ignored_with_national_only",
+			gpp:             gpp,
+			gppSIDs:         []int8{7, 23},
+			cfg:             config.AccountUSNat{Normalization: config.USNatNormalizationNationalOnly},
+			expectedConsent: Consent{SaleOptOut: true},
+			expectedOK:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			consent, ok := Read(test.gpp, test.gppErrs, test.gppSIDs, test.cfg)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedConsent, consent)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		section  gpplib.Section
+		expected Consent
+	}{
+		{
+			name:     "national_nothing_opted_out",
+			section:  uspnat.USPNAT{CoreSegment: uspnat.USPNATCoreSegment{SensitiveDataProcessing: make([]byte, 16)}},
+			expected: Consent{},
+		},
+		{
+			name: "national_notices_not_provided",
+			section: uspnat.USPNAT{CoreSegment: uspnat.USPNATCoreSegment{
+				SharingNotice:                       2,
+				SaleOptOutNotice:                    2,
+				TargetedAdvertisingOptOutNotice:     2,
+				SensitiveDataProcessingOptOutNotice: 2,
+			}},
+			expected: Consent{SaleOptOut: true, SharingOptOut: true, TargetedAdvertisingOptOut: true, SensitiveDataOptOut: true, PreciseGeolocationOptOut: true},
+		},
+		{
+			name: "national_precise_geolocation_only",
+			section: uspnat.USPNAT{
+				CoreSegment: uspnat.USPNATCoreSegment{SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1}},
+				GPCSegment:  sections.CommonUSGPCSegment{Gpc: true},
+			},
+			expected: Consent{PreciseGeolocationOptOut: true, GPC: true},
+		},
+		{
+			name: "national_known_child_and_service_provider",
+			section: uspnat.USPNAT{CoreSegment: uspnat.USPNATCoreSegment{
+				KnownChildSensitiveDataConsents: []byte{0, 1},
+				MspaServiceProviderMode:         1,
+			}},
+			expected: Consent{KnownChild: true, ServiceProviderMode: true},
+		},
+		{
+			name:     "california_precise_geolocation",
+			section:  uspca.USPCA{CoreSegment: uspca.USPCACoreSegment{SensitiveDataProcessing: []byte{0, 0, 1}}},
+			expected: Consent{PreciseGeolocationOptOut: true},
+		},
+		{
+			name:     "virginia_sensitive_data",
+			section:  uspva.USPVA{CoreSegment: sections.CommonUSCoreSegment{SensitiveDataProcessing: []byte{1}}},
+			expected: Consent{SensitiveDataOptOut: true},
+		},
+		{
+			name:     "colorado_has_no_precise_geolocation",
+			section:  uspco.USPCO{CoreSegment: sections.CommonUSCoreSegment{SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1}}},
+			expected: Consent{SensitiveDataOptOut: true},
+		},
+		{
+			name:     "utah_targeted_advertising",
+			section:  usput.USPUT{CoreSegment: usput.USPUTCoreSegment{TargetedAdvertisingOptOut: 1}},
+			expected: Consent{TargetedAdvertisingOptOut: true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, normalize(test.section))
+		})
+	}
+}
+
+func TestConsentAllow(t *testing.T) {
+	testCases := []struct {
+		name               string
+		consent            Consent
+		expectedUserData   bool
+		expectedPreciseGeo bool
+	}{
+		{
+			name:               "nothing_opted_out",
+			consent:            Consent{},
+			expectedUserData:   true,
+			expectedPreciseGeo: true,
+		},
+		{
+			name:               "sale_opted_out",
+			consent:            Consent{SaleOptOut: true},
+			expectedUserData:   false,
+			expectedPreciseGeo: false,
+		},
+		{
+			name:               "targeted_advertising_opted_out",
+			consent:            Consent{TargetedAdvertisingOptOut: true},
+			expectedUserData:   false,
+			expectedPreciseGeo: true,
+		},
+		{
+			name:               "precise_geolocation_opted_out",
+			consent:            Consent{PreciseGeolocationOptOut: true},
+			expectedUserData:   true,
+			expectedPreciseGeo: false,
+		},
+		{
+			name:               "gpc",
+			consent:            Consent{GPC: true},
+			expectedUserData:   false,
+			expectedPreciseGeo: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedUserData, test.consent.AllowSyncUser())
+			assert.Equal(t, test.expectedUserData, test.consent.AllowTransmitUserFPD())
+			assert.Equal(t, test.expectedPreciseGeo, test.consent.AllowTransmitPreciseGeo())
+		})
+	}
+}
+
+func TestNormalizeExported(t *testing.T) {
+	consent, ok := Normalize(uspnat.USPNAT{SectionID: gppConstants.SectionUSPNAT, CoreSegment: uspnat.USPNATCoreSegment{SaleOptOut: 1}})
+	assert.True(t, ok)
+	assert.Equal(t, Consent{SaleOptOut: true}, consent)
+
+	_, ok = Normalize(uspca.USPCA{SectionID: gppConstants.SectionUSPCA})
+	assert.True(t, ok)
+
+	gpp, errs := gpplib.Parse("DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN")
+	assert.Empty(t, errs)
+	for _, section := range gpp.Sections {
+		_, ok = Normalize(section)
+		assert.False(t, ok)
+	}
+}
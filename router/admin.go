@@ -10,7 +10,7 @@ import (
 	"github.com/prebid/prebid-server/v3/version"
 )
 
-func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, consentInspection, vendorListVersions http.HandlerFunc, moduleEndpoints map[string]http.HandlerFunc) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -22,6 +22,12 @@ func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
+	mux.HandleFunc("/consent/inspect", consentInspection)
+	mux.HandleFunc("/vendorlist/versions", vendorListVersions)
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
+	// Register module defined admin handlers
+	for path, handler := range moduleEndpoints {
+		mux.HandleFunc(path, handler)
+	}
 	return mux
 }
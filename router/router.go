@@ -13,6 +13,7 @@ import (
 
 	openrtb2model "github.com/prebid/openrtb/v20/openrtb2"
 	analyticsBuild "github.com/prebid/prebid-server/v3/analytics/build"
+	"github.com/prebid/prebid-server/v3/capture"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/endpoints"
@@ -25,6 +26,7 @@ import (
 	"github.com/prebid/prebid-server/v3/floors"
 	"github.com/prebid/prebid-server/v3/gdpr"
 	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v3/macros"
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
@@ -37,8 +39,11 @@ import (
 	"github.com/prebid/prebid-server/v3/router/aspects"
 	"github.com/prebid/prebid-server/v3/server/ssl"
 	storedRequestsConf "github.com/prebid/prebid-server/v3/stored_requests/config"
+	"github.com/prebid/prebid-server/v3/trafficshaping"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/ratelimit"
+	"github.com/prebid/prebid-server/v3/util/task"
 	"github.com/prebid/prebid-server/v3/util/uuidutil"
 	"github.com/prebid/prebid-server/v3/version"
 
@@ -117,11 +122,22 @@ type Router struct {
 	*httprouter.Router
 	MetricsEngine   *metricsConf.DetailedMetricsEngine
 	ParamsValidator openrtb_ext.BidderParamValidator
+	AdminEndpoints  map[string]http.HandlerFunc
+	// ConsentInspection is the admin handler inspecting the consent strings of a request
+	ConsentInspection http.HandlerFunc
+	// VendorListVersions is the admin handler listing the versions of the vendor lists loaded
+	VendorListVersions http.HandlerFunc
 
 	shutdowns []func()
 }
 
 func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *Router, err error) {
+	return NewWithBidderTransport(cfg, rateConvertor, nil)
+}
+
+// NewWithBidderTransport creates the router with the HTTP calls to the bidders sent through the given transport,
+// such as the one of a capture replay, the default transport being used when nil
+func NewWithBidderTransport(cfg *config.Configuration, rateConvertor *currency.RateConverter, bidderTransport http.RoundTripper) (r *Router, err error) {
 	const schemaDirectory = "./static/bidder-params"
 
 	r = &Router{
@@ -208,16 +224,20 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		syncerKeys = append(syncerKeys, k)
 	}
 
-	moduleDeps := moduledeps.ModuleDeps{HTTPClient: generalHttpClient, RateConvertor: rateConvertor}
-	repo, moduleStageNames, shutdownModules, err := modules.NewBuilder().Build(cfg.Hooks.Modules, moduleDeps)
+	moduleMetricsEngine := moduledeps.NewMetricsEngineRef(nil)
+	moduleDeps := moduledeps.ModuleDeps{HTTPClient: generalHttpClient, RateConvertor: rateConvertor, MetricsEngine: moduleMetricsEngine}
+	repo, moduleStageNames, shutdownModules, adminModules, err := modules.NewBuilder().Build(cfg.Hooks.Modules, moduleDeps)
 	if err != nil {
 		glog.Fatalf("Failed to init hook modules: %v", err)
 	}
 
 	// Metrics engine
 	r.MetricsEngine = metricsConf.NewMetricsEngine(cfg, openrtb_ext.CoreBidderNames(), syncerKeys, moduleStageNames)
+	moduleMetricsEngine.Set(r.MetricsEngine)
 	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router)
 
+	r.AdminEndpoints = adminModules.Endpoints(moduledeps.AdminDeps{Config: cfg, AccountFetcher: accounts, MetricsEngine: r.MetricsEngine})
+
 	analyticsRunner := analyticsBuild.New(&cfg.Analytics)
 
 	// register the analytics runner for shutdown
@@ -234,13 +254,20 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	defReqJSON := readDefaultRequest(cfg.DefReqConfig)
 
 	gvlVendorIDs := cfg.BidderInfos.ToGVLVendorIDMap()
-	vendorListFetcher := gdpr.NewVendorListFetcher(context.Background(), cfg.GDPR, generalHttpClient, r.MetricsEngine, gdpr.VendorListURLMaker)
+	vendorListFetcher, vendorListVersions := gdpr.NewVendorListFetcher(context.Background(), cfg.GDPR, generalHttpClient, r.MetricsEngine, gdpr.VendorListURLMaker)
 	gdprPermsBuilder := gdpr.NewPermissionsBuilder(cfg.GDPR, gvlVendorIDs, vendorListFetcher, r.MetricsEngine)
 	tcf2CfgBuilder := gdpr.NewTCF2Config
+	r.VendorListVersions = endpoints.NewVendorListVersionsEndpoint(vendorListVersions)
+	r.ConsentInspection = endpoints.NewConsentInspectionEndpoint(cfg, gdprPermsBuilder, tcf2CfgBuilder, vendorListFetcher, accounts, activeBidders, r.MetricsEngine)
 
 	cacheClient := pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
 
-	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(generalHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
+	bidderHttpClient := generalHttpClient
+	if bidderTransport != nil {
+		bidderHttpClient = &http.Client{Transport: bidderTransport}
+	}
+
+	adapters, singleFormatAdapters, adaptersErrs := exchange.BuildAdapters(bidderHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
 	if len(adaptersErrs) > 0 {
 		errs := errortypes.NewAggregateError("Failed to initialize adapters", adaptersErrs)
 		return nil, errs
@@ -255,20 +282,40 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 
 	tmaxAdjustments := exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments)
 	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, repo)
+	hookAsyncPool := hookexecution.NewAsyncPool(cfg.Hooks.AsyncExecution)
 	macroReplacer := macros.NewStringIndexBasedReplacer()
-	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher, singleFormatAdapters)
+	trafficShaper, err := trafficshaping.NewShaper(cfg.TrafficShaping)
+	if err != nil {
+		glog.Fatalf("Failed to create the traffic shaper: %v", err)
+	}
+	if trafficShaper != nil {
+		trafficShaperTickerTask := task.NewTickerTask(time.Duration(cfg.TrafficShaping.RefreshPeriodSeconds)*time.Second, trafficShaper)
+		trafficShaperTickerTask.Start()
+		r.shutdowns = append(r.shutdowns, trafficShaperTickerTask.Stop, trafficShaper.Shutdown)
+		r.AdminEndpoints["/traffic_shaping/model"] = endpoints.NewTrafficShapingEndpoint(trafficShaper)
+	}
+	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher, singleFormatAdapters, trafficShaper)
+	captureWriter, err := capture.NewWriter(cfg.Capture)
+	if err != nil {
+		glog.Fatalf("Failed to create the capture writer: %v", err)
+	}
+	r.shutdowns = append(r.shutdowns, captureWriter.Shutdown)
+
+	// The auctions of an account are rate limited across all the auction endpoints
+	accountRateLimiter := ratelimit.NewLimiter()
+
 	var uuidGenerator uuidutil.UUIDRandomGenerator
-	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, hookAsyncPool, tmaxAdjustments, captureWriter, accountRateLimiter)
 	if err != nil {
 		glog.Fatalf("Failed to create the openrtb2 endpoint handler. %v", err)
 	}
 
-	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, requestValidator, ampFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, requestValidator, ampFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, hookAsyncPool, tmaxAdjustments, captureWriter, accountRateLimiter)
 	if err != nil {
 		glog.Fatalf("Failed to create the amp endpoint handler. %v", err)
 	}
 
-	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, tmaxAdjustments)
+	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, requestValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, tmaxAdjustments, accountRateLimiter)
 	if err != nil {
 		glog.Fatalf("Failed to create the video endpoint handler. %v", err)
 	}
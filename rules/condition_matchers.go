@@ -0,0 +1,154 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rule conditions are matched exactly against schema function results unless they are the "*" wildcard
+// or start with one of the following prefixes:
+//
+//	in:a,b,c       matches any of the comma separated values
+//	range:min,max  matches numeric values between min and max inclusive, either bound may be omitted
+//	regex:pattern  matches values the regular expression finds a match in
+const (
+	WildcardCondition    = "*"
+	SetConditionPrefix   = "in:"
+	RangeConditionPrefix = "range:"
+	RegexConditionPrefix = "regex:"
+)
+
+// conditionMatcher matches schema function results against a rule condition that is not an exact value.
+// Its rank sets the precedence among the pattern children of a node, lower ranks being checked first.
+type conditionMatcher interface {
+	match(value string) bool
+	rank() int
+}
+
+// newConditionMatcher parses the given rule condition returning a matcher if the condition is a pattern
+// or nil if it is an exact value or the wildcard. An error is returned if the pattern is malformed.
+func newConditionMatcher(condition string) (conditionMatcher, error) {
+	switch {
+	case strings.HasPrefix(condition, SetConditionPrefix):
+		return newSetMatcher(strings.TrimPrefix(condition, SetConditionPrefix))
+	case strings.HasPrefix(condition, RangeConditionPrefix):
+		return newRangeMatcher(strings.TrimPrefix(condition, RangeConditionPrefix))
+	case strings.HasPrefix(condition, RegexConditionPrefix):
+		return newRegexMatcher(strings.TrimPrefix(condition, RegexConditionPrefix))
+	default:
+		return nil, nil
+	}
+}
+
+// ------------set--------------------------
+type setMatcher struct {
+	values map[string]struct{}
+}
+
+func newSetMatcher(args string) (conditionMatcher, error) {
+	m := &setMatcher{values: make(map[string]struct{})}
+	for _, v := range strings.Split(args, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			m.values[v] = struct{}{}
+		}
+	}
+	if len(m.values) == 0 {
+		return nil, errors.New("set condition requires at least one value")
+	}
+	return m, nil
+}
+
+func (m *setMatcher) match(value string) bool {
+	_, found := m.values[value]
+	return found
+}
+
+func (m *setMatcher) rank() int {
+	return 0
+}
+
+// ------------range------------------------
+type rangeMatcher struct {
+	min *float64
+	max *float64
+}
+
+func newRangeMatcher(args string) (conditionMatcher, error) {
+	bounds := strings.Split(args, ",")
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("range condition %q must have the form min,max", args)
+	}
+
+	m := &rangeMatcher{}
+	var err error
+	if m.min, err = parseBound(bounds[0]); err != nil {
+		return nil, err
+	}
+	if m.max, err = parseBound(bounds[1]); err != nil {
+		return nil, err
+	}
+	if m.min == nil && m.max == nil {
+		return nil, errors.New("range condition requires at least one bound")
+	}
+	if m.min != nil && m.max != nil && *m.min > *m.max {
+		return nil, fmt.Errorf("range condition %q lower bound is greater than its upper bound", args)
+	}
+	return m, nil
+}
+
+func parseBound(bound string) (*float64, error) {
+	bound = strings.TrimSpace(bound)
+	if len(bound) == 0 {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(bound, 64)
+	if err != nil {
+		return nil, fmt.Errorf("range condition bound %q is not a number", bound)
+	}
+	return &v, nil
+}
+
+func (m *rangeMatcher) match(value string) bool {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	if m.min != nil && v < *m.min {
+		return false
+	}
+	if m.max != nil && v > *m.max {
+		return false
+	}
+	return true
+}
+
+func (m *rangeMatcher) rank() int {
+	return 1
+}
+
+// ------------regex------------------------
+type regexMatcher struct {
+	re *regexp.Regexp
+}
+
+func newRegexMatcher(args string) (conditionMatcher, error) {
+	if len(args) == 0 {
+		return nil, errors.New("regex condition requires a pattern")
+	}
+	re, err := regexp.Compile(args)
+	if err != nil {
+		return nil, fmt.Errorf("regex condition %q is invalid: %s", args, err)
+	}
+	return &regexMatcher{re: re}, nil
+}
+
+func (m *regexMatcher) match(value string) bool {
+	return m.re.MatchString(value)
+}
+
+func (m *regexMatcher) rank() int {
+	return 2
+}
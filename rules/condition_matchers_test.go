@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConditionMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inCondition   string
+		expectMatcher bool
+		expectedError error
+	}{
+		{
+			desc:        "exact value",
+			inCondition: "web",
+		},
+		{
+			desc:        "wildcard",
+			inCondition: "*",
+		},
+		{
+			desc:          "set",
+			inCondition:   "in:USA, CAN",
+			expectMatcher: true,
+		},
+		{
+			desc:          "empty set",
+			inCondition:   "in: , ",
+			expectedError: errors.New("set condition requires at least one value"),
+		},
+		{
+			desc:          "closed range",
+			inCondition:   "range:0,500",
+			expectMatcher: true,
+		},
+		{
+			desc:          "open range",
+			inCondition:   "range:500,",
+			expectMatcher: true,
+		},
+		{
+			desc:          "range without bounds",
+			inCondition:   "range:,",
+			expectedError: errors.New("range condition requires at least one bound"),
+		},
+		{
+			desc:          "range without comma",
+			inCondition:   "range:500",
+			expectedError: errors.New(`range condition "500" must have the form min,max`),
+		},
+		{
+			desc:          "range with non numeric bound",
+			inCondition:   "range:a,5",
+			expectedError: errors.New(`range condition bound "a" is not a number`),
+		},
+		{
+			desc:          "range with inverted bounds",
+			inCondition:   "range:5,1",
+			expectedError: errors.New(`range condition "5,1" lower bound is greater than its upper bound`),
+		},
+		{
+			desc:          "regex",
+			inCondition:   `regex:^.*\.example\.com$`,
+			expectMatcher: true,
+		},
+		{
+			desc:          "empty regex",
+			inCondition:   "regex:",
+			expectedError: errors.New("regex condition requires a pattern"),
+		},
+		{
+			desc:          "invalid regex",
+			inCondition:   "regex:(",
+			expectedError: errors.New("regex condition \"(\" is invalid: error parsing regexp: missing closing ): `(`"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			m, err := newConditionMatcher(tc.inCondition)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectMatcher, m != nil)
+		})
+	}
+}
+
+func TestConditionMatchersMatch(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		inCondition string
+		inValue     string
+		expected    bool
+	}{
+		{desc: "set match", inCondition: "in:USA,CAN", inValue: "CAN", expected: true},
+		{desc: "set no match", inCondition: "in:USA,CAN", inValue: "MEX", expected: false},
+		{desc: "range lower bound inclusive", inCondition: "range:0,500", inValue: "0", expected: true},
+		{desc: "range upper bound inclusive", inCondition: "range:0,500", inValue: "500", expected: true},
+		{desc: "range above", inCondition: "range:0,500", inValue: "500.01", expected: false},
+		{desc: "range open upper bound", inCondition: "range:500,", inValue: "10000", expected: true},
+		{desc: "range open lower bound", inCondition: "range:,500", inValue: "-1", expected: true},
+		{desc: "range non numeric value", inCondition: "range:0,500", inValue: "abc", expected: false},
+		{desc: "regex match", inCondition: `regex:\.example\.com$`, inValue: "www.example.com", expected: true},
+		{desc: "regex no match", inCondition: `regex:\.example\.com$`, inValue: "www.example.org", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			m, err := newConditionMatcher(tc.inCondition)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, m.match(tc.inValue))
+		})
+	}
+}
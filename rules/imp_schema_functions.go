@@ -0,0 +1,246 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+)
+
+const (
+	AdUnitCode   = "adUnitCode"
+	Gpid         = "gpid"
+	ImpBidFloor  = "impBidFloor"
+	ImpMediaType = "impMediaType"
+	ImpSize      = "impSize"
+)
+
+// ImpWrapper is the payload schema functions operate on when a tree runs once per impression.
+// It holds the impression being evaluated along with the request it belongs to so request-level
+// schema functions can be mixed with imp-level ones in the same tree.
+type ImpWrapper struct {
+	Request *openrtb_ext.RequestWrapper
+	Imp     *openrtb_ext.ImpWrapper
+}
+
+// NewImpSchemaFunction returns the specified schema function that operates on an impression payload along with
+// any schema function args validation errors that occurred during instantiation. Request-level schema functions
+// are also supported and are evaluated against the request the impression belongs to.
+func NewImpSchemaFunction(name string, params json.RawMessage) (SchemaFunction[ImpWrapper], error) {
+	switch name {
+	case ImpMediaType:
+		return NewImpMediaType(params)
+	case ImpSize:
+		return NewImpSize(params)
+	case AdUnitCode:
+		return NewAdUnitCode(params)
+	case Gpid:
+		return NewGpid(params)
+	case ImpBidFloor:
+		return NewImpBidFloor(params)
+	default:
+		requestFunc, err := NewRequestSchemaFunction(name, params)
+		if err != nil {
+			return nil, err
+		}
+		return &impRequestSchemaFunction{requestFunc: requestFunc}, nil
+	}
+}
+
+// ------------request schema function------
+// impRequestSchemaFunction adapts a request-level schema function so it can be part of an imp tree
+type impRequestSchemaFunction struct {
+	requestFunc SchemaFunction[openrtb_ext.RequestWrapper]
+}
+
+func (irsf *impRequestSchemaFunction) Call(wrapper *ImpWrapper) (string, error) {
+	if wrapper == nil {
+		return irsf.requestFunc.Call(nil)
+	}
+	return irsf.requestFunc.Call(wrapper.Request)
+}
+
+func (irsf *impRequestSchemaFunction) Name() string {
+	return irsf.requestFunc.Name()
+}
+
+// ------------impMediaType-----------------
+type impMediaType struct{}
+
+func NewImpMediaType(params json.RawMessage) (SchemaFunction[ImpWrapper], error) {
+	if err := checkNilArgs(params, ImpMediaType); err != nil {
+		return nil, err
+	}
+	return &impMediaType{}, nil
+}
+
+// Call returns the media types of the impression in alphabetical order separated by commas
+// so multi-format impressions yield values such as "banner,video"
+func (imt *impMediaType) Call(wrapper *ImpWrapper) (string, error) {
+	imp := getImp(wrapper)
+	if imp == nil {
+		return "", nil
+	}
+
+	mediaTypes := make([]string, 0, 4)
+	if imp.Banner != nil {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeBanner))
+	}
+	if imp.Video != nil {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeVideo))
+	}
+	if imp.Audio != nil {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeAudio))
+	}
+	if imp.Native != nil {
+		mediaTypes = append(mediaTypes, string(openrtb_ext.BidTypeNative))
+	}
+	sort.Strings(mediaTypes)
+
+	return strings.Join(mediaTypes, ","), nil
+}
+
+func (imt *impMediaType) Name() string {
+	return ImpMediaType
+}
+
+// ------------impSize----------------------
+type impSize struct{}
+
+func NewImpSize(params json.RawMessage) (SchemaFunction[ImpWrapper], error) {
+	if err := checkNilArgs(params, ImpSize); err != nil {
+		return nil, err
+	}
+	return &impSize{}, nil
+}
+
+// Call returns the impression size in WxH format. Banner impressions declaring several formats have no size.
+func (is *impSize) Call(wrapper *ImpWrapper) (string, error) {
+	imp := getImp(wrapper)
+	if imp == nil {
+		return "", nil
+	}
+
+	var width, height int64
+	if imp.Banner != nil {
+		if len(imp.Banner.Format) == 1 {
+			width, height = imp.Banner.Format[0].W, imp.Banner.Format[0].H
+		} else if len(imp.Banner.Format) == 0 {
+			width, height = ptrutil.ValueOrDefault(imp.Banner.W), ptrutil.ValueOrDefault(imp.Banner.H)
+		}
+	} else if imp.Video != nil {
+		width, height = ptrutil.ValueOrDefault(imp.Video.W), ptrutil.ValueOrDefault(imp.Video.H)
+	}
+
+	if width == 0 || height == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%dx%d", width, height), nil
+}
+
+func (is *impSize) Name() string {
+	return ImpSize
+}
+
+// ------------adUnitCode-------------------
+type adUnitCode struct{}
+
+func NewAdUnitCode(params json.RawMessage) (SchemaFunction[ImpWrapper], error) {
+	if err := checkNilArgs(params, AdUnitCode); err != nil {
+		return nil, err
+	}
+	return &adUnitCode{}, nil
+}
+
+// Call returns the first non empty value out of imp.ext.gpid, imp.tagid, imp.ext.data.pbadslot
+// and imp.ext.prebid.storedrequest.id
+func (auc *adUnitCode) Call(wrapper *ImpWrapper) (string, error) {
+	imp := getImp(wrapper)
+	if imp == nil {
+		return "", nil
+	}
+
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return "", err
+	}
+	if gpid := impExt.GetGpId(); len(gpid) > 0 {
+		return gpid, nil
+	}
+	if len(imp.TagID) > 0 {
+		return imp.TagID, nil
+	}
+	if impExtData := impExt.GetData(); impExtData != nil && len(impExtData.PbAdslot) > 0 {
+		return impExtData.PbAdslot, nil
+	}
+	if prebid := impExt.GetPrebid(); prebid != nil && prebid.StoredRequest != nil {
+		return prebid.StoredRequest.ID, nil
+	}
+	return "", nil
+}
+
+func (auc *adUnitCode) Name() string {
+	return AdUnitCode
+}
+
+// ------------gpid-------------------------
+type gpid struct{}
+
+func NewGpid(params json.RawMessage) (SchemaFunction[ImpWrapper], error) {
+	if err := checkNilArgs(params, Gpid); err != nil {
+		return nil, err
+	}
+	return &gpid{}, nil
+}
+
+func (g *gpid) Call(wrapper *ImpWrapper) (string, error) {
+	imp := getImp(wrapper)
+	if imp == nil {
+		return "", nil
+	}
+
+	impExt, err := imp.GetImpExt()
+	if err != nil {
+		return "", err
+	}
+	return impExt.GetGpId(), nil
+}
+
+func (g *gpid) Name() string {
+	return Gpid
+}
+
+// ------------impBidFloor------------------
+type impBidFloor struct{}
+
+func NewImpBidFloor(params json.RawMessage) (SchemaFunction[ImpWrapper], error) {
+	if err := checkNilArgs(params, ImpBidFloor); err != nil {
+		return nil, err
+	}
+	return &impBidFloor{}, nil
+}
+
+// Call returns the impression bid floor in its shortest decimal representation, or an empty
+// string if the impression carries no floor
+func (ibf *impBidFloor) Call(wrapper *ImpWrapper) (string, error) {
+	imp := getImp(wrapper)
+	if imp == nil || imp.BidFloor <= 0 {
+		return "", nil
+	}
+	return strconv.FormatFloat(imp.BidFloor, 'f', -1, 64), nil
+}
+
+func (ibf *impBidFloor) Name() string {
+	return ImpBidFloor
+}
+
+func getImp(wrapper *ImpWrapper) *openrtb_ext.ImpWrapper {
+	if wrapper != nil && wrapper.Imp != nil && wrapper.Imp.Imp != nil {
+		return wrapper.Imp
+	}
+	return nil
+}
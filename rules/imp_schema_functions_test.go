@@ -0,0 +1,215 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewImpSchemaFunction(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inName        string
+		inParams      json.RawMessage
+		expectedFunc  SchemaFunction[ImpWrapper]
+		expectedError error
+	}{
+		{
+			desc:         "impMediaType",
+			inName:       ImpMediaType,
+			expectedFunc: &impMediaType{},
+		},
+		{
+			desc:         "impSize",
+			inName:       ImpSize,
+			expectedFunc: &impSize{},
+		},
+		{
+			desc:         "adUnitCode",
+			inName:       AdUnitCode,
+			expectedFunc: &adUnitCode{},
+		},
+		{
+			desc:         "gpid",
+			inName:       Gpid,
+			expectedFunc: &gpid{},
+		},
+		{
+			desc:         "impBidFloor",
+			inName:       ImpBidFloor,
+			expectedFunc: &impBidFloor{},
+		},
+		{
+			desc:         "request-level function is adapted",
+			inName:       Channel,
+			expectedFunc: &impRequestSchemaFunction{requestFunc: &channel{}},
+		},
+		{
+			desc:          "unknown function",
+			inName:        "unknown",
+			expectedError: errors.New("Schema function unknown was not created"),
+		},
+		{
+			desc:          "gpid with args",
+			inName:        Gpid,
+			inParams:      json.RawMessage(`{"a":1}`),
+			expectedError: errors.New("gpid expects 0 arguments"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := NewImpSchemaFunction(tc.inName, tc.inParams)
+			assert.Equal(t, tc.expectedFunc, f)
+			assert.Equal(t, tc.expectedError, err)
+			if f != nil {
+				assert.Equal(t, tc.inName, f.Name())
+			}
+		})
+	}
+}
+
+func TestImpSchemaFunctionsCall(t *testing.T) {
+	newImpWrapper := func(imp openrtb2.Imp) *ImpWrapper {
+		return &ImpWrapper{
+			Request: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}}},
+			},
+			Imp: &openrtb_ext.ImpWrapper{Imp: &imp},
+		}
+	}
+
+	testCases := []struct {
+		desc           string
+		inFunc         SchemaFunction[ImpWrapper]
+		inWrapper      *ImpWrapper
+		expectedResult string
+		expectedError  bool
+	}{
+		{
+			desc:           "impMediaType single format",
+			inFunc:         &impMediaType{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Video: &openrtb2.Video{}}),
+			expectedResult: "video",
+		},
+		{
+			desc:           "impMediaType multi format",
+			inFunc:         &impMediaType{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Video: &openrtb2.Video{}, Banner: &openrtb2.Banner{}}),
+			expectedResult: "banner,video",
+		},
+		{
+			desc:           "impMediaType nil imp",
+			inFunc:         &impMediaType{},
+			inWrapper:      &ImpWrapper{},
+			expectedResult: "",
+		},
+		{
+			desc:           "impSize single banner format",
+			inFunc:         &impSize{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}}),
+			expectedResult: "300x250",
+		},
+		{
+			desc:           "impSize several banner formats",
+			inFunc:         &impSize{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}}}),
+			expectedResult: "",
+		},
+		{
+			desc:           "impSize banner w and h",
+			inFunc:         &impSize{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](320), H: ptrutil.ToPtr[int64](50)}}),
+			expectedResult: "320x50",
+		},
+		{
+			desc:           "impSize video",
+			inFunc:         &impSize{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Video: &openrtb2.Video{W: ptrutil.ToPtr[int64](640), H: ptrutil.ToPtr[int64](480)}}),
+			expectedResult: "640x480",
+		},
+		{
+			desc:           "adUnitCode from gpid",
+			inFunc:         &adUnitCode{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{TagID: "tag", Ext: json.RawMessage(`{"gpid":"/1/home"}`)}),
+			expectedResult: "/1/home",
+		},
+		{
+			desc:           "adUnitCode from tagid",
+			inFunc:         &adUnitCode{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{TagID: "tag", Ext: json.RawMessage(`{"data":{"pbadslot":"slot"}}`)}),
+			expectedResult: "tag",
+		},
+		{
+			desc:           "adUnitCode from pbadslot",
+			inFunc:         &adUnitCode{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Ext: json.RawMessage(`{"data":{"pbadslot":"slot"}}`)}),
+			expectedResult: "slot",
+		},
+		{
+			desc:           "adUnitCode from stored request",
+			inFunc:         &adUnitCode{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Ext: json.RawMessage(`{"prebid":{"storedrequest":{"id":"stored"}}}`)}),
+			expectedResult: "stored",
+		},
+		{
+			desc:          "adUnitCode malformed ext",
+			inFunc:        &adUnitCode{},
+			inWrapper:     newImpWrapper(openrtb2.Imp{Ext: json.RawMessage(`malformed`)}),
+			expectedError: true,
+		},
+		{
+			desc:           "gpid",
+			inFunc:         &gpid{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{Ext: json.RawMessage(`{"gpid":"/1/home"}`)}),
+			expectedResult: "/1/home",
+		},
+		{
+			desc:           "gpid absent",
+			inFunc:         &gpid{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{}),
+			expectedResult: "",
+		},
+		{
+			desc:           "impBidFloor",
+			inFunc:         &impBidFloor{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{BidFloor: 1.25}),
+			expectedResult: "1.25",
+		},
+		{
+			desc:           "impBidFloor absent",
+			inFunc:         &impBidFloor{},
+			inWrapper:      newImpWrapper(openrtb2.Imp{}),
+			expectedResult: "",
+		},
+		{
+			desc:           "request-level function runs against the imp request",
+			inFunc:         &impRequestSchemaFunction{requestFunc: &deviceCountry{}},
+			inWrapper:      newImpWrapper(openrtb2.Imp{}),
+			expectedResult: "USA",
+		},
+		{
+			desc:           "request-level function nil wrapper",
+			inFunc:         &impRequestSchemaFunction{requestFunc: &deviceCountry{}},
+			inWrapper:      nil,
+			expectedResult: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := tc.inFunc.Call(tc.inWrapper)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, res)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	Adomain        = "adomain"
+	AdomainIn      = "adomainIn"
+	BidPriceBucket = "bidPriceBucket"
+	DealId         = "dealId"
+	MediaType      = "mediaType"
+	Seat           = "seat"
+	SeatIn         = "seatIn"
+)
+
+// BidWrapper is the payload schema functions operate on at the bid response stages.
+// It holds a single bid along with the seat that returned it and the bid media type.
+type BidWrapper struct {
+	Seat    string
+	Bid     *openrtb2.Bid
+	BidType openrtb_ext.BidType
+}
+
+// NewResponseSchemaFunction returns the specified schema function that operates on a bid payload along with
+// any schema function args validation errors that occurred during instantiation
+func NewResponseSchemaFunction(name string, params json.RawMessage) (SchemaFunction[BidWrapper], error) {
+	switch name {
+	case Seat:
+		return NewSeat(params)
+	case SeatIn:
+		return NewSeatIn(params)
+	case Adomain:
+		return NewAdomain(params)
+	case AdomainIn:
+		return NewAdomainIn(params)
+	case BidPriceBucket:
+		return NewBidPriceBucket(params)
+	case MediaType:
+		return NewMediaType(params)
+	case DealId:
+		return NewDealId(params)
+	default:
+		return nil, fmt.Errorf("Schema function %s was not created", name)
+	}
+}
+
+// ------------seat-------------------------
+type seat struct{}
+
+func NewSeat(params json.RawMessage) (SchemaFunction[BidWrapper], error) {
+	if err := checkNilArgs(params, Seat); err != nil {
+		return nil, err
+	}
+	return &seat{}, nil
+}
+
+func (s *seat) Call(wrapper *BidWrapper) (string, error) {
+	if wrapper == nil {
+		return "", nil
+	}
+	return wrapper.Seat, nil
+}
+
+func (s *seat) Name() string {
+	return Seat
+}
+
+// ------------seatIn-----------------------
+type seatIn struct {
+	Seats   []string `json:"seats"`
+	SeatDir map[string]struct{}
+}
+
+func NewSeatIn(params json.RawMessage) (SchemaFunction[BidWrapper], error) {
+	schemaFunc := &seatIn{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Seats) == 0 {
+		return nil, errors.New("Empty seats argument in seatIn schema function")
+	}
+
+	schemaFunc.SeatDir = make(map[string]struct{})
+	for i := range schemaFunc.Seats {
+		schemaFunc.SeatDir[schemaFunc.Seats[i]] = struct{}{}
+	}
+
+	return schemaFunc, nil
+}
+
+func (si *seatIn) Call(wrapper *BidWrapper) (string, error) {
+	if wrapper == nil || len(wrapper.Seat) == 0 {
+		return "false", nil
+	}
+
+	_, found := si.SeatDir[wrapper.Seat]
+	return fmt.Sprintf("%t", found), nil
+}
+
+func (si *seatIn) Name() string {
+	return SeatIn
+}
+
+// ------------adomain----------------------
+type adomain struct{}
+
+func NewAdomain(params json.RawMessage) (SchemaFunction[BidWrapper], error) {
+	if err := checkNilArgs(params, Adomain); err != nil {
+		return nil, err
+	}
+	return &adomain{}, nil
+}
+
+// Call returns the first advertiser domain declared on the bid
+func (a *adomain) Call(wrapper *BidWrapper) (string, error) {
+	if bid := getBid(wrapper); bid != nil && len(bid.ADomain) > 0 {
+		return bid.ADomain[0], nil
+	}
+	return "", nil
+}
+
+func (a *adomain) Name() string {
+	return Adomain
+}
+
+// ------------adomainIn--------------------
+type adomainIn struct {
+	Domains   []string `json:"domains"`
+	DomainDir map[string]struct{}
+}
+
+func NewAdomainIn(params json.RawMessage) (SchemaFunction[BidWrapper], error) {
+	schemaFunc := &adomainIn{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Domains) == 0 {
+		return nil, errors.New("Empty domains argument in adomainIn schema function")
+	}
+
+	schemaFunc.DomainDir = make(map[string]struct{})
+	for i := range schemaFunc.Domains {
+		schemaFunc.DomainDir[schemaFunc.Domains[i]] = struct{}{}
+	}
+
+	return schemaFunc, nil
+}
+
+// Call returns true if any of the advertiser domains declared on the bid is found in the configured list
+func (ai *adomainIn) Call(wrapper *BidWrapper) (string, error) {
+	bid := getBid(wrapper)
+	if bid == nil {
+		return "false", nil
+	}
+
+	for i := range bid.ADomain {
+		if _, found := ai.DomainDir[bid.ADomain[i]]; found {
+			return "true", nil
+		}
+	}
+	return "false", nil
+}
+
+func (ai *adomainIn) Name() string {
+	return AdomainIn
+}
+
+// ------------bidPriceBucket---------------
+type bidPriceBucket struct {
+	Increment float64 `json:"increment"`
+	Max       float64 `json:"max"`
+}
+
+func NewBidPriceBucket(params json.RawMessage) (SchemaFunction[BidWrapper], error) {
+	schemaFunc := &bidPriceBucket{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if schemaFunc.Increment <= 0 {
+		return nil, errors.New("Missing or non-positive increment argument for bidPriceBucket schema function")
+	}
+	if schemaFunc.Max < 0 {
+		return nil, errors.New("Negative max argument for bidPriceBucket schema function")
+	}
+
+	return schemaFunc, nil
+}
+
+// Call rounds the bid price down to the nearest increment, capped at max when max is set,
+// and returns it formatted with two decimals
+func (bpb *bidPriceBucket) Call(wrapper *BidWrapper) (string, error) {
+	bid := getBid(wrapper)
+	if bid == nil || bid.Price <= 0 {
+		return "0.00", nil
+	}
+
+	price := bid.Price
+	if bpb.Max > 0 && price > bpb.Max {
+		price = bpb.Max
+	}
+	bucket := math.Floor(price/bpb.Increment) * bpb.Increment
+
+	return strconv.FormatFloat(bucket, 'f', 2, 64), nil
+}
+
+func (bpb *bidPriceBucket) Name() string {
+	return BidPriceBucket
+}
+
+// ------------mediaType--------------------
+type mediaType struct{}
+
+func NewMediaType(params json.RawMessage) (SchemaFunction[BidWrapper], error) {
+	if err := checkNilArgs(params, MediaType); err != nil {
+		return nil, err
+	}
+	return &mediaType{}, nil
+}
+
+func (mt *mediaType) Call(wrapper *BidWrapper) (string, error) {
+	if wrapper == nil {
+		return "", nil
+	}
+	return string(wrapper.BidType), nil
+}
+
+func (mt *mediaType) Name() string {
+	return MediaType
+}
+
+// ------------dealId-----------------------
+type dealId struct{}
+
+func NewDealId(params json.RawMessage) (SchemaFunction[BidWrapper], error) {
+	if err := checkNilArgs(params, DealId); err != nil {
+		return nil, err
+	}
+	return &dealId{}, nil
+}
+
+func (d *dealId) Call(wrapper *BidWrapper) (string, error) {
+	if bid := getBid(wrapper); bid != nil {
+		return bid.DealID, nil
+	}
+	return "", nil
+}
+
+func (d *dealId) Name() string {
+	return DealId
+}
+
+func getBid(wrapper *BidWrapper) *openrtb2.Bid {
+	if wrapper != nil && wrapper.Bid != nil {
+		return wrapper.Bid
+	}
+	return nil
+}
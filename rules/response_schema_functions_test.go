@@ -0,0 +1,225 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResponseSchemaFunction(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inName        string
+		inParams      json.RawMessage
+		expectedFunc  SchemaFunction[BidWrapper]
+		expectedError error
+	}{
+		{
+			desc:         "seat",
+			inName:       Seat,
+			expectedFunc: &seat{},
+		},
+		{
+			desc:         "seatIn",
+			inName:       SeatIn,
+			inParams:     json.RawMessage(`{"seats":["appnexus"]}`),
+			expectedFunc: &seatIn{Seats: []string{"appnexus"}, SeatDir: map[string]struct{}{"appnexus": {}}},
+		},
+		{
+			desc:         "adomain",
+			inName:       Adomain,
+			expectedFunc: &adomain{},
+		},
+		{
+			desc:         "adomainIn",
+			inName:       AdomainIn,
+			inParams:     json.RawMessage(`{"domains":["a.com"]}`),
+			expectedFunc: &adomainIn{Domains: []string{"a.com"}, DomainDir: map[string]struct{}{"a.com": {}}},
+		},
+		{
+			desc:         "bidPriceBucket",
+			inName:       BidPriceBucket,
+			inParams:     json.RawMessage(`{"increment":0.5,"max":20}`),
+			expectedFunc: &bidPriceBucket{Increment: 0.5, Max: 20},
+		},
+		{
+			desc:         "mediaType",
+			inName:       MediaType,
+			expectedFunc: &mediaType{},
+		},
+		{
+			desc:         "dealId",
+			inName:       DealId,
+			expectedFunc: &dealId{},
+		},
+		{
+			desc:          "unknown function",
+			inName:        "unknown",
+			expectedError: errors.New("Schema function unknown was not created"),
+		},
+		{
+			desc:          "seat with args",
+			inName:        Seat,
+			inParams:      json.RawMessage(`{"seats":["a"]}`),
+			expectedError: errors.New("seat expects 0 arguments"),
+		},
+		{
+			desc:          "seatIn empty seats",
+			inName:        SeatIn,
+			inParams:      json.RawMessage(`{"seats":[]}`),
+			expectedError: errors.New("Empty seats argument in seatIn schema function"),
+		},
+		{
+			desc:          "adomainIn empty domains",
+			inName:        AdomainIn,
+			inParams:      json.RawMessage(`{}`),
+			expectedError: errors.New("Empty domains argument in adomainIn schema function"),
+		},
+		{
+			desc:          "bidPriceBucket missing increment",
+			inName:        BidPriceBucket,
+			inParams:      json.RawMessage(`{"max":20}`),
+			expectedError: errors.New("Missing or non-positive increment argument for bidPriceBucket schema function"),
+		},
+		{
+			desc:          "bidPriceBucket negative max",
+			inName:        BidPriceBucket,
+			inParams:      json.RawMessage(`{"increment":1,"max":-1}`),
+			expectedError: errors.New("Negative max argument for bidPriceBucket schema function"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := NewResponseSchemaFunction(tc.inName, tc.inParams)
+			assert.Equal(t, tc.expectedFunc, f)
+			assert.Equal(t, tc.expectedError, err)
+			if f != nil {
+				assert.Equal(t, tc.inName, f.Name())
+			}
+		})
+	}
+}
+
+func TestResponseSchemaFunctionsCall(t *testing.T) {
+	bidWrapper := &BidWrapper{
+		Seat:    "appnexus",
+		BidType: openrtb_ext.BidTypeVideo,
+		Bid: &openrtb2.Bid{
+			ID:      "bid1",
+			Price:   2.37,
+			ADomain: []string{"a.com", "b.com"},
+			DealID:  "deal1",
+		},
+	}
+
+	testCases := []struct {
+		desc           string
+		inFunc         SchemaFunction[BidWrapper]
+		inWrapper      *BidWrapper
+		expectedResult string
+	}{
+		{
+			desc:           "seat",
+			inFunc:         &seat{},
+			inWrapper:      bidWrapper,
+			expectedResult: "appnexus",
+		},
+		{
+			desc:           "seat nil wrapper",
+			inFunc:         &seat{},
+			inWrapper:      nil,
+			expectedResult: "",
+		},
+		{
+			desc:           "seatIn found",
+			inFunc:         &seatIn{SeatDir: map[string]struct{}{"appnexus": {}}},
+			inWrapper:      bidWrapper,
+			expectedResult: "true",
+		},
+		{
+			desc:           "seatIn not found",
+			inFunc:         &seatIn{SeatDir: map[string]struct{}{"rubicon": {}}},
+			inWrapper:      bidWrapper,
+			expectedResult: "false",
+		},
+		{
+			desc:           "adomain",
+			inFunc:         &adomain{},
+			inWrapper:      bidWrapper,
+			expectedResult: "a.com",
+		},
+		{
+			desc:           "adomain nil bid",
+			inFunc:         &adomain{},
+			inWrapper:      &BidWrapper{},
+			expectedResult: "",
+		},
+		{
+			desc:           "adomainIn matches second domain",
+			inFunc:         &adomainIn{DomainDir: map[string]struct{}{"b.com": {}}},
+			inWrapper:      bidWrapper,
+			expectedResult: "true",
+		},
+		{
+			desc:           "adomainIn no match",
+			inFunc:         &adomainIn{DomainDir: map[string]struct{}{"c.com": {}}},
+			inWrapper:      bidWrapper,
+			expectedResult: "false",
+		},
+		{
+			desc:           "adomainIn nil bid",
+			inFunc:         &adomainIn{DomainDir: map[string]struct{}{"c.com": {}}},
+			inWrapper:      nil,
+			expectedResult: "false",
+		},
+		{
+			desc:           "bidPriceBucket rounds down",
+			inFunc:         &bidPriceBucket{Increment: 0.5},
+			inWrapper:      bidWrapper,
+			expectedResult: "2.00",
+		},
+		{
+			desc:           "bidPriceBucket capped at max",
+			inFunc:         &bidPriceBucket{Increment: 0.5, Max: 1.2},
+			inWrapper:      bidWrapper,
+			expectedResult: "1.00",
+		},
+		{
+			desc:           "bidPriceBucket nil bid",
+			inFunc:         &bidPriceBucket{Increment: 0.5},
+			inWrapper:      &BidWrapper{},
+			expectedResult: "0.00",
+		},
+		{
+			desc:           "mediaType",
+			inFunc:         &mediaType{},
+			inWrapper:      bidWrapper,
+			expectedResult: "video",
+		},
+		{
+			desc:           "dealId",
+			inFunc:         &dealId{},
+			inWrapper:      bidWrapper,
+			expectedResult: "deal1",
+		},
+		{
+			desc:           "dealId nil bid",
+			inFunc:         &dealId{},
+			inWrapper:      nil,
+			expectedResult: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := tc.inFunc.Call(tc.inWrapper)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, res)
+		})
+	}
+}
@@ -4,31 +4,58 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/buger/jsonparser"
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/floors"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/prebid/prebid-server/v3/util/randomutil"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
 )
 
 const (
+	BundleIn         = "bundleIn"
 	Channel          = "channel"
+	CoppaInScope     = "coppaInScope"
 	DataCenter       = "dataCenter"
 	DataCenterIn     = "dataCenterIn"
+	DayOfWeek        = "dayOfWeek"
 	DeviceCountry    = "deviceCountry"
 	DeviceCountryIn  = "deviceCountryIn"
+	DeviceLmt        = "deviceLmt"
+	DeviceType       = "deviceType"
+	DomainIn         = "domainIn"
 	EidAvailable     = "eidAvailable"
 	EidIn            = "eidIn"
 	FpdAvailable     = "fpdAvailable"
 	GppSidAvailable  = "gppSidAvailable"
 	GppSidIn         = "gppSidIn"
+	HourOfDay        = "hourOfDay"
+	Integration      = "integration"
+	PagePathPrefix   = "pagePathPrefix"
 	Percent          = "percent"
 	TcfInScope       = "tcfInScope"
+	TmaxBucket       = "tmaxBucket"
+	UserAgentFamily  = "userAgentFamily"
 	UserFpdAvailable = "userFpdAvailable"
 )
 
+// Device types returned by the deviceType schema function
+const (
+	DeviceTypePhone   = floors.Phone
+	DeviceTypeTablet  = floors.Tablet
+	DeviceTypeDesktop = floors.Desktop
+)
+
 // SchemaFunction...
 type SchemaFunction[T any] interface {
 	Call(payload *T) (string, error)
@@ -65,6 +92,28 @@ func NewRequestSchemaFunction(name string, params json.RawMessage) (SchemaFuncti
 		return NewTcfInScope(params)
 	case Percent:
 		return NewPercent(params)
+	case DeviceType:
+		return NewDeviceType(params)
+	case DomainIn:
+		return NewDomainIn(params)
+	case BundleIn:
+		return NewBundleIn(params)
+	case UserAgentFamily:
+		return NewUserAgentFamily(params)
+	case CoppaInScope:
+		return NewCoppaInScope(params)
+	case DeviceLmt:
+		return NewDeviceLmt(params)
+	case DayOfWeek:
+		return NewDayOfWeek(params)
+	case HourOfDay:
+		return NewHourOfDay(params)
+	case PagePathPrefix:
+		return NewPagePathPrefix(params)
+	case Integration:
+		return NewIntegration(params)
+	case TmaxBucket:
+		return NewTmaxBucket(params)
 	default:
 		return nil, fmt.Errorf("Schema function %s was not created", name)
 	}
@@ -462,6 +511,366 @@ func (p *percent) Name() string {
 	return Percent
 }
 
+// ------------deviceType-------------------
+type deviceType struct{}
+
+func NewDeviceType(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, DeviceType); err != nil {
+		return nil, err
+	}
+	return &deviceType{}, nil
+}
+
+// Call classifies the device from its user agent the same way price floors rules do
+func (dt *deviceType) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	ua := getDeviceUA(wrapper)
+	if len(ua) == 0 {
+		return "", nil
+	}
+	return floors.DeviceTypeFromUserAgent(ua), nil
+}
+
+func (dt *deviceType) Name() string {
+	return DeviceType
+}
+
+// ------------domainIn---------------------
+type domainIn struct {
+	Domains   []string `json:"domains"`
+	DomainDir map[string]struct{}
+}
+
+func NewDomainIn(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	schemaFunc := &domainIn{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Domains) == 0 {
+		return nil, errors.New("Empty domains argument in domainIn schema function")
+	}
+
+	schemaFunc.DomainDir = make(map[string]struct{})
+	for i := range schemaFunc.Domains {
+		schemaFunc.DomainDir[schemaFunc.Domains[i]] = struct{}{}
+	}
+
+	return schemaFunc, nil
+}
+
+// Call looks for the site domain, the app domain or the publisher domain in the configured domains
+func (di *domainIn) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	for _, domain := range getDomains(wrapper) {
+		if _, found := di.DomainDir[domain]; found {
+			return "true", nil
+		}
+	}
+	return "false", nil
+}
+
+func (di *domainIn) Name() string {
+	return DomainIn
+}
+
+// ------------bundleIn---------------------
+type bundleIn struct {
+	Bundles   []string `json:"bundles"`
+	BundleDir map[string]struct{}
+}
+
+func NewBundleIn(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	schemaFunc := &bundleIn{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Bundles) == 0 {
+		return nil, errors.New("Empty bundles argument in bundleIn schema function")
+	}
+
+	schemaFunc.BundleDir = make(map[string]struct{})
+	for i := range schemaFunc.Bundles {
+		schemaFunc.BundleDir[schemaFunc.Bundles[i]] = struct{}{}
+	}
+
+	return schemaFunc, nil
+}
+
+func (bi *bundleIn) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if wrapper == nil || wrapper.BidRequest == nil || wrapper.App == nil || len(wrapper.App.Bundle) == 0 {
+		return "false", nil
+	}
+
+	_, found := bi.BundleDir[wrapper.App.Bundle]
+	return fmt.Sprintf("%t", found), nil
+}
+
+func (bi *bundleIn) Name() string {
+	return BundleIn
+}
+
+// ------------userAgentFamily--------------
+// userAgentFamilies are checked in order since most user agents claim to be several browsers at once
+var userAgentFamilies = []struct {
+	family  string
+	pattern *regexp.Regexp
+}{
+	{family: "edge", pattern: regexp.MustCompile(`Edg(e|A|iOS)?/`)},
+	{family: "opera", pattern: regexp.MustCompile(`OPR/|Opera`)},
+	{family: "samsung", pattern: regexp.MustCompile(`SamsungBrowser/`)},
+	{family: "ie", pattern: regexp.MustCompile(`MSIE |Trident/`)},
+	{family: "firefox", pattern: regexp.MustCompile(`Firefox/|FxiOS/`)},
+	{family: "chrome", pattern: regexp.MustCompile(`Chrome/|CriOS/`)},
+	{family: "safari", pattern: regexp.MustCompile(`Safari/`)},
+}
+
+type userAgentFamily struct{}
+
+func NewUserAgentFamily(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, UserAgentFamily); err != nil {
+		return nil, err
+	}
+	return &userAgentFamily{}, nil
+}
+
+// Call returns the browser family of the device user agent or "other" if the browser is not recognized
+func (uaf *userAgentFamily) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	ua := getDeviceUA(wrapper)
+	if len(ua) == 0 {
+		return "", nil
+	}
+	for _, f := range userAgentFamilies {
+		if f.pattern.MatchString(ua) {
+			return f.family, nil
+		}
+	}
+	return "other", nil
+}
+
+func (uaf *userAgentFamily) Name() string {
+	return UserAgentFamily
+}
+
+// ------------coppaInScope-----------------
+type coppaInScope struct{}
+
+func NewCoppaInScope(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, CoppaInScope); err != nil {
+		return nil, err
+	}
+	return &coppaInScope{}, nil
+}
+
+func (c *coppaInScope) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if regs := getRequestRegs(wrapper); regs != nil && regs.COPPA == int8(1) {
+		return "true", nil
+	}
+	return "false", nil
+}
+
+func (c *coppaInScope) Name() string {
+	return CoppaInScope
+}
+
+// ------------deviceLmt--------------------
+type deviceLmt struct{}
+
+func NewDeviceLmt(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, DeviceLmt); err != nil {
+		return nil, err
+	}
+	return &deviceLmt{}, nil
+}
+
+func (dl *deviceLmt) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if wrapper != nil && wrapper.BidRequest != nil && wrapper.Device != nil && wrapper.Device.Lmt != nil && *wrapper.Device.Lmt == int8(1) {
+		return "true", nil
+	}
+	return "false", nil
+}
+
+func (dl *deviceLmt) Name() string {
+	return DeviceLmt
+}
+
+// ------------dayOfWeek--------------------
+type dayOfWeek struct {
+	TimeZone string `json:"timezone"`
+	location *time.Location
+	clock    timeutil.Time
+}
+
+func NewDayOfWeek(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	schemaFunc := &dayOfWeek{clock: &timeutil.RealTime{}}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	location, err := loadTimeZone(schemaFunc.TimeZone, DayOfWeek)
+	if err != nil {
+		return nil, err
+	}
+	schemaFunc.location = location
+
+	return schemaFunc, nil
+}
+
+// Call returns the lowercase name of the current day in the configured time zone
+func (dw *dayOfWeek) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	return strings.ToLower(dw.clock.Now().In(dw.location).Weekday().String()), nil
+}
+
+func (dw *dayOfWeek) Name() string {
+	return DayOfWeek
+}
+
+// ------------hourOfDay--------------------
+type hourOfDay struct {
+	TimeZone string `json:"timezone"`
+	location *time.Location
+	clock    timeutil.Time
+}
+
+func NewHourOfDay(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	schemaFunc := &hourOfDay{clock: &timeutil.RealTime{}}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	location, err := loadTimeZone(schemaFunc.TimeZone, HourOfDay)
+	if err != nil {
+		return nil, err
+	}
+	schemaFunc.location = location
+
+	return schemaFunc, nil
+}
+
+// Call returns the current hour from 0 to 23 in the configured time zone
+func (hd *hourOfDay) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	return strconv.Itoa(hd.clock.Now().In(hd.location).Hour()), nil
+}
+
+func (hd *hourOfDay) Name() string {
+	return HourOfDay
+}
+
+// ------------pagePathPrefix---------------
+type pagePathPrefix struct {
+	Depth int `json:"depth"`
+}
+
+func NewPagePathPrefix(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	schemaFunc := &pagePathPrefix{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if schemaFunc.Depth <= 0 {
+		return nil, errors.New("Missing or non positive depth argument in pagePathPrefix schema function")
+	}
+
+	return schemaFunc, nil
+}
+
+// Call returns the first depth segments of the site page URL path, e.g. "/sports/football" for
+// https://example.com/sports/football/match-report with a depth of 2
+func (ppp *pagePathPrefix) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if wrapper == nil || wrapper.BidRequest == nil || wrapper.Site == nil || len(wrapper.Site.Page) == 0 {
+		return "", nil
+	}
+
+	page, err := url.Parse(wrapper.Site.Page)
+	if err != nil {
+		return "", nil
+	}
+
+	segments := make([]string, 0, ppp.Depth)
+	for _, segment := range strings.Split(page.Path, "/") {
+		if len(segment) == 0 {
+			continue
+		}
+		segments = append(segments, segment)
+		if len(segments) == ppp.Depth {
+			break
+		}
+	}
+	return "/" + strings.Join(segments, "/"), nil
+}
+
+func (ppp *pagePathPrefix) Name() string {
+	return PagePathPrefix
+}
+
+// ------------integration------------------
+type integration struct{}
+
+func NewIntegration(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	if err := checkNilArgs(params, Integration); err != nil {
+		return nil, err
+	}
+	return &integration{}, nil
+}
+
+func (i *integration) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	prebid, err := getExtRequestPrebid(wrapper)
+	if err != nil {
+		return "", err
+	}
+
+	if prebid == nil {
+		return "", nil
+	}
+	return prebid.Integration, nil
+}
+
+func (i *integration) Name() string {
+	return Integration
+}
+
+// ------------tmaxBucket-------------------
+type tmaxBucket struct {
+	Buckets []int64 `json:"buckets"`
+}
+
+func NewTmaxBucket(params json.RawMessage) (SchemaFunction[openrtb_ext.RequestWrapper], error) {
+	schemaFunc := &tmaxBucket{}
+	if err := jsonutil.Unmarshal(params, schemaFunc); err != nil {
+		return nil, err
+	}
+
+	if len(schemaFunc.Buckets) == 0 {
+		return nil, errors.New("Empty buckets argument in tmaxBucket schema function")
+	}
+	for _, bucket := range schemaFunc.Buckets {
+		if bucket <= 0 {
+			return nil, errors.New("Non positive bucket in tmaxBucket schema function")
+		}
+	}
+	sort.Slice(schemaFunc.Buckets, func(i, j int) bool { return schemaFunc.Buckets[i] < schemaFunc.Buckets[j] })
+
+	return schemaFunc, nil
+}
+
+// Call returns the upper bound of the smallest bucket the request tmax fits in or the largest bucket
+// followed by a "+" if the tmax exceeds all of them. An empty string is returned if tmax is not set.
+func (tb *tmaxBucket) Call(wrapper *openrtb_ext.RequestWrapper) (string, error) {
+	if wrapper == nil || wrapper.BidRequest == nil || wrapper.TMax <= 0 {
+		return "", nil
+	}
+
+	for _, bucket := range tb.Buckets {
+		if wrapper.TMax <= bucket {
+			return strconv.FormatInt(bucket, 10), nil
+		}
+	}
+	return strconv.FormatInt(tb.Buckets[len(tb.Buckets)-1], 10) + "+", nil
+}
+
+func (tb *tmaxBucket) Name() string {
+	return TmaxBucket
+}
+
 func checkUserDataAndUserExtData(wrapper *openrtb_ext.RequestWrapper) (string, error) {
 	if wrapper == nil {
 		return "false", nil
@@ -600,3 +1009,44 @@ func getRequestRegs(wrapper *openrtb_ext.RequestWrapper) *openrtb2.Regs {
 	}
 	return nil
 }
+
+func getDeviceUA(wrapper *openrtb_ext.RequestWrapper) string {
+	if wrapper != nil && wrapper.BidRequest != nil && wrapper.Device != nil {
+		return wrapper.Device.UA
+	}
+	return ""
+}
+
+// getDomains returns the site, app and publisher domains set in the request
+func getDomains(wrapper *openrtb_ext.RequestWrapper) []string {
+	if wrapper == nil || wrapper.BidRequest == nil {
+		return nil
+	}
+
+	var domains []string
+	if wrapper.Site != nil {
+		domains = append(domains, wrapper.Site.Domain)
+		if wrapper.Site.Publisher != nil {
+			domains = append(domains, wrapper.Site.Publisher.Domain)
+		}
+	}
+	if wrapper.App != nil {
+		domains = append(domains, wrapper.App.Domain)
+		if wrapper.App.Publisher != nil {
+			domains = append(domains, wrapper.App.Publisher.Domain)
+		}
+	}
+	return slices.DeleteFunc(domains, func(domain string) bool { return len(domain) == 0 })
+}
+
+// loadTimeZone loads the IANA time zone of a schema function defaulting to UTC if none is configured
+func loadTimeZone(name string, funcName string) (*time.Location, error) {
+	if len(name) == 0 {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid timezone argument %s in %s schema function", name, funcName)
+	}
+	return location, nil
+}
@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/prebid/prebid-server/v3/util/randomutil"
+	"github.com/prebid/prebid-server/v3/util/timeutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -1170,6 +1172,645 @@ func (g *mockRandomGenerator) GenerateInt63() int64 {
 	return int64(g.returnValue)
 }
 
+func TestDeviceTypeCall(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		inWrapper *openrtb_ext.RequestWrapper
+		result    string
+	}{
+		{
+			desc:      "nil wrapper",
+			inWrapper: nil,
+			result:    "",
+		},
+		{
+			desc: "nil wrapper.Device",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{},
+			},
+			result: "",
+		},
+		{
+			desc: "phone",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{UA: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"},
+				},
+			},
+			result: DeviceTypePhone,
+		},
+		{
+			desc: "tablet",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{UA: "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)"},
+				},
+			},
+			result: DeviceTypeTablet,
+		},
+		{
+			desc: "desktop",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{UA: "Mozilla/5.0 (Windows NT 10.0; Win64; x64)"},
+				},
+			},
+			result: DeviceTypeDesktop,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schemaFunc := &deviceType{}
+
+			result, err := schemaFunc.Call(tc.inWrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestNewDomainIn(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inParams      json.RawMessage
+		expectedError error
+	}{
+		{
+			desc:          "empty domains",
+			inParams:      json.RawMessage(`{"domains": []}`),
+			expectedError: errors.New("Empty domains argument in domainIn schema function"),
+		},
+		{
+			desc:     "success",
+			inParams: json.RawMessage(`{"domains": ["example.com"]}`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := NewDomainIn(tc.inParams)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestDomainInCall(t *testing.T) {
+	schemaFunc, err := NewDomainIn(json.RawMessage(`{"domains": ["example.com", "publisher.com"]}`))
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		desc      string
+		inWrapper *openrtb_ext.RequestWrapper
+		result    string
+	}{
+		{
+			desc:      "nil wrapper",
+			inWrapper: nil,
+			result:    "false",
+		},
+		{
+			desc: "site domain found",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Site: &openrtb2.Site{Domain: "example.com"},
+				},
+			},
+			result: "true",
+		},
+		{
+			desc: "site publisher domain found",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Site: &openrtb2.Site{Domain: "other.com", Publisher: &openrtb2.Publisher{Domain: "publisher.com"}},
+				},
+			},
+			result: "true",
+		},
+		{
+			desc: "app domain found",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					App: &openrtb2.App{Domain: "example.com"},
+				},
+			},
+			result: "true",
+		},
+		{
+			desc: "not found",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Site: &openrtb2.Site{Domain: "other.com"},
+				},
+			},
+			result: "false",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := schemaFunc.Call(tc.inWrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestNewBundleIn(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inParams      json.RawMessage
+		expectedError error
+	}{
+		{
+			desc:          "missing bundles",
+			inParams:      json.RawMessage(`{}`),
+			expectedError: errors.New("Empty bundles argument in bundleIn schema function"),
+		},
+		{
+			desc:     "success",
+			inParams: json.RawMessage(`{"bundles": ["com.example.app"]}`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := NewBundleIn(tc.inParams)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestBundleInCall(t *testing.T) {
+	schemaFunc, err := NewBundleIn(json.RawMessage(`{"bundles": ["com.example.app"]}`))
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		desc      string
+		inWrapper *openrtb_ext.RequestWrapper
+		result    string
+	}{
+		{
+			desc: "nil wrapper.App",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{},
+			},
+			result: "false",
+		},
+		{
+			desc: "bundle found",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					App: &openrtb2.App{Bundle: "com.example.app"},
+				},
+			},
+			result: "true",
+		},
+		{
+			desc: "bundle not found",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					App: &openrtb2.App{Bundle: "com.other.app"},
+				},
+			},
+			result: "false",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result, err := schemaFunc.Call(tc.inWrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestUserAgentFamilyCall(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		inUA   string
+		result string
+	}{
+		{
+			desc:   "no user agent",
+			inUA:   "",
+			result: "",
+		},
+		{
+			desc:   "chrome",
+			inUA:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			result: "chrome",
+		},
+		{
+			desc:   "edge",
+			inUA:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			result: "edge",
+		},
+		{
+			desc:   "firefox",
+			inUA:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			result: "firefox",
+		},
+		{
+			desc:   "safari",
+			inUA:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			result: "safari",
+		},
+		{
+			desc:   "other",
+			inUA:   "curl/8.4.0",
+			result: "other",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schemaFunc := &userAgentFamily{}
+			wrapper := &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{UA: tc.inUA},
+				},
+			}
+
+			result, err := schemaFunc.Call(wrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestCoppaInScopeCall(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		inWrapper *openrtb_ext.RequestWrapper
+		result    string
+	}{
+		{
+			desc: "nil wrapper.Regs",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{},
+			},
+			result: "false",
+		},
+		{
+			desc: "wrapper.Regs.COPPA not set",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Regs: &openrtb2.Regs{},
+				},
+			},
+			result: "false",
+		},
+		{
+			desc: "success",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Regs: &openrtb2.Regs{COPPA: 1},
+				},
+			},
+			result: "true",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schemaFunc := &coppaInScope{}
+
+			result, err := schemaFunc.Call(tc.inWrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestDeviceLmtCall(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		inWrapper *openrtb_ext.RequestWrapper
+		result    string
+	}{
+		{
+			desc: "nil wrapper.Device",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{},
+			},
+			result: "false",
+		},
+		{
+			desc: "nil wrapper.Device.Lmt",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{},
+				},
+			},
+			result: "false",
+		},
+		{
+			desc: "wrapper.Device.Lmt zero",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{Lmt: ptrutil.ToPtr(int8(0))},
+				},
+			},
+			result: "false",
+		},
+		{
+			desc: "success",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{Lmt: ptrutil.ToPtr(int8(1))},
+				},
+			},
+			result: "true",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schemaFunc := &deviceLmt{}
+
+			result, err := schemaFunc.Call(tc.inWrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (ft *fakeTime) Now() time.Time {
+	return ft.time
+}
+
+func TestNewDayOfWeekAndHourOfDay(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		inParams         json.RawMessage
+		expectedLocation string
+		expectedError    bool
+	}{
+		{
+			desc:             "no timezone defaults to UTC",
+			inParams:         json.RawMessage(`{}`),
+			expectedLocation: "UTC",
+		},
+		{
+			desc:             "valid timezone",
+			inParams:         json.RawMessage(`{"timezone": "America/New_York"}`),
+			expectedLocation: "America/New_York",
+		},
+		{
+			desc:          "invalid timezone",
+			inParams:      json.RawMessage(`{"timezone": "Mars/Olympus_Mons"}`),
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			dow, err := NewDayOfWeek(tc.inParams)
+			if tc.expectedError {
+				assert.Nil(t, dow)
+				assert.Equal(t, errors.New("Invalid timezone argument Mars/Olympus_Mons in dayOfWeek schema function"), err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expectedLocation, dow.(*dayOfWeek).location.String())
+			}
+
+			hod, err := NewHourOfDay(tc.inParams)
+			if tc.expectedError {
+				assert.Nil(t, hod)
+				assert.Equal(t, errors.New("Invalid timezone argument Mars/Olympus_Mons in hourOfDay schema function"), err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expectedLocation, hod.(*hourOfDay).location.String())
+			}
+		})
+	}
+}
+
+func TestDayOfWeekAndHourOfDayCall(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	// Monday 2024-01-01 03:30 UTC is Sunday 2023-12-31 22:30 in New York
+	clock := &fakeTime{time: time.Date(2024, time.January, 1, 3, 30, 0, 0, time.UTC)}
+
+	testCases := []struct {
+		desc         string
+		inLocation   *time.Location
+		expectedDay  string
+		expectedHour string
+	}{
+		{
+			desc:         "UTC",
+			inLocation:   time.UTC,
+			expectedDay:  "monday",
+			expectedHour: "3",
+		},
+		{
+			desc:         "America/New_York",
+			inLocation:   newYork,
+			expectedDay:  "sunday",
+			expectedHour: "22",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			day, err := (&dayOfWeek{location: tc.inLocation, clock: clock}).Call(&openrtb_ext.RequestWrapper{})
+			assert.Equal(t, tc.expectedDay, day)
+			assert.Nil(t, err)
+
+			hour, err := (&hourOfDay{location: tc.inLocation, clock: clock}).Call(&openrtb_ext.RequestWrapper{})
+			assert.Equal(t, tc.expectedHour, hour)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestNewPagePathPrefix(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inParams      json.RawMessage
+		expectedError error
+	}{
+		{
+			desc:          "missing depth",
+			inParams:      json.RawMessage(`{}`),
+			expectedError: errors.New("Missing or non positive depth argument in pagePathPrefix schema function"),
+		},
+		{
+			desc:          "negative depth",
+			inParams:      json.RawMessage(`{"depth": -1}`),
+			expectedError: errors.New("Missing or non positive depth argument in pagePathPrefix schema function"),
+		},
+		{
+			desc:     "success",
+			inParams: json.RawMessage(`{"depth": 1}`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := NewPagePathPrefix(tc.inParams)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestPagePathPrefixCall(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		inDepth int
+		inPage  string
+		result  string
+	}{
+		{
+			desc:    "no page",
+			inDepth: 1,
+			inPage:  "",
+			result:  "",
+		},
+		{
+			desc:    "depth 1",
+			inDepth: 1,
+			inPage:  "https://example.com/sports/football/match-report?id=1",
+			result:  "/sports",
+		},
+		{
+			desc:    "depth 2",
+			inDepth: 2,
+			inPage:  "https://example.com/sports/football/match-report?id=1",
+			result:  "/sports/football",
+		},
+		{
+			desc:    "depth exceeds path segments",
+			inDepth: 5,
+			inPage:  "https://example.com/sports/",
+			result:  "/sports",
+		},
+		{
+			desc:    "root path",
+			inDepth: 1,
+			inPage:  "https://example.com",
+			result:  "/",
+		},
+		{
+			desc:    "malformed url",
+			inDepth: 1,
+			inPage:  "://example.com",
+			result:  "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schemaFunc := &pagePathPrefix{Depth: tc.inDepth}
+			wrapper := &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Site: &openrtb2.Site{Page: tc.inPage},
+				},
+			}
+
+			result, err := schemaFunc.Call(wrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestIntegrationCall(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inWrapper     *openrtb_ext.RequestWrapper
+		result        string
+		expectedError bool
+	}{
+		{
+			desc: "nil request.ext",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{},
+			},
+			result: "",
+		},
+		{
+			desc: "malformed request.ext",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Ext: json.RawMessage("malformed"),
+				},
+			},
+			result:        "",
+			expectedError: true,
+		},
+		{
+			desc: "success",
+			inWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Ext: json.RawMessage(`{"prebid":{"integration":"amp"}}`),
+				},
+			},
+			result: "amp",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schemaFunc := &integration{}
+
+			result, err := schemaFunc.Call(tc.inWrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Equal(t, tc.expectedError, err != nil)
+		})
+	}
+}
+
+func TestNewTmaxBucket(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		inParams      json.RawMessage
+		expectedError error
+	}{
+		{
+			desc:          "empty buckets",
+			inParams:      json.RawMessage(`{"buckets": []}`),
+			expectedError: errors.New("Empty buckets argument in tmaxBucket schema function"),
+		},
+		{
+			desc:          "non positive bucket",
+			inParams:      json.RawMessage(`{"buckets": [500, 0]}`),
+			expectedError: errors.New("Non positive bucket in tmaxBucket schema function"),
+		},
+		{
+			desc:     "success",
+			inParams: json.RawMessage(`{"buckets": [500]}`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := NewTmaxBucket(tc.inParams)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestTmaxBucketCall(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		inTmax int64
+		result string
+	}{
+		{desc: "tmax not set", inTmax: 0, result: ""},
+		{desc: "below first bucket", inTmax: 300, result: "500"},
+		{desc: "first bucket upper bound", inTmax: 500, result: "500"},
+		{desc: "second bucket", inTmax: 501, result: "1000"},
+		{desc: "above all buckets", inTmax: 3000, result: "1000+"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schemaFunc := &tmaxBucket{Buckets: []int64{500, 1000}}
+			wrapper := &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{TMax: tc.inTmax},
+			}
+
+			result, err := schemaFunc.Call(wrapper)
+			assert.Equal(t, tc.result, result)
+			assert.Nil(t, err)
+		})
+	}
+}
+
 func TestCheckUserDataAndUserExtData(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -2054,6 +2695,31 @@ func TestConstructorsOfParamLessSchemaFunctions(t *testing.T) {
 			constructorFunc:    NewTcfInScope,
 			expectedSchemaFunc: &tcfInScope{},
 		},
+		{
+			schemaFuncName:     DeviceType,
+			constructorFunc:    NewDeviceType,
+			expectedSchemaFunc: &deviceType{},
+		},
+		{
+			schemaFuncName:     UserAgentFamily,
+			constructorFunc:    NewUserAgentFamily,
+			expectedSchemaFunc: &userAgentFamily{},
+		},
+		{
+			schemaFuncName:     CoppaInScope,
+			constructorFunc:    NewCoppaInScope,
+			expectedSchemaFunc: &coppaInScope{},
+		},
+		{
+			schemaFuncName:     DeviceLmt,
+			constructorFunc:    NewDeviceLmt,
+			expectedSchemaFunc: &deviceLmt{},
+		},
+		{
+			schemaFuncName:     Integration,
+			constructorFunc:    NewIntegration,
+			expectedSchemaFunc: &integration{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2130,6 +2796,50 @@ func TestSchemaFunctionsName(t *testing.T) {
 			expectedSchemaFuncName: UserFpdAvailable,
 			inSchemaFunc:           &userFpdAvailable{},
 		},
+		{
+			expectedSchemaFuncName: BundleIn,
+			inSchemaFunc:           &bundleIn{},
+		},
+		{
+			expectedSchemaFuncName: CoppaInScope,
+			inSchemaFunc:           &coppaInScope{},
+		},
+		{
+			expectedSchemaFuncName: DayOfWeek,
+			inSchemaFunc:           &dayOfWeek{},
+		},
+		{
+			expectedSchemaFuncName: DeviceLmt,
+			inSchemaFunc:           &deviceLmt{},
+		},
+		{
+			expectedSchemaFuncName: DeviceType,
+			inSchemaFunc:           &deviceType{},
+		},
+		{
+			expectedSchemaFuncName: DomainIn,
+			inSchemaFunc:           &domainIn{},
+		},
+		{
+			expectedSchemaFuncName: HourOfDay,
+			inSchemaFunc:           &hourOfDay{},
+		},
+		{
+			expectedSchemaFuncName: Integration,
+			inSchemaFunc:           &integration{},
+		},
+		{
+			expectedSchemaFuncName: PagePathPrefix,
+			inSchemaFunc:           &pagePathPrefix{},
+		},
+		{
+			expectedSchemaFuncName: TmaxBucket,
+			inSchemaFunc:           &tmaxBucket{},
+		},
+		{
+			expectedSchemaFuncName: UserAgentFamily,
+			inSchemaFunc:           &userAgentFamily{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2235,6 +2945,78 @@ func TestNewRequestSchemaFunction(t *testing.T) {
 			inParams:           json.RawMessage(`{}`),
 			expectedSchemaFunc: &userFpdAvailable{},
 		},
+		{
+			inFunctionName:     DeviceType,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &deviceType{},
+		},
+		{
+			inFunctionName: DomainIn,
+			inParams:       json.RawMessage(`{"domains": ["example.com"]}`),
+			expectedSchemaFunc: &domainIn{
+				Domains: []string{"example.com"},
+				DomainDir: map[string]struct{}{
+					"example.com": {},
+				},
+			},
+		},
+		{
+			inFunctionName: BundleIn,
+			inParams:       json.RawMessage(`{"bundles": ["com.example.app"]}`),
+			expectedSchemaFunc: &bundleIn{
+				Bundles: []string{"com.example.app"},
+				BundleDir: map[string]struct{}{
+					"com.example.app": {},
+				},
+			},
+		},
+		{
+			inFunctionName:     UserAgentFamily,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &userAgentFamily{},
+		},
+		{
+			inFunctionName:     CoppaInScope,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &coppaInScope{},
+		},
+		{
+			inFunctionName:     DeviceLmt,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &deviceLmt{},
+		},
+		{
+			inFunctionName: DayOfWeek,
+			inParams:       json.RawMessage(`{"timezone": "UTC"}`),
+			expectedSchemaFunc: &dayOfWeek{
+				TimeZone: "UTC",
+				location: time.UTC,
+				clock:    &timeutil.RealTime{},
+			},
+		},
+		{
+			inFunctionName: HourOfDay,
+			inParams:       json.RawMessage(`{}`),
+			expectedSchemaFunc: &hourOfDay{
+				location: time.UTC,
+				clock:    &timeutil.RealTime{},
+			},
+		},
+		{
+			inFunctionName:     PagePathPrefix,
+			inParams:           json.RawMessage(`{"depth": 2}`),
+			expectedSchemaFunc: &pagePathPrefix{Depth: 2},
+		},
+		{
+			inFunctionName:     Integration,
+			inParams:           json.RawMessage(`{}`),
+			expectedSchemaFunc: &integration{},
+		},
+		{
+			inFunctionName:     TmaxBucket,
+			inParams:           json.RawMessage(`{"buckets": [1000, 500]}`),
+			expectedSchemaFunc: &tmaxBucket{Buckets: []int64{500, 1000}},
+		},
 		{
 			inFunctionName:     "unknown",
 			inParams:           json.RawMessage(`{}`),
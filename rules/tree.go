@@ -3,30 +3,80 @@ package rules
 import (
 	"encoding/json"
 	"errors"
+	"sort"
 )
 
 // Node represents a node in the tree structure.
 // It contains a schema function, a list of result functions, and a map of child nodes.
+// Children whose condition is a pattern (set, range or regex) are kept apart from the
+// exact match children in the order they must be checked.
 type Node[T1 any, T2 any] struct {
 	SchemaFunction  SchemaFunction[T1]
 	ResultFunctions []ResultFunction[T1, T2]
 	Children        map[string]*Node[T1, T2]
+	patternChildren []patternChild[T1, T2]
+}
+
+// patternChild is a child node reached when its condition matcher matches the parent schema function result
+type patternChild[T1 any, T2 any] struct {
+	condition string
+	matcher   conditionMatcher
+	node      *Node[T1, T2]
 }
 
 // isLeaf checks if the node is a leaf node.
 func (n *Node[T1, T2]) isLeaf() bool {
-	return len(n.Children) == 0
+	return len(n.Children) == 0 && len(n.patternChildren) == 0
+}
+
+// AddChild returns the child node for the given rule condition creating it if it doesn't exist.
+// Pattern conditions are validated and an error is returned if they are malformed.
+func (n *Node[T1, T2]) AddChild(condition string) (*Node[T1, T2], error) {
+	if child, ok := n.Children[condition]; ok {
+		return child, nil
+	}
+	for _, pc := range n.patternChildren {
+		if pc.condition == condition {
+			return pc.node, nil
+		}
+	}
+
+	matcher, err := newConditionMatcher(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	child := &Node[T1, T2]{}
+	if matcher == nil {
+		if n.Children == nil {
+			n.Children = make(map[string]*Node[T1, T2])
+		}
+		n.Children[condition] = child
+		return child, nil
+	}
+
+	n.patternChildren = append(n.patternChildren, patternChild[T1, T2]{condition: condition, matcher: matcher, node: child})
+	sort.SliceStable(n.patternChildren, func(i, j int) bool {
+		return n.patternChildren[i].matcher.rank() < n.patternChildren[j].matcher.rank()
+	})
+	return child, nil
 }
 
 // matchingChild checks if the node has a child that matches the given value.
-// It first checks for an exact match, and if not found, it checks for a wildcard match returning the child node.
+// It first checks for an exact match, then for a pattern match trying set, range and regex conditions
+// in that order, each in the order they were added, and finally for a wildcard match returning the child node.
 // If no matching child is found, it returns nil.
 func (n *Node[T1, T2]) matchChild(value string) (string, *Node[T1, T2]) {
 	if child, ok := n.Children[value]; ok {
 		return value, child
 	}
-	if child, ok := n.Children["*"]; ok {
-		return "*", child
+	for _, pc := range n.patternChildren {
+		if pc.matcher.match(value) {
+			return pc.condition, pc.node
+		}
+	}
+	if child, ok := n.Children[WildcardCondition]; ok {
+		return WildcardCondition, child
 	}
 	return "", nil
 }
@@ -42,39 +92,59 @@ type Tree[T1 any, T2 any] struct {
 	ModelVersion     string
 }
 
+// Trace describes a tree run. Along with the metadata passed to the result functions, it holds the
+// conditions matched on the way down, whether the default functions were selected because no leaf was
+// reached and the names of the result functions executed.
+type Trace struct {
+	ResultFunctionMeta
+	MatchedConditions []string
+	Default           bool
+	ResultFunctions   []string
+}
+
 // Run attempts to walk down the tree from the root to a leaf node. Each node references a schema function
 // to execute that returns a result that is used to compare against the node values on the level below it.
 // If the result matches one of the node values on the next level, we move to that node, otherwise we exit.
 // If a leaf node is reached, it's result functions are executed on the provided result payload.
 func (t *Tree[T1, T2]) Run(payload *T1, result *T2) error {
+	_, err := t.Explain(payload, result)
+	return err
+}
+
+// Explain walks down the tree and executes the result functions exactly like Run does, returning
+// a trace of the path taken that is filled up to the point an error is found if any.
+func (t *Tree[T1, T2]) Explain(payload *T1, result *T2) (Trace, error) {
 	var nodeKey string
+	trace := Trace{
+		ResultFunctionMeta: ResultFunctionMeta{
+			AnalyticsKey: t.AnalyticsKey,
+			ModelVersion: t.ModelVersion,
+		},
+	}
 	if t.Root == nil {
-		return errors.New("tree root is nil")
+		return trace, errors.New("tree root is nil")
 	}
 	currNode := t.Root
 
-	resFuncMeta := ResultFunctionMeta{
-		AnalyticsKey: t.AnalyticsKey,
-		ModelVersion: t.ModelVersion,
-	}
-
 	for !currNode.isLeaf() {
 		if currNode.SchemaFunction == nil {
-			return errors.New("schema function is nil")
+			return trace, errors.New("schema function is nil")
 		}
 
 		res, err := currNode.SchemaFunction.Call(payload)
 		if err != nil {
-			return err
+			return trace, err
 		}
-		resFuncMeta.appendToSchemaFunctionResults(currNode.SchemaFunction.Name(), res)
+		trace.appendToSchemaFunctionResults(currNode.SchemaFunction.Name(), res)
 
 		nodeKey, currNode = currNode.matchChild(res)
 		if currNode == nil {
-			resFuncMeta.RuleFired = "default"
+			trace.RuleFired = "default"
+			trace.Default = true
 			break
 		}
-		resFuncMeta.appendToRuleFired(nodeKey)
+		trace.appendToRuleFired(nodeKey)
+		trace.MatchedConditions = append(trace.MatchedConditions, nodeKey)
 	}
 
 	resultFuncs := t.DefaultFunctions
@@ -83,12 +153,13 @@ func (t *Tree[T1, T2]) Run(payload *T1, result *T2) error {
 	}
 
 	for _, rf := range resultFuncs {
-		if err := rf.Call(payload, result, resFuncMeta); err != nil {
-			return err
+		trace.ResultFunctions = append(trace.ResultFunctions, rf.Name())
+		if err := rf.Call(payload, result, trace.ResultFunctionMeta); err != nil {
+			return trace, err
 		}
 	}
 
-	return nil
+	return trace, nil
 }
 
 // validate checks if the tree is well-formed which means all leaves are at the same depth.
@@ -132,6 +203,11 @@ func validateNode[T1 any, T2 any](node *Node[T1, T2], depth int, firstLeafDepth
 			return false
 		}
 	}
+	for _, pc := range node.patternChildren {
+		if !validateNode(pc.node, depth+1, firstLeafDepth) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -110,6 +110,57 @@ func TestMatchChild(t *testing.T) {
 	}
 }
 
+func TestMatchChildPrecedence(t *testing.T) {
+	node := &Node[struct{}, struct{}]{}
+	for _, condition := range []string{"*", "regex:^1", "range:0,500", "in:100,200", "100"} {
+		_, err := node.AddChild(condition)
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		name            string
+		value           string
+		expectedNodeKey string
+	}{
+		{name: "exact_beats_patterns", value: "100", expectedNodeKey: "100"},
+		{name: "set_beats_range_and_regex", value: "200", expectedNodeKey: "in:100,200"},
+		{name: "range_beats_regex", value: "150", expectedNodeKey: "range:0,500"},
+		{name: "regex", value: "1000", expectedNodeKey: "regex:^1"},
+		{name: "wildcard", value: "2000", expectedNodeKey: "*"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nodeKey, child := node.matchChild(tc.value)
+			assert.Equal(t, tc.expectedNodeKey, nodeKey)
+			assert.NotNil(t, child)
+		})
+	}
+}
+
+func TestAddChild(t *testing.T) {
+	node := &Node[struct{}, struct{}]{}
+
+	exact, err := node.AddChild("web")
+	assert.NoError(t, err)
+	exactAgain, err := node.AddChild("web")
+	assert.NoError(t, err)
+	assert.Same(t, exact, exactAgain)
+
+	pattern, err := node.AddChild("range:1,2")
+	assert.NoError(t, err)
+	patternAgain, err := node.AddChild("range:1,2")
+	assert.NoError(t, err)
+	assert.Same(t, pattern, patternAgain)
+
+	_, err = node.AddChild("range:2,1")
+	assert.Error(t, err)
+
+	assert.Len(t, node.Children, 1)
+	assert.Len(t, node.patternChildren, 1)
+	assert.False(t, node.isLeaf())
+}
+
 func TestTreeValidate(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -567,6 +618,119 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name          string
+		inTree        *Tree[struct{}, runTestAssertableData]
+		expectedTrace Trace
+		expectedErr   error
+	}{
+		{
+			name:          "Nil_tree.Root",
+			inTree:        &Tree[struct{}, runTestAssertableData]{AnalyticsKey: "key", ModelVersion: "v1"},
+			expectedTrace: Trace{ResultFunctionMeta: ResultFunctionMeta{AnalyticsKey: "key", ModelVersion: "v1"}},
+			expectedErr:   errors.New("tree root is nil"),
+		},
+		{
+			name: "Leaf_reached_through_wildcard",
+			inTree: &Tree[struct{}, runTestAssertableData]{
+				AnalyticsKey: "key",
+				ModelVersion: "v1",
+				Root: &Node[struct{}, runTestAssertableData]{
+					SchemaFunction: &nodeSchemaFunction{},
+					Children: map[string]*Node[struct{}, runTestAssertableData]{
+						"nodeSchemaResult": {
+							SchemaFunction: &nodeSchemaFunction{},
+							Children: map[string]*Node[struct{}, runTestAssertableData]{
+								"*": {
+									ResultFunctions: []ResultFunction[struct{}, runTestAssertableData]{
+										&leafResultFunction{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTrace: Trace{
+				ResultFunctionMeta: ResultFunctionMeta{
+					SchemaFunctionResults: []SchemaFunctionStep{
+						{FuncName: "nodeSchemaFuncName", FuncResult: "nodeSchemaResult"},
+						{FuncName: "nodeSchemaFuncName", FuncResult: "nodeSchemaResult"},
+					},
+					AnalyticsKey: "key",
+					RuleFired:    "nodeSchemaResult|*",
+					ModelVersion: "v1",
+				},
+				MatchedConditions: []string{"nodeSchemaResult", "*"},
+				ResultFunctions:   []string{"leafResultFunction"},
+			},
+		},
+		{
+			name: "Leaf_not_reached_default_functions_run",
+			inTree: &Tree[struct{}, runTestAssertableData]{
+				DefaultFunctions: []ResultFunction[struct{}, runTestAssertableData]{
+					&defaultResultFunction{},
+				},
+				Root: &Node[struct{}, runTestAssertableData]{
+					SchemaFunction: &nodeSchemaFunction{},
+					Children: map[string]*Node[struct{}, runTestAssertableData]{
+						"unreachable-leaf": {},
+					},
+				},
+			},
+			expectedTrace: Trace{
+				ResultFunctionMeta: ResultFunctionMeta{
+					SchemaFunctionResults: []SchemaFunctionStep{
+						{FuncName: "nodeSchemaFuncName", FuncResult: "nodeSchemaResult"},
+					},
+					RuleFired: "default",
+				},
+				Default:         true,
+				ResultFunctions: []string{"defaultResultFunction"},
+			},
+		},
+		{
+			name: "Result_function_error",
+			inTree: &Tree[struct{}, runTestAssertableData]{
+				Root: &Node[struct{}, runTestAssertableData]{
+					SchemaFunction: &nodeSchemaFunction{},
+					Children: map[string]*Node[struct{}, runTestAssertableData]{
+						"nodeSchemaResult": {
+							ResultFunctions: []ResultFunction[struct{}, runTestAssertableData]{
+								&errorProneResultFunction{},
+								&leafResultFunction{},
+							},
+						},
+					},
+				},
+			},
+			expectedTrace: Trace{
+				ResultFunctionMeta: ResultFunctionMeta{
+					SchemaFunctionResults: []SchemaFunctionStep{
+						{FuncName: "nodeSchemaFuncName", FuncResult: "nodeSchemaResult"},
+					},
+					RuleFired: "nodeSchemaResult",
+				},
+				MatchedConditions: []string{"nodeSchemaResult"},
+				ResultFunctions:   []string{"faultyResultFunction"},
+			},
+			expectedErr: errors.New("faulty result function error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			anyPayload := struct{}{}
+			result := runTestAssertableData{modifiableData: "unmodified_data"}
+
+			trace, err := tc.inTree.Explain(&anyPayload, &result)
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedTrace, trace)
+		})
+	}
+}
+
 // helper schema functions
 type nodeSchemaFunction struct{}
 
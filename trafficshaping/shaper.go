@@ -0,0 +1,209 @@
+package trafficshaping
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/floors"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const (
+	// groupDelimiter joins the values of the dimensions of an imp into the key of its group
+	groupDelimiter = "|"
+	// agingFactor is how many times the min requests a group is sent before its counts are halved, so that the
+	// model follows the changes in what the bidders bid on
+	agingFactor = 10
+)
+
+// Shaper learns the groups of imps each bidder bids on, the imps being grouped by the values of the configured floors
+// schema dimensions, and tells which imps are not worth sending to a bidder.
+// All the methods of a nil Shaper, returned when traffic shaping is disabled, send every imp.
+type Shaper struct {
+	cfg    config.TrafficShaping
+	random func() float64
+	now    func() time.Time
+
+	mutex sync.Mutex
+	model Model
+}
+
+// Model is the state learned by the shaper, saved to the configured file
+type Model struct {
+	// Dimensions are the schema fields the groups are keyed by, a model learned with other dimensions being discarded
+	Dimensions []string `json:"dimensions"`
+	// UpdatedAt is when the pruned groups were last computed
+	UpdatedAt time.Time `json:"updated_at"`
+	// Bidders holds the groups of imps sent to each bidder, keyed by the values of the dimensions joined by "|"
+	Bidders map[string]map[string]*GroupStats `json:"bidders"`
+}
+
+// GroupStats counts the imps of a group sent to a bidder and the ones it bid on
+type GroupStats struct {
+	Requests int64 `json:"requests"`
+	Bids     int64 `json:"bids"`
+	// Pruned is whether the imps of the group are no longer sent to the bidder, but for exploration
+	Pruned bool `json:"pruned"`
+}
+
+// NewShaper creates the shaper, starting from the model saved to the configured file if any. It returns nil when
+// traffic shaping is disabled.
+func NewShaper(cfg config.TrafficShaping) (*Shaper, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if err := floors.ValidateSchemaDimensions(cfg.Dimensions); err != nil {
+		return nil, fmt.Errorf("traffic_shaping.dimensions: %v", err)
+	}
+
+	s := &Shaper{
+		cfg:    cfg,
+		random: rand.Float64,
+		now:    time.Now,
+		model:  Model{Dimensions: cfg.Dimensions, Bidders: make(map[string]map[string]*GroupStats)},
+	}
+	if cfg.File == "" {
+		return s, nil
+	}
+
+	model, err := loadModel(cfg.File)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		glog.Infof("No traffic shaping model in %s, starting a new one", cfg.File)
+	case err != nil:
+		return nil, fmt.Errorf("failed to load the traffic shaping model from %s: %v", cfg.File, err)
+	case !slices.Equal(model.Dimensions, cfg.Dimensions):
+		glog.Warningf("Discarding the traffic shaping model in %s, learned with the dimensions %v instead of %v", cfg.File, model.Dimensions, cfg.Dimensions)
+	default:
+		s.model = model
+	}
+	return s, nil
+}
+
+// Group returns the key of the group of an imp, made of the values of the dimensions for the imp
+func (s *Shaper) Group(request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper) string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(floors.RuleKey(s.cfg.Dimensions, request, imp), groupDelimiter)
+}
+
+// Allow returns whether an imp of the group should be sent to the bidder.
+// The imps of a pruned group are still sent for the configured exploration percentage.
+func (s *Shaper) Allow(bidder, group string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mutex.Lock()
+	stats, ok := s.model.Bidders[bidder][group]
+	pruned := ok && stats.Pruned
+	s.mutex.Unlock()
+
+	if !pruned {
+		return true
+	}
+	return s.random()*100 < float64(s.cfg.ExplorationPercent)
+}
+
+// Record counts an imp of the group sent to the bidder and whether the bidder bid on it
+func (s *Shaper) Record(bidder, group string, bid bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	groups, ok := s.model.Bidders[bidder]
+	if !ok {
+		groups = make(map[string]*GroupStats)
+		s.model.Bidders[bidder] = groups
+	}
+	stats, ok := groups[group]
+	if !ok {
+		if len(groups) >= s.cfg.MaxGroupsPerBidder {
+			return
+		}
+		stats = &GroupStats{}
+		groups[group] = stats
+	}
+	stats.Requests++
+	if bid {
+		stats.Bids++
+	}
+}
+
+// Run computes the groups to prune for each bidder and saves the model, run periodically
+func (s *Shaper) Run() error {
+	s.mutex.Lock()
+	minRequests := int64(s.cfg.MinRequests)
+	for _, groups := range s.model.Bidders {
+		for _, stats := range groups {
+			if stats.Requests >= agingFactor*minRequests {
+				stats.Requests /= 2
+				stats.Bids /= 2
+			}
+			stats.Pruned = stats.Requests >= minRequests && (stats.Bids == 0 || float64(stats.Bids) < s.cfg.MinBidRate*float64(stats.Requests))
+		}
+	}
+	s.model.UpdatedAt = s.now()
+	data, err := jsonutil.Marshal(s.model)
+	s.mutex.Unlock()
+
+	if err == nil && s.cfg.File != "" {
+		err = saveModel(s.cfg.File, data)
+	}
+	if err != nil {
+		glog.Errorf("Failed to save the traffic shaping model: %v", err)
+	}
+	return err
+}
+
+// Shutdown saves the model one last time
+func (s *Shaper) Shutdown() {
+	if s == nil {
+		return
+	}
+	s.Run()
+}
+
+// MarshalModel returns the current model as JSON
+func (s *Shaper) MarshalModel() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return jsonutil.Marshal(s.model)
+}
+
+func loadModel(file string) (Model, error) {
+	var model Model
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return model, err
+	}
+	if err := jsonutil.UnmarshalValid(data, &model); err != nil {
+		return model, err
+	}
+	if model.Bidders == nil {
+		model.Bidders = make(map[string]map[string]*GroupStats)
+	}
+	return model, nil
+}
+
+// saveModel replaces the file at once, a model partially written not being loaded at the next start
+func saveModel(file string, data []byte) error {
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
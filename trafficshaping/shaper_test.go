@@ -0,0 +1,168 @@
+package trafficshaping
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() config.TrafficShaping {
+	return config.TrafficShaping{
+		Enabled:            true,
+		Dimensions:         []string{"mediaType", "country"},
+		MinRequests:        10,
+		MinBidRate:         0.1,
+		ExplorationPercent: 5,
+		MaxGroupsPerBidder: 2,
+	}
+}
+
+func TestNewShaper(t *testing.T) {
+	shaper, err := NewShaper(config.TrafficShaping{Enabled: false})
+	assert.NoError(t, err)
+	assert.Nil(t, shaper)
+
+	cfg := testConfig()
+	cfg.Dimensions = []string{"unknown"}
+	_, err = NewShaper(cfg)
+	assert.Error(t, err)
+
+	cfg = testConfig()
+	cfg.File = filepath.Join(t.TempDir(), "model.json")
+	shaper, err = NewShaper(cfg)
+	require.NoError(t, err)
+	assert.Empty(t, shaper.model.Bidders)
+}
+
+func TestNewShaperLoadsModel(t *testing.T) {
+	testCases := []struct {
+		description     string
+		file            string
+		expectedBidders map[string]map[string]*GroupStats
+		expectedError   bool
+	}{
+		{
+			description:     "same-dimensions",
+			file:            `{"dimensions":["mediaType","country"],"bidders":{"appnexus":{"banner|USA":{"requests":20,"bids":0,"pruned":true}}}}`,
+			expectedBidders: map[string]map[string]*GroupStats{"appnexus": {"banner|USA": {Requests: 20, Pruned: true}}},
+		},
+		{
+			description:     "other-dimensions",
+			file:            `{"dimensions":["mediaType"],"bidders":{"appnexus":{"banner":{"requests":20,"bids":0,"pruned":true}}}}`,
+			expectedBidders: map[string]map[string]*GroupStats{},
+		},
+		{
+			description:   "malformed",
+			file:          `{"dimensions":`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.File = filepath.Join(t.TempDir(), "model.json")
+			require.NoError(t, os.WriteFile(cfg.File, []byte(test.file), 0644))
+
+			shaper, err := NewShaper(cfg)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBidders, shaper.model.Bidders)
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	shaper, err := NewShaper(testConfig())
+	require.NoError(t, err)
+
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Imp:    []openrtb2.Imp{{ID: "imp1", Banner: &openrtb2.Banner{}}},
+		Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}},
+	}}
+	assert.Equal(t, "banner|USA", shaper.Group(request, request.GetImp()[0]))
+}
+
+func TestAllow(t *testing.T) {
+	shaper, err := NewShaper(testConfig())
+	require.NoError(t, err)
+	shaper.model.Bidders["appnexus"] = map[string]*GroupStats{
+		"banner|USA": {Requests: 100, Pruned: true},
+		"video|USA":  {Requests: 100, Bids: 50},
+	}
+
+	shaper.random = func() float64 { return 0.5 }
+	assert.False(t, shaper.Allow("appnexus", "banner|USA"), "pruned")
+	assert.True(t, shaper.Allow("appnexus", "video|USA"), "not pruned")
+	assert.True(t, shaper.Allow("appnexus", "native|USA"), "unknown group")
+	assert.True(t, shaper.Allow("rubicon", "banner|USA"), "unknown bidder")
+
+	shaper.random = func() float64 { return 0.01 }
+	assert.True(t, shaper.Allow("appnexus", "banner|USA"), "explored")
+}
+
+func TestRecordAndRun(t *testing.T) {
+	cfg := testConfig()
+	cfg.File = filepath.Join(t.TempDir(), "model.json")
+	shaper, err := NewShaper(cfg)
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	shaper.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		shaper.Record("appnexus", "banner|USA", false)
+		shaper.Record("appnexus", "video|USA", i == 0)
+		shaper.Record("rubicon", "banner|USA", i%2 == 0)
+	}
+	shaper.Record("appnexus", "native|USA", true)
+
+	require.NoError(t, shaper.Run())
+
+	expectedModel := Model{
+		Dimensions: []string{"mediaType", "country"},
+		UpdatedAt:  now,
+		Bidders: map[string]map[string]*GroupStats{
+			"appnexus": {
+				"banner|USA": {Requests: 10, Pruned: true},
+				"video|USA":  {Requests: 10, Bids: 1},
+			},
+			"rubicon": {
+				"banner|USA": {Requests: 10, Bids: 5},
+			},
+		},
+	}
+	assert.Equal(t, expectedModel, shaper.model, "the groups over the max per bidder are not recorded")
+
+	saved, err := loadModel(cfg.File)
+	require.NoError(t, err)
+	assert.Equal(t, expectedModel, saved)
+}
+
+func TestRunAgesCounts(t *testing.T) {
+	shaper, err := NewShaper(testConfig())
+	require.NoError(t, err)
+	shaper.model.Bidders["appnexus"] = map[string]*GroupStats{
+		"banner|USA": {Requests: 100, Bids: 40, Pruned: true},
+	}
+
+	require.NoError(t, shaper.Run())
+	assert.Equal(t, &GroupStats{Requests: 50, Bids: 20}, shaper.model.Bidders["appnexus"]["banner|USA"])
+}
+
+func TestNilShaper(t *testing.T) {
+	var shaper *Shaper
+	assert.Equal(t, "", shaper.Group(nil, nil))
+	assert.True(t, shaper.Allow("appnexus", "banner|USA"))
+	shaper.Record("appnexus", "banner|USA", false)
+	shaper.Shutdown()
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/timeutil"
+)
+
+// cleanupInterval is how often the buckets not used for long enough to be full again are removed
+const cleanupInterval = time.Minute
+
+// Limit is the rate a token bucket is refilled at and the number of tokens it holds
+type Limit struct {
+	// PerSecond is the number of tokens added to the bucket every second. A limit of 0 per second allows everything.
+	PerSecond float64
+	// Burst is the number of tokens the bucket holds, defaulting to the tokens added in a second
+	Burst int
+}
+
+func (l Limit) size() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return math.Max(1, math.Ceil(l.PerSecond))
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the bucket was last used
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(b.limit.size(), b.tokens+now.Sub(b.last).Seconds()*b.limit.PerSecond)
+	b.last = now
+}
+
+// Limiter holds a token bucket for each key, e.g. an account or a bidder, created on first use.
+// All the methods of a nil Limiter allow everything.
+type Limiter struct {
+	time timeutil.Time
+
+	mutex       sync.Mutex
+	buckets     map[string]*bucket
+	lastCleanup time.Time
+}
+
+func NewLimiter() *Limiter {
+	return newLimiter(&timeutil.RealTime{})
+}
+
+func newLimiter(clock timeutil.Time) *Limiter {
+	return &Limiter{
+		time:        clock,
+		buckets:     make(map[string]*bucket),
+		lastCleanup: clock.Now(),
+	}
+}
+
+// Allow takes a token from the bucket of the key, returning false when the bucket is empty.
+// The bucket starts again full when the limit of the key changes.
+func (l *Limiter) Allow(key string, limit Limit) bool {
+	if l == nil || limit.PerSecond <= 0 {
+		return true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.time.Now()
+	if now.Sub(l.lastCleanup) >= cleanupInterval {
+		l.cleanup(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{limit: limit, tokens: limit.size(), last: now}
+		l.buckets[key] = b
+	} else {
+		b.refill(now)
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// cleanup removes the buckets which are full again, they are the same as new ones
+func (l *Limiter) cleanup(now time.Time) {
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= b.limit.size() {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastCleanup = now
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTime struct {
+	time time.Time
+}
+
+func (ft *fakeTime) Now() time.Time {
+	return ft.time
+}
+
+func TestAllow(t *testing.T) {
+	clock := &fakeTime{time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := newLimiter(clock)
+	limit := Limit{PerSecond: 2, Burst: 3}
+
+	// the bucket starts full
+	assert.True(t, limiter.Allow("a", limit))
+	assert.True(t, limiter.Allow("a", limit))
+	assert.True(t, limiter.Allow("a", limit))
+	assert.False(t, limiter.Allow("a", limit))
+
+	// the other keys have their own bucket
+	assert.True(t, limiter.Allow("b", limit))
+
+	// refilled at the rate of the limit
+	clock.time = clock.time.Add(500 * time.Millisecond)
+	assert.True(t, limiter.Allow("a", limit))
+	assert.False(t, limiter.Allow("a", limit))
+
+	// never holding more than the burst
+	clock.time = clock.time.Add(10 * time.Second)
+	assert.True(t, limiter.Allow("a", limit))
+	assert.True(t, limiter.Allow("a", limit))
+	assert.True(t, limiter.Allow("a", limit))
+	assert.False(t, limiter.Allow("a", limit))
+
+	// a new limit starts a full bucket
+	assert.True(t, limiter.Allow("a", Limit{PerSecond: 1}))
+	assert.False(t, limiter.Allow("a", Limit{PerSecond: 1}))
+}
+
+func TestAllowNoLimit(t *testing.T) {
+	limiter := NewLimiter()
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.Allow("a", Limit{}))
+	}
+	assert.Empty(t, limiter.buckets)
+
+	var nilLimiter *Limiter
+	assert.True(t, nilLimiter.Allow("a", Limit{PerSecond: 1}))
+}
+
+func TestAllowCleanup(t *testing.T) {
+	clock := &fakeTime{time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := newLimiter(clock)
+
+	limiter.Allow("idle", Limit{PerSecond: 10})
+	clock.time = clock.time.Add(59 * time.Second)
+	limiter.Allow("busy", Limit{PerSecond: 0.5, Burst: 2})
+	clock.time = clock.time.Add(time.Second)
+	limiter.Allow("other", Limit{PerSecond: 1})
+
+	assert.NotContains(t, limiter.buckets, "idle")
+	assert.Contains(t, limiter.buckets, "busy")
+	assert.Contains(t, limiter.buckets, "other")
+}